@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func adoptCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "adopt",
+		Usage:     "Create a thread from existing git checkouts",
+		ArgsUsage: "--name <thread> <dir...>",
+		Description: "Searches each directory for git checkouts and matches them to registered repos by remote URL. " +
+			"Matched checkouts are linked into the new thread in place, or moved into it with --move " +
+			"(linked worktrees move with `git worktree move`). Checkouts that match no registered repo are skipped.",
+		Flags: appendOutputFlags([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Usage:    "Thread name",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "path",
+				Usage: "Thread directory (defaults to <workset_root>/worksets/<workset>/<name>)",
+			},
+			&cli.StringFlag{
+				Name:  "workset",
+				Usage: "Canonical workset name to place the thread under",
+			},
+			&cli.BoolFlag{
+				Name:  "move",
+				Usage: "Move checkouts into the thread instead of linking them in place",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			dirs := cmd.Args().Slice()
+			if len(dirs) == 0 {
+				return usageError(ctx, cmd, "at least one directory required")
+			}
+			result, err := apiService(ctx, cmd).AdoptWorkspace(ctx, worksetapi.WorkspaceAdoptInput{
+				Name:    cmd.String("name"),
+				Path:    cmd.String("path"),
+				Workset: cmd.String("workset"),
+				Dirs:    dirs,
+				Move:    cmd.Bool("move"),
+			})
+			mode := outputModeFromContext(cmd)
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			if err != nil {
+				if len(result.Repos) > 0 && !mode.JSON {
+					_ = printAdoptedRepos(commandErrWriter(cmd), styles, result.Repos)
+				}
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+			}
+			info := output.WorkspaceCreated{
+				Name:    result.Workspace.Name,
+				Path:    result.Workspace.Path,
+				Workset: result.Workspace.Workset,
+				Branch:  result.Workspace.Branch,
+				Next:    result.Workspace.Next,
+			}
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), struct {
+					output.WorkspaceCreated
+
+					Repos    []worksetapi.AdoptedRepoJSON `json:"repos"`
+					Warnings []string                     `json:"warnings,omitempty"`
+				}{
+					WorkspaceCreated: info,
+					Repos:            result.Repos,
+					Warnings:         result.Warnings,
+				})
+			}
+			if err := printWorkspaceCreated(commandWriter(cmd), info, false, mode.Plain); err != nil {
+				return err
+			}
+			return printAdoptedRepos(commandWriter(cmd), styles, result.Repos)
+		},
+	}
+}
+
+func printAdoptedRepos(w io.Writer, styles output.Styles, repos []worksetapi.AdoptedRepoJSON) error {
+	rows := make([][]string, 0, len(repos))
+	for _, repo := range repos {
+		detail := repo.Reason
+		if detail == "" {
+			detail = strings.TrimSpace(repo.Branch)
+		}
+		rows = append(rows, []string{repo.Source, repo.Name, repo.Status, detail})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"CHECKOUT", "REPO", "STATUS", "DETAIL"}, rows))
+	return err
+}
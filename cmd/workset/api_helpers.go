@@ -30,6 +30,18 @@ func printConfigInfo(cmd *cli.Command, info any) {
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.WorkspaceCreateResult:
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.TrashListResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.TrashRestoreResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.TrashPurgeResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.GCResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.RepoStoreResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.ThreadExportResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.WorkspaceDeleteResult:
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.RepoListResult:
@@ -40,6 +52,8 @@ func printConfigInfo(cmd *cli.Command, info any) {
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.WorkspaceStatusResult:
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.ThreadLogResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.RegisteredRepoListResult:
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.HooksRunResult:
@@ -54,6 +68,18 @@ func printConfigInfo(cmd *cli.Command, info any) {
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	case worksetapi.PullRequestTrackedResult:
 		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.ReviewCommentResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.ResolveReviewThreadResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.LinkedPullRequestsResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.CommitAndPushThreadResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.WorkspaceSyncResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
+	case worksetapi.WorkspaceAdoptResult:
+		printConfigLoadInfo(cmd, cmd.String("config"), value.Config)
 	default:
 		// no-op
 	}
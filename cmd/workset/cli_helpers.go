@@ -290,8 +290,10 @@ func interspersedFlag(token string) (flagSpec, bool) {
 		return flagSpec{TakesValue: true}, true
 	case "--interactive", "--pty":
 		return flagSpec{TakesValue: false}, true
-	case "--yes":
+	case "--yes", "--each-repo", "--fetch", "--all-repos":
 		return flagSpec{TakesValue: false}, true
+	case "--parallel":
+		return flagSpec{TakesValue: true}, true
 	}
 	switch {
 	case strings.HasPrefix(token, "--thread="):
@@ -320,6 +322,10 @@ func interspersedFlag(token string) (flagSpec, bool) {
 		return flagSpec{TakesValue: false}, true
 	case strings.HasPrefix(token, "--yes="):
 		return flagSpec{TakesValue: false}, true
+	case strings.HasPrefix(token, "--each-repo="):
+		return flagSpec{TakesValue: false}, true
+	case strings.HasPrefix(token, "--parallel="):
+		return flagSpec{TakesValue: false}, true
 	}
 	return flagSpec{}, false
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func commitCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "commit",
+		Usage:     "Commit and push every dirty repo in a thread (requires -t)",
+		ArgsUsage: "-t <thread> [-m <message>]",
+		Description: "Every dirty repo is checked and given a commit message before anything is committed. " +
+			"If one repo fails to commit, commits already made in this run are undone (changes stay staged) " +
+			"and nothing is pushed. Without -m, each repo gets a message generated with defaults.agent.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Commit message shared by every repo (defaults to a generated message per repo)",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			mode := outputModeFromContext(cmd)
+			result, err := apiService(ctx, cmd).CommitAndPushThread(ctx, worksetapi.CommitAndPushThreadInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Message:   cmd.String("message"),
+				OnStage: func(repo string, stage worksetapi.CommitAndPushStage) {
+					if mode.JSON {
+						return
+					}
+					_, _ = fmt.Fprintf(commandErrWriter(cmd), "%s: %s\n", repo, stage)
+				},
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			if mode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), result.Repos); err != nil {
+					return err
+				}
+			} else if err := printCommitEntries(commandWriter(cmd), output.NewStyles(commandWriter(cmd), mode.Plain), result.Repos); err != nil {
+				return err
+			}
+			failed := 0
+			for _, entry := range result.Repos {
+				if entry.Status == worksetapi.CommitAndPushRepoStatusFailed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return cli.Exit(fmt.Sprintf("commit failed in %d of %d repos", failed, len(result.Repos)), 1)
+			}
+			return nil
+		},
+	}
+}
+
+func printCommitEntries(w io.Writer, styles output.Styles, entries []worksetapi.CommitAndPushRepoJSON) error {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		detail := entry.Error
+		if detail == "" {
+			detail, _, _ = strings.Cut(entry.Message, "\n")
+		}
+		sha := entry.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		rows = append(rows, []string{entry.Repo, string(entry.Status), sha, detail})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "STATUS", "SHA", "DETAIL"}, rows))
+	return err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func execCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "exec",
+		Usage:     "Run a command in the thread root or in every repo worktree (requires -t)",
+		ArgsUsage: "-t <thread> [--each-repo] [--parallel N] -- <command...>",
+		Description: "Runs the command with WORKSET_ROOT, WORKSET_CONFIG, and WORKSET_WORKSPACE set. " +
+			"With --each-repo the command runs once per repo worktree with WORKSET_REPO and " +
+			"WORKSET_WORKTREE set, and output lines are prefixed with the repo name.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.BoolFlag{
+				Name:  "each-repo",
+				Usage: "Run the command once in each repo worktree",
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Usage: "Maximum repos to run concurrently with --each-repo",
+				Value: 1,
+			},
+			&cli.StringSliceFlag{
+				Name:  "repo",
+				Usage: "Limit --each-repo to a repo in the thread (repeatable)",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			command := cmd.Args().Slice()
+			if len(command) > 0 && command[0] == "--" {
+				command = command[1:]
+			}
+			mode := outputModeFromContext(cmd)
+			selector := worksetapi.WorkspaceSelector{Value: cmd.String("thread")}
+			svc := apiService(ctx, cmd)
+			if !cmd.Bool("each-repo") {
+				if mode.JSON {
+					return usageError(ctx, cmd, "--json requires --each-repo")
+				}
+				if len(cmd.StringSlice("repo")) > 0 {
+					return usageError(ctx, cmd, "--repo requires --each-repo")
+				}
+				err := svc.Exec(ctx, worksetapi.ExecInput{Workspace: selector, Command: command})
+				if code := commandExitCode(err); code > 0 {
+					return cli.Exit("", code)
+				}
+				return err
+			}
+			if len(command) == 0 {
+				return usageError(ctx, cmd, "command required (example: workset exec -t <thread> --each-repo -- git status)")
+			}
+			if cmd.Int("parallel") < 1 {
+				return usageError(ctx, cmd, "--parallel must be at least 1")
+			}
+			input := worksetapi.ExecEachRepoInput{
+				Workspace: selector,
+				Command:   command,
+				Repos:     cmd.StringSlice("repo"),
+				Parallel:  cmd.Int("parallel"),
+				Stdout:    commandWriter(cmd),
+				Stderr:    commandErrWriter(cmd),
+			}
+			if mode.JSON {
+				// Keep stdout reserved for the JSON summary.
+				input.Stdout = commandErrWriter(cmd)
+			}
+			result, err := svc.ExecEachRepo(ctx, input)
+			if err != nil {
+				return err
+			}
+			summary := result.Payload
+			if mode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), summary); err != nil {
+					return err
+				}
+			} else if err := printExecSummary(cmd, mode, summary); err != nil {
+				return err
+			}
+			if summary.Failed > 0 {
+				return cli.Exit(fmt.Sprintf("exec failed in %d of %d repos", summary.Failed, summary.Total), 1)
+			}
+			return nil
+		},
+	}
+}
+
+func printExecSummary(cmd *cli.Command, mode outputMode, summary worksetapi.ExecEachRepoJSON) error {
+	w := commandWriter(cmd)
+	styles := output.NewStyles(w, mode.Plain)
+	rows := make([][]string, 0, len(summary.Repos))
+	for _, repo := range summary.Repos {
+		detail := fmt.Sprintf("exit %d", repo.ExitCode)
+		if repo.Status == worksetapi.ExecRepoStatusMissing {
+			detail = repo.Error
+		}
+		rows = append(rows, []string{repo.Repo, repo.Status, detail})
+	}
+	if _, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "STATUS", "DETAIL"}, rows)); err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%d ok, %d failed (%s)", summary.Succeeded, summary.Failed, strings.Join(summary.Command, " "))
+	if styles.Enabled {
+		style := styles.Success
+		if summary.Failed > 0 {
+			style = styles.Error
+		}
+		line = styles.Render(style, line)
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+func commandExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return 0
+}
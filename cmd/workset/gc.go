@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func gcCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "gc",
+		Usage: "Archive finished threads and prune worktrees of archived threads",
+		Description: "Applies defaults.auto_archive: threads whose tracked pull requests are all merged or " +
+			"closed, or that were unused for defaults.auto_archive.stale_days and have no dirty or unpushed " +
+			"repos, are archived. Pinned threads are skipped.",
+		Flags: appendOutputFlags([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Report what would be archived or pruned without changing anything",
+			},
+			&cli.BoolFlag{
+				Name:  "prune-worktrees",
+				Usage: "Also remove the worktrees of archived threads that have no dirty or unpushed repos",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "Use recorded pull request states instead of querying the provider",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			result, err := apiService(ctx, cmd).GC(ctx, worksetapi.GCInput{
+				DryRun:         cmd.Bool("dry-run"),
+				Offline:        cmd.Bool("offline"),
+				PruneWorktrees: cmd.Bool("prune-worktrees"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), worksetapi.GCResultJSON{
+					Archived: result.Archived,
+					Pruned:   result.Pruned,
+				})
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			if len(result.Archived) == 0 && len(result.Pruned) == 0 {
+				msg := "nothing to collect"
+				if styles.Enabled {
+					msg = styles.Render(styles.Muted, msg)
+				}
+				_, err := fmt.Fprintln(commandWriter(cmd), msg)
+				return err
+			}
+			return printGCResult(commandWriter(cmd), styles, result)
+		},
+	}
+}
+
+func printGCResult(w io.Writer, styles output.Styles, result worksetapi.GCResult) error {
+	rows := make([][]string, 0, len(result.Archived)+len(result.Pruned))
+	for _, thread := range result.Archived {
+		rows = append(rows, []string{thread.Name, thread.Action, thread.Reason})
+	}
+	for _, thread := range result.Pruned {
+		detail := strings.Join(thread.Repos, ", ")
+		if thread.Reason != "" {
+			detail = thread.Reason
+		}
+		rows = append(rows, []string{thread.Name, thread.Action, detail})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"THREAD", "ACTION", "DETAIL"}, rows))
+	return err
+}
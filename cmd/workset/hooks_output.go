@@ -18,6 +18,12 @@ func printHookRunReport(w io.Writer, styles output.Styles, repo, event string, r
 	}
 	for _, run := range runs {
 		line := fmt.Sprintf("- %s: %s", run.ID, run.Status)
+		if run.Reason != "" {
+			line = fmt.Sprintf("%s (%s)", line, run.Reason)
+		}
+		if run.TimedOut {
+			line += " (timed out)"
+		}
 		if run.LogPath != "" {
 			line = fmt.Sprintf("%s (log: %s)", line, run.LogPath)
 		}
@@ -61,9 +67,12 @@ func printHookExecutionResults(w io.Writer, styles output.Styles, runs []workset
 			currentEvent = run.Event
 		}
 		group = append(group, worksetapi.HookRunJSON{
-			ID:      run.ID,
-			Status:  run.Status,
-			LogPath: run.LogPath,
+			ID:       run.ID,
+			Status:   run.Status,
+			LogPath:  run.LogPath,
+			Reason:   run.Reason,
+			Attempts: run.Attempts,
+			TimedOut: run.TimedOut,
 		})
 	}
 	return flush()
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func logCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "log",
+		Usage:     "Show a thread's commits across all repos, newest first (requires -t)",
+		ArgsUsage: "-t <thread> [--since <when>] [--author <pattern>] [--patch]",
+		Description: "Lists the commits each repo's thread branch has made since it diverged from the repo's " +
+			"default branch, merged into one timeline.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only commits after a duration ago (48h, 7d) or a date (2006-01-02 or RFC3339)",
+			},
+			&cli.StringFlag{
+				Name:  "author",
+				Usage: "Only commits whose author name or email matches a pattern",
+			},
+			&cli.BoolFlag{
+				Name:  "patch",
+				Usage: "Include each commit's diff",
+			},
+			&cli.StringSliceFlag{
+				Name:  "repo",
+				Usage: "Limit the log to a repo in the thread (repeatable)",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var since time.Time
+			if value := strings.TrimSpace(cmd.String("since")); value != "" {
+				parsed, err := parseSince(value, time.Now())
+				if err != nil {
+					return usageError(ctx, cmd, err.Error())
+				}
+				since = parsed
+			}
+			result, err := apiService(ctx, cmd).ThreadLog(ctx, worksetapi.ThreadLogInput{
+				Selector: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Repos:    cmd.StringSlice("repo"),
+				Since:    since,
+				Author:   cmd.String("author"),
+				Patch:    cmd.Bool("patch"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), result.Commits)
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			if len(result.Commits) == 0 {
+				msg := "no commits on thread"
+				if styles.Enabled {
+					msg = styles.Render(styles.Muted, msg)
+				}
+				_, err := fmt.Fprintln(commandWriter(cmd), msg)
+				return err
+			}
+			if cmd.Bool("patch") {
+				return printThreadPatches(commandWriter(cmd), styles, result.Commits)
+			}
+			return printThreadLog(commandWriter(cmd), styles, result.Commits)
+		},
+	}
+}
+
+func printThreadLog(w io.Writer, styles output.Styles, commits []worksetapi.ThreadCommitJSON) error {
+	rows := make([][]string, 0, len(commits))
+	for _, commit := range commits {
+		rows = append(rows, []string{commitTime(commit), commit.Repo, shortSHA(commit.SHA), commit.Author, commit.Subject})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"TIME", "REPO", "SHA", "AUTHOR", "SUBJECT"}, rows))
+	return err
+}
+
+func printThreadPatches(w io.Writer, styles output.Styles, commits []worksetapi.ThreadCommitJSON) error {
+	for i, commit := range commits {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		header := fmt.Sprintf("[%s] %s %s", commit.Repo, shortSHA(commit.SHA), commit.Subject)
+		if styles.Enabled {
+			header = styles.Render(styles.Title, header)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n%s  %s\n", header, commit.Author, commitTime(commit)); err != nil {
+			return err
+		}
+		if commit.Patch != "" {
+			if _, err := fmt.Fprintf(w, "\n%s\n", commit.Patch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func commitTime(commit worksetapi.ThreadCommitJSON) string {
+	parsed, err := time.Parse(time.RFC3339, commit.Time)
+	if err != nil {
+		return commit.Time
+	}
+	return parsed.Local().Format("2006-01-02 15:04")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// parseSince accepts a Go duration, a day count like "7d", a date, or an
+// RFC3339 timestamp and returns the earliest time it allows.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration (48h, 7d), a date (2006-01-02), or RFC3339", value)
+}
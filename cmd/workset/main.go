@@ -35,13 +35,28 @@ func main() {
 		},
 		Commands: []*cli.Command{
 			newCommand(),
+			adoptCommand(),
+			threadCommand(),
 			listCommand(),
 			hooksCommand(),
 			versionCommand(),
 			removeWorkspaceCommand(),
+			trashCommand(),
+			gcCommand(),
+			storeCommand(),
 			configCommand(),
 			repoCommand(),
 			statusCommand(),
+			logCommand(),
+			execCommand(),
+			prCommand(),
+			commitCommand(),
+			syncCommand(),
+			upCommand(),
+			downCommand(),
+			psCommand(),
+			logsCommand(),
+			termCommand(),
 		},
 	}
 	enableSuggestions(root)
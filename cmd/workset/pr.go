@@ -0,0 +1,595 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+type prCreateEntryJSON struct {
+	Repo        string                             `json:"repo"`
+	PullRequest *worksetapi.PullRequestCreatedJSON `json:"pull_request,omitempty"`
+	Error       string                             `json:"error,omitempty"`
+}
+
+type prStatusEntryJSON struct {
+	Repo        string                            `json:"repo"`
+	PullRequest *worksetapi.PullRequestStatusJSON `json:"pull_request,omitempty"`
+	Checks      []worksetapi.PullRequestCheckJSON `json:"checks,omitempty"`
+	Error       string                            `json:"error,omitempty"`
+}
+
+type prCommentsEntryJSON struct {
+	Repo     string                                    `json:"repo"`
+	Comments []worksetapi.PullRequestReviewCommentJSON `json:"comments"`
+	Error    string                                    `json:"error,omitempty"`
+}
+
+func prCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "pr",
+		Usage: "Create and inspect pull requests for repos in a thread (requires -t)",
+		Description: "Repo selection: --repo (repeatable) targets named repos, --all-repos targets every repo " +
+			"in the thread, and with neither the repo containing the current directory (or the only repo) is used.",
+		Commands: []*cli.Command{
+			prCreateCommand(),
+			prLinkCommand(),
+			prStatusCommand(false),
+			prStatusCommand(true),
+			prCommentsCommand(),
+			prReplyCommand(),
+			prResolveCommand(),
+			prGenerateCommand(),
+		},
+	}
+}
+
+func prCreateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "create",
+		Usage:     "Open a pull request for each selected repo",
+		ArgsUsage: "-t <thread> [--repo <name>...|--all-repos] (--title <title>|--generate)",
+		Flags: appendOutputFlags(append(prRepoFlags(true),
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "Pull request title",
+			},
+			&cli.StringFlag{
+				Name:  "body",
+				Usage: "Pull request body",
+			},
+			&cli.BoolFlag{
+				Name:  "generate",
+				Usage: "Generate the title and body with defaults.agent when --title is not set",
+			},
+			&cli.StringFlag{
+				Name:  "base",
+				Usage: "Base branch (defaults to the remote default branch)",
+			},
+			&cli.StringFlag{
+				Name:  "base-remote",
+				Usage: "Remote that owns the base repo (defaults to auto-detect)",
+			},
+			&cli.BoolFlag{
+				Name:  "draft",
+				Usage: "Open as a draft",
+			},
+			&cli.BoolFlag{
+				Name:  "commit",
+				Usage: "Commit pending changes before opening",
+			},
+			&cli.BoolFlag{
+				Name:  "push",
+				Usage: "Push the head branch before opening",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			title := strings.TrimSpace(cmd.String("title"))
+			if title == "" && !cmd.Bool("generate") {
+				return usageError(ctx, cmd, "--title or --generate required")
+			}
+			svc := apiService(ctx, cmd)
+			selector := worksetapi.WorkspaceSelector{Value: cmd.String("thread")}
+			targets, err := prRepoTargets(ctx, cmd, svc, selector)
+			if err != nil {
+				return err
+			}
+			entries := make([]prCreateEntryJSON, 0, len(targets))
+			failed, firstErr := runPRTargets(targets, func(repo string) error {
+				entry := prCreateEntryJSON{Repo: repo}
+				input := worksetapi.PullRequestCreateInput{
+					Workspace:  selector,
+					Repo:       repo,
+					Base:       cmd.String("base"),
+					BaseRemote: cmd.String("base-remote"),
+					Title:      title,
+					Body:       cmd.String("body"),
+					Draft:      cmd.Bool("draft"),
+					AutoCommit: cmd.Bool("commit"),
+					AutoPush:   cmd.Bool("push"),
+				}
+				err := func() error {
+					if input.Title == "" {
+						generated, err := svc.GeneratePullRequestText(ctx, worksetapi.PullRequestGenerateInput{
+							Workspace: selector,
+							Repo:      repo,
+							Base:      input.Base,
+						})
+						if err != nil {
+							return err
+						}
+						input.Title = generated.Payload.Title
+						if strings.TrimSpace(input.Body) == "" {
+							input.Body = generated.Payload.Body
+						}
+					}
+					result, err := svc.CreatePullRequest(ctx, input)
+					if err != nil {
+						return err
+					}
+					printConfigInfo(cmd, result)
+					entry.Repo = result.Payload.Repo
+					entry.PullRequest = &result.Payload
+					return nil
+				}()
+				if err != nil {
+					entry.Error = err.Error()
+				}
+				entries = append(entries, entry)
+				return err
+			})
+			if len(targets) == 1 && firstErr != nil {
+				return firstErr
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), entries); err != nil {
+					return err
+				}
+			} else if err := printPRCreateEntries(commandWriter(cmd), output.NewStyles(commandWriter(cmd), mode.Plain), entries); err != nil {
+				return err
+			}
+			return prTargetsError("create", len(targets), failed)
+		},
+	}
+}
+
+// prLinkCommand opens pull requests for every repo with commits ahead of base
+// and cross-links them through a Related PRs section in each body.
+func prLinkCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "link",
+		Usage:     "Open cross-linked pull requests for every repo with commits ahead of base",
+		ArgsUsage: "-t <thread> (--title <title>|--generate)",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "Pull request title shared by every repo",
+			},
+			&cli.StringFlag{
+				Name:  "body",
+				Usage: "Pull request body shared by every repo",
+			},
+			&cli.BoolFlag{
+				Name:  "generate",
+				Usage: "Generate each repo's title and body with defaults.agent when --title is not set",
+			},
+			&cli.StringFlag{
+				Name:  "base",
+				Usage: "Base branch (defaults to each repo's remote default branch)",
+			},
+			&cli.BoolFlag{
+				Name:  "draft",
+				Usage: "Open as drafts",
+			},
+			&cli.BoolFlag{
+				Name:  "push",
+				Usage: "Push head branches before opening",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			title := strings.TrimSpace(cmd.String("title"))
+			if title == "" && !cmd.Bool("generate") {
+				return usageError(ctx, cmd, "--title or --generate required")
+			}
+			result, err := apiService(ctx, cmd).CreateLinkedPullRequests(ctx, worksetapi.LinkedPullRequestsInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Base:      cmd.String("base"),
+				Title:     title,
+				Body:      cmd.String("body"),
+				Draft:     cmd.Bool("draft"),
+				AutoPush:  cmd.Bool("push"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintf(commandErrWriter(cmd), "warning: %s\n", warning)
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), result.PullRequests); err != nil {
+					return err
+				}
+			} else if err := printPRLinkEntries(commandWriter(cmd), output.NewStyles(commandWriter(cmd), mode.Plain), result.PullRequests); err != nil {
+				return err
+			}
+			failed := 0
+			for _, entry := range result.PullRequests {
+				if entry.Status == worksetapi.LinkedPullRequestStatusFailed {
+					failed++
+				}
+			}
+			return prTargetsError("link", len(result.PullRequests), failed)
+		},
+	}
+}
+
+// prStatusCommand builds `pr status`, or `pr checks` when checksOnly is set.
+// Both fetch the same payload; checks lists individual check runs and exits
+// non-zero when any of them failed so it can gate CI.
+func prStatusCommand(checksOnly bool) *cli.Command {
+	name := "status"
+	usage := "Show pull request state and a checks summary for each selected repo"
+	if checksOnly {
+		name = "checks"
+		usage = "List check runs for each selected repo's pull request (exits 1 if any failed)"
+	}
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		ArgsUsage: "-t <thread> [--repo <name>...|--all-repos] [--number <n>]",
+		Flags: appendOutputFlags(append(prRepoFlags(true),
+			&cli.IntFlag{
+				Name:  "number",
+				Usage: "Pull request number (single repo only; defaults to the branch's PR)",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			svc := apiService(ctx, cmd)
+			selector := worksetapi.WorkspaceSelector{Value: cmd.String("thread")}
+			targets, err := prRepoTargets(ctx, cmd, svc, selector)
+			if err != nil {
+				return err
+			}
+			number := cmd.Int("number")
+			if number > 0 && len(targets) > 1 {
+				return usageError(ctx, cmd, "--number requires a single repo")
+			}
+			entries := make([]prStatusEntryJSON, 0, len(targets))
+			failed, firstErr := runPRTargets(targets, func(repo string) error {
+				entry := prStatusEntryJSON{Repo: repo}
+				result, err := svc.GetPullRequestStatus(ctx, worksetapi.PullRequestStatusInput{
+					Workspace: selector,
+					Repo:      repo,
+					Number:    number,
+				})
+				if err != nil {
+					entry.Error = err.Error()
+				} else {
+					printConfigInfo(cmd, result)
+					entry.Repo = result.PullRequest.Repo
+					entry.PullRequest = &result.PullRequest
+					entry.Checks = result.Checks
+				}
+				entries = append(entries, entry)
+				return err
+			})
+			if len(targets) == 1 && firstErr != nil {
+				return firstErr
+			}
+			mode := outputModeFromContext(cmd)
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			switch {
+			case mode.JSON:
+				err = output.WriteJSON(commandWriter(cmd), entries)
+			case checksOnly:
+				err = printPRCheckEntries(commandWriter(cmd), styles, entries)
+			default:
+				err = printPRStatusEntries(commandWriter(cmd), styles, entries)
+			}
+			if err != nil {
+				return err
+			}
+			if err := prTargetsError(name, len(targets), failed); err != nil {
+				return err
+			}
+			if checksOnly {
+				if failing := countFailingChecks(entries); failing > 0 {
+					return cli.Exit(fmt.Sprintf("%d check(s) failed", failing), 1)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func prCommentsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "comments",
+		Usage:     "List review comments on each selected repo's pull request",
+		ArgsUsage: "-t <thread> [--repo <name>...|--all-repos] [--number <n>] [--unresolved]",
+		Flags: appendOutputFlags(append(prRepoFlags(true),
+			&cli.IntFlag{
+				Name:  "number",
+				Usage: "Pull request number (single repo only; defaults to the branch's PR)",
+			},
+			&cli.BoolFlag{
+				Name:  "unresolved",
+				Usage: "Only show comments in unresolved threads",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			svc := apiService(ctx, cmd)
+			selector := worksetapi.WorkspaceSelector{Value: cmd.String("thread")}
+			targets, err := prRepoTargets(ctx, cmd, svc, selector)
+			if err != nil {
+				return err
+			}
+			number := cmd.Int("number")
+			if number > 0 && len(targets) > 1 {
+				return usageError(ctx, cmd, "--number requires a single repo")
+			}
+			entries := make([]prCommentsEntryJSON, 0, len(targets))
+			failed, firstErr := runPRTargets(targets, func(repo string) error {
+				entry := prCommentsEntryJSON{Repo: repo, Comments: []worksetapi.PullRequestReviewCommentJSON{}}
+				result, err := svc.ListPullRequestReviewComments(ctx, worksetapi.PullRequestReviewsInput{
+					Workspace: selector,
+					Repo:      repo,
+					Number:    number,
+				})
+				if err != nil {
+					entry.Error = err.Error()
+				} else {
+					printConfigInfo(cmd, result)
+					for _, comment := range result.Comments {
+						if cmd.Bool("unresolved") && comment.Resolved {
+							continue
+						}
+						entry.Comments = append(entry.Comments, comment)
+					}
+				}
+				entries = append(entries, entry)
+				return err
+			})
+			if len(targets) == 1 && firstErr != nil {
+				return firstErr
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), entries); err != nil {
+					return err
+				}
+			} else if err := printPRCommentEntries(commandWriter(cmd), output.NewStyles(commandWriter(cmd), mode.Plain), entries); err != nil {
+				return err
+			}
+			return prTargetsError("comments", len(targets), failed)
+		},
+	}
+}
+
+func prReplyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "reply",
+		Usage:     "Reply to a review comment",
+		ArgsUsage: "-t <thread> [--repo <name>] <comment-id> <body...>",
+		Flags: appendOutputFlags(append(prRepoFlags(false),
+			&cli.IntFlag{
+				Name:  "number",
+				Usage: "Pull request number (defaults to the branch's PR)",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			args := cmd.Args().Slice()
+			if len(args) < 2 {
+				return usageError(ctx, cmd, "usage: workset pr reply -t <thread> [--repo <name>] <comment-id> <body...>")
+			}
+			commentID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || commentID <= 0 {
+				return usageError(ctx, cmd, fmt.Sprintf("invalid comment id %q", args[0]))
+			}
+			svc := apiService(ctx, cmd)
+			result, err := svc.ReplyToReviewComment(ctx, worksetapi.ReplyToReviewCommentInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Repo:      cmd.String("repo"),
+				Number:    cmd.Int("number"),
+				CommentID: commentID,
+				Body:      strings.Join(args[1:], " "),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), result.Comment)
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			msg := fmt.Sprintf("replied to comment %d", commentID)
+			if result.Comment.URL != "" {
+				msg = fmt.Sprintf("%s: %s", msg, result.Comment.URL)
+			}
+			if styles.Enabled {
+				msg = styles.Render(styles.Success, msg)
+			}
+			_, err = fmt.Fprintln(commandWriter(cmd), msg)
+			return err
+		},
+	}
+}
+
+func prResolveCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "resolve",
+		Usage:     "Resolve (or unresolve) a review thread",
+		ArgsUsage: "-t <thread> [--repo <name>] <thread-id> [--unresolve]",
+		Description: "Accepts a review thread ID (as shown by `workset pr comments`) or a review " +
+			"comment node ID, which is mapped to its thread.",
+		Flags: appendOutputFlags(append(prRepoFlags(false),
+			&cli.BoolFlag{
+				Name:  "unresolve",
+				Usage: "Mark the thread unresolved instead",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			threadID := strings.TrimSpace(cmd.Args().First())
+			if threadID == "" {
+				return usageError(ctx, cmd, "usage: workset pr resolve -t <thread> [--repo <name>] <thread-id>")
+			}
+			svc := apiService(ctx, cmd)
+			result, err := svc.ResolveReviewThread(ctx, worksetapi.ResolveReviewThreadInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Repo:      cmd.String("repo"),
+				ThreadID:  threadID,
+				Resolve:   !cmd.Bool("unresolve"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), struct {
+					ThreadID string `json:"thread_id"`
+					Resolved bool   `json:"resolved"`
+				}{
+					ThreadID: threadID,
+					Resolved: result.Resolved,
+				})
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			msg := "resolved " + threadID
+			if !result.Resolved {
+				msg = "unresolved " + threadID
+			}
+			if styles.Enabled {
+				msg = styles.Render(styles.Success, msg)
+			}
+			_, err = fmt.Fprintln(commandWriter(cmd), msg)
+			return err
+		},
+	}
+}
+
+func prGenerateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "generate",
+		Usage:     "Generate a pull request title and body with defaults.agent",
+		ArgsUsage: "-t <thread> [--repo <name>]",
+		Flags: appendOutputFlags(append(prRepoFlags(false),
+			&cli.StringFlag{
+				Name:  "base",
+				Usage: "Base branch to describe changes against",
+			},
+		)),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			svc := apiService(ctx, cmd)
+			result, err := svc.GeneratePullRequestText(ctx, worksetapi.PullRequestGenerateInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Repo:      cmd.String("repo"),
+				Base:      cmd.String("base"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), result.Payload)
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			title := result.Payload.Title
+			if styles.Enabled {
+				title = styles.Render(styles.Title, title)
+			}
+			_, err = fmt.Fprintf(commandWriter(cmd), "%s\n\n%s\n", title, strings.TrimSpace(result.Payload.Body))
+			return err
+		},
+	}
+}
+
+// prRepoFlags returns the thread and repo selection flags. Commands that act
+// on a single pull request take one --repo; fan-out commands also accept
+// repeated --repo and --all-repos.
+func prRepoFlags(multi bool) []cli.Flag {
+	if !multi {
+		return []cli.Flag{
+			threadFlag(true),
+			&cli.StringFlag{
+				Name:  "repo",
+				Usage: "Repo in the thread (defaults to the repo containing the current directory)",
+			},
+		}
+	}
+	return []cli.Flag{
+		threadFlag(true),
+		&cli.StringSliceFlag{
+			Name:  "repo",
+			Usage: "Repo in the thread (repeatable; defaults to the repo containing the current directory)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "all-repos",
+			Usage: "Target every repo in the thread",
+		},
+	}
+}
+
+// prRepoTargets resolves the repos a fan-out command should visit. An empty
+// name lets the service pick the repo from the working directory.
+func prRepoTargets(ctx context.Context, cmd *cli.Command, svc *worksetapi.Service, selector worksetapi.WorkspaceSelector) ([]string, error) {
+	repos := cmd.StringSlice("repo")
+	if !cmd.Bool("all-repos") {
+		if len(repos) == 0 {
+			return []string{""}, nil
+		}
+		return repos, nil
+	}
+	if len(repos) > 0 {
+		return nil, usageError(ctx, cmd, "--repo and --all-repos cannot be combined")
+	}
+	result, err := svc.ListRepos(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Repos) == 0 {
+		return nil, errors.New("no repos in thread")
+	}
+	names := make([]string, 0, len(result.Repos))
+	for _, repo := range result.Repos {
+		names = append(names, repo.Name)
+	}
+	return names, nil
+}
+
+// runPRTargets calls fn for each repo in order, continuing past failures.
+func runPRTargets(targets []string, fn func(repo string) error) (int, error) {
+	failed := 0
+	var firstErr error
+	for _, repo := range targets {
+		if err := fn(repo); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return failed, firstErr
+}
+
+func prTargetsError(name string, total, failed int) error {
+	if failed == 0 {
+		return nil
+	}
+	return cli.Exit(fmt.Sprintf("pr %s failed in %d of %d repos", name, failed, total), 1)
+}
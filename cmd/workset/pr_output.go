@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+)
+
+const prCommentPreviewLen = 60
+
+func printPRCreateEntries(w io.Writer, styles output.Styles, entries []prCreateEntryJSON) error {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PullRequest == nil {
+			rows = append(rows, []string{entry.Repo, "failed", entry.Error})
+			continue
+		}
+		rows = append(rows, []string{entry.Repo, fmt.Sprintf("#%d", entry.PullRequest.Number), entry.PullRequest.URL})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "PR", "DETAIL"}, rows))
+	return err
+}
+
+func printPRLinkEntries(w io.Writer, styles output.Styles, entries []worksetapi.LinkedPullRequestJSON) error {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.PullRequest == nil {
+			rows = append(rows, []string{entry.Repo, "", string(entry.Status), entry.Reason})
+			continue
+		}
+		rows = append(rows, []string{entry.Repo, fmt.Sprintf("#%d", entry.PullRequest.Number), string(entry.Status), entry.PullRequest.URL})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "PR", "STATUS", "DETAIL"}, rows))
+	return err
+}
+
+func printPRStatusEntries(w io.Writer, styles output.Styles, entries []prStatusEntryJSON) error {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		pr := entry.PullRequest
+		if pr == nil {
+			rows = append(rows, []string{entry.Repo, "", "error", "", entry.Error})
+			continue
+		}
+		state := pr.State
+		switch {
+		case pr.Merged:
+			state = "merged"
+		case pr.Draft:
+			state = "draft"
+		}
+		if pr.Mergeable == "conflicts" {
+			state += " (conflicts)"
+		}
+		rows = append(rows, []string{entry.Repo, fmt.Sprintf("#%d", pr.Number), state, summarizeChecks(entry.Checks), pr.URL})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "PR", "STATE", "CHECKS", "URL"}, rows))
+	return err
+}
+
+func printPRCheckEntries(w io.Writer, styles output.Styles, entries []prStatusEntryJSON) error {
+	rows := [][]string{}
+	for _, entry := range entries {
+		if entry.PullRequest == nil {
+			rows = append(rows, []string{entry.Repo, "", "error", entry.Error})
+			continue
+		}
+		if len(entry.Checks) == 0 {
+			rows = append(rows, []string{entry.Repo, "", "none", ""})
+			continue
+		}
+		for _, check := range entry.Checks {
+			result := check.Conclusion
+			if result == "" {
+				result = check.Status
+			}
+			if styles.Enabled && checkFailed(check) {
+				result = styles.Render(styles.Error, result)
+			}
+			rows = append(rows, []string{entry.Repo, check.Name, result, check.DetailsURL})
+		}
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "CHECK", "RESULT", "DETAILS"}, rows))
+	return err
+}
+
+func printPRCommentEntries(w io.Writer, styles output.Styles, entries []prCommentsEntryJSON) error {
+	rows := [][]string{}
+	for _, entry := range entries {
+		if entry.Error != "" {
+			rows = append(rows, []string{entry.Repo, "", "", "", "", "error", entry.Error})
+			continue
+		}
+		for _, comment := range entry.Comments {
+			location := comment.Path
+			if comment.Line > 0 {
+				location = fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+			}
+			state := "open"
+			switch {
+			case comment.Resolved:
+				state = "resolved"
+			case comment.Outdated:
+				state = "outdated"
+			}
+			rows = append(rows, []string{
+				entry.Repo,
+				fmt.Sprintf("%d", comment.ID),
+				comment.ThreadID,
+				location,
+				comment.Author,
+				state,
+				commentPreview(comment.Body),
+			})
+		}
+	}
+	if len(rows) == 0 {
+		msg := "no review comments"
+		if styles.Enabled {
+			msg = styles.Render(styles.Muted, msg)
+		}
+		_, err := fmt.Fprintln(w, msg)
+		return err
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "ID", "THREAD", "LOCATION", "AUTHOR", "STATE", "BODY"}, rows))
+	return err
+}
+
+func commentPreview(body string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	if len(line) > prCommentPreviewLen {
+		return line[:prCommentPreviewLen-3] + "..."
+	}
+	return line
+}
+
+func summarizeChecks(checks []worksetapi.PullRequestCheckJSON) string {
+	if len(checks) == 0 {
+		return "none"
+	}
+	passed, failed, pending := 0, 0, 0
+	for _, check := range checks {
+		switch {
+		case checkFailed(check):
+			failed++
+		case check.Status != "completed":
+			pending++
+		default:
+			passed++
+		}
+	}
+	return fmt.Sprintf("%d passed, %d failed, %d pending", passed, failed, pending)
+}
+
+func checkFailed(check worksetapi.PullRequestCheckJSON) bool {
+	switch check.Conclusion {
+	case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+		return true
+	}
+	return false
+}
+
+func countFailingChecks(entries []prStatusEntryJSON) int {
+	count := 0
+	for _, entry := range entries {
+		for _, check := range entry.Checks {
+			if checkFailed(check) {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+const processFollowInterval = 500 * time.Millisecond
+
+func upCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "up",
+		Usage:     "Start a thread's processes (requires -t)",
+		ArgsUsage: "-t <thread> [process...]",
+		Description: "Starts the processes in the thread's workset.yaml as terminal-service sessions, " +
+			"dependencies first, waiting for each dependency's ready pattern. Naming processes starts " +
+			"only those and what they depend on. The desktop app or `workset term serve` must be running.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "How long to wait for each dependency to become ready",
+				Value: 2 * time.Minute,
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			result, err := apiService(ctx, cmd).ProcessesUp(ctx, worksetapi.ProcessesInput{
+				Workspace:    worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Names:        cmd.Args().Slice(),
+				ReadyTimeout: cmd.Duration("timeout"),
+			})
+			if err != nil {
+				return err
+			}
+			return printProcesses(cmd, result)
+		},
+	}
+}
+
+func downCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "down",
+		Usage:     "Stop a thread's processes (requires -t)",
+		ArgsUsage: "-t <thread> [process...]",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			result, err := apiService(ctx, cmd).ProcessesDown(ctx, worksetapi.ProcessesInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Names:     cmd.Args().Slice(),
+			})
+			if err != nil {
+				return err
+			}
+			return printProcesses(cmd, result)
+		},
+	}
+}
+
+func psCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "ps",
+		Usage:     "Show the state of a thread's processes (requires -t)",
+		ArgsUsage: "-t <thread> [process...]",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			result, err := apiService(ctx, cmd).ProcessStatus(ctx, worksetapi.ProcessesInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Names:     cmd.Args().Slice(),
+			})
+			if err != nil {
+				return err
+			}
+			return printProcesses(cmd, result)
+		},
+	}
+}
+
+func logsCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "logs",
+		Usage:       "Show a thread's process output (requires -t)",
+		ArgsUsage:   "-t <thread> [-f] [process...]",
+		Description: "Prints each process's recent output with lines prefixed by repo and process name.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Keep printing new output until interrupted",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			svc := apiService(ctx, cmd)
+			input := worksetapi.ProcessLogsInput{
+				Workspace: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Names:     cmd.Args().Slice(),
+				Follow:    cmd.Bool("follow"),
+			}
+			mode := outputModeFromContext(cmd)
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			for {
+				result, err := svc.ProcessLogs(ctx, input)
+				if err != nil {
+					return err
+				}
+				if mode.JSON && !input.Follow {
+					return output.WriteJSON(commandWriter(cmd), result.Lines)
+				}
+				if err := printProcessLogs(commandWriter(cmd), styles, mode.JSON, result.Lines); err != nil {
+					return err
+				}
+				if !input.Follow {
+					return nil
+				}
+				input.Offsets = result.NextOffsets
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(processFollowInterval):
+				}
+			}
+		},
+	}
+}
+
+func printProcesses(cmd *cli.Command, result worksetapi.ProcessesResult) error {
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+	}
+	mode := outputModeFromContext(cmd)
+	if mode.JSON {
+		return output.WriteJSON(commandWriter(cmd), result.Processes)
+	}
+	styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+	rows := make([][]string, 0, len(result.Processes))
+	for _, proc := range result.Processes {
+		state := proc.State
+		if proc.State == worksetapi.ProcessStateExited && proc.ExitCode != nil {
+			state = fmt.Sprintf("exited (%d)", *proc.ExitCode)
+		}
+		repo := proc.Repo
+		if repo == "" {
+			repo = "-"
+		}
+		rows = append(rows, []string{proc.Name, repo, state, strconv.Itoa(proc.Restarts), proc.Command})
+	}
+	_, err := fmt.Fprint(commandWriter(cmd), output.RenderTable(styles, []string{"NAME", "REPO", "STATE", "RESTARTS", "COMMAND"}, rows))
+	return err
+}
+
+// printProcessLogs writes lines as "[prefix] text", or as one JSON object per
+// line when following with --json.
+func printProcessLogs(w io.Writer, styles output.Styles, jsonLines bool, lines []worksetapi.ProcessLogLineJSON) error {
+	for _, line := range lines {
+		if jsonLines {
+			if err := json.NewEncoder(w).Encode(line); err != nil {
+				return err
+			}
+			continue
+		}
+		prefix := "[" + line.Prefix + "]"
+		if styles.Enabled {
+			prefix = styles.Render(styles.Muted, prefix)
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", prefix, line.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -72,7 +72,7 @@ func repoCommand() *cli.Command {
 				Name:      "add",
 				Usage:     "Add a repo to the thread and clone it (requires -t)",
 				ArgsUsage: "-t <thread> <alias|url|path>",
-				Flags: appendOutputFlags([]cli.Flag{
+				Flags: appendOutputFlags(append([]cli.Flag{
 					threadFlag(true),
 					&cli.StringFlag{
 						Name:  "name",
@@ -82,7 +82,7 @@ func repoCommand() *cli.Command {
 						Name:  "repo-dir",
 						Usage: "Directory name for the repo within the thread",
 					},
-				}),
+				}, repoStartFlags()...)),
 				ShellComplete: func(ctx context.Context, cmd *cli.Command) {
 					if cmd.NArg() == 0 {
 						completeRegisteredRepos(cmd)
@@ -101,6 +101,7 @@ func repoCommand() *cli.Command {
 						Name:      strings.TrimSpace(cmd.String("name")),
 						NameSet:   cmd.IsSet("name"),
 						RepoDir:   cmd.String("repo-dir"),
+						Start:     repoStartFromFlags(cmd),
 					})
 					if err != nil {
 						return err
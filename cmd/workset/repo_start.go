@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+// threadRepoStartFlags are the start-point flags for `workset new`. --from
+// and --pr take an optional <repo>= prefix to target a single repo.
+func threadRepoStartFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "from",
+			Usage: "Start the thread branch from a branch, tag, or commit ([repo=]ref, repeatable)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "existing-branch",
+			Usage: "Check out the thread branch as it already exists on the remote",
+		},
+		&cli.StringSliceFlag{
+			Name:  "pr",
+			Usage: "Check out a pull request's head branch ([repo=]number, repeatable)",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	}
+}
+
+// repoStartFlags are the start-point flags for `workset repo add`.
+func repoStartFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from",
+			Usage: "Start the thread branch from a branch, tag, or commit",
+		},
+		&cli.BoolFlag{
+			Name:  "existing-branch",
+			Usage: "Check out the thread branch as it already exists on the remote",
+		},
+		&cli.IntFlag{
+			Name:  "pr",
+			Usage: "Check out a pull request's head branch",
+		},
+	}
+}
+
+func repoStartFromFlags(cmd *cli.Command) worksetapi.RepoStart {
+	return worksetapi.RepoStart{
+		From:           strings.TrimSpace(cmd.String("from")),
+		ExistingBranch: cmd.Bool("existing-branch"),
+		PullRequest:    cmd.Int("pr"),
+	}
+}
+
+// threadRepoStartsFromFlags splits --from/--pr values into the thread-wide
+// start and per-repo overrides.
+func threadRepoStartsFromFlags(cmd *cli.Command) (worksetapi.RepoStart, map[string]worksetapi.RepoStart, error) {
+	start := worksetapi.RepoStart{ExistingBranch: cmd.Bool("existing-branch")}
+	perRepo := map[string]worksetapi.RepoStart{}
+	override := func(repo string, apply func(*worksetapi.RepoStart)) {
+		entry := perRepo[repo]
+		apply(&entry)
+		perRepo[repo] = entry
+	}
+	for _, value := range cmd.StringSlice("from") {
+		repo, ref := splitRepoStartValue(value)
+		if ref == "" {
+			return start, nil, fmt.Errorf("--from %q: ref required", value)
+		}
+		if repo == "" {
+			start.From = ref
+			continue
+		}
+		override(repo, func(entry *worksetapi.RepoStart) { entry.From = ref })
+	}
+	for _, value := range cmd.StringSlice("pr") {
+		repo, raw := splitRepoStartValue(value)
+		number, err := strconv.Atoi(raw)
+		if err != nil || number <= 0 {
+			return start, nil, fmt.Errorf("--pr %q: pull request number required", value)
+		}
+		if repo == "" {
+			start.PullRequest = number
+			continue
+		}
+		override(repo, func(entry *worksetapi.RepoStart) { entry.PullRequest = number })
+	}
+	if len(perRepo) == 0 {
+		perRepo = nil
+	}
+	return start, perRepo, nil
+}
+
+func splitRepoStartValue(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if repo, rest, ok := strings.Cut(value, "="); ok {
+		return strings.TrimSpace(repo), strings.TrimSpace(rest)
+	}
+	return "", value
+}
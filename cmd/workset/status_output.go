@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+)
+
+func repoStatusRow(repo worksetapi.RepoStatusJSON) output.StatusRow {
+	row := output.StatusRow{
+		Name:   repo.Name,
+		State:  repo.State,
+		Branch: repo.Branch,
+	}
+	if repo.Detached {
+		row.Branch = "(detached)"
+	}
+
+	sync := []string{}
+	if repo.Upstream != "" {
+		sync = append(sync, fmt.Sprintf("+%d/-%d %s", repo.Ahead, repo.Behind, repo.Upstream))
+	} else if repo.Branch != "" {
+		sync = append(sync, "no upstream")
+	}
+	if repo.BaseRef != "" {
+		base := strings.TrimPrefix(strings.TrimPrefix(repo.BaseRef, "refs/remotes/"), "refs/heads/")
+		sync = append(sync, fmt.Sprintf("+%d/-%d %s", repo.BaseAhead, repo.BaseBehind, base))
+	}
+	row.Sync = strings.Join(sync, ", ")
+
+	if pr := repo.PullRequest; pr != nil {
+		state := pr.State
+		switch {
+		case pr.Merged:
+			state = "merged"
+		case pr.Draft:
+			state = "draft"
+		}
+		row.PR = fmt.Sprintf("#%d %s", pr.Number, state)
+	}
+
+	details := []string{repo.Path}
+	if repo.Error != "" {
+		details = []string{repo.Error}
+	}
+	if len(repo.Conflicts) > 0 {
+		details = append(details, "conflicts: "+strings.Join(repo.Conflicts, ", "))
+	}
+	if repo.Stashes > 0 {
+		details = append(details, fmt.Sprintf("%d stash(es)", repo.Stashes))
+	}
+	if repo.FetchError != "" {
+		details = append(details, "fetch failed: "+repo.FetchError)
+	}
+	row.Detail = strings.Join(details, "; ")
+	return row
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/internal/repostore"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func storeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "store",
+		Usage: "Manage the bare mirrors that URL-registered repos are served from",
+		Description: "Each repo registered by URL has one bare mirror under defaults.repo_store_root. " +
+			"Every thread's worktree of that repo is checked out from it.",
+		Commands: []*cli.Command{
+			{
+				Name:    "ls",
+				Aliases: []string{"list", "status"},
+				Usage:   "Show each mirror's size, last fetch, and worktree count",
+				Flags:   outputFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := apiService(ctx, cmd).RepoStoreStatus(ctx)
+					if err != nil {
+						return err
+					}
+					return printRepoStoreResult(cmd, result, "no repos registered by url")
+				},
+			},
+			{
+				Name:      "fetch",
+				Usage:     "Clone missing mirrors and fetch existing ones",
+				ArgsUsage: "[<repo>...]",
+				Flags: appendOutputFlags([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "interval",
+						Usage: "Keep running and fetch again after this long (for example 15m, or auto for defaults.repo_store.prefetch_interval)",
+					},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					svc := apiService(ctx, cmd)
+					interval, err := storeFetchInterval(ctx, svc, cmd.String("interval"))
+					if err != nil {
+						return usageError(ctx, cmd, err.Error())
+					}
+					input := worksetapi.RepoStoreFetchInput{Repos: cmd.Args().Slice()}
+					for {
+						result, err := svc.FetchRepoStore(ctx, input)
+						if err != nil {
+							return err
+						}
+						if err := printRepoStoreResult(cmd, result, "no repos registered by url"); err != nil {
+							return err
+						}
+						if interval == 0 {
+							return repoStoreFailure(result, "fetch")
+						}
+						select {
+						case <-ctx.Done():
+							return nil
+						case <-time.After(interval):
+						}
+					}
+				},
+			},
+			{
+				Name:  "gc",
+				Usage: "Prune stale worktree metadata and repack every mirror",
+				Flags: appendOutputFlags([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report what would be collected or removed without changing anything",
+					},
+					&cli.BoolFlag{
+						Name:  "prune-orphans",
+						Usage: "Delete store directories that no registered repo or thread uses",
+					},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := apiService(ctx, cmd).GCRepoStore(ctx, worksetapi.RepoStoreGCInput{
+						DryRun:       cmd.Bool("dry-run"),
+						PruneOrphans: cmd.Bool("prune-orphans"),
+					})
+					if err != nil {
+						return err
+					}
+					if err := printRepoStoreResult(cmd, result, "repo store is empty"); err != nil {
+						return err
+					}
+					return repoStoreFailure(result, "gc")
+				},
+			},
+			{
+				Name:      "verify",
+				Usage:     "Check mirrors for missing objects and mismatched remotes",
+				ArgsUsage: "[<repo>...]",
+				Flags:     outputFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := apiService(ctx, cmd).VerifyRepoStore(ctx, worksetapi.RepoStoreVerifyInput{
+						Repos: cmd.Args().Slice(),
+					})
+					if err != nil {
+						return err
+					}
+					if err := printRepoStoreResult(cmd, result, "no repos registered by url"); err != nil {
+						return err
+					}
+					return repoStoreFailure(result, "verification")
+				},
+			},
+		},
+	}
+}
+
+// storeFetchInterval resolves --interval; "auto" uses
+// defaults.repo_store.prefetch_interval.
+func storeFetchInterval(ctx context.Context, svc *worksetapi.Service, value string) (time.Duration, error) {
+	if value == "auto" {
+		cfg, _, err := svc.GetConfig(ctx)
+		if err != nil {
+			return 0, err
+		}
+		value = cfg.Defaults.RepoStore.PrefetchInterval
+	}
+	interval, err := repostore.ParseInterval(value)
+	if err != nil {
+		return 0, fmt.Errorf("--interval: %v", err)
+	}
+	return interval, nil
+}
+
+func repoStoreFailure(result worksetapi.RepoStoreResult, what string) error {
+	failed := 0
+	for _, entry := range result.Repos {
+		if entry.Action == "failed" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return cli.Exit(fmt.Sprintf("%s failed for %d of %d repos", what, failed, len(result.Repos)), 1)
+}
+
+func printRepoStoreResult(cmd *cli.Command, result worksetapi.RepoStoreResult, empty string) error {
+	printConfigInfo(cmd, result)
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+	}
+	mode := outputModeFromContext(cmd)
+	if mode.JSON {
+		return output.WriteJSON(commandWriter(cmd), result.Repos)
+	}
+	styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+	if len(result.Repos) == 0 {
+		msg := empty
+		if styles.Enabled {
+			msg = styles.Render(styles.Muted, msg)
+		}
+		_, err := fmt.Fprintln(commandWriter(cmd), msg)
+		return err
+	}
+	return printRepoStoreEntries(commandWriter(cmd), styles, result.Repos)
+}
+
+func printRepoStoreEntries(w io.Writer, styles output.Styles, entries []worksetapi.RepoStoreEntryJSON) error {
+	withAction := false
+	for _, entry := range entries {
+		if entry.Action != "" {
+			withAction = true
+			break
+		}
+	}
+	header := []string{"REPO", "SIZE", "LAST FETCH", "WORKTREES", "PATH"}
+	if withAction {
+		header = append(header, "RESULT")
+	}
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		size := "-"
+		if entry.Present {
+			size = formatStoreSize(entry.SizeBytes)
+		}
+		lastFetch := entry.LastFetch
+		if lastFetch == "" {
+			lastFetch = "never"
+		}
+		row := []string{entry.Name, size, lastFetch, strconv.Itoa(entry.Worktrees), entry.Path}
+		if withAction {
+			action := entry.Action
+			if entry.Error != "" {
+				action += ": " + entry.Error
+			}
+			row = append(row, action)
+		}
+		rows = append(rows, row)
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, header, rows))
+	return err
+}
+
+func formatStoreSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	suffix := ""
+	for _, next := range suffixes {
+		value /= unit
+		suffix = next
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
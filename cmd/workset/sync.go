@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func syncCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "sync",
+		Usage:     "Rebase or merge thread branches onto their updated base branches (requires -t)",
+		ArgsUsage: "-t <thread> [--rebase|--merge] [--continue|--abort]",
+		Description: "Fetches each repo's remote and integrates <remote>/<default_branch> into the thread branch. " +
+			"Repos with uncommitted changes are skipped. A repo that hits conflicts is left mid-rebase or mid-merge; " +
+			"resolve and stage the files, then run `workset sync --continue`, or `workset sync --abort` to back out.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(true),
+			&cli.BoolFlag{
+				Name:  "rebase",
+				Usage: "Rebase thread branches onto the base branch (default)",
+			},
+			&cli.BoolFlag{
+				Name:  "merge",
+				Usage: "Merge the base branch into thread branches",
+			},
+			&cli.BoolFlag{
+				Name:  "continue",
+				Usage: "Continue rebases or merges stopped on conflicts",
+			},
+			&cli.BoolFlag{
+				Name:  "abort",
+				Usage: "Abort rebases or merges stopped on conflicts",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Bool("rebase") && cmd.Bool("merge") {
+				return usageError(ctx, cmd, "--rebase and --merge are mutually exclusive")
+			}
+			if cmd.Bool("continue") && cmd.Bool("abort") {
+				return usageError(ctx, cmd, "--continue and --abort are mutually exclusive")
+			}
+			mode := "rebase"
+			if cmd.Bool("merge") {
+				mode = "merge"
+			}
+			result, err := apiService(ctx, cmd).SyncWorkspace(ctx, worksetapi.WorkspaceSyncInput{
+				Selector: worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				Mode:     mode,
+				Continue: cmd.Bool("continue"),
+				Abort:    cmd.Bool("abort"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			outputMode := outputModeFromContext(cmd)
+			if outputMode.JSON {
+				if err := output.WriteJSON(commandWriter(cmd), result.Repos); err != nil {
+					return err
+				}
+			} else if err := printSyncEntries(commandWriter(cmd), output.NewStyles(commandWriter(cmd), outputMode.Plain), result.Repos); err != nil {
+				return err
+			}
+			conflicted, failed := 0, 0
+			for _, repo := range result.Repos {
+				switch repo.Status {
+				case "conflicted":
+					conflicted++
+				case "failed":
+					failed++
+				}
+			}
+			switch {
+			case conflicted > 0:
+				return cli.Exit(fmt.Sprintf("sync stopped on conflicts in %d repo(s); resolve and stage them, then run workset sync --continue (or --abort)", conflicted), 1)
+			case failed > 0:
+				return cli.Exit(fmt.Sprintf("sync failed in %d of %d repos", failed, len(result.Repos)), 1)
+			}
+			return nil
+		},
+	}
+}
+
+func printSyncEntries(w io.Writer, styles output.Styles, repos []worksetapi.RepoSyncJSON) error {
+	rows := make([][]string, 0, len(repos))
+	for _, repo := range repos {
+		detail := repo.Error
+		switch {
+		case detail != "":
+		case len(repo.Conflicts) > 0:
+			detail = "conflicts: " + strings.Join(repo.Conflicts, ", ")
+			if repo.Reason != "" {
+				detail = repo.Reason + "; " + detail
+			}
+		case repo.Reason != "":
+			detail = repo.Reason
+		case repo.BaseRef != "":
+			detail = strings.TrimPrefix(repo.BaseRef, "refs/remotes/")
+		}
+		status := repo.Status
+		if repo.Mode != "" && (status == "conflicted" || status == "synced") {
+			status += " (" + repo.Mode + ")"
+		}
+		rows = append(rows, []string{repo.Name, status, detail})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"REPO", "STATUS", "DETAIL"}, rows))
+	return err
+}
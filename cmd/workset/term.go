@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/terminalservice"
+	"github.com/urfave/cli/v3"
+)
+
+func termCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "term",
+		Usage: "Work with the terminal service that hosts thread terminals and processes",
+		Commands: []*cli.Command{
+			termServeCommand(),
+			termGrepCommand(),
+		},
+	}
+}
+
+func termServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run the terminal service in the foreground",
+		Description: "Serves terminal sessions on the same socket the desktop app uses, so `workset up` " +
+			"works without the app. The desktop app replaces a service started by a different build.",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			socketPath, err := terminalservice.DefaultSocketPath()
+			if err != nil {
+				return err
+			}
+			if err := terminalservice.NewClient(socketPath).Ping(ctx); err == nil {
+				return fmt.Errorf("terminal service already running at %s", socketPath)
+			}
+			opts := terminalservice.DefaultOptions()
+			opts.SocketPath = socketPath
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			server := terminalservice.NewServer(opts)
+			server.SetShutdown(stop)
+			_, _ = fmt.Fprintf(commandErrWriter(cmd), "terminal service listening on %s\n", socketPath)
+			return server.Listen(ctx)
+		},
+	}
+}
+
+func termGrepCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "grep",
+		Usage:     "Search terminal output across threads",
+		ArgsUsage: "[-t <thread>] [-i] [-E] [-C <n>] <pattern>",
+		Description: "Searches the output of open terminals and the transcripts of closed ones, with " +
+			"colors and other escape sequences removed. The pattern is plain text unless -E is set.",
+		Flags: appendOutputFlags([]cli.Flag{
+			threadFlag(false),
+			&cli.StringFlag{
+				Name:  "session",
+				Usage: "Only search one terminal session by id",
+			},
+			&cli.BoolFlag{
+				Name:    "ignore-case",
+				Aliases: []string{"i"},
+				Usage:   "Match case-insensitively",
+			},
+			&cli.BoolFlag{
+				Name:    "regexp",
+				Aliases: []string{"E"},
+				Usage:   "Treat the pattern as a regular expression",
+			},
+			&cli.IntFlag{
+				Name:    "context",
+				Aliases: []string{"C"},
+				Usage:   "Lines of context around each match",
+				Value:   2,
+			},
+			&cli.IntFlag{
+				Name:  "max",
+				Usage: "Maximum matches to show",
+				Value: 200,
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() != 1 {
+				return usageError(ctx, cmd, "pattern required (example: workset term grep -i 'connection refused')")
+			}
+			socketPath, err := terminalservice.DefaultSocketPath()
+			if err != nil {
+				return err
+			}
+			result, err := terminalservice.NewClient(socketPath).Search(ctx, terminalservice.SearchRequest{
+				Query:      cmd.Args().First(),
+				Regex:      cmd.Bool("regexp"),
+				IgnoreCase: cmd.Bool("ignore-case"),
+				SessionID:  strings.TrimSpace(cmd.String("session")),
+				Workspace:  strings.TrimSpace(cmd.String("thread")),
+				Context:    cmd.Int("context"),
+				MaxMatches: cmd.Int("max"),
+			})
+			if err != nil {
+				return fmt.Errorf("search terminals: %w (is the desktop app or `workset term serve` running?)", err)
+			}
+			if result.Truncated {
+				_, _ = fmt.Fprintf(commandErrWriter(cmd), "warning: showing the first %d matches\n", len(result.Matches))
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), result.Matches)
+			}
+			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+			if len(result.Matches) == 0 {
+				msg := "no matches"
+				if styles.Enabled {
+					msg = styles.Render(styles.Muted, msg)
+				}
+				_, err := fmt.Fprintln(commandWriter(cmd), msg)
+				return err
+			}
+			return printTermMatches(commandWriter(cmd), styles, result.Matches)
+		},
+	}
+}
+
+func printTermMatches(w io.Writer, styles output.Styles, matches []terminalservice.SearchMatch) error {
+	for i, match := range matches {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		header := match.SessionID
+		if !match.Live {
+			header += " (closed)"
+		}
+		if styles.Enabled {
+			header = styles.Render(styles.Title, header)
+		}
+		detail := fmt.Sprintf("@%d", match.Offset)
+		if parsed, err := time.Parse(time.RFC3339, match.Timestamp); err == nil {
+			detail = parsed.Local().Format("2006-01-02 15:04:05") + "  " + detail
+		}
+		if styles.Enabled {
+			detail = styles.Render(styles.Muted, detail)
+		}
+		if _, err := fmt.Fprintf(w, "%s  %s\n", header, detail); err != nil {
+			return err
+		}
+		for _, line := range match.Before {
+			if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "> %s\n", match.Line); err != nil {
+			return err
+		}
+		for _, line := range match.After {
+			if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+func threadCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "thread",
+		Usage: "Export and import threads",
+		Commands: []*cli.Command{
+			threadExportCommand(),
+			threadImportCommand(),
+		},
+	}
+}
+
+func threadExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Write a thread bundle to stdout (requires -t)",
+		ArgsUsage: "-t <thread>",
+		Description: "The bundle records the thread's workset, repos with their remote URLs, branches, base branches, " +
+			"description, color, and tracked pull requests. Recreate it with `workset thread import`.",
+		Flags: appendOutputFlags([]cli.Flag{threadFlag(true)}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			result, err := apiService(ctx, cmd).ExportThread(ctx, worksetapi.WorkspaceSelector{Value: cmd.String("thread")})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+			}
+			if outputModeFromContext(cmd).JSON {
+				return output.WriteJSON(commandWriter(cmd), result.Bundle)
+			}
+			data, err := yaml.Marshal(result.Bundle)
+			if err != nil {
+				return err
+			}
+			_, err = commandWriter(cmd).Write(data)
+			return err
+		},
+	}
+}
+
+func threadImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Recreate a thread from a bundle",
+		ArgsUsage: "<file|->",
+		Description: "Registers repos the bundle names that are not registered yet, fetches each repo's branch " +
+			"from its remote, and adds the worktrees. Use - to read the bundle from stdin.",
+		Flags: appendOutputFlags([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Thread name (defaults to the bundled name)",
+			},
+			&cli.StringFlag{
+				Name:  "path",
+				Usage: "Thread directory (defaults to <workset_root>/worksets/<workset>/<name>)",
+			},
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			source := strings.TrimSpace(cmd.Args().First())
+			if source == "" || cmd.Args().Len() > 1 {
+				return usageError(ctx, cmd, "bundle file required")
+			}
+			data, err := readThreadBundle(source)
+			if err != nil {
+				return err
+			}
+			bundle, err := worksetapi.ParseThreadBundle(data)
+			if err != nil {
+				return err
+			}
+			result, err := apiService(ctx, cmd).ImportThread(ctx, worksetapi.ThreadImportInput{
+				Bundle: bundle,
+				Name:   cmd.String("name"),
+				Path:   cmd.String("path"),
+			})
+			if err != nil {
+				return err
+			}
+			printConfigInfo(cmd, result)
+			for _, warning := range result.Warnings {
+				_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+			}
+			for _, pending := range result.PendingHooks {
+				_, _ = fmt.Fprintf(
+					commandErrWriter(cmd),
+					"warning: repo %s hooks pending approval; run `workset hooks run -t %s %s` to execute\n",
+					pending.Repo,
+					shellQuoteArg(result.Workspace.Name),
+					shellQuoteArg(pending.Repo),
+				)
+			}
+			info := output.WorkspaceCreated{
+				Name:    result.Workspace.Name,
+				Path:    result.Workspace.Path,
+				Workset: result.Workspace.Workset,
+				Branch:  result.Workspace.Branch,
+				Next:    result.Workspace.Next,
+			}
+			mode := outputModeFromContext(cmd)
+			if mode.JSON {
+				return output.WriteJSON(commandWriter(cmd), struct {
+					output.WorkspaceCreated
+
+					Warnings []string `json:"warnings,omitempty"`
+				}{
+					WorkspaceCreated: info,
+					Warnings:         result.Warnings,
+				})
+			}
+			if err := printWorkspaceCreated(commandWriter(cmd), info, false, mode.Plain); err != nil {
+				return err
+			}
+			return printHookExecutionResults(commandWriter(cmd), output.NewStyles(commandWriter(cmd), mode.Plain), result.HookRuns)
+		},
+	}
+}
+
+func readThreadBundle(source string) ([]byte, error) {
+	if source == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(source)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/strantalis/workset/internal/output"
+	"github.com/strantalis/workset/pkg/worksetapi"
+	"github.com/urfave/cli/v3"
+)
+
+func trashCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "trash",
+		Usage: "List, restore, or purge deleted threads",
+		Description: "`workset rm --delete` moves threads to <workset_root>/trash. Entries are purged " +
+			"after defaults.trash_retention_days.",
+		Commands: []*cli.Command{
+			{
+				Name:    "ls",
+				Aliases: []string{"list"},
+				Usage:   "List deleted threads",
+				Flags:   outputFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					result, err := apiService(ctx, cmd).ListTrash(ctx)
+					if err != nil {
+						return err
+					}
+					printConfigInfo(cmd, result)
+					printTrashWarnings(cmd, result.Warnings)
+					mode := outputModeFromContext(cmd)
+					if mode.JSON {
+						return output.WriteJSON(commandWriter(cmd), result.Entries)
+					}
+					styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+					if len(result.Entries) == 0 {
+						msg := "trash is empty"
+						if styles.Enabled {
+							msg = styles.Render(styles.Muted, msg)
+						}
+						_, err := fmt.Fprintln(commandWriter(cmd), msg)
+						return err
+					}
+					return printTrashEntries(commandWriter(cmd), styles, result.Entries)
+				},
+			},
+			{
+				Name:      "restore",
+				Usage:     "Restore a deleted thread",
+				ArgsUsage: "<id>",
+				Flags: appendOutputFlags([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "path",
+						Usage: "Restore to this directory instead of the original location",
+					},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					if cmd.Args().Len() != 1 {
+						return usageError(ctx, cmd, "trash entry id required")
+					}
+					result, err := apiService(ctx, cmd).RestoreTrash(ctx, worksetapi.TrashRestoreInput{
+						ID:   cmd.Args().First(),
+						Path: cmd.String("path"),
+					})
+					if err != nil {
+						return err
+					}
+					printConfigInfo(cmd, result)
+					printTrashWarnings(cmd, result.Warnings)
+					mode := outputModeFromContext(cmd)
+					if mode.JSON {
+						return output.WriteJSON(commandWriter(cmd), result.Entry)
+					}
+					styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+					msg := fmt.Sprintf("thread %s restored to %s", result.Entry.Name, result.Entry.Path)
+					if styles.Enabled {
+						msg = styles.Render(styles.Success, msg)
+					}
+					_, err = fmt.Fprintln(commandWriter(cmd), msg)
+					return err
+				},
+			},
+			{
+				Name:      "purge",
+				Usage:     "Permanently delete trashed threads (expired ones by default)",
+				ArgsUsage: "[<id>...]",
+				Flags: appendOutputFlags([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Purge every entry, not only expired ones",
+					},
+					&cli.BoolFlag{
+						Name:  "yes",
+						Usage: "Skip confirmation",
+					},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					ids := cmd.Args().Slice()
+					if len(ids) > 0 && cmd.Bool("all") {
+						return usageError(ctx, cmd, "use either entry ids or --all")
+					}
+					if (len(ids) > 0 || cmd.Bool("all")) && !cmd.Bool("yes") {
+						ok, err := confirmPrompt(os.Stdin, commandWriter(cmd), "permanently delete trashed threads? [y/N] ")
+						if err != nil {
+							return err
+						}
+						if !ok {
+							return cli.Exit("aborted", 1)
+						}
+					}
+					result, err := apiService(ctx, cmd).PurgeTrash(ctx, worksetapi.TrashPurgeInput{
+						IDs: ids,
+						All: cmd.Bool("all"),
+					})
+					if err != nil {
+						return err
+					}
+					printConfigInfo(cmd, result)
+					printTrashWarnings(cmd, result.Warnings)
+					mode := outputModeFromContext(cmd)
+					if mode.JSON {
+						return output.WriteJSON(commandWriter(cmd), result.Purged)
+					}
+					styles := output.NewStyles(commandWriter(cmd), mode.Plain)
+					msg := fmt.Sprintf("purged %d trashed thread(s)", len(result.Purged))
+					if styles.Enabled {
+						msg = styles.Render(styles.Success, msg)
+					}
+					if _, err := fmt.Fprintln(commandWriter(cmd), msg); err != nil {
+						return err
+					}
+					if len(result.Warnings) > 0 {
+						return errors.New("some trash entries could not be purged")
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func printTrashWarnings(cmd *cli.Command, warnings []string) {
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintln(commandErrWriter(cmd), "warning:", warning)
+	}
+}
+
+func printTrashEntries(w io.Writer, styles output.Styles, entries []worksetapi.TrashEntryJSON) error {
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, []string{entry.ID, entry.Name, entry.Path, entry.DeletedAt, entry.ExpiresAt})
+	}
+	_, err := fmt.Fprint(w, output.RenderTable(styles, []string{"ID", "THREAD", "PATH", "DELETED", "EXPIRES"}, rows))
+	return err
+}
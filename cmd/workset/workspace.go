@@ -31,6 +31,7 @@ func newCommand() *cli.Command {
 			},
 		},
 	}
+	flags = append(flags, threadRepoStartFlags()...)
 	flags = append(flags, outputFlags()...)
 	return &cli.Command{
 		Name:      "new",
@@ -42,12 +43,18 @@ func newCommand() *cli.Command {
 			if name == "" {
 				return usageError(ctx, cmd, "thread name required")
 			}
+			start, repoStarts, err := threadRepoStartsFromFlags(cmd)
+			if err != nil {
+				return usageError(ctx, cmd, err.Error())
+			}
 			svc := apiService(ctx, cmd)
 			result, err := svc.CreateWorkspace(ctx, worksetapi.WorkspaceCreateInput{
-				Name:    name,
-				Path:    cmd.String("path"),
-				Workset: cmd.String("workset"),
-				Repos:   cmd.StringSlice("repo"),
+				Name:       name,
+				Path:       cmd.String("path"),
+				Workset:    cmd.String("workset"),
+				Repos:      cmd.StringSlice("repo"),
+				Start:      start,
+				RepoStarts: repoStarts,
 			})
 			if err != nil {
 				return err
@@ -209,12 +216,16 @@ func listCommand() *cli.Command {
 func removeWorkspaceCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "rm",
-		Usage: "Remove a thread (use --delete to remove files on disk)",
+		Usage: "Remove a thread (use --delete to move its files to the trash)",
 		Flags: appendOutputFlags([]cli.Flag{
 			threadFlag(false),
 			&cli.BoolFlag{
 				Name:  "delete",
-				Usage: "Delete the thread directory",
+				Usage: "Move the thread directory to the trash",
+			},
+			&cli.BoolFlag{
+				Name:  "purge",
+				Usage: "With --delete, delete the thread directory permanently instead",
 			},
 			&cli.BoolFlag{
 				Name:  "force",
@@ -243,6 +254,7 @@ func removeWorkspaceCommand() *cli.Command {
 				Force:        cmd.Bool("force"),
 				Confirmed:    cmd.Bool("yes"),
 				FetchRemotes: true,
+				Purge:        cmd.Bool("purge"),
 			}
 			result, err := svc.DeleteWorkspace(ctx, input)
 			if err != nil {
@@ -284,13 +296,24 @@ func removeWorkspaceCommand() *cli.Command {
 			styles := output.NewStyles(commandWriter(cmd), mode.Plain)
 			if deleteRequested {
 				msg := fmt.Sprintf("thread %s deleted", result.Payload.Path)
+				if result.Payload.TrashID != "" {
+					msg = fmt.Sprintf("thread %s moved to trash", result.Payload.Path)
+				}
 				if styles.Enabled {
 					msg = styles.Render(styles.Success, msg)
 				}
 				if _, err := fmt.Fprintln(commandWriter(cmd), msg); err != nil {
 					return err
 				}
-				return nil
+				if result.Payload.TrashID == "" {
+					return nil
+				}
+				note := "note: to undo, run: workset trash restore " + shellQuoteArg(result.Payload.TrashID)
+				if styles.Enabled {
+					note = styles.Render(styles.Muted, note)
+				}
+				_, err := fmt.Fprintln(commandWriter(cmd), note)
+				return err
 			}
 			msg := "removed thread registration for " + result.Payload.Path
 			if styles.Enabled {
@@ -314,14 +337,21 @@ func removeWorkspaceCommand() *cli.Command {
 func statusCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "status",
-		Usage:     "Show status for repos in a thread (requires -t)",
-		ArgsUsage: "-t <thread>",
+		Usage:     "Show branch, sync, and PR status for repos in a thread (requires -t)",
+		ArgsUsage: "-t <thread> [--fetch]",
 		Flags: appendOutputFlags([]cli.Flag{
 			threadFlag(true),
+			&cli.BoolFlag{
+				Name:  "fetch",
+				Usage: "Fetch each repo's remote before reporting status",
+			},
 		}),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			svc := apiService(ctx, cmd)
-			result, err := svc.StatusWorkspace(ctx, worksetapi.WorkspaceSelector{Value: cmd.String("thread")})
+			result, err := svc.StatusWorkspace(ctx, worksetapi.WorkspaceStatusInput{
+				Selector:     worksetapi.WorkspaceSelector{Value: cmd.String("thread")},
+				FetchRemotes: cmd.Bool("fetch"),
+			})
 			if err != nil {
 				return err
 			}
@@ -347,15 +377,7 @@ func statusCommand() *cli.Command {
 			} else {
 				rows := make([]output.StatusRow, 0, len(result.Statuses))
 				for _, repo := range result.Statuses {
-					detail := repo.Path
-					if repo.Error != "" {
-						detail = repo.Error
-					}
-					rows = append(rows, output.StatusRow{
-						Name:   repo.Name,
-						State:  repo.State,
-						Detail: detail,
-					})
+					rows = append(rows, repoStatusRow(repo))
 				}
 				styles := output.NewStyles(commandWriter(cmd), mode.Plain)
 				if err := output.PrintStatus(commandWriter(cmd), styles, rows); err != nil {
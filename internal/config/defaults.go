@@ -23,6 +23,17 @@ func DefaultConfig() GlobalConfig {
 			TerminalFontSize:     "13",
 			TerminalCursorBlink:  "on",
 			TerminalKeybindings:  map[string][]string{},
+			ProvisionParallelism: 4,
+			TrashRetentionDays:   14,
+			AutoArchive: AutoArchiveConfig{
+				Enabled:   false,
+				Merged:    true,
+				StaleDays: 30,
+			},
+			RepoStore: RepoStoreConfig{
+				PrefetchInterval: "0",
+				FetchMaxAge:      "0",
+			},
 		},
 		GitHub: GitHubConfig{
 			CLIPath: "",
@@ -31,8 +42,9 @@ func DefaultConfig() GlobalConfig {
 			CLIPath: "",
 		},
 		Hooks: HooksConfig{
-			Enabled: true,
-			OnError: "fail",
+			Enabled:     true,
+			OnError:     "fail",
+			MaxParallel: 4,
 			RepoHooks: RepoHooksConfig{
 				TrustedRepos: []string{},
 			},
@@ -54,6 +66,9 @@ func (cfg *GlobalConfig) EnsureMaps() {
 	if cfg.WorksetRepos == nil {
 		cfg.WorksetRepos = map[string][]string{}
 	}
+	if cfg.WorksetTemplates == nil {
+		cfg.WorksetTemplates = map[string]WorksetTemplate{}
+	}
 	if cfg.Hooks.RepoHooks.TrustedRepos == nil {
 		cfg.Hooks.RepoHooks.TrustedRepos = []string{}
 	}
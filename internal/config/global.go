@@ -40,8 +40,9 @@ type GlobalConfigLoadInfo struct {
 }
 
 type serializedWorksetGroup struct {
-	Repos   []string                `yaml:"repos,omitempty" json:"repos,omitempty"`
-	Threads map[string]WorkspaceRef `yaml:"threads,omitempty" json:"threads,omitempty"`
+	Repos    []string                `yaml:"repos,omitempty" json:"repos,omitempty"`
+	Template *WorksetTemplate        `yaml:"template,omitempty" json:"template,omitempty"`
+	Threads  map[string]WorkspaceRef `yaml:"threads,omitempty" json:"threads,omitempty"`
 }
 
 type serializedGlobalConfig struct {
@@ -50,6 +51,7 @@ type serializedGlobalConfig struct {
 	GitHub        GitHubConfig                      `yaml:"github,omitempty" json:"github,omitempty"`
 	Agent         AgentConfig                       `yaml:"agent,omitempty" json:"agent,omitempty"`
 	Hooks         HooksConfig                       `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	Hosts         map[string]HostConfig             `yaml:"hosts,omitempty" json:"hosts,omitempty"`
 	Repos         map[string]RegisteredRepo         `yaml:"repos" json:"repos"`
 	Worksets      map[string]serializedWorksetGroup `yaml:"worksets,omitempty" json:"worksets,omitempty"`
 }
@@ -120,7 +122,17 @@ func loadGlobal(path string) (GlobalConfig, GlobalConfigLoadInfo, error) {
 		if hasNestedWorksets {
 			cfg.Workspaces = nestedWorkspaces
 			cfg.WorksetRepos = nestedWorksetRepos
+			templates, err := parseWorksetTemplates(rawData)
+			if err != nil {
+				return GlobalConfig{}, info, err
+			}
+			cfg.WorksetTemplates = templates
+		}
+		hosts, err := parseHosts(rawData)
+		if err != nil {
+			return GlobalConfig{}, info, err
 		}
+		cfg.Hosts = hosts
 	}
 	cfg.ConfigVersion = normalizeConfigVersion(info.ConfigVersion)
 	if cfg.ConfigVersion > CurrentGlobalConfigVersion {
@@ -177,7 +189,17 @@ func loadGlobalFromBytes(data []byte) (GlobalConfig, error) {
 		if hasNestedWorksets {
 			cfg.Workspaces = nestedWorkspaces
 			cfg.WorksetRepos = nestedWorksetRepos
+			templates, err := parseWorksetTemplates(data)
+			if err != nil {
+				return GlobalConfig{}, err
+			}
+			cfg.WorksetTemplates = templates
 		}
+		hosts, err := parseHosts(data)
+		if err != nil {
+			return GlobalConfig{}, err
+		}
+		cfg.Hosts = hosts
 	}
 	cfg.ConfigVersion = version
 	finalizeGlobal(&cfg, defaults)
@@ -257,12 +279,22 @@ func toSerializedGlobalConfig(cfg GlobalConfig) serializedGlobalConfig {
 		group.Repos = normalizeRepoList(repos)
 		worksets[normalizedWorksetName] = group
 	}
+	for worksetName, template := range cfg.WorksetTemplates {
+		normalizedWorksetName := strings.TrimSpace(worksetName)
+		if normalizedWorksetName == "" {
+			continue
+		}
+		group := worksets[normalizedWorksetName]
+		group.Template = &template
+		worksets[normalizedWorksetName] = group
+	}
 	return serializedGlobalConfig{
 		ConfigVersion: cfg.ConfigVersion,
 		Defaults:      cfg.Defaults,
 		GitHub:        cfg.GitHub,
 		Agent:         cfg.Agent,
 		Hooks:         cfg.Hooks,
+		Hosts:         cfg.Hosts,
 		Repos:         cfg.Repos,
 		Worksets:      worksets,
 	}
@@ -309,6 +341,10 @@ func parseNestedWorksets(raw []byte) (map[string]WorkspaceRef, map[string][]stri
 			hasNested = true
 			break
 		}
+		if _, ok := groupMap["template"]; ok {
+			hasNested = true
+			break
+		}
 	}
 	if !hasNested {
 		return nil, nil, false, nil
@@ -341,6 +377,51 @@ func parseNestedWorksets(raw []byte) (map[string]WorkspaceRef, map[string][]stri
 	return flattened, worksetRepos, true, nil
 }
 
+// parseWorksetTemplates reads worksets.<name>.template directly from YAML
+// because template file paths and hook ids may contain dots.
+func parseWorksetTemplates(raw []byte) (map[string]WorksetTemplate, error) {
+	var serialized struct {
+		Worksets map[string]struct {
+			Template *WorksetTemplate `yaml:"template"`
+		} `yaml:"worksets"`
+	}
+	if err := yaml.Unmarshal(raw, &serialized); err != nil {
+		return nil, err
+	}
+	templates := map[string]WorksetTemplate{}
+	for worksetName, group := range serialized.Worksets {
+		normalizedWorksetName := strings.TrimSpace(worksetName)
+		if normalizedWorksetName == "" || group.Template == nil {
+			continue
+		}
+		templates[normalizedWorksetName] = *group.Template
+	}
+	return templates, nil
+}
+
+// parseHosts reads the hosts section directly from YAML because host names
+// contain dots, which koanf would otherwise split into nested keys.
+func parseHosts(raw []byte) (map[string]HostConfig, error) {
+	var serialized struct {
+		Hosts map[string]HostConfig `yaml:"hosts"`
+	}
+	if err := yaml.Unmarshal(raw, &serialized); err != nil {
+		return nil, err
+	}
+	hosts := map[string]HostConfig{}
+	for host, hostCfg := range serialized.Hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		hostCfg.Provider = strings.ToLower(strings.TrimSpace(hostCfg.Provider))
+		hostCfg.APIURL = strings.TrimRight(strings.TrimSpace(hostCfg.APIURL), "/")
+		hostCfg.TokenEnv = strings.TrimSpace(hostCfg.TokenEnv)
+		hosts[host] = hostCfg
+	}
+	return hosts, nil
+}
+
 func normalizeRepoList(repos []string) []string {
 	if len(repos) == 0 {
 		return nil
@@ -386,24 +467,32 @@ func asStringAnyMap(value any) (map[string]any, bool) {
 
 func defaultConfigMap(defaults GlobalConfig) map[string]any {
 	return map[string]any{
-		"defaults.remote":                 defaults.Defaults.Remote,
-		"defaults.base_branch":            defaults.Defaults.BaseBranch,
-		"defaults.thread":                 defaults.Defaults.Thread,
-		"defaults.workset_root":           defaults.Defaults.WorksetRoot,
-		"defaults.repo_store_root":        defaults.Defaults.RepoStoreRoot,
-		"defaults.agent":                  defaults.Defaults.Agent,
-		"defaults.agent_model":            defaults.Defaults.AgentModel,
-		"defaults.terminal_idle_timeout":  defaults.Defaults.TerminalIdleTimeout,
-		"defaults.terminal_debug_log":     defaults.Defaults.TerminalDebugLog,
-		"defaults.terminal_protocol_log":  defaults.Defaults.TerminalProtocolLog,
-		"defaults.terminal_debug_overlay": defaults.Defaults.TerminalDebugOverlay,
-		"defaults.terminal_font_size":     defaults.Defaults.TerminalFontSize,
-		"defaults.terminal_cursor_blink":  defaults.Defaults.TerminalCursorBlink,
-		"defaults.terminal_keybindings":   defaults.Defaults.TerminalKeybindings,
-		"github.cli_path":                 defaults.GitHub.CLIPath,
-		"hooks.enabled":                   defaults.Hooks.Enabled,
-		"hooks.on_error":                  defaults.Hooks.OnError,
-		"hooks.repo_hooks.trusted_repos":  defaults.Hooks.RepoHooks.TrustedRepos,
+		"defaults.remote":                       defaults.Defaults.Remote,
+		"defaults.base_branch":                  defaults.Defaults.BaseBranch,
+		"defaults.thread":                       defaults.Defaults.Thread,
+		"defaults.workset_root":                 defaults.Defaults.WorksetRoot,
+		"defaults.repo_store_root":              defaults.Defaults.RepoStoreRoot,
+		"defaults.agent":                        defaults.Defaults.Agent,
+		"defaults.agent_model":                  defaults.Defaults.AgentModel,
+		"defaults.terminal_idle_timeout":        defaults.Defaults.TerminalIdleTimeout,
+		"defaults.terminal_debug_log":           defaults.Defaults.TerminalDebugLog,
+		"defaults.terminal_protocol_log":        defaults.Defaults.TerminalProtocolLog,
+		"defaults.terminal_debug_overlay":       defaults.Defaults.TerminalDebugOverlay,
+		"defaults.terminal_font_size":           defaults.Defaults.TerminalFontSize,
+		"defaults.terminal_cursor_blink":        defaults.Defaults.TerminalCursorBlink,
+		"defaults.terminal_keybindings":         defaults.Defaults.TerminalKeybindings,
+		"defaults.provision_parallelism":        defaults.Defaults.ProvisionParallelism,
+		"defaults.trash_retention_days":         defaults.Defaults.TrashRetentionDays,
+		"defaults.auto_archive.enabled":         defaults.Defaults.AutoArchive.Enabled,
+		"defaults.auto_archive.merged":          defaults.Defaults.AutoArchive.Merged,
+		"defaults.auto_archive.stale_days":      defaults.Defaults.AutoArchive.StaleDays,
+		"defaults.repo_store.prefetch_interval": defaults.Defaults.RepoStore.PrefetchInterval,
+		"defaults.repo_store.fetch_max_age":     defaults.Defaults.RepoStore.FetchMaxAge,
+		"github.cli_path":                       defaults.GitHub.CLIPath,
+		"hooks.enabled":                         defaults.Hooks.Enabled,
+		"hooks.on_error":                        defaults.Hooks.OnError,
+		"hooks.max_parallel":                    defaults.Hooks.MaxParallel,
+		"hooks.repo_hooks.trusted_repos":        defaults.Hooks.RepoHooks.TrustedRepos,
 	}
 }
 
@@ -465,9 +554,24 @@ func finalizeGlobal(cfg *GlobalConfig, defaults GlobalConfig) {
 	if cfg.Defaults.TerminalKeybindings == nil {
 		cfg.Defaults.TerminalKeybindings = defaults.Defaults.TerminalKeybindings
 	}
+	if cfg.Defaults.ProvisionParallelism <= 0 {
+		cfg.Defaults.ProvisionParallelism = defaults.Defaults.ProvisionParallelism
+	}
+	if cfg.Defaults.TrashRetentionDays <= 0 {
+		cfg.Defaults.TrashRetentionDays = defaults.Defaults.TrashRetentionDays
+	}
+	if cfg.Defaults.RepoStore.PrefetchInterval == "" {
+		cfg.Defaults.RepoStore.PrefetchInterval = defaults.Defaults.RepoStore.PrefetchInterval
+	}
+	if cfg.Defaults.RepoStore.FetchMaxAge == "" {
+		cfg.Defaults.RepoStore.FetchMaxAge = defaults.Defaults.RepoStore.FetchMaxAge
+	}
 	if cfg.Hooks.OnError == "" {
 		cfg.Hooks.OnError = defaults.Hooks.OnError
 	}
+	if cfg.Hooks.MaxParallel <= 0 {
+		cfg.Hooks.MaxParallel = defaults.Hooks.MaxParallel
+	}
 	if cfg.Hooks.RepoHooks.TrustedRepos == nil {
 		cfg.Hooks.RepoHooks.TrustedRepos = defaults.Hooks.RepoHooks.TrustedRepos
 	}
@@ -340,3 +340,67 @@ func TestSaveLoadGlobalPersistsWorksetReposAndRepoOverrides(t *testing.T) {
 		t.Fatalf("expected normalized repo_overrides [extra-repo], got %#v", got)
 	}
 }
+
+func TestSaveLoadGlobalPersistsHostsWithDottedNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := DefaultConfig()
+	cfg.Hosts = map[string]HostConfig{
+		"gitlab.example.com": {Provider: "gitlab", APIURL: "https://gitlab.example.com/api/v4", TokenEnv: "CORP_GITLAB_TOKEN"},
+	}
+
+	if err := SaveGlobal(path, cfg); err != nil {
+		t.Fatalf("SaveGlobal: %v", err)
+	}
+	loaded, err := LoadGlobal(path)
+	if err != nil {
+		t.Fatalf("LoadGlobal: %v", err)
+	}
+	if len(loaded.Hosts) != 1 {
+		t.Fatalf("expected one host, got %#v", loaded.Hosts)
+	}
+	if got := loaded.Hosts["gitlab.example.com"]; got != cfg.Hosts["gitlab.example.com"] {
+		t.Fatalf("unexpected host config: %#v", got)
+	}
+}
+
+func TestSaveLoadGlobalPersistsWorksetTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := DefaultConfig()
+	cfg.WorksetRepos = map[string][]string{"platform": {"api"}}
+	cfg.WorksetTemplates = map[string]WorksetTemplate{
+		"platform": {
+			Description: "Platform work",
+			Color:       "#3366ff",
+			Agent:       "claude",
+			Files: []TemplateFile{
+				{Path: ".envrc", Content: "export THREAD={workspace.name}\n", Render: true},
+				{Path: "AGENTS.md", Source: "templates/platform.md", Append: true},
+			},
+			Hooks: []HookSpec{{ID: "deps.install", On: []string{"worktree.created"}, Run: []string{"make", "deps"}}},
+		},
+	}
+
+	if err := SaveGlobal(path, cfg); err != nil {
+		t.Fatalf("SaveGlobal: %v", err)
+	}
+	loaded, err := LoadGlobal(path)
+	if err != nil {
+		t.Fatalf("LoadGlobal: %v", err)
+	}
+	got, ok := loaded.WorksetTemplates["platform"]
+	if !ok {
+		t.Fatalf("expected platform template, got %#v", loaded.WorksetTemplates)
+	}
+	if got.Description != "Platform work" || got.Color != "#3366ff" || got.Agent != "claude" {
+		t.Fatalf("unexpected template: %#v", got)
+	}
+	if len(got.Files) != 2 || got.Files[0].Path != ".envrc" || !got.Files[0].Render || !got.Files[1].Append {
+		t.Fatalf("unexpected template files: %#v", got.Files)
+	}
+	if len(got.Hooks) != 1 || got.Hooks[0].ID != "deps.install" {
+		t.Fatalf("unexpected template hooks: %#v", got.Hooks)
+	}
+	if repos := loaded.WorksetRepos["platform"]; len(repos) != 1 || repos[0] != "api" {
+		t.Fatalf("unexpected workset repos: %#v", repos)
+	}
+}
@@ -15,12 +15,55 @@ type Defaults struct {
 	TerminalFontSize     string              `yaml:"terminal_font_size" json:"terminal_font_size" mapstructure:"terminal_font_size"`
 	TerminalCursorBlink  string              `yaml:"terminal_cursor_blink" json:"terminal_cursor_blink" mapstructure:"terminal_cursor_blink"`
 	TerminalKeybindings  map[string][]string `yaml:"terminal_keybindings" json:"terminal_keybindings" mapstructure:"terminal_keybindings"`
+	// ProvisionParallelism bounds how many repos are cloned and checked out
+	// at once when a thread is created.
+	ProvisionParallelism int `yaml:"provision_parallelism" json:"provision_parallelism" mapstructure:"provision_parallelism"`
+	// TrashRetentionDays is how long deleted threads stay in the trash before
+	// they are purged.
+	TrashRetentionDays int `yaml:"trash_retention_days" json:"trash_retention_days" mapstructure:"trash_retention_days"`
+	// AutoArchive is the policy `workset gc` and the desktop app use to
+	// archive finished threads.
+	AutoArchive AutoArchiveConfig `yaml:"auto_archive" json:"auto_archive" mapstructure:"auto_archive"`
+	// RepoStore controls how the bare mirrors under RepoStoreRoot are kept
+	// up to date.
+	RepoStore RepoStoreConfig `yaml:"repo_store" json:"repo_store" mapstructure:"repo_store"`
+}
+
+// RepoStoreConfig schedules prefetching of the repo store. Durations use Go
+// syntax ("15m", "1h"); "0" turns a setting off.
+type RepoStoreConfig struct {
+	// PrefetchInterval is how often the desktop app (and `workset store
+	// fetch --interval`) fetches every registered repo in the background.
+	PrefetchInterval string `yaml:"prefetch_interval" json:"prefetch_interval" mapstructure:"prefetch_interval"`
+	// FetchMaxAge lets thread creation skip the network when a mirror was
+	// fetched more recently than this.
+	FetchMaxAge string `yaml:"fetch_max_age" json:"fetch_max_age" mapstructure:"fetch_max_age"`
+}
+
+// AutoArchiveConfig selects which threads are archived automatically.
+type AutoArchiveConfig struct {
+	// Enabled applies the policy whenever the desktop app starts.
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// Merged archives threads whose tracked pull requests are all merged or
+	// closed.
+	Merged bool `yaml:"merged" json:"merged" mapstructure:"merged"`
+	// StaleDays archives threads unused for this many days that have no
+	// dirty or unpushed repos. Zero turns the rule off.
+	StaleDays int `yaml:"stale_days" json:"stale_days" mapstructure:"stale_days"`
 }
 
 type GitHubConfig struct {
 	CLIPath string `yaml:"cli_path,omitempty" json:"cli_path,omitempty" mapstructure:"cli_path"`
 }
 
+// HostConfig selects the code hosting provider for a git remote host, keyed by
+// host name under hosts. Hosts without an entry use GitHub.
+type HostConfig struct {
+	Provider string `yaml:"provider,omitempty" json:"provider,omitempty" mapstructure:"provider"`
+	APIURL   string `yaml:"api_url,omitempty" json:"api_url,omitempty" mapstructure:"api_url"`
+	TokenEnv string `yaml:"token_env,omitempty" json:"token_env,omitempty" mapstructure:"token_env"`
+}
+
 type AgentConfig struct {
 	CLIPath string `yaml:"cli_path,omitempty" json:"cli_path,omitempty" mapstructure:"cli_path"`
 }
@@ -53,14 +96,72 @@ type GlobalConfig struct {
 	GitHub        GitHubConfig              `yaml:"github,omitempty" json:"github,omitempty" mapstructure:"github"`
 	Agent         AgentConfig               `yaml:"agent,omitempty" json:"agent,omitempty" mapstructure:"agent"`
 	Hooks         HooksConfig               `yaml:"hooks,omitempty" json:"hooks,omitempty" mapstructure:"hooks"`
+	Hosts         map[string]HostConfig     `yaml:"hosts,omitempty" json:"hosts,omitempty" mapstructure:"hosts"`
 	Repos         map[string]RegisteredRepo `yaml:"repos" json:"repos" mapstructure:"repos"`
 	Workspaces    map[string]WorkspaceRef   `yaml:"worksets" json:"worksets" mapstructure:"worksets"`
 	WorksetRepos  map[string][]string       `yaml:"-" json:"-" mapstructure:"-"`
+	// WorksetTemplates holds the template of each workset, keyed by workset
+	// name. It is persisted under worksets.<name>.template.
+	WorksetTemplates map[string]WorksetTemplate `yaml:"-" json:"-" mapstructure:"-"`
+}
+
+// WorksetTemplate seeds every new thread created in a workset.
+type WorksetTemplate struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty" mapstructure:"description"`
+	Color       string `yaml:"color,omitempty" json:"color,omitempty" mapstructure:"color"`
+	// Agent and AgentModel override defaults.agent and defaults.agent_model
+	// for the workset's threads.
+	Agent      string `yaml:"agent,omitempty" json:"agent,omitempty" mapstructure:"agent"`
+	AgentModel string `yaml:"agent_model,omitempty" json:"agent_model,omitempty" mapstructure:"agent_model"`
+	// Files are written into the thread root when the thread is created.
+	Files []TemplateFile `yaml:"files,omitempty" json:"files,omitempty" mapstructure:"files"`
+	// Hooks run for every repo in the workset's threads alongside the repo's
+	// own hooks. They come from the user's config, so they need no trust.
+	Hooks []HookSpec `yaml:"hooks,omitempty" json:"hooks,omitempty" mapstructure:"hooks"`
+}
+
+// TemplateFile is one file a workset template writes into a new thread root.
+// At most one of Source and Content is set.
+type TemplateFile struct {
+	// Path is relative to the thread root.
+	Path string `yaml:"path" json:"path" mapstructure:"path"`
+	// Source is a file to copy; relative paths resolve against the directory
+	// of the global config file.
+	Source  string `yaml:"source,omitempty" json:"source,omitempty" mapstructure:"source"`
+	Content string `yaml:"content,omitempty" json:"content,omitempty" mapstructure:"content"`
+	// Render replaces hook tokens such as {workspace.name} and {branch}.
+	Render bool `yaml:"render,omitempty" json:"render,omitempty" mapstructure:"render"`
+	// Append adds to an existing file (such as AGENTS.md) instead of
+	// replacing it.
+	Append bool `yaml:"append,omitempty" json:"append,omitempty" mapstructure:"append"`
 }
 
 type WorkspaceConfig struct {
 	Name  string       `yaml:"name" json:"name" mapstructure:"name"`
 	Repos []RepoConfig `yaml:"repos" json:"repos" mapstructure:"repos"`
+	// Processes are the thread's long-running processes, started by
+	// `workset up` as terminal-service sessions.
+	Processes []ProcessConfig `yaml:"processes,omitempty" json:"processes,omitempty" mapstructure:"processes"`
+}
+
+// ProcessConfig is one supervised process of a thread.
+type ProcessConfig struct {
+	Name string `yaml:"name" json:"name" mapstructure:"name"`
+	// Command is run by /bin/sh -c.
+	Command string `yaml:"command" json:"command" mapstructure:"command"`
+	// Repo names the repo worktree Cwd is relative to. Without it Cwd is
+	// relative to the thread root.
+	Repo string            `yaml:"repo,omitempty" json:"repo,omitempty" mapstructure:"repo"`
+	Cwd  string            `yaml:"cwd,omitempty" json:"cwd,omitempty" mapstructure:"cwd"`
+	Env  map[string]string `yaml:"env,omitempty" json:"env,omitempty" mapstructure:"env"`
+	// DependsOn names processes that must be ready before this one starts.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty" mapstructure:"depends_on"`
+	// Ready is a regular expression matched against the process output; the
+	// process counts as ready once it matches. Without it a process is ready
+	// as soon as it starts.
+	Ready string `yaml:"ready,omitempty" json:"ready,omitempty" mapstructure:"ready"`
+	// Restart is never, on-failure, or always; it defaults to on-failure.
+	Restart string `yaml:"restart,omitempty" json:"restart,omitempty" mapstructure:"restart"`
 }
 
 type RepoConfig struct {
@@ -68,13 +169,27 @@ type RepoConfig struct {
 	LocalPath string `yaml:"local_path" json:"local_path" mapstructure:"local_path"`
 	Managed   bool   `yaml:"managed,omitempty" json:"managed,omitempty" mapstructure:"managed"`
 	RepoDir   string `yaml:"repo_dir" json:"repo_dir" mapstructure:"repo_dir"`
+	// BaseBranch overrides the alias default branch as the base this repo's
+	// status, diffs, sync, and pull requests compare against.
+	BaseBranch string `yaml:"base_branch,omitempty" json:"base_branch,omitempty" mapstructure:"base_branch"`
+	// Branch is the branch checked out in the worktree when it differs from
+	// the thread branch, such as a pull request head.
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty" mapstructure:"branch"`
+	// Adopted is RepoAdoptedInPlace when the worktree is a link to a
+	// checkout adopted where it was; removing the thread only unlinks it.
+	Adopted string `yaml:"adopted,omitempty" json:"adopted,omitempty" mapstructure:"adopted"`
 }
 
+// RepoAdoptedInPlace marks a repo whose checkout the thread links to
+// rather than owns.
+const RepoAdoptedInPlace = "in_place"
+
 type HooksConfig struct {
-	Enabled   bool            `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
-	OnError   string          `yaml:"on_error,omitempty" json:"on_error,omitempty" mapstructure:"on_error"`
-	RepoHooks RepoHooksConfig `yaml:"repo_hooks,omitempty" json:"repo_hooks,omitempty" mapstructure:"repo_hooks"`
-	Items     []HookSpec      `yaml:"items,omitempty" json:"items,omitempty" mapstructure:"items"`
+	Enabled     bool            `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	OnError     string          `yaml:"on_error,omitempty" json:"on_error,omitempty" mapstructure:"on_error"`
+	MaxParallel int             `yaml:"max_parallel,omitempty" json:"max_parallel,omitempty" mapstructure:"max_parallel"`
+	RepoHooks   RepoHooksConfig `yaml:"repo_hooks,omitempty" json:"repo_hooks,omitempty" mapstructure:"repo_hooks"`
+	Items       []HookSpec      `yaml:"items,omitempty" json:"items,omitempty" mapstructure:"items"`
 }
 
 type RepoHooksConfig struct {
@@ -82,10 +197,22 @@ type RepoHooksConfig struct {
 }
 
 type HookSpec struct {
-	ID      string            `yaml:"id" json:"id" mapstructure:"id"`
-	On      []string          `yaml:"on" json:"on" mapstructure:"on"`
-	Run     []string          `yaml:"run" json:"run" mapstructure:"run"`
-	Cwd     string            `yaml:"cwd,omitempty" json:"cwd,omitempty" mapstructure:"cwd"`
-	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty" mapstructure:"env"`
-	OnError string            `yaml:"on_error,omitempty" json:"on_error,omitempty" mapstructure:"on_error"`
+	ID           string            `yaml:"id" json:"id" mapstructure:"id"`
+	On           []string          `yaml:"on" json:"on" mapstructure:"on"`
+	Run          []string          `yaml:"run" json:"run" mapstructure:"run"`
+	Cwd          string            `yaml:"cwd,omitempty" json:"cwd,omitempty" mapstructure:"cwd"`
+	Env          map[string]string `yaml:"env,omitempty" json:"env,omitempty" mapstructure:"env"`
+	OnError      string            `yaml:"on_error,omitempty" json:"on_error,omitempty" mapstructure:"on_error"`
+	DependsOn    []string          `yaml:"depends_on,omitempty" json:"depends_on,omitempty" mapstructure:"depends_on"`
+	Parallel     string            `yaml:"parallel,omitempty" json:"parallel,omitempty" mapstructure:"parallel"`
+	Timeout      string            `yaml:"timeout,omitempty" json:"timeout,omitempty" mapstructure:"timeout"`
+	Retries      int               `yaml:"retries,omitempty" json:"retries,omitempty" mapstructure:"retries"`
+	RetryBackoff string            `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty" mapstructure:"retry_backoff"`
+	When         *HookWhenSpec     `yaml:"when,omitempty" json:"when,omitempty" mapstructure:"when"`
+}
+
+type HookWhenSpec struct {
+	FileExists []string `yaml:"file_exists,omitempty" json:"file_exists,omitempty" mapstructure:"file_exists"`
+	Branch     []string `yaml:"branch,omitempty" json:"branch,omitempty" mapstructure:"branch"`
+	Repos      []string `yaml:"repos,omitempty" json:"repos,omitempty" mapstructure:"repos"`
 }
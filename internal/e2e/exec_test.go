@@ -0,0 +1,34 @@
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecEachRepoJSONSummary(t *testing.T) {
+	runner := newRunner(t)
+	first := setupRepo(t, filepath.Join(runner.root, "src", "exec-a"))
+	second := setupRepo(t, filepath.Join(runner.root, "src", "exec-b"))
+	if _, err := runner.run("new", "demo", "--repo", first, "--repo", second); err != nil {
+		t.Fatalf("workset new: %v", err)
+	}
+	out, err := runner.run("exec", "-t", "demo", "--each-repo", "--json", "--", "sh", "-c", "echo $WORKSET_REPO")
+	if err != nil {
+		t.Fatalf("exec --each-repo: %v", err)
+	}
+	if !strings.Contains(out, "[exec-a] exec-a") || !strings.Contains(out, "[exec-b] exec-b") {
+		t.Fatalf("exec output missing repo prefixes: %s", out)
+	}
+	if !strings.Contains(out, "\"succeeded\": 2") {
+		t.Fatalf("exec json missing summary: %s", out)
+	}
+
+	_, err = runner.run("exec", "-t", "demo", "--each-repo", "--", "sh", "-c", "test \"$WORKSET_REPO\" = exec-a")
+	if err == nil {
+		t.Fatalf("expected exec failure when a repo command fails")
+	}
+	if !strings.Contains(err.Error(), "exec failed in 1 of 2 repos") {
+		t.Fatalf("unexpected exec error: %v", err)
+	}
+}
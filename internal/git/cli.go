@@ -86,17 +86,6 @@ func (c CLIClient) Clone(ctx context.Context, url, path, remoteName string) erro
 	return err
 }
 
-func (c CLIClient) CloneBare(ctx context.Context, url, path, remoteName string) error {
-	if remoteName == "" {
-		remoteName = "origin"
-	}
-	if err := os.MkdirAll(path, 0o755); err != nil {
-		return err
-	}
-	_, err := c.run(ctx, "", "clone", "--bare", "--origin", remoteName, url, path)
-	return err
-}
-
 func (c CLIClient) AddRemote(path, name, url string) error {
 	if name == "" {
 		return errors.New("remote name required")
@@ -464,6 +453,13 @@ func (c CLIClient) WorktreeAdd(ctx context.Context, opts WorktreeAddOptions) err
 		}
 	}
 
+	if opts.StartRef != "" {
+		if _, err := c.run(ctx, opts.RepoPath, "rev-parse", "--verify", "--quiet", opts.StartRef+"^{commit}"); err != nil {
+			return fmt.Errorf("start point %q not found", opts.StartRef)
+		}
+		startRef = opts.StartRef
+	}
+
 	refExists, err := c.ReferenceExists(ctx, opts.RepoPath, "refs/heads/"+opts.BranchName)
 	if err != nil {
 		return err
@@ -538,6 +534,25 @@ func (c CLIClient) WorktreeRemove(opts WorktreeRemoveOptions) error {
 	}
 }
 
+func (c CLIClient) WorktreeMove(ctx context.Context, repoPath, worktreePath, newPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	if worktreePath == "" || newPath == "" {
+		return errors.New("worktree path required")
+	}
+	_, err := c.run(ctx, repoPath, "worktree", "move", worktreePath, newPath)
+	return err
+}
+
+func (c CLIClient) WorktreeRepair(ctx context.Context, worktreePath string) error {
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	_, err := c.run(ctx, worktreePath, "worktree", "repair")
+	return err
+}
+
 func (c CLIClient) WorktreeList(repoPath string) ([]string, error) {
 	if repoPath == "" {
 		return nil, errors.New("repo path required")
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+func (c CLIClient) Integrate(ctx context.Context, path, ref string, mode IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if ref == "" {
+		return errors.New("ref required")
+	}
+	var args []string
+	switch mode {
+	case IntegrateRebase:
+		args = []string{"rebase", ref}
+	case IntegrateMerge:
+		args = []string{"merge", "--no-edit", ref}
+	default:
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	_, err := c.run(ctx, path, args...)
+	if err == nil {
+		return nil
+	}
+	return c.stoppedIntegrationError(ctx, path, err)
+}
+
+func (c CLIClient) IntegrationInProgress(ctx context.Context, path string) (IntegrateMode, bool, error) {
+	if path == "" {
+		return "", false, errors.New("repo path required")
+	}
+	checks := []struct {
+		name string
+		mode IntegrateMode
+	}{
+		{name: "rebase-merge", mode: IntegrateRebase},
+		{name: "rebase-apply", mode: IntegrateRebase},
+		{name: "MERGE_HEAD", mode: IntegrateMerge},
+	}
+	for _, check := range checks {
+		adminPath, err := c.gitAdminPath(path, check.name)
+		if err != nil {
+			return "", false, err
+		}
+		if _, err := os.Stat(adminPath); err == nil {
+			return check.mode, true, nil
+		} else if !os.IsNotExist(err) {
+			return "", false, err
+		}
+	}
+	return "", false, nil
+}
+
+func (c CLIClient) ContinueIntegration(ctx context.Context, path string, mode IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if mode != IntegrateRebase && mode != IntegrateMerge {
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	conflicts, err := c.unmergedPaths(ctx, path)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return ConflictError{Mode: mode, Files: conflicts}
+	}
+	// core.editor=true accepts the prepared commit message without prompting.
+	_, err = c.run(ctx, path, "-c", "core.editor=true", string(mode), "--continue")
+	if err == nil {
+		return nil
+	}
+	return c.stoppedIntegrationError(ctx, path, err)
+}
+
+func (c CLIClient) AbortIntegration(ctx context.Context, path string, mode IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if mode != IntegrateRebase && mode != IntegrateMerge {
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	_, err := c.run(ctx, path, string(mode), "--abort")
+	return err
+}
+
+// stoppedIntegrationError converts a failed rebase/merge into ConflictError
+// when the operation is still in progress; otherwise git refused to start
+// (for example because of local changes) and runErr is returned as is.
+func (c CLIClient) stoppedIntegrationError(ctx context.Context, path string, runErr error) error {
+	mode, inProgress, err := c.IntegrationInProgress(ctx, path)
+	if err != nil || !inProgress {
+		return runErr
+	}
+	conflicts, err := c.unmergedPaths(ctx, path)
+	if err != nil {
+		return runErr
+	}
+	return ConflictError{Mode: mode, Files: conflicts}
+}
+
+func (c CLIClient) unmergedPaths(ctx context.Context, path string) ([]string, error) {
+	result, err := c.run(ctx, path, "diff", "--name-only", "--diff-filter=U", "-z")
+	if err != nil {
+		return nil, err
+	}
+	return splitNull([]byte(result.stdout)), nil
+}
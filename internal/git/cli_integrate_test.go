@@ -0,0 +1,105 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// divergedRepo returns a repo on branch feature whose only commit conflicts
+// with the tip of main.
+func divergedRepo(t *testing.T) string {
+	t.Helper()
+	repo := initGitRepo(t)
+	ensureBranch(t, repo, "main")
+	commitFile(t, repo, "file.txt", "one\n", "initial")
+	runGit(t, repo, "checkout", "-b", "feature")
+	commitFile(t, repo, "file.txt", "feature\n", "feature change")
+	runGit(t, repo, "checkout", "main")
+	commitFile(t, repo, "file.txt", "main\n", "main change")
+	runGit(t, repo, "checkout", "feature")
+	return repo
+}
+
+func TestIntegrateRebaseStopsOnConflictAndContinues(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := divergedRepo(t)
+	client := NewCLIClient()
+
+	err := client.Integrate(ctx, repo, "refs/heads/main", IntegrateRebase)
+	var conflict ConflictError
+	if !errors.As(err, &conflict) || conflict.Mode != IntegrateRebase || strings.Join(conflict.Files, ",") != "file.txt" {
+		t.Fatalf("expected rebase conflict on file.txt, got %v", err)
+	}
+	mode, inProgress, err := client.IntegrationInProgress(ctx, repo)
+	if err != nil || !inProgress || mode != IntegrateRebase {
+		t.Fatalf("expected rebase in progress, got mode=%q inProgress=%v err=%v", mode, inProgress, err)
+	}
+	if err := client.ContinueIntegration(ctx, repo, IntegrateRebase); !errors.As(err, &conflict) {
+		t.Fatalf("expected unresolved conflict to block continue, got %v", err)
+	}
+
+	writeFile(t, repo, "file.txt", "resolved\n")
+	runGit(t, repo, "add", "file.txt")
+	if err := client.ContinueIntegration(ctx, repo, IntegrateRebase); err != nil {
+		t.Fatalf("ContinueIntegration: %v", err)
+	}
+	if _, inProgress, _ := client.IntegrationInProgress(ctx, repo); inProgress {
+		t.Fatalf("expected rebase to finish")
+	}
+	ancestor, err := client.IsAncestor(repo, "refs/heads/main", "HEAD")
+	if err != nil || !ancestor {
+		t.Fatalf("expected main to be an ancestor of HEAD, got %v err=%v", ancestor, err)
+	}
+}
+
+func TestIntegrateMergeAbortRestoresBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := divergedRepo(t)
+	before := gitRevParse(t, repo, "HEAD")
+	client := NewCLIClient()
+
+	err := client.Integrate(ctx, repo, "refs/heads/main", IntegrateMerge)
+	var conflict ConflictError
+	if !errors.As(err, &conflict) || conflict.Mode != IntegrateMerge {
+		t.Fatalf("expected merge conflict, got %v", err)
+	}
+	if err := client.AbortIntegration(ctx, repo, IntegrateMerge); err != nil {
+		t.Fatalf("AbortIntegration: %v", err)
+	}
+	if _, inProgress, _ := client.IntegrationInProgress(ctx, repo); inProgress {
+		t.Fatalf("expected merge to be aborted")
+	}
+	if after := gitRevParse(t, repo, "HEAD"); after != before {
+		t.Fatalf("expected HEAD %s after abort, got %s", before, after)
+	}
+}
+
+func TestIntegrateFastForwardMerge(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := initGitRepo(t)
+	ensureBranch(t, repo, "main")
+	commitFile(t, repo, "file.txt", "one\n", "initial")
+	runGit(t, repo, "checkout", "-b", "feature")
+	runGit(t, repo, "checkout", "main")
+	commitFile(t, repo, "other.txt", "two\n", "main change")
+	runGit(t, repo, "checkout", "feature")
+
+	if err := NewCLIClient().Integrate(ctx, repo, "refs/heads/main", IntegrateMerge); err != nil {
+		t.Fatalf("Integrate: %v", err)
+	}
+	if gitRevParse(t, repo, "HEAD") != gitRevParse(t, repo, "refs/heads/main") {
+		t.Fatalf("expected feature to fast-forward to main")
+	}
+}
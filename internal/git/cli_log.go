@@ -0,0 +1,76 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	logRecordSep = "\x1e"
+	logFieldSep  = "\x1f"
+)
+
+func (c CLIClient) Log(ctx context.Context, repoPath string, opts LogOptions) ([]Commit, error) {
+	if repoPath == "" {
+		return nil, errors.New("repo path required")
+	}
+	ref := opts.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	args := []string{"log", "--no-color", "--format=" + logRecordSep + "%H" + logFieldSep + "%an" + logFieldSep + "%ae" + logFieldSep + "%aI" + logFieldSep + "%s"}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since="+opts.Since.Format(time.RFC3339))
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Patch {
+		args = append(args, "--patch", "--no-ext-diff")
+	}
+	args = append(args, ref)
+	if opts.Exclude != "" {
+		args = append(args, "^"+opts.Exclude)
+	}
+	args = append(args, "--")
+	result, err := c.run(ctx, repoPath, args...)
+	if err != nil {
+		if message := strings.TrimSpace(result.stderr); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return parseLog(result.stdout)
+}
+
+// parseLog parses records written with logRecordSep before each commit's
+// header line; anything after the header is the commit's patch.
+func parseLog(output string) ([]Commit, error) {
+	commits := []Commit{}
+	for record := range strings.SplitSeq(output, logRecordSep) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		header, patch, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, logFieldSep)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected log record: %q", header)
+		}
+		when, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("parse commit time %q: %w", fields[3], err)
+		}
+		commits = append(commits, Commit{
+			SHA:         fields[0],
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Time:        when,
+			Subject:     fields[4],
+			Patch:       strings.TrimSpace(patch),
+		})
+	}
+	return commits, nil
+}
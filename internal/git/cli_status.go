@@ -0,0 +1,106 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func (c CLIClient) StatusDetail(ctx context.Context, path, baseRef string) (StatusDetail, error) {
+	if path == "" {
+		return StatusDetail{}, errors.New("repo path required")
+	}
+	result, err := c.run(ctx, path, "status", "--porcelain=v2", "--branch", "-z")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || isNotRepo(result) {
+			return StatusDetail{StatusSummary: StatusSummary{Missing: true}}, nil
+		}
+		return StatusDetail{}, err
+	}
+	detail := parseStatusV2([]byte(result.stdout))
+
+	if baseRef != "" {
+		// An unknown base ref or unborn HEAD leaves BaseRef empty rather than
+		// failing the whole status.
+		counts, err := c.run(ctx, path, "rev-list", "--left-right", "--count", "HEAD..."+baseRef)
+		if err == nil {
+			if ahead, behind, ok := parseLeftRightCounts(counts.stdout); ok {
+				detail.BaseRef = baseRef
+				detail.BaseAhead = ahead
+				detail.BaseBehind = behind
+			}
+		}
+	}
+
+	stashes, err := c.run(ctx, path, "stash", "list")
+	if err != nil {
+		return StatusDetail{}, err
+	}
+	for line := range strings.SplitSeq(stashes.stdout, "\n") {
+		if strings.TrimSpace(line) != "" {
+			detail.Stashes++
+		}
+	}
+	return detail, nil
+}
+
+// parseStatusV2 parses `git status --porcelain=v2 --branch -z` output.
+func parseStatusV2(data []byte) StatusDetail {
+	detail := StatusDetail{}
+	parts := strings.Split(string(data), "\x00")
+	for i := 0; i < len(parts); i++ {
+		entry := parts[i]
+		if entry == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(entry, "# branch.head "):
+			head := strings.TrimPrefix(entry, "# branch.head ")
+			if head == "(detached)" {
+				detail.Detached = true
+			} else {
+				detail.Branch = head
+			}
+		case strings.HasPrefix(entry, "# branch.upstream "):
+			detail.Upstream = strings.TrimPrefix(entry, "# branch.upstream ")
+		case strings.HasPrefix(entry, "# branch.ab "):
+			fields := strings.Fields(strings.TrimPrefix(entry, "# branch.ab "))
+			if len(fields) == 2 {
+				detail.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				detail.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(entry, "#"):
+		case strings.HasPrefix(entry, "u "):
+			detail.Dirty = true
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			if fields := strings.SplitN(entry, " ", 11); len(fields) == 11 {
+				detail.Conflicts = append(detail.Conflicts, fields[10])
+			}
+		case strings.HasPrefix(entry, "2 "):
+			detail.Dirty = true
+			// Renames and copies are followed by the original path.
+			i++
+		default:
+			detail.Dirty = true
+		}
+	}
+	return detail
+}
+
+func parseLeftRightCounts(output string) (int, int, bool) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	left, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	right, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return left, right, true
+}
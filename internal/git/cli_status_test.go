@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStatusDetailReportsTrackingAndConflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	origin := initGitRepo(t)
+	ensureBranch(t, origin, "main")
+	commitFile(t, origin, "file.txt", "one\n", "initial")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, origin, "clone", origin, clone)
+	runGit(t, clone, "config", "user.email", "test@example.com")
+	runGit(t, clone, "config", "user.name", "Workset Tests")
+	runGit(t, clone, "checkout", "-b", "feature", "--track", "origin/main")
+	commitFile(t, clone, "feature.txt", "feature\n", "feature work")
+
+	commitFile(t, origin, "file.txt", "two\n", "upstream change")
+	runGit(t, clone, "fetch", "origin")
+
+	writeFile(t, clone, "scratch.txt", "stash me\n")
+	runGit(t, clone, "stash", "push", "--include-untracked")
+
+	client := NewCLIClient()
+	detail, err := client.StatusDetail(context.Background(), clone, "refs/remotes/origin/main")
+	if err != nil {
+		t.Fatalf("StatusDetail: %v", err)
+	}
+	if detail.Branch != "feature" || detail.Upstream != "origin/main" {
+		t.Fatalf("unexpected branch info: %+v", detail)
+	}
+	if detail.Ahead != 1 || detail.Behind != 1 || detail.BaseAhead != 1 || detail.BaseBehind != 1 {
+		t.Fatalf("unexpected ahead/behind: %+v", detail)
+	}
+	if detail.Stashes != 1 || detail.Dirty {
+		t.Fatalf("expected clean tree with one stash: %+v", detail)
+	}
+
+	commitFile(t, clone, "file.txt", "three\n", "conflicting change")
+	if err := runGitAllowError(clone, "merge", "origin/main"); err == nil {
+		t.Fatalf("expected merge conflict")
+	}
+	detail, err = client.StatusDetail(context.Background(), clone, "")
+	if err != nil {
+		t.Fatalf("StatusDetail: %v", err)
+	}
+	if !detail.Dirty || !slices.Equal(detail.Conflicts, []string{"file.txt"}) {
+		t.Fatalf("expected file.txt conflict: %+v", detail)
+	}
+	if detail.BaseRef != "" {
+		t.Fatalf("expected no base ref, got %q", detail.BaseRef)
+	}
+}
+
+func TestStatusDetailMissingPath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	client := NewCLIClient()
+	detail, err := client.StatusDetail(context.Background(), filepath.Join(t.TempDir(), "missing"), "")
+	if err != nil {
+		t.Fatalf("StatusDetail: %v", err)
+	}
+	if !detail.Missing {
+		t.Fatalf("expected missing status: %+v", detail)
+	}
+}
+
+func TestParseStatusV2(t *testing.T) {
+	data := "# branch.oid abc\x00# branch.head (detached)\x00" +
+		"2 R. N... 100644 100644 100644 abc def R100 new.txt\x00old.txt\x00" +
+		"? untracked.txt\x00"
+	detail := parseStatusV2([]byte(data))
+	if !detail.Detached || detail.Branch != "" || detail.Upstream != "" {
+		t.Fatalf("unexpected branch info: %+v", detail)
+	}
+	if !detail.Dirty || len(detail.Conflicts) != 0 {
+		t.Fatalf("unexpected dirty/conflicts: %+v", detail)
+	}
+}
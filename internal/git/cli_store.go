@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func (c CLIClient) CloneBare(ctx context.Context, url, path, remoteName string) error {
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+	if _, err := c.run(ctx, "", "clone", "--bare", "--origin", remoteName, url, path); err != nil {
+		return err
+	}
+	refspec := fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName)
+	if _, err := c.run(ctx, path, "config", "remote."+remoteName+".fetch", refspec); err != nil {
+		return err
+	}
+	if _, err := c.run(ctx, path, "fetch", remoteName); err != nil {
+		return err
+	}
+	head, err := c.run(ctx, path, "symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		return err
+	}
+	defaultRef := strings.TrimSpace(head.stdout)
+	heads, err := c.run(ctx, path, "for-each-ref", "--format=%(refname)", "refs/heads")
+	if err != nil {
+		return err
+	}
+	for _, ref := range strings.Split(strings.TrimSpace(heads.stdout), "\n") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || ref == defaultRef {
+			continue
+		}
+		if _, err := c.run(ctx, path, "update-ref", "-d", ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c CLIClient) GC(ctx context.Context, repoPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	if _, err := c.run(ctx, repoPath, "worktree", "prune"); err != nil {
+		return err
+	}
+	_, err := c.run(ctx, repoPath, "gc", "--quiet")
+	return err
+}
+
+func (c CLIClient) Fsck(ctx context.Context, repoPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	_, err := c.run(ctx, repoPath, "fsck", "--connectivity-only", "--no-progress")
+	return err
+}
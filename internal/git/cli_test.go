@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -255,6 +256,111 @@ func TestWorktreeRemoveRetriesWithForce(t *testing.T) {
 	}
 }
 
+func TestWorktreeAddStartRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := initGitRepo(t)
+	ensureBranch(t, repo, "main")
+	commitFile(t, repo, "file.txt", "one\n", "initial")
+	runGit(t, repo, "branch", "release/1.2")
+	commitFile(t, repo, "file.txt", "two\n", "second")
+
+	client := NewCLIClient()
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	if err := client.WorktreeAdd(context.Background(), WorktreeAddOptions{
+		RepoPath:     repo,
+		WorktreePath: worktreePath,
+		WorktreeName: "feature",
+		BranchName:   "feature",
+		StartBranch:  "main",
+		StartRef:     "release/1.2",
+	}); err != nil {
+		t.Fatalf("WorktreeAdd: %v", err)
+	}
+	if got, want := gitRevParse(t, repo, "refs/heads/feature"), gitRevParse(t, repo, "refs/heads/release/1.2"); got != want {
+		t.Fatalf("expected feature to start at release/1.2 (%s), got %s", want, got)
+	}
+
+	err := client.WorktreeAdd(context.Background(), WorktreeAddOptions{
+		RepoPath:     repo,
+		WorktreePath: filepath.Join(t.TempDir(), "missing"),
+		WorktreeName: "other",
+		BranchName:   "other",
+		StartRef:     "no-such-ref",
+	})
+	if err == nil || !strings.Contains(err.Error(), "no-such-ref") {
+		t.Fatalf("expected missing start point error, got %v", err)
+	}
+}
+
+func TestWorktreeMove(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := initGitRepo(t)
+	ensureBranch(t, repo, "main")
+	commitFile(t, repo, "file.txt", "one\n", "initial")
+	base := t.TempDir()
+	from := filepath.Join(base, "old")
+	to := filepath.Join(base, "new")
+	runGit(t, repo, "worktree", "add", "-b", "feature", from)
+
+	client := NewCLIClient()
+	if err := client.WorktreeMove(context.Background(), repo, from, to); err != nil {
+		t.Fatalf("WorktreeMove: %v", err)
+	}
+	branch, ok, err := client.CurrentBranch(to)
+	if err != nil || !ok || branch != "feature" {
+		t.Fatalf("expected moved worktree on feature, got %q (ok=%v, err=%v)", branch, ok, err)
+	}
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Fatalf("expected old worktree path gone, stat err: %v", err)
+	}
+}
+
+func TestWorktreeRepair(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := initGitRepo(t)
+	ensureBranch(t, repo, "main")
+	commitFile(t, repo, "file.txt", "one\n", "initial")
+	base := t.TempDir()
+	from := filepath.Join(base, "feature")
+	to := filepath.Join(base, "moved", "feature")
+	runGit(t, repo, "worktree", "add", "-b", "feature", from)
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		t.Fatalf("rename worktree: %v", err)
+	}
+
+	client := NewCLIClient()
+	if err := client.WorktreeRepair(context.Background(), to); err != nil {
+		t.Fatalf("WorktreeRepair: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, ".git", "worktrees", "feature", "gitdir"))
+	if err != nil {
+		t.Fatalf("read worktree gitdir: %v", err)
+	}
+	got, err := filepath.EvalSymlinks(filepath.Dir(strings.TrimSpace(string(data))))
+	if err != nil {
+		t.Fatalf("resolve worktree gitdir: %v", err)
+	}
+	want, err := filepath.EvalSymlinks(to)
+	if err != nil {
+		t.Fatalf("resolve moved worktree: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected metadata to point at %s, got %s", want, got)
+	}
+}
+
 func TestUpdateBranchBareRepo(t *testing.T) {
 	if _, err := exec.LookPath("git"); err != nil {
 		t.Skip("git not available")
@@ -291,6 +397,73 @@ func TestUpdateBranchBareRepo(t *testing.T) {
 	}
 }
 
+func TestCloneBareKeepsDefaultBranchAndTracksRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	source := initGitRepo(t)
+	ensureBranch(t, source, "main")
+	commitFile(t, source, "file.txt", "one\n", "initial")
+	runGit(t, source, "branch", "feature")
+
+	mirror := filepath.Join(t.TempDir(), "mirror")
+	client := NewCLIClient()
+	if err := client.CloneBare(context.Background(), source, mirror, "origin"); err != nil {
+		t.Fatalf("CloneBare: %v", err)
+	}
+	for ref, want := range map[string]bool{
+		"refs/heads/main":             true,
+		"refs/heads/feature":          false,
+		"refs/remotes/origin/main":    true,
+		"refs/remotes/origin/feature": true,
+	} {
+		exists, err := client.ReferenceExists(context.Background(), mirror, ref)
+		if err != nil {
+			t.Fatalf("ReferenceExists %s: %v", ref, err)
+		}
+		if exists != want {
+			t.Fatalf("expected %s exists=%v, got %v", ref, want, exists)
+		}
+	}
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	if err := client.WorktreeAdd(context.Background(), WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: worktreePath,
+		WorktreeName: "thread",
+		BranchName:   "thread",
+		StartRemote:  "origin",
+		StartBranch:  "main",
+	}); err != nil {
+		t.Fatalf("WorktreeAdd: %v", err)
+	}
+	commitFile(t, source, "file.txt", "two\n", "second")
+	if err := client.Fetch(context.Background(), mirror, "origin"); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got, want := gitRevParse(t, mirror, "refs/remotes/origin/main"), gitRevParse(t, source, "refs/heads/main"); got != want {
+		t.Fatalf("expected origin/main at %s after fetch, got %s", want, got)
+	}
+
+	if err := os.RemoveAll(worktreePath); err != nil {
+		t.Fatalf("remove worktree: %v", err)
+	}
+	if err := client.GC(context.Background(), mirror); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	worktrees, err := client.WorktreeList(mirror)
+	if err != nil {
+		t.Fatalf("WorktreeList: %v", err)
+	}
+	if len(worktrees) != 0 {
+		t.Fatalf("expected GC to prune stale worktree metadata, got %v", worktrees)
+	}
+	if err := client.Fsck(context.Background(), mirror); err != nil {
+		t.Fatalf("Fsck: %v", err)
+	}
+}
+
 func initGitRepo(t *testing.T) string {
 	t.Helper()
 	dir := t.TempDir()
@@ -3,6 +3,9 @@ package git
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 // ErrWorktreeNotFound indicates a worktree entry was missing.
@@ -13,13 +16,84 @@ type StatusSummary struct {
 	Missing bool
 }
 
+// StatusDetail extends StatusSummary with branch tracking information.
+type StatusDetail struct {
+	StatusSummary
+	Branch   string
+	Detached bool
+	Upstream string
+	// Ahead and Behind count commits relative to Upstream.
+	Ahead  int
+	Behind int
+	// BaseRef is the ref BaseAhead/BaseBehind were measured against; empty
+	// when no base ref was requested or it could not be resolved.
+	BaseRef    string
+	BaseAhead  int
+	BaseBehind int
+	Stashes    int
+	Conflicts  []string
+}
+
+// IntegrateMode selects how upstream commits are brought into a branch.
+type IntegrateMode string
+
+const (
+	IntegrateRebase IntegrateMode = "rebase"
+	IntegrateMerge  IntegrateMode = "merge"
+)
+
+// ConflictError reports that a rebase or merge stopped on conflicts. The
+// operation is left in progress so it can be continued or aborted.
+type ConflictError struct {
+	Mode  IntegrateMode
+	Files []string
+}
+
+func (e ConflictError) Error() string {
+	if len(e.Files) == 0 {
+		return string(e.Mode) + " stopped"
+	}
+	return fmt.Sprintf("%s stopped on conflicts in %s", e.Mode, strings.Join(e.Files, ", "))
+}
+
+// LogOptions selects the commits Log returns.
+type LogOptions struct {
+	// Ref is the commit to walk back from; empty means HEAD.
+	Ref string
+	// Exclude hides commits reachable from it, so the walk stops at Ref's
+	// merge-base with Exclude.
+	Exclude string
+	// Since drops commits authored before it; zero keeps all.
+	Since time.Time
+	// Author keeps commits whose "Name <email>" matches this regular
+	// expression.
+	Author string
+	// Patch includes each commit's diff against its first parent.
+	Patch bool
+}
+
+// Commit is one entry returned by Log.
+type Commit struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	// Time is the author date.
+	Time    time.Time
+	Subject string
+	Patch   string
+}
+
 type WorktreeAddOptions struct {
-	RepoPath      string
-	WorktreePath  string
-	WorktreeName  string
-	BranchName    string
-	StartRemote   string
-	StartBranch   string
+	RepoPath     string
+	WorktreePath string
+	WorktreeName string
+	BranchName   string
+	StartRemote  string
+	StartBranch  string
+	// StartRef is an explicit start point (branch, tag, remote-tracking
+	// branch, or commit) for a new branch. When set it replaces
+	// StartRemote/StartBranch and must resolve to a commit.
+	StartRef      string
 	ForceCheckout bool
 }
 
@@ -31,6 +105,10 @@ type WorktreeRemoveOptions struct {
 
 type Client interface {
 	Clone(ctx context.Context, url, path, remoteName string) error
+	// CloneBare clones url as a bare mirror whose fetches update
+	// refs/remotes/<remoteName>/*, so branches checked out in linked
+	// worktrees are never rewritten by a fetch. Only the default branch is
+	// kept as a local branch.
 	CloneBare(ctx context.Context, url, path, remoteName string) error
 	AddRemote(path, name, url string) error
 	RemoteNames(repoPath string) ([]string, error)
@@ -40,6 +118,20 @@ type Client interface {
 	// UpdateBranch force-updates branchName to targetRef. Callers must ensure the update is safe.
 	UpdateBranch(ctx context.Context, repoPath, branchName, targetRef string) error
 	Status(path string) (StatusSummary, error)
+	// StatusDetail reports branch, upstream, and conflict details for a
+	// worktree. baseRef is optional; when set, ahead/behind counts against it
+	// are included.
+	StatusDetail(ctx context.Context, path, baseRef string) (StatusDetail, error)
+	// Integrate rebases the checked-out branch in path onto ref, or merges ref
+	// into it. Conflicts leave the operation in progress and return
+	// ConflictError.
+	Integrate(ctx context.Context, path, ref string, mode IntegrateMode) error
+	// IntegrationInProgress reports a rebase or merge stopped in path.
+	IntegrationInProgress(ctx context.Context, path string) (IntegrateMode, bool, error)
+	// ContinueIntegration resumes a stopped rebase or merge once conflicts are
+	// resolved and staged; AbortIntegration restores the pre-operation branch.
+	ContinueIntegration(ctx context.Context, path string, mode IntegrateMode) error
+	AbortIntegration(ctx context.Context, path string, mode IntegrateMode) error
 	IsRepo(path string) (bool, error)
 	IsAncestor(repoPath, ancestorRef, descendantRef string) (bool, error)
 	IsContentMerged(repoPath, branchRef, baseRef string) (bool, error)
@@ -47,5 +139,18 @@ type Client interface {
 	RemoteExists(repoPath, remoteName string) (bool, error)
 	WorktreeAdd(ctx context.Context, opts WorktreeAddOptions) error
 	WorktreeRemove(opts WorktreeRemoveOptions) error
+	// WorktreeMove relocates the linked worktree at worktreePath to newPath,
+	// keeping repoPath's worktree metadata in step.
+	WorktreeMove(ctx context.Context, repoPath, worktreePath, newPath string) error
+	// WorktreeRepair points a linked worktree's metadata in its repo back at
+	// worktreePath after the worktree directory was moved by other means.
+	WorktreeRepair(ctx context.Context, worktreePath string) error
 	WorktreeList(repoPath string) ([]string, error)
+	// GC prunes stale worktree metadata and repacks repoPath.
+	GC(ctx context.Context, repoPath string) error
+	// Fsck checks that every object reachable from repoPath's refs is
+	// present.
+	Fsck(ctx context.Context, repoPath string) error
+	// Log lists commits newest first, like git log.
+	Log(ctx context.Context, repoPath string, opts LogOptions) ([]Commit, error)
 }
@@ -0,0 +1,138 @@
+package gittest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+// Backend is a git.Client plus the scripting the conformance suite needs to
+// set repositories up. Fake is a Backend; CLIBackend drives real git.
+type Backend interface {
+	Client() git.Client
+	// InitRepo creates a non-bare repo at path with branch checked out and
+	// one commit adding README.md.
+	InitRepo(tb testing.TB, path, branch string)
+	// Commit writes files into the worktree at path and commits every
+	// change there, returning the new commit id.
+	Commit(tb testing.TB, path, message string, files map[string]string) string
+	// WriteFile leaves an uncommitted change in the worktree at path.
+	WriteFile(tb testing.TB, path, name, content string)
+	// Resolve settles and stages a conflicted file.
+	Resolve(tb testing.TB, path, name, content string)
+	// Branch creates a branch pointing at start.
+	Branch(tb testing.TB, repoPath, name, start string)
+	// Rev resolves rev to a commit id.
+	Rev(tb testing.TB, repoPath, rev string) string
+}
+
+// CLIBackend is a Backend backed by git.CLIClient and the git binary.
+type CLIBackend struct {
+	client git.CLIClient
+	seq    int
+}
+
+// NewCLIBackend returns a CLIBackend, skipping tb when git is not installed.
+// It sets a git identity in the environment for the rest of tb so clones can
+// commit during rebases and merges.
+func NewCLIBackend(tb testing.TB) *CLIBackend {
+	tb.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		tb.Skip("git not installed")
+	}
+	name, email := authorParts(DefaultAuthor)
+	for _, role := range []string{"AUTHOR", "COMMITTER"} {
+		tb.Setenv("GIT_"+role+"_NAME", name)
+		tb.Setenv("GIT_"+role+"_EMAIL", email)
+	}
+	return &CLIBackend{client: git.NewCLIClient()}
+}
+
+func (b *CLIBackend) Client() git.Client {
+	return b.client
+}
+
+func (b *CLIBackend) InitRepo(tb testing.TB, path, branch string) {
+	tb.Helper()
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		tb.Fatalf("gittest: init %s: %v", path, err)
+	}
+	b.git(tb, path, "init", "--quiet", "--initial-branch", branch)
+	b.Commit(tb, path, "initial commit", map[string]string{"README.md": "# " + filepath.Base(path) + "\n"})
+}
+
+func (b *CLIBackend) Commit(tb testing.TB, path, message string, files map[string]string) string {
+	tb.Helper()
+	for name, content := range files {
+		if err := writeWorktreeFile(path, name, content); err != nil {
+			tb.Fatalf("gittest: commit: %v", err)
+		}
+	}
+	b.git(tb, path, "add", "--all")
+	// Commits one minute apart keep history ordering deterministic.
+	b.seq++
+	date := time.Date(2024, 1, 1, 0, b.seq, 0, 0, time.UTC).Format(time.RFC3339)
+	b.gitEnv(tb, path, []string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date},
+		"commit", "--quiet", "--allow-empty", "-m", message)
+	return b.Rev(tb, path, "HEAD")
+}
+
+func (b *CLIBackend) WriteFile(tb testing.TB, path, name, content string) {
+	tb.Helper()
+	if err := writeWorktreeFile(path, name, content); err != nil {
+		tb.Fatalf("gittest: write: %v", err)
+	}
+}
+
+func (b *CLIBackend) Resolve(tb testing.TB, path, name, content string) {
+	tb.Helper()
+	b.WriteFile(tb, path, name, content)
+	b.git(tb, path, "add", "--", name)
+}
+
+func (b *CLIBackend) Branch(tb testing.TB, repoPath, name, start string) {
+	tb.Helper()
+	b.git(tb, repoPath, "branch", name, start)
+}
+
+func (b *CLIBackend) Rev(tb testing.TB, repoPath, rev string) string {
+	tb.Helper()
+	return strings.TrimSpace(b.git(tb, repoPath, "rev-parse", "--verify", rev+"^{commit}"))
+}
+
+func (b *CLIBackend) git(tb testing.TB, dir string, args ...string) string {
+	tb.Helper()
+	return b.gitEnv(tb, dir, nil, args...)
+}
+
+func (b *CLIBackend) gitEnv(tb testing.TB, dir string, env []string, args ...string) string {
+	tb.Helper()
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		tb.Fatalf("gittest: git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String()
+}
+
+// authorParts splits "Name <email>".
+func authorParts(author string) (string, string) {
+	name, email, _ := strings.Cut(author, "<")
+	return strings.TrimSpace(name), strings.TrimSuffix(strings.TrimSpace(email), ">")
+}
+
+var (
+	_ Backend = (*CLIBackend)(nil)
+	_ Backend = (*Fake)(nil)
+)
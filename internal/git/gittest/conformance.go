@@ -0,0 +1,591 @@
+package gittest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+// RunConformance runs the behaviours workset relies on against a fresh
+// Backend per case, so Fake and CLIClient can be held to the same contract.
+func RunConformance(t *testing.T, newBackend func(testing.TB) Backend) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, b Backend, root string)
+	}{
+		{"CloneTracksRemote", conformClone},
+		{"CloneBareKeepsDefaultBranch", conformCloneBare},
+		{"FetchUpdatesRemoteTracking", conformFetch},
+		{"WorktreeLifecycle", conformWorktreeLifecycle},
+		{"WorktreeAddStartPoints", conformWorktreeStartPoints},
+		{"WorktreeRepairAndGC", conformWorktreeRepairAndGC},
+		{"StatusDetailCounts", conformStatusDetail},
+		{"IntegrateFastForwardAndRebase", conformIntegrate},
+		{"IntegrateConflicts", conformIntegrateConflicts},
+		{"IsContentMerged", conformContentMerged},
+		{"UpdateBranch", conformUpdateBranch},
+		{"LogSinceMergeBase", conformLog},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			if err != nil {
+				t.Fatalf("resolve temp dir: %v", err)
+			}
+			tc.run(t, newBackend(t), root)
+		})
+	}
+}
+
+// originAndMirror creates an origin repo on main and a bare mirror of it.
+func originAndMirror(t *testing.T, b Backend, root string) (string, string) {
+	t.Helper()
+	origin := filepath.Join(root, "origin")
+	b.InitRepo(t, origin, "main")
+	mirror := filepath.Join(root, "mirror.git")
+	if err := b.Client().CloneBare(context.Background(), origin, mirror, "origin"); err != nil {
+		t.Fatalf("clone bare: %v", err)
+	}
+	return origin, mirror
+}
+
+// threadWorktree adds a worktree on a new branch started from
+// origin/main in mirror.
+func threadWorktree(t *testing.T, b Backend, mirror, path, branch string) {
+	t.Helper()
+	err := b.Client().WorktreeAdd(context.Background(), git.WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: path,
+		WorktreeName: filepath.Base(path),
+		BranchName:   branch,
+		StartRemote:  "origin",
+		StartBranch:  "main",
+	})
+	if err != nil {
+		t.Fatalf("worktree add %s: %v", branch, err)
+	}
+}
+
+func expectRef(t *testing.T, client git.Client, repoPath, ref string, want bool) {
+	t.Helper()
+	got, err := client.ReferenceExists(context.Background(), repoPath, ref)
+	if err != nil {
+		t.Fatalf("reference exists %s: %v", ref, err)
+	}
+	if got != want {
+		t.Fatalf("expected %s exists=%v, got %v", ref, want, got)
+	}
+}
+
+func conformClone(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	origin := filepath.Join(root, "origin")
+	b.InitRepo(t, origin, "main")
+	clone := filepath.Join(root, "clone")
+	if err := client.Clone(context.Background(), origin, clone, "upstream"); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+
+	names, err := client.RemoteNames(clone)
+	if err != nil || !slices.Equal(names, []string{"upstream"}) {
+		t.Fatalf("expected remote upstream, got %v err=%v", names, err)
+	}
+	urls, err := client.RemoteURLs(clone, "upstream")
+	if err != nil || !slices.Equal(urls, []string{origin}) {
+		t.Fatalf("expected upstream url %s, got %v err=%v", origin, urls, err)
+	}
+	if _, err := client.RemoteURLs(clone, "missing"); err == nil {
+		t.Fatalf("expected error for unknown remote")
+	}
+	if ok, err := client.RemoteExists(clone, "missing"); err != nil || ok {
+		t.Fatalf("expected missing remote to not exist, got %v err=%v", ok, err)
+	}
+	if err := client.AddRemote(clone, "fork", "https://example.com/fork.git"); err != nil {
+		t.Fatalf("add remote: %v", err)
+	}
+	if err := client.AddRemote(clone, "fork", "https://example.com/other.git"); err != nil {
+		t.Fatalf("expected re-adding a remote to be a no-op, got %v", err)
+	}
+	if ok, err := client.RemoteExists(clone, "fork"); err != nil || !ok {
+		t.Fatalf("expected fork remote, got %v err=%v", ok, err)
+	}
+
+	expectRef(t, client, clone, "refs/heads/main", true)
+	expectRef(t, client, clone, "refs/remotes/upstream/main", true)
+	expectRef(t, client, clone, "refs/heads/missing", false)
+	if branch, ok, err := client.CurrentBranch(clone); err != nil || !ok || branch != "main" {
+		t.Fatalf("expected main checked out, got %q ok=%v err=%v", branch, ok, err)
+	}
+	if ok, err := client.IsRepo(clone); err != nil || !ok {
+		t.Fatalf("expected clone to be a repo, got %v err=%v", ok, err)
+	}
+	if ok, err := client.IsRepo(filepath.Join(clone, ".git")); err != nil || !ok {
+		t.Fatalf("expected .git dir to resolve to the repo, got %v err=%v", ok, err)
+	}
+	if ok, err := client.IsRepo(root); err != nil || ok {
+		t.Fatalf("expected plain dir to not be a repo, got %v err=%v", ok, err)
+	}
+	detail, err := client.StatusDetail(context.Background(), clone, "")
+	if err != nil || detail.Upstream != "upstream/main" || detail.Dirty {
+		t.Fatalf("expected clean clone tracking upstream/main, got %+v err=%v", detail, err)
+	}
+	if err := client.Clone(context.Background(), origin, clone, "origin"); err == nil {
+		t.Fatalf("expected clone into a non-empty dir to fail")
+	}
+}
+
+func conformCloneBare(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	origin := filepath.Join(root, "origin")
+	b.InitRepo(t, origin, "main")
+	b.Branch(t, origin, "feature", "main")
+	mirror := filepath.Join(root, "mirror.git")
+	if err := client.CloneBare(context.Background(), origin, mirror, "origin"); err != nil {
+		t.Fatalf("clone bare: %v", err)
+	}
+
+	expectRef(t, client, mirror, "refs/heads/main", true)
+	expectRef(t, client, mirror, "refs/heads/feature", false)
+	expectRef(t, client, mirror, "refs/remotes/origin/feature", true)
+	if ok, err := client.IsRepo(mirror); err != nil || ok {
+		t.Fatalf("expected bare mirror to not be a work tree, got %v err=%v", ok, err)
+	}
+	if branch, ok, err := client.CurrentBranch(mirror); err != nil || !ok || branch != "main" {
+		t.Fatalf("expected mirror HEAD on main, got %q ok=%v err=%v", branch, ok, err)
+	}
+	if _, err := client.Status(mirror); err == nil {
+		t.Fatalf("expected status of a bare mirror to fail")
+	}
+	if err := client.Fsck(context.Background(), mirror); err != nil {
+		t.Fatalf("fsck: %v", err)
+	}
+}
+
+func conformFetch(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	origin, mirror := originAndMirror(t, b, root)
+	before := b.Rev(t, mirror, "refs/heads/main")
+	next := b.Commit(t, origin, "advance", map[string]string{"a.txt": "a\n"})
+
+	if err := client.Fetch(context.Background(), mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if got := b.Rev(t, mirror, "refs/remotes/origin/main"); got != next {
+		t.Fatalf("expected origin/main at %s, got %s", next, got)
+	}
+	if got := b.Rev(t, mirror, "refs/heads/main"); got != before {
+		t.Fatalf("expected local main left at %s, got %s", before, got)
+	}
+	if err := client.Fetch(context.Background(), mirror, "missing"); err == nil {
+		t.Fatalf("expected fetch from unknown remote to fail")
+	}
+}
+
+func conformWorktreeLifecycle(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	_, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "threads", "demo", "repo")
+	threadWorktree(t, b, mirror, wt, "demo")
+
+	names, err := client.WorktreeList(mirror)
+	if err != nil || !slices.Equal(names, []string{"repo"}) {
+		t.Fatalf("expected worktree repo, got %v err=%v", names, err)
+	}
+	detail, err := client.StatusDetail(ctx, wt, "")
+	if err != nil || detail.Branch != "demo" || detail.Upstream != "origin/main" || detail.Dirty || detail.Missing {
+		t.Fatalf("expected clean demo tracking origin/main, got %+v err=%v", detail, err)
+	}
+	if branch, ok, err := client.CurrentBranch(wt); err != nil || !ok || branch != "demo" {
+		t.Fatalf("expected demo checked out, got %q ok=%v err=%v", branch, ok, err)
+	}
+	b.WriteFile(t, wt, "notes.txt", "wip\n")
+	if status, err := client.Status(wt); err != nil || !status.Dirty {
+		t.Fatalf("expected dirty worktree, got %+v err=%v", status, err)
+	}
+
+	err = client.WorktreeAdd(ctx, git.WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: filepath.Join(root, "threads", "other", "repo"),
+		WorktreeName: "repo",
+		BranchName:   "demo",
+	})
+	if err == nil {
+		t.Fatalf("expected adding a branch checked out elsewhere to fail")
+	}
+
+	moved := filepath.Join(root, "threads", "renamed", "repo")
+	if err := client.WorktreeMove(ctx, mirror, wt, moved); err == nil {
+		t.Fatalf("expected move into a missing parent dir to fail")
+	}
+	if err := os.MkdirAll(filepath.Dir(moved), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := client.WorktreeMove(ctx, mirror, wt, moved); err != nil {
+		t.Fatalf("worktree move: %v", err)
+	}
+	if status, err := client.Status(wt); err != nil || !status.Missing {
+		t.Fatalf("expected old path missing after move, got %+v err=%v", status, err)
+	}
+	if status, err := client.Status(moved); err != nil || !status.Dirty {
+		t.Fatalf("expected moved worktree to keep its changes, got %+v err=%v", status, err)
+	}
+
+	if err := client.WorktreeRemove(git.WorktreeRemoveOptions{RepoPath: mirror, WorktreeName: "repo"}); err != nil {
+		t.Fatalf("worktree remove: %v", err)
+	}
+	if _, err := os.Stat(moved); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree dir removed, stat err: %v", err)
+	}
+	err = client.WorktreeRemove(git.WorktreeRemoveOptions{RepoPath: mirror, WorktreeName: "repo"})
+	if !errors.Is(err, git.ErrWorktreeNotFound) {
+		t.Fatalf("expected ErrWorktreeNotFound, got %v", err)
+	}
+	if names, err := client.WorktreeList(mirror); err != nil || len(names) != 0 {
+		t.Fatalf("expected no worktrees, got %v err=%v", names, err)
+	}
+	expectRef(t, client, mirror, "refs/heads/demo", true)
+}
+
+func conformWorktreeStartPoints(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	first := b.Rev(t, origin, "HEAD")
+	b.Commit(t, origin, "advance", map[string]string{"a.txt": "a\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	err := client.WorktreeAdd(ctx, git.WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: filepath.Join(root, "bad"),
+		WorktreeName: "bad",
+		BranchName:   "bad",
+		StartRef:     "no-such-ref",
+	})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected unknown start ref to fail, got %v", err)
+	}
+
+	pinned := filepath.Join(root, "pinned")
+	err = client.WorktreeAdd(ctx, git.WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: pinned,
+		WorktreeName: "pinned",
+		BranchName:   "pinned",
+		StartRemote:  "origin",
+		StartBranch:  "main",
+		StartRef:     first,
+	})
+	if err != nil {
+		t.Fatalf("worktree add from commit: %v", err)
+	}
+	if got := b.Rev(t, pinned, "HEAD"); got != first {
+		t.Fatalf("expected explicit start ref %s to win, got %s", first, got)
+	}
+	if detail, err := client.StatusDetail(ctx, pinned, ""); err != nil || detail.Upstream != "" {
+		t.Fatalf("expected no upstream from a commit start point, got %+v err=%v", detail, err)
+	}
+
+	local := filepath.Join(root, "local")
+	err = client.WorktreeAdd(ctx, git.WorktreeAddOptions{
+		RepoPath:     mirror,
+		WorktreePath: local,
+		WorktreeName: "local",
+		BranchName:   "local",
+		StartBranch:  "main",
+	})
+	if err != nil {
+		t.Fatalf("worktree add from local branch: %v", err)
+	}
+	if got, want := b.Rev(t, local, "HEAD"), b.Rev(t, mirror, "refs/heads/main"); got != want {
+		t.Fatalf("expected local start branch %s, got %s", want, got)
+	}
+}
+
+func conformWorktreeRepairAndGC(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	_, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "one")
+	threadWorktree(t, b, mirror, wt, "one")
+	gone := filepath.Join(root, "two")
+	threadWorktree(t, b, mirror, gone, "two")
+
+	moved := filepath.Join(root, "moved")
+	if err := os.Rename(wt, moved); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := client.WorktreeRepair(ctx, moved); err != nil {
+		t.Fatalf("worktree repair: %v", err)
+	}
+	if detail, err := client.StatusDetail(ctx, moved, ""); err != nil || detail.Missing || detail.Branch != "one" {
+		t.Fatalf("expected repaired worktree on one, got %+v err=%v", detail, err)
+	}
+
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := client.GC(ctx, mirror); err != nil {
+		t.Fatalf("gc: %v", err)
+	}
+	if names, err := client.WorktreeList(mirror); err != nil || !slices.Equal(names, []string{"one"}) {
+		t.Fatalf("expected gc to prune the deleted worktree, got %v err=%v", names, err)
+	}
+}
+
+func conformStatusDetail(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "wt")
+	threadWorktree(t, b, mirror, wt, "demo")
+	b.Commit(t, wt, "one", map[string]string{"one.txt": "1\n"})
+	b.Commit(t, wt, "two", map[string]string{"two.txt": "2\n"})
+	b.Commit(t, origin, "upstream", map[string]string{"up.txt": "up\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	detail, err := client.StatusDetail(ctx, wt, "origin/main")
+	if err != nil {
+		t.Fatalf("status detail: %v", err)
+	}
+	if detail.Ahead != 2 || detail.Behind != 1 || detail.BaseRef != "origin/main" || detail.BaseAhead != 2 || detail.BaseBehind != 1 {
+		t.Fatalf("expected 2 ahead/1 behind of origin/main, got %+v", detail)
+	}
+	if detail, err := client.StatusDetail(ctx, wt, "no-such-ref"); err != nil || detail.BaseRef != "" {
+		t.Fatalf("expected unknown base ref to be skipped, got %+v err=%v", detail, err)
+	}
+	if detail, err := client.StatusDetail(ctx, filepath.Join(root, "missing"), ""); err != nil || !detail.Missing {
+		t.Fatalf("expected missing path to report Missing, got %+v err=%v", detail, err)
+	}
+}
+
+func conformIntegrate(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "wt")
+	threadWorktree(t, b, mirror, wt, "demo")
+	upstream := b.Commit(t, origin, "upstream", map[string]string{"up.txt": "up\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	if err := client.Integrate(ctx, wt, "origin/main", git.IntegrateRebase); err != nil {
+		t.Fatalf("fast-forward rebase: %v", err)
+	}
+	if got := b.Rev(t, wt, "HEAD"); got != upstream {
+		t.Fatalf("expected fast-forward to %s, got %s", upstream, got)
+	}
+
+	b.Commit(t, wt, "local", map[string]string{"local.txt": "local\n"})
+	b.Commit(t, origin, "upstream two", map[string]string{"up2.txt": "up\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	b.WriteFile(t, wt, "README.md", "edited\n")
+	if err := client.Integrate(ctx, wt, "origin/main", git.IntegrateRebase); err == nil {
+		t.Fatalf("expected rebase with local changes to fail")
+	} else if errors.As(err, new(git.ConflictError)) {
+		t.Fatalf("expected refusal rather than a conflict, got %v", err)
+	}
+	b.Commit(t, wt, "readme", nil)
+	if err := client.Integrate(ctx, wt, "origin/main", git.IntegrateRebase); err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if ok, err := client.IsAncestor(wt, "origin/main", "HEAD"); err != nil || !ok {
+		t.Fatalf("expected origin/main under HEAD after rebase, got %v err=%v", ok, err)
+	}
+	if detail, err := client.StatusDetail(ctx, wt, ""); err != nil || detail.Ahead != 2 || detail.Behind != 0 || detail.Branch != "demo" {
+		t.Fatalf("expected demo 2 ahead after rebase, got %+v err=%v", detail, err)
+	}
+	if _, err := client.IsAncestor(wt, "no-such-ref", "HEAD"); err == nil {
+		t.Fatalf("expected unknown ref to error")
+	}
+}
+
+func conformIntegrateConflicts(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "wt")
+	threadWorktree(t, b, mirror, wt, "demo")
+	local := b.Commit(t, wt, "local", map[string]string{"README.md": "local\n"})
+	b.Commit(t, origin, "upstream", map[string]string{"README.md": "upstream\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	for _, mode := range []git.IntegrateMode{git.IntegrateRebase, git.IntegrateMerge} {
+		err := client.Integrate(ctx, wt, "origin/main", mode)
+		var conflict git.ConflictError
+		if !errors.As(err, &conflict) || conflict.Mode != mode || !slices.Equal(conflict.Files, []string{"README.md"}) {
+			t.Fatalf("expected %s conflict on README.md, got %v", mode, err)
+		}
+		if got, ok, err := client.IntegrationInProgress(ctx, wt); err != nil || !ok || got != mode {
+			t.Fatalf("expected %s in progress, got %q ok=%v err=%v", mode, got, ok, err)
+		}
+		detail, err := client.StatusDetail(ctx, wt, "")
+		if err != nil || !detail.Dirty || !slices.Equal(detail.Conflicts, []string{"README.md"}) {
+			t.Fatalf("expected conflict reported in status, got %+v err=%v", detail, err)
+		}
+		if detail.Detached != (mode == git.IntegrateRebase) {
+			t.Fatalf("expected detached=%v during %s, got %+v", mode == git.IntegrateRebase, mode, detail)
+		}
+		if err := client.ContinueIntegration(ctx, wt, mode); !errors.As(err, &conflict) {
+			t.Fatalf("expected unresolved conflict to block continue, got %v", err)
+		}
+		if err := client.AbortIntegration(ctx, wt, mode); err != nil {
+			t.Fatalf("abort %s: %v", mode, err)
+		}
+		if _, ok, err := client.IntegrationInProgress(ctx, wt); err != nil || ok {
+			t.Fatalf("expected %s aborted, got ok=%v err=%v", mode, ok, err)
+		}
+		if got := b.Rev(t, wt, "HEAD"); got != local {
+			t.Fatalf("expected abort to restore %s, got %s", local, got)
+		}
+		if status, err := client.Status(wt); err != nil || status.Dirty {
+			t.Fatalf("expected clean worktree after abort, got %+v err=%v", status, err)
+		}
+	}
+
+	if err := client.Integrate(ctx, wt, "origin/main", git.IntegrateMerge); !errors.As(err, new(git.ConflictError)) {
+		t.Fatalf("expected merge conflict, got %v", err)
+	}
+	b.Resolve(t, wt, "README.md", "resolved\n")
+	if err := client.ContinueIntegration(ctx, wt, git.IntegrateMerge); err != nil {
+		t.Fatalf("continue merge: %v", err)
+	}
+	if _, ok, err := client.IntegrationInProgress(ctx, wt); err != nil || ok {
+		t.Fatalf("expected merge finished, got ok=%v err=%v", ok, err)
+	}
+	for _, ref := range []string{"origin/main", local} {
+		if ok, err := client.IsAncestor(wt, ref, "HEAD"); err != nil || !ok {
+			t.Fatalf("expected %s merged into HEAD, got %v err=%v", ref, ok, err)
+		}
+	}
+	if detail, err := client.StatusDetail(ctx, wt, ""); err != nil || detail.Dirty || detail.Ahead != 2 {
+		t.Fatalf("expected clean merge 2 ahead, got %+v err=%v", detail, err)
+	}
+}
+
+func conformContentMerged(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	origin := filepath.Join(root, "origin")
+	b.InitRepo(t, origin, "main")
+	b.Branch(t, origin, "squashed", "main")
+	b.Branch(t, origin, "open", "main")
+	b.Branch(t, origin, "merged", "main")
+
+	wt := filepath.Join(root, "wt")
+	err := client.WorktreeAdd(context.Background(), git.WorktreeAddOptions{
+		RepoPath: origin, WorktreePath: wt, WorktreeName: "wt", BranchName: "squashed",
+	})
+	if err != nil {
+		t.Fatalf("worktree add: %v", err)
+	}
+	b.Commit(t, wt, "feature one", map[string]string{"feature.txt": "one\n"})
+	b.Commit(t, wt, "feature two", map[string]string{"feature.txt": "two\n"})
+	// The squash lands on main as a single commit with the branch's content.
+	b.Commit(t, origin, "squash", map[string]string{"feature.txt": "two\n"})
+	b.Commit(t, origin, "later", map[string]string{"later.txt": "later\n"})
+
+	merged, err := client.IsContentMerged(origin, "squashed", "main")
+	if err != nil || !merged {
+		t.Fatalf("expected squash-merged branch to count as merged, got %v err=%v", merged, err)
+	}
+	if merged, err := client.IsContentMerged(origin, "merged", "main"); err != nil || !merged {
+		t.Fatalf("expected ancestor branch to count as merged, got %v err=%v", merged, err)
+	}
+
+	if err := client.WorktreeRemove(git.WorktreeRemoveOptions{RepoPath: origin, WorktreeName: "wt"}); err != nil {
+		t.Fatalf("worktree remove: %v", err)
+	}
+	err = client.WorktreeAdd(context.Background(), git.WorktreeAddOptions{
+		RepoPath: origin, WorktreePath: wt, WorktreeName: "wt", BranchName: "open",
+	})
+	if err != nil {
+		t.Fatalf("worktree add: %v", err)
+	}
+	b.Commit(t, wt, "unmerged", map[string]string{"open.txt": "open\n"})
+	if merged, err := client.IsContentMerged(origin, "open", "main"); err != nil || merged {
+		t.Fatalf("expected open branch to not count as merged, got %v err=%v", merged, err)
+	}
+}
+
+func conformUpdateBranch(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	next := b.Commit(t, origin, "advance", map[string]string{"a.txt": "a\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if err := client.UpdateBranch(ctx, mirror, "main", "origin/main"); err != nil {
+		t.Fatalf("update branch: %v", err)
+	}
+	if got := b.Rev(t, mirror, "refs/heads/main"); got != next {
+		t.Fatalf("expected main at %s, got %s", next, got)
+	}
+
+	wt := filepath.Join(root, "wt")
+	threadWorktree(t, b, mirror, wt, "demo")
+	if err := client.UpdateBranch(ctx, mirror, "demo", "main"); err == nil {
+		t.Fatalf("expected updating a checked-out branch to fail")
+	}
+}
+
+func conformLog(t *testing.T, b Backend, root string) {
+	client := b.Client()
+	ctx := context.Background()
+	origin, mirror := originAndMirror(t, b, root)
+	wt := filepath.Join(root, "wt")
+	threadWorktree(t, b, mirror, wt, "demo")
+	first := b.Commit(t, wt, "first change", map[string]string{"a.txt": "a\n"})
+	second := b.Commit(t, wt, "second change\n\nWith a body.", map[string]string{"b.txt": "b\n"})
+	b.Commit(t, origin, "upstream", map[string]string{"up.txt": "up\n"})
+	if err := client.Fetch(ctx, mirror, "origin"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	commits, err := client.Log(ctx, wt, git.LogOptions{Exclude: "refs/remotes/origin/main"})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if len(commits) != 2 || commits[0].SHA != second || commits[1].SHA != first {
+		t.Fatalf("expected the two thread commits newest first, got %+v", commits)
+	}
+	head := commits[0]
+	if head.Subject != "second change" || head.Author != "Workset Tests" || head.AuthorEmail != "test@example.com" || head.Patch != "" {
+		t.Fatalf("unexpected commit %+v", head)
+	}
+	if !head.Time.After(commits[1].Time) {
+		t.Fatalf("expected author times in order, got %s then %s", commits[1].Time, head.Time)
+	}
+
+	if since, err := client.Log(ctx, wt, git.LogOptions{Exclude: "origin/main", Since: head.Time}); err != nil || len(since) != 1 || since[0].SHA != second {
+		t.Fatalf("expected --since to keep only the newest commit, got %+v err=%v", since, err)
+	}
+	if none, err := client.Log(ctx, wt, git.LogOptions{Exclude: "origin/main", Author: "^Nobody"}); err != nil || len(none) != 0 {
+		t.Fatalf("expected --author to filter every commit, got %+v err=%v", none, err)
+	}
+	patched, err := client.Log(ctx, wt, git.LogOptions{Ref: "demo", Exclude: "origin/main", Patch: true})
+	if err != nil || len(patched) != 2 {
+		t.Fatalf("log with patch: %+v err=%v", patched, err)
+	}
+	if patch := patched[0].Patch; !strings.Contains(patch, "b.txt") || !strings.Contains(patch, "+b") || strings.Contains(patch, "a.txt") {
+		t.Fatalf("expected patch of b.txt only, got %q", patch)
+	}
+	if _, err := client.Log(ctx, wt, git.LogOptions{Exclude: "no-such-ref"}); err == nil {
+		t.Fatalf("expected unknown ref to fail")
+	}
+}
@@ -0,0 +1,19 @@
+package gittest_test
+
+import (
+	"testing"
+
+	"github.com/strantalis/workset/internal/git/gittest"
+)
+
+func TestConformanceCLIClient(t *testing.T) {
+	gittest.RunConformance(t, func(tb testing.TB) gittest.Backend {
+		return gittest.NewCLIBackend(tb)
+	})
+}
+
+func TestConformanceFake(t *testing.T) {
+	gittest.RunConformance(t, func(testing.TB) gittest.Backend {
+		return gittest.New()
+	})
+}
@@ -0,0 +1,672 @@
+// Package gittest provides an in-memory git.Client for tests.
+//
+// Fake keeps commits, refs, remotes and worktrees in memory and lays down
+// just enough on disk (.git directories and files, bare HEAD/objects,
+// worktree admin directories) for code that inspects repositories with os
+// calls to see what it would see with real git. File contents are only
+// written to disk by the scripting methods; checkouts do not materialize
+// trees.
+//
+// RunConformance checks Fake against git.CLIClient so the two stay in step.
+package gittest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+// DefaultAuthor is the author recorded on scripted commits.
+const DefaultAuthor = "Workset Tests <test@example.com>"
+
+// Fake is an in-memory git.Client. The zero value is not usable; call New.
+type Fake struct {
+	mu       sync.Mutex
+	commits  map[string]*commit
+	repos    []*repo
+	urls     map[string]*repo
+	seq      int
+	now      time.Time
+	failures map[string]error
+	calls    []Call
+}
+
+// Call records one git.Client method invocation against Fake.
+type Call struct {
+	Method string
+	Path   string
+	Arg    string
+}
+
+type commit struct {
+	id      string
+	seq     int
+	parents []string
+	tree    map[string]string
+	author  string
+	message string
+	when    time.Time
+}
+
+type repo struct {
+	// path is the worktree root of a non-bare repo or the git dir of a bare
+	// one.
+	path      string
+	gitDir    string
+	bare      bool
+	head      string
+	refs      map[string]string
+	upstreams map[string]string
+	remotes   map[string][]string
+	worktrees []*worktree
+}
+
+type worktree struct {
+	repo     *repo
+	name     string
+	path     string
+	branch   string
+	detached string
+	changes  map[string]string
+	stashes  int
+	op       *integration
+}
+
+type integration struct {
+	mode      git.IntegrateMode
+	origHead  string
+	theirs    string
+	tip       string
+	pending   []string
+	tree      map[string]string
+	conflicts []string
+	saved     map[string]string
+}
+
+var _ git.Client = (*Fake)(nil)
+
+// New returns an empty Fake whose scripted commits are stamped one minute
+// apart starting at 2024-01-01T00:00:00Z.
+func New() *Fake {
+	return &Fake{
+		commits:  map[string]*commit{},
+		urls:     map[string]*repo{},
+		now:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		failures: map[string]error{},
+	}
+}
+
+// Client returns f; it lets Fake serve as a conformance Backend.
+func (f *Fake) Client() git.Client {
+	return f
+}
+
+// Fail makes method return err for path ("" matches every path) until Fail
+// is called again with a nil err.
+func (f *Fake) Fail(method, path string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := method + "\x00" + canonical(path)
+	if path == "" {
+		key = method + "\x00"
+	}
+	if err == nil {
+		delete(f.failures, key)
+		return
+	}
+	f.failures[key] = err
+}
+
+// Calls returns the recorded invocations of method, or all invocations when
+// method is empty.
+func (f *Fake) Calls(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []Call{}
+	for _, call := range f.calls {
+		if method == "" || call.Method == method {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+// RegisterURL makes url resolve to the fake repo at path for Clone,
+// CloneBare and Fetch. Local paths and file:// URLs resolve without
+// registration.
+func (f *Fake) RegisterURL(tb testing.TB, url, path string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, _ := f.locate(path)
+	if r == nil {
+		tb.Fatalf("gittest: register %s: no repo at %s", url, path)
+	}
+	f.urls[url] = r
+}
+
+// InitRepo creates a non-bare repo at path with branch checked out and one
+// commit adding README.md.
+func (f *Fake) InitRepo(tb testing.TB, path, branch string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, err := f.newRepo(path, branch, false)
+	if err != nil {
+		tb.Fatalf("gittest: init %s: %v", path, err)
+	}
+	readme := "# " + filepath.Base(path) + "\n"
+	if err := os.WriteFile(filepath.Join(r.path, "README.md"), []byte(readme), 0o644); err != nil {
+		tb.Fatalf("gittest: init %s: %v", path, err)
+	}
+	id := f.newCommit(nil, map[string]string{"README.md": readme}, "initial commit", DefaultAuthor)
+	r.refs["refs/heads/"+branch] = id
+}
+
+// Commit writes files into the worktree at path and commits them, together
+// with any uncommitted changes, on the checked-out branch (or detached
+// HEAD). It returns the new commit id.
+func (f *Fake) Commit(tb testing.TB, path, message string, files map[string]string) string {
+	tb.Helper()
+	return f.CommitAs(tb, path, DefaultAuthor, message, files)
+}
+
+// CommitAs is Commit with an explicit "Name <email>" author.
+func (f *Fake) CommitAs(tb testing.TB, path, author, message string, files map[string]string) string {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, wt := f.locate(path)
+	if wt == nil {
+		tb.Fatalf("gittest: commit: no worktree at %s", path)
+	}
+	if wt.op != nil {
+		tb.Fatalf("gittest: commit: %s is in the middle of a %s", path, wt.op.mode)
+	}
+	for name, content := range files {
+		if err := writeWorktreeFile(wt.path, name, content); err != nil {
+			tb.Fatalf("gittest: commit: %v", err)
+		}
+	}
+	head := f.headOf(r, wt)
+	tree := f.treeOf(head)
+	maps.Copy(tree, wt.changes)
+	maps.Copy(tree, files)
+	var parents []string
+	if head != "" {
+		parents = []string{head}
+	}
+	id := f.newCommit(parents, tree, message, author)
+	f.moveHead(r, wt, id)
+	wt.changes = map[string]string{}
+	return id
+}
+
+// WriteFile leaves an uncommitted change to name in the worktree at path,
+// which makes it dirty.
+func (f *Fake) WriteFile(tb testing.TB, path, name, content string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, wt := f.locate(path)
+	if wt == nil {
+		tb.Fatalf("gittest: write: no worktree at %s", path)
+	}
+	if err := writeWorktreeFile(wt.path, name, content); err != nil {
+		tb.Fatalf("gittest: write: %v", err)
+	}
+	wt.changes[name] = content
+}
+
+// Resolve settles a conflicted file in the worktree at path with content and
+// stages it, ready for ContinueIntegration.
+func (f *Fake) Resolve(tb testing.TB, path, name, content string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, wt := f.locate(path)
+	if wt == nil || wt.op == nil {
+		tb.Fatalf("gittest: resolve: no integration in progress at %s", path)
+	}
+	if err := writeWorktreeFile(wt.path, name, content); err != nil {
+		tb.Fatalf("gittest: resolve: %v", err)
+	}
+	wt.op.tree[name] = content
+	wt.op.conflicts = slices.DeleteFunc(wt.op.conflicts, func(file string) bool { return file == name })
+	delete(wt.changes, name)
+}
+
+// Branch creates branch name in the repo at repoPath pointing at start.
+func (f *Fake) Branch(tb testing.TB, repoPath, name, start string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		tb.Fatalf("gittest: branch: no repo at %s", repoPath)
+	}
+	id, ok := f.resolve(r, wt, start)
+	if !ok {
+		tb.Fatalf("gittest: branch: %q not found in %s", start, repoPath)
+	}
+	r.refs["refs/heads/"+name] = id
+}
+
+// Tag creates a lightweight tag in the repo at repoPath pointing at rev.
+func (f *Fake) Tag(tb testing.TB, repoPath, name, rev string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		tb.Fatalf("gittest: tag: no repo at %s", repoPath)
+	}
+	id, ok := f.resolve(r, wt, rev)
+	if !ok {
+		tb.Fatalf("gittest: tag: %q not found in %s", rev, repoPath)
+	}
+	r.refs["refs/tags/"+name] = id
+}
+
+// Rev resolves rev (a ref, short branch or remote-tracking name, commit id
+// or prefix, or HEAD, optionally followed by ~N or ^) in the repo at
+// repoPath.
+func (f *Fake) Rev(tb testing.TB, repoPath, rev string) string {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		tb.Fatalf("gittest: rev: no repo at %s", repoPath)
+	}
+	id, ok := f.resolve(r, wt, rev)
+	if !ok {
+		tb.Fatalf("gittest: rev: %q not found in %s", rev, repoPath)
+	}
+	return id
+}
+
+// Detach checks out rev as a detached HEAD in the worktree at path.
+func (f *Fake) Detach(tb testing.TB, path, rev string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, wt := f.locate(path)
+	if wt == nil {
+		tb.Fatalf("gittest: detach: no worktree at %s", path)
+	}
+	id, ok := f.resolve(r, wt, rev)
+	if !ok {
+		tb.Fatalf("gittest: detach: %q not found in %s", rev, path)
+	}
+	wt.branch = ""
+	wt.detached = id
+}
+
+// Stash moves the uncommitted changes in the worktree at path onto the
+// stash.
+func (f *Fake) Stash(tb testing.TB, path string) {
+	tb.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, wt := f.locate(path)
+	if wt == nil {
+		tb.Fatalf("gittest: stash: no worktree at %s", path)
+	}
+	if len(wt.changes) == 0 {
+		tb.Fatalf("gittest: stash: no local changes in %s", path)
+	}
+	wt.changes = map[string]string{}
+	wt.stashes++
+}
+
+func (f *Fake) record(method, path, arg string) error {
+	f.calls = append(f.calls, Call{Method: method, Path: path, Arg: arg})
+	if err, ok := f.failures[method+"\x00"+canonical(path)]; ok && path != "" {
+		return err
+	}
+	if err, ok := f.failures[method+"\x00"]; ok {
+		return err
+	}
+	return nil
+}
+
+func (f *Fake) newRepo(path, branch string, bare bool) (*repo, error) {
+	path = canonical(path)
+	if entries, err := os.ReadDir(path); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("destination path %q already exists and is not an empty directory", path)
+	}
+	r := &repo{
+		path:      path,
+		gitDir:    filepath.Join(path, ".git"),
+		bare:      bare,
+		head:      branch,
+		refs:      map[string]string{},
+		upstreams: map[string]string{},
+		remotes:   map[string][]string{},
+	}
+	if bare {
+		r.gitDir = path
+	}
+	if err := os.MkdirAll(filepath.Join(r.gitDir, "objects"), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(r.gitDir, "HEAD"), []byte("ref: refs/heads/"+branch+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	if !bare {
+		r.worktrees = []*worktree{{repo: r, path: path, branch: branch, changes: map[string]string{}}}
+	}
+	f.repos = append(f.repos, r)
+	return r, nil
+}
+
+func (f *Fake) newCommit(parents []string, tree map[string]string, message, author string) string {
+	f.seq++
+	when := f.now.Add(time.Duration(f.seq) * time.Minute)
+	sum := sha1.New()
+	_, _ = fmt.Fprintf(sum, "%d\x00%s\x00%s\x00%s", f.seq, strings.Join(parents, ","), message, author)
+	names := slices.Sorted(maps.Keys(tree))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(sum, "\x00%s\x00%s", name, tree[name])
+	}
+	id := hex.EncodeToString(sum.Sum(nil))
+	f.commits[id] = &commit{
+		id:      id,
+		seq:     f.seq,
+		parents: parents,
+		tree:    tree,
+		author:  author,
+		message: message,
+		when:    when,
+	}
+	return id
+}
+
+// locate finds the repo and worktree (nil for a bare repo) that path belongs
+// to, accepting worktree roots and subdirectories, a non-bare repo's .git
+// directory, and linked worktree admin directories. Worktrees whose
+// directory is gone are not found.
+func (f *Fake) locate(path string) (*repo, *worktree) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	for cur := canonical(path); ; {
+		if r, wt, ok := f.at(cur); ok {
+			return r, wt
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return nil, nil
+		}
+		cur = parent
+	}
+}
+
+func (f *Fake) at(path string) (*repo, *worktree, bool) {
+	for _, r := range f.repos {
+		if r.gitDir == path {
+			if !exists(r.gitDir) {
+				return nil, nil, true
+			}
+			if r.bare {
+				return r, nil, true
+			}
+			return r, r.worktrees[0], true
+		}
+		for _, wt := range r.worktrees {
+			admin := wt.path
+			if wt.name != "" {
+				admin = filepath.Join(r.gitDir, "worktrees", wt.name)
+			}
+			if wt.path == path || admin == path {
+				if !exists(wt.path) {
+					return nil, nil, true
+				}
+				return r, wt, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+func (f *Fake) source(url string) (*repo, error) {
+	if r, ok := f.urls[url]; ok {
+		return r, nil
+	}
+	path := strings.TrimPrefix(url, "file://")
+	if filepath.IsAbs(path) {
+		if r, _ := f.locate(path); r != nil {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("fatal: '%s' does not appear to be a git repository", url)
+}
+
+func (f *Fake) headOf(r *repo, wt *worktree) string {
+	if wt == nil {
+		return r.refs["refs/heads/"+r.head]
+	}
+	if wt.op != nil && wt.op.mode == git.IntegrateRebase {
+		return wt.op.tip
+	}
+	if wt.branch == "" {
+		return wt.detached
+	}
+	return r.refs["refs/heads/"+wt.branch]
+}
+
+func (f *Fake) moveHead(r *repo, wt *worktree, id string) {
+	if wt.branch == "" {
+		wt.detached = id
+		return
+	}
+	r.refs["refs/heads/"+wt.branch] = id
+}
+
+func (f *Fake) treeOf(id string) map[string]string {
+	if c, ok := f.commits[id]; ok {
+		return maps.Clone(c.tree)
+	}
+	return map[string]string{}
+}
+
+// resolve turns rev into a commit id the way rev-parse would for the refs
+// used in tests.
+func (f *Fake) resolve(r *repo, wt *worktree, rev string) (string, bool) {
+	rev = strings.TrimSuffix(strings.TrimSpace(rev), "^{commit}")
+	base, steps := splitAncestry(rev)
+	id, ok := f.resolveName(r, wt, base)
+	if !ok {
+		return "", false
+	}
+	for range steps {
+		c := f.commits[id]
+		if c == nil || len(c.parents) == 0 {
+			return "", false
+		}
+		id = c.parents[0]
+	}
+	return id, true
+}
+
+func (f *Fake) resolveName(r *repo, wt *worktree, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	if name == "HEAD" {
+		id := f.headOf(r, wt)
+		return id, id != ""
+	}
+	for _, ref := range []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name} {
+		if id, ok := r.refs[ref]; ok {
+			return id, true
+		}
+	}
+	if len(name) < 4 || strings.Trim(name, "0123456789abcdef") != "" {
+		return "", false
+	}
+	match := ""
+	for id := range f.commits {
+		if strings.HasPrefix(id, name) {
+			if match != "" {
+				return "", false
+			}
+			match = id
+		}
+	}
+	return match, match != ""
+}
+
+// splitAncestry splits trailing ~N and ^ suffixes off rev and returns the
+// number of first-parent steps they add up to.
+func splitAncestry(rev string) (string, int) {
+	steps := 0
+	for {
+		if strings.HasSuffix(rev, "^") {
+			rev = strings.TrimSuffix(rev, "^")
+			steps++
+			continue
+		}
+		idx := strings.LastIndex(rev, "~")
+		if idx < 0 {
+			return rev, steps
+		}
+		count := 1
+		if digits := rev[idx+1:]; digits != "" {
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				return rev, steps
+			}
+			count = n
+		}
+		rev = rev[:idx]
+		steps += count
+	}
+}
+
+// reachable returns every commit reachable from id, including id.
+func (f *Fake) reachable(id string) map[string]bool {
+	seen := map[string]bool{}
+	stack := []string{id}
+	for len(stack) > 0 {
+		next := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if next == "" || seen[next] {
+			continue
+		}
+		seen[next] = true
+		if c := f.commits[next]; c != nil {
+			stack = append(stack, c.parents...)
+		}
+	}
+	return seen
+}
+
+// only returns the commits reachable from include but not from exclude,
+// oldest first.
+func (f *Fake) only(include, exclude string) []*commit {
+	excluded := f.reachable(exclude)
+	out := []*commit{}
+	for id := range f.reachable(include) {
+		if !excluded[id] {
+			out = append(out, f.commits[id])
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].seq < out[j].seq })
+	return out
+}
+
+// mergeBase returns the newest common ancestor of left and right.
+func (f *Fake) mergeBase(left, right string) (string, bool) {
+	common := f.reachable(right)
+	best := ""
+	for id := range f.reachable(left) {
+		if common[id] && (best == "" || f.commits[id].seq > f.commits[best].seq) {
+			best = id
+		}
+	}
+	return best, best != ""
+}
+
+func (f *Fake) upstreamOf(r *repo, branch string) (string, bool) {
+	upstream, ok := r.upstreams[branch]
+	if !ok {
+		return "", false
+	}
+	_, exists := r.refs["refs/remotes/"+upstream]
+	return upstream, exists
+}
+
+func (r *repo) checkedOut(branch string) *worktree {
+	for _, wt := range r.worktrees {
+		if wt.branch == branch && exists(wt.path) {
+			return wt
+		}
+	}
+	return nil
+}
+
+func (r *repo) linked(name string) *worktree {
+	for _, wt := range r.worktrees {
+		if wt.name != "" && wt.name == name {
+			return wt
+		}
+	}
+	return nil
+}
+
+func writeWorktreeFile(root, name, content string) error {
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// canonical makes path absolute with symlinks resolved as far as it exists,
+// so temp dirs behind symlinks compare equal.
+func canonical(path string) string {
+	if path == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	rest := ""
+	for dir := abs; ; {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+var (
+	errNotRepo    = errors.New("fatal: not a git repository (or any of the parent directories): .git")
+	errBareStatus = errors.New("fatal: this operation must be run in a work tree")
+)
@@ -0,0 +1,708 @@
+package gittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+func (f *Fake) Clone(_ context.Context, url, path, remoteName string) error {
+	return f.clone(url, path, remoteName, false)
+}
+
+func (f *Fake) CloneBare(_ context.Context, url, path, remoteName string) error {
+	return f.clone(url, path, remoteName, true)
+}
+
+func (f *Fake) clone(url, path, remoteName string, bare bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	method := "Clone"
+	if bare {
+		method = "CloneBare"
+	}
+	if err := f.record(method, path, url); err != nil {
+		return err
+	}
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+	src, err := f.source(url)
+	if err != nil {
+		return err
+	}
+	branch := src.defaultBranch()
+	r, err := f.newRepo(path, branch, bare)
+	if err != nil {
+		return err
+	}
+	r.remotes[remoteName] = []string{url}
+	f.fetchInto(r, src, remoteName)
+	if id, ok := src.refs["refs/heads/"+branch]; ok {
+		r.refs["refs/heads/"+branch] = id
+		if !bare {
+			r.upstreams[branch] = remoteName + "/" + branch
+		}
+	}
+	return nil
+}
+
+func (r *repo) defaultBranch() string {
+	if r.bare || len(r.worktrees) == 0 {
+		return r.head
+	}
+	if branch := r.worktrees[0].branch; branch != "" {
+		return branch
+	}
+	return r.head
+}
+
+func (f *Fake) fetchInto(r, src *repo, remoteName string) {
+	for ref, id := range src.refs {
+		if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			r.refs["refs/remotes/"+remoteName+"/"+branch] = id
+		}
+	}
+}
+
+func (f *Fake) AddRemote(path, name, url string) error {
+	if name == "" {
+		return errors.New("remote name required")
+	}
+	if url == "" {
+		return errors.New("remote url required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("AddRemote", path, name); err != nil {
+		return err
+	}
+	r, _ := f.locate(path)
+	if r == nil {
+		return errNotRepo
+	}
+	if _, ok := r.remotes[name]; !ok {
+		r.remotes[name] = []string{url}
+	}
+	return nil
+}
+
+func (f *Fake) RemoteNames(repoPath string) ([]string, error) {
+	if repoPath == "" {
+		return nil, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("RemoteNames", repoPath, ""); err != nil {
+		return nil, err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return nil, errNotRepo
+	}
+	return slices.Sorted(maps.Keys(r.remotes)), nil
+}
+
+func (f *Fake) RemoteURLs(repoPath, remoteName string) ([]string, error) {
+	if repoPath == "" {
+		return nil, errors.New("repo path required")
+	}
+	if remoteName == "" {
+		return nil, errors.New("remote name required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("RemoteURLs", repoPath, remoteName); err != nil {
+		return nil, err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return nil, errNotRepo
+	}
+	urls, ok := r.remotes[remoteName]
+	if !ok {
+		return nil, fmt.Errorf("error: No such remote '%s'", remoteName)
+	}
+	if len(urls) == 0 {
+		return nil, errors.New("remote has no URLs configured")
+	}
+	return slices.Clone(urls), nil
+}
+
+func (f *Fake) RemoteExists(repoPath, remoteName string) (bool, error) {
+	if repoPath == "" || remoteName == "" {
+		return false, errors.New("repo path and remote name required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("RemoteExists", repoPath, remoteName); err != nil {
+		return false, err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return false, nil
+	}
+	_, ok := r.remotes[remoteName]
+	return ok, nil
+}
+
+func (f *Fake) ReferenceExists(_ context.Context, repoPath, ref string) (bool, error) {
+	if repoPath == "" || ref == "" {
+		return false, errors.New("repo path and ref required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ReferenceExists", repoPath, ref); err != nil {
+		return false, err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return false, errNotRepo
+	}
+	_, ok := r.refs[ref]
+	return ok, nil
+}
+
+func (f *Fake) Fetch(_ context.Context, repoPath, remoteName string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	if remoteName == "" {
+		return errors.New("remote name required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Fetch", repoPath, remoteName); err != nil {
+		return err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	urls, ok := r.remotes[remoteName]
+	if !ok || len(urls) == 0 {
+		return fmt.Errorf("fatal: '%s' does not appear to be a git repository", remoteName)
+	}
+	src, err := f.source(urls[0])
+	if err != nil {
+		return err
+	}
+	f.fetchInto(r, src, remoteName)
+	return nil
+}
+
+func (f *Fake) UpdateBranch(_ context.Context, repoPath, branchName, targetRef string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	if branchName == "" {
+		return errors.New("branch name required")
+	}
+	if targetRef == "" {
+		return errors.New("target ref required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("UpdateBranch", repoPath, branchName); err != nil {
+		return err
+	}
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	id, ok := f.resolve(r, wt, targetRef)
+	if !ok {
+		return fmt.Errorf("fatal: not a valid object name: '%s'", targetRef)
+	}
+	if holder := r.checkedOut(branchName); holder != nil {
+		return fmt.Errorf("fatal: cannot force update the branch '%s' used by worktree at '%s'", branchName, holder.path)
+	}
+	r.refs["refs/heads/"+branchName] = id
+	return nil
+}
+
+func (f *Fake) Status(path string) (git.StatusSummary, error) {
+	if path == "" {
+		return git.StatusSummary{}, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Status", path, ""); err != nil {
+		return git.StatusSummary{}, err
+	}
+	r, wt := f.locate(path)
+	if r != nil && wt == nil {
+		return git.StatusSummary{}, errBareStatus
+	}
+	if wt == nil {
+		return git.StatusSummary{Missing: true}, nil
+	}
+	return git.StatusSummary{Dirty: wt.dirty()}, nil
+}
+
+func (wt *worktree) dirty() bool {
+	return len(wt.changes) > 0 || (wt.op != nil && len(wt.op.conflicts) > 0)
+}
+
+func (f *Fake) StatusDetail(_ context.Context, path, baseRef string) (git.StatusDetail, error) {
+	if path == "" {
+		return git.StatusDetail{}, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("StatusDetail", path, baseRef); err != nil {
+		return git.StatusDetail{}, err
+	}
+	r, wt := f.locate(path)
+	if r != nil && wt == nil {
+		return git.StatusDetail{}, errBareStatus
+	}
+	if wt == nil {
+		return git.StatusDetail{StatusSummary: git.StatusSummary{Missing: true}}, nil
+	}
+	detail := git.StatusDetail{
+		StatusSummary: git.StatusSummary{Dirty: wt.dirty()},
+		Stashes:       wt.stashes,
+	}
+	head := f.headOf(r, wt)
+	rebasing := wt.op != nil && wt.op.mode == git.IntegrateRebase
+	if wt.branch == "" || rebasing {
+		detail.Detached = true
+	} else {
+		detail.Branch = wt.branch
+		if upstream, ok := f.upstreamOf(r, wt.branch); ok {
+			detail.Upstream = upstream
+			if head != "" {
+				upstreamID := r.refs["refs/remotes/"+upstream]
+				detail.Ahead = len(f.only(head, upstreamID))
+				detail.Behind = len(f.only(upstreamID, head))
+			}
+		} else if upstream, ok := r.upstreams[wt.branch]; ok {
+			detail.Upstream = upstream
+		}
+	}
+	if wt.op != nil {
+		detail.Conflicts = slices.Sorted(slices.Values(wt.op.conflicts))
+	}
+	if baseRef != "" && head != "" {
+		if baseID, ok := f.resolve(r, wt, baseRef); ok {
+			detail.BaseRef = baseRef
+			detail.BaseAhead = len(f.only(head, baseID))
+			detail.BaseBehind = len(f.only(baseID, head))
+		}
+	}
+	return detail, nil
+}
+
+func (f *Fake) IsRepo(path string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("IsRepo", path, ""); err != nil {
+		return false, err
+	}
+	_, wt := f.locate(path)
+	return wt != nil, nil
+}
+
+func (f *Fake) IsAncestor(repoPath, ancestorRef, descendantRef string) (bool, error) {
+	if ancestorRef == "" || descendantRef == "" {
+		return false, errors.New("ancestor and descendant refs required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("IsAncestor", repoPath, ancestorRef+".."+descendantRef); err != nil {
+		return false, err
+	}
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		return false, errNotRepo
+	}
+	ancestor, descendant, err := f.resolvePair(r, wt, ancestorRef, descendantRef)
+	if err != nil {
+		return false, err
+	}
+	return f.reachable(descendant)[ancestor], nil
+}
+
+func (f *Fake) resolvePair(r *repo, wt *worktree, left, right string) (string, string, error) {
+	leftID, ok := f.resolve(r, wt, left)
+	if !ok {
+		return "", "", fmt.Errorf("fatal: Not a valid object name %s", left)
+	}
+	rightID, ok := f.resolve(r, wt, right)
+	if !ok {
+		return "", "", fmt.Errorf("fatal: Not a valid object name %s", right)
+	}
+	return leftID, rightID, nil
+}
+
+// IsContentMerged reports branchRef merged into baseRef when it is an
+// ancestor, the trees match, every branch commit's patch was cherry-picked,
+// or every file the branch changed already has the branch's content in
+// baseRef (a squash merge).
+func (f *Fake) IsContentMerged(repoPath, branchRef, baseRef string) (bool, error) {
+	if repoPath == "" {
+		return false, errors.New("repo path required")
+	}
+	if branchRef == "" || baseRef == "" {
+		return false, errors.New("branch and base refs required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("IsContentMerged", repoPath, branchRef+"->"+baseRef); err != nil {
+		return false, err
+	}
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		return false, errNotRepo
+	}
+	branch, base, err := f.resolvePair(r, wt, branchRef, baseRef)
+	if err != nil {
+		return false, err
+	}
+	if f.reachable(base)[branch] || maps.Equal(f.commits[branch].tree, f.commits[base].tree) {
+		return true, nil
+	}
+	if f.patchesApplied(f.only(branch, base), f.only(base, branch)) {
+		return true, nil
+	}
+	mergeBase, ok := f.mergeBase(branch, base)
+	if !ok {
+		return false, nil
+	}
+	before, after, merged := f.commits[mergeBase].tree, f.commits[branch].tree, f.commits[base].tree
+	for _, name := range changedFiles(before, after) {
+		want, wantOK := after[name]
+		got, gotOK := merged[name]
+		if want != got || wantOK != gotOK {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (f *Fake) patchesApplied(branchOnly, baseOnly []*commit) bool {
+	if len(branchOnly) == 0 {
+		return true
+	}
+	applied := map[string]bool{}
+	for _, c := range baseOnly {
+		applied[f.patchID(c)] = true
+	}
+	for _, c := range branchOnly {
+		if len(c.parents) > 1 || !applied[f.patchID(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+// patchID identifies c's change relative to its first parent, independent of
+// where it was applied.
+func (f *Fake) patchID(c *commit) string {
+	before := map[string]string{}
+	if len(c.parents) > 0 {
+		before = f.commits[c.parents[0]].tree
+	}
+	var b strings.Builder
+	for _, name := range changedFiles(before, c.tree) {
+		old, oldOK := before[name]
+		next, nextOK := c.tree[name]
+		fmt.Fprintf(&b, "%s\x00%t%q\x00%t%q\x00", name, oldOK, old, nextOK, next)
+	}
+	return b.String()
+}
+
+func (f *Fake) CurrentBranch(repoPath string) (string, bool, error) {
+	if repoPath == "" {
+		return "", false, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("CurrentBranch", repoPath, ""); err != nil {
+		return "", false, err
+	}
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		return "", false, errNotRepo
+	}
+	if f.headOf(r, wt) == "" {
+		return "", false, nil
+	}
+	if wt == nil {
+		return r.head, true, nil
+	}
+	if wt.branch == "" || (wt.op != nil && wt.op.mode == git.IntegrateRebase) {
+		return "", false, nil
+	}
+	return wt.branch, true, nil
+}
+
+func (f *Fake) WorktreeAdd(_ context.Context, opts git.WorktreeAddOptions) error {
+	if opts.RepoPath == "" {
+		return errors.New("repo path required")
+	}
+	if opts.WorktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	if opts.BranchName == "" {
+		return errors.New("branch name required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WorktreeAdd", opts.RepoPath, opts.WorktreePath); err != nil {
+		return err
+	}
+	r, wt := f.locate(opts.RepoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	if err := os.MkdirAll(opts.WorktreePath, 0o755); err != nil {
+		return err
+	}
+	path := canonical(opts.WorktreePath)
+	if entries, err := os.ReadDir(path); err == nil && len(entries) > 0 {
+		return fmt.Errorf("fatal: '%s' already exists", opts.WorktreePath)
+	}
+
+	startRef := ""
+	if opts.StartBranch != "" {
+		if _, ok := r.refs["refs/heads/"+opts.StartBranch]; ok {
+			startRef = opts.StartBranch
+		}
+	}
+	if opts.StartRemote != "" && opts.StartBranch != "" {
+		if _, ok := r.refs["refs/remotes/"+opts.StartRemote+"/"+opts.StartBranch]; ok {
+			startRef = opts.StartRemote + "/" + opts.StartBranch
+		}
+	}
+	if opts.StartRef != "" {
+		if _, ok := f.resolve(r, wt, opts.StartRef); !ok {
+			return fmt.Errorf("start point %q not found", opts.StartRef)
+		}
+		startRef = opts.StartRef
+	}
+
+	branchRef := "refs/heads/" + opts.BranchName
+	if _, ok := r.refs[branchRef]; ok {
+		if holder := r.checkedOut(opts.BranchName); holder != nil && !opts.ForceCheckout {
+			return fmt.Errorf("fatal: '%s' is already checked out at '%s'", opts.BranchName, holder.path)
+		}
+	} else {
+		if startRef == "" {
+			startRef = "HEAD"
+		}
+		id, ok := f.resolve(r, wt, startRef)
+		if !ok {
+			return fmt.Errorf("fatal: invalid reference: %s", startRef)
+		}
+		r.refs[branchRef] = id
+		if _, remote := r.refs["refs/remotes/"+startRef]; remote {
+			r.upstreams[opts.BranchName] = startRef
+		}
+	}
+	return f.linkWorktree(r, path, opts.BranchName)
+}
+
+func (f *Fake) linkWorktree(r *repo, path, branch string) error {
+	name := filepath.Base(path)
+	for i := 1; r.linked(name) != nil || exists(filepath.Join(r.gitDir, "worktrees", name)); i++ {
+		name = filepath.Base(path) + strconv.Itoa(i)
+	}
+	admin := filepath.Join(r.gitDir, "worktrees", name)
+	if err := os.MkdirAll(admin, 0o755); err != nil {
+		return err
+	}
+	files := map[string]string{
+		filepath.Join(admin, "gitdir"):    filepath.Join(path, ".git") + "\n",
+		filepath.Join(admin, "commondir"): "../..\n",
+		filepath.Join(admin, "HEAD"):      "ref: refs/heads/" + branch + "\n",
+		filepath.Join(path, ".git"):       "gitdir: " + admin + "\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	r.worktrees = append(r.worktrees, &worktree{
+		repo:    r,
+		name:    name,
+		path:    path,
+		branch:  branch,
+		changes: map[string]string{},
+	})
+	return nil
+}
+
+func (f *Fake) WorktreeRemove(opts git.WorktreeRemoveOptions) error {
+	if opts.RepoPath == "" {
+		return errors.New("repo path required")
+	}
+	if opts.WorktreeName == "" {
+		return errors.New("worktree name required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WorktreeRemove", opts.RepoPath, opts.WorktreeName); err != nil {
+		return err
+	}
+	r, _ := f.locate(opts.RepoPath)
+	if r == nil {
+		return git.ErrWorktreeNotFound
+	}
+	wt := r.linked(opts.WorktreeName)
+	if wt == nil {
+		return git.ErrWorktreeNotFound
+	}
+	if err := os.RemoveAll(wt.path); err != nil {
+		return err
+	}
+	return f.unlink(r, wt)
+}
+
+func (f *Fake) unlink(r *repo, wt *worktree) error {
+	r.worktrees = slices.DeleteFunc(r.worktrees, func(candidate *worktree) bool { return candidate == wt })
+	return os.RemoveAll(filepath.Join(r.gitDir, "worktrees", wt.name))
+}
+
+func (f *Fake) WorktreeMove(_ context.Context, repoPath, worktreePath, newPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	if worktreePath == "" || newPath == "" {
+		return errors.New("worktree path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WorktreeMove", repoPath, newPath); err != nil {
+		return err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	_, wt := f.locate(worktreePath)
+	if wt == nil || wt.repo != r || wt.name == "" || wt.path != canonical(worktreePath) {
+		return fmt.Errorf("fatal: '%s' is not a working tree", worktreePath)
+	}
+	if exists(newPath) {
+		return fmt.Errorf("fatal: '%s' already exists", newPath)
+	}
+	if err := os.Rename(wt.path, newPath); err != nil {
+		return fmt.Errorf("fatal: failed to move '%s' to '%s': %w", worktreePath, newPath, err)
+	}
+	return f.relink(wt, canonical(newPath))
+}
+
+func (f *Fake) WorktreeRepair(_ context.Context, worktreePath string) error {
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WorktreeRepair", worktreePath, ""); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return errNotRepo
+	}
+	admin, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return errNotRepo
+	}
+	admin = canonical(strings.TrimSpace(admin))
+	for _, r := range f.repos {
+		for _, wt := range r.worktrees {
+			if wt.name != "" && filepath.Join(r.gitDir, "worktrees", wt.name) == admin {
+				return f.relink(wt, canonical(worktreePath))
+			}
+		}
+	}
+	return errNotRepo
+}
+
+func (f *Fake) relink(wt *worktree, path string) error {
+	wt.path = path
+	admin := filepath.Join(wt.repo.gitDir, "worktrees", wt.name)
+	return os.WriteFile(filepath.Join(admin, "gitdir"), []byte(filepath.Join(path, ".git")+"\n"), 0o644)
+}
+
+func (f *Fake) WorktreeList(repoPath string) ([]string, error) {
+	if repoPath == "" {
+		return nil, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("WorktreeList", repoPath, ""); err != nil {
+		return nil, err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return nil, git.ErrWorktreeNotFound
+	}
+	names := []string{}
+	for _, wt := range r.worktrees {
+		if wt.name != "" {
+			names = append(names, wt.name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// GC prunes linked worktrees whose directory is gone, like git worktree
+// prune; there is nothing to repack.
+func (f *Fake) GC(_ context.Context, repoPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("GC", repoPath, ""); err != nil {
+		return err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	for _, wt := range slices.Clone(r.worktrees) {
+		if wt.name != "" && !exists(wt.path) {
+			if err := f.unlink(r, wt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Fsck checks that every ref points at a known commit; use Fail to script
+// corruption.
+func (f *Fake) Fsck(_ context.Context, repoPath string) error {
+	if repoPath == "" {
+		return errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Fsck", repoPath, ""); err != nil {
+		return err
+	}
+	r, _ := f.locate(repoPath)
+	if r == nil {
+		return errNotRepo
+	}
+	for ref, id := range r.refs {
+		if _, ok := f.commits[id]; !ok {
+			return fmt.Errorf("broken link from %s to %s", ref, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package gittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+// Integrate rebases or merges with a per-file three-way merge: a file
+// conflicts when both sides changed it to different content.
+func (f *Fake) Integrate(_ context.Context, path, ref string, mode git.IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if ref == "" {
+		return errors.New("ref required")
+	}
+	if mode != git.IntegrateRebase && mode != git.IntegrateMerge {
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Integrate", path, string(mode)+" "+ref); err != nil {
+		return err
+	}
+	r, wt := f.locate(path)
+	if wt == nil {
+		return errNotRepo
+	}
+	if wt.op != nil {
+		return fmt.Errorf("fatal: a %s is already in progress", wt.op.mode)
+	}
+	theirs, ok := f.resolve(r, wt, ref)
+	if !ok {
+		return fmt.Errorf("fatal: invalid upstream '%s'", ref)
+	}
+	head := f.headOf(r, wt)
+	if head == "" {
+		return errors.New("fatal: HEAD does not point to a commit")
+	}
+	if err := f.checkLocalChanges(wt, head, theirs, mode); err != nil {
+		return err
+	}
+	if f.reachable(head)[theirs] {
+		return nil
+	}
+	if f.reachable(theirs)[head] {
+		f.moveHead(r, wt, theirs)
+		return nil
+	}
+	op := &integration{
+		mode:     mode,
+		origHead: head,
+		theirs:   theirs,
+		tip:      theirs,
+		saved:    maps.Clone(wt.changes),
+	}
+	if mode == git.IntegrateMerge {
+		mergeBase, _ := f.mergeBase(head, theirs)
+		op.tree, op.conflicts = merge3(f.treeOf(mergeBase), f.treeOf(head), f.treeOf(theirs))
+		if len(op.conflicts) > 0 {
+			return f.stop(wt, op)
+		}
+		f.moveHead(r, wt, f.newCommit([]string{head, theirs}, op.tree, "Merge "+ref, DefaultAuthor))
+		return nil
+	}
+	applied := map[string]bool{}
+	for _, c := range f.only(theirs, head) {
+		applied[f.patchID(c)] = true
+	}
+	for _, c := range f.only(head, theirs) {
+		if len(c.parents) < 2 && !applied[f.patchID(c)] {
+			op.pending = append(op.pending, c.id)
+		}
+	}
+	return f.replay(r, wt, op)
+}
+
+// checkLocalChanges refuses to start when uncommitted changes would be
+// touched: any tracked change blocks a rebase, and a merge is blocked by
+// changes to files the merge brings in.
+func (f *Fake) checkLocalChanges(wt *worktree, head, theirs string, mode git.IntegrateMode) error {
+	headTree, theirTree := f.commits[head].tree, f.commits[theirs].tree
+	incoming := changedFiles(headTree, theirTree)
+	for name := range wt.changes {
+		_, tracked := headTree[name]
+		switch {
+		case mode == git.IntegrateRebase && tracked:
+			return errors.New("error: cannot rebase: You have unstaged changes.")
+		case slices.Contains(incoming, name):
+			return fmt.Errorf("error: Your local changes to the following files would be overwritten by %s:\n\t%s", mode, name)
+		}
+	}
+	return nil
+}
+
+// replay applies op.pending onto op.tip one commit at a time, stopping on
+// the first conflict.
+func (f *Fake) replay(r *repo, wt *worktree, op *integration) error {
+	for len(op.pending) > 0 {
+		c := f.commits[op.pending[0]]
+		tree, conflicts := merge3(f.treeOf(c.parents[0]), f.treeOf(op.tip), c.tree)
+		op.tree = tree
+		if len(conflicts) > 0 {
+			op.conflicts = conflicts
+			return f.stop(wt, op)
+		}
+		op.tip = f.newCommit([]string{op.tip}, tree, c.message, c.author)
+		op.pending = op.pending[1:]
+	}
+	wt.op = nil
+	f.moveHead(r, wt, op.tip)
+	return nil
+}
+
+func (f *Fake) stop(wt *worktree, op *integration) error {
+	wt.op = op
+	for _, name := range op.conflicts {
+		wt.changes[name] = "<<<<<<< ours\n=======\n>>>>>>> theirs\n"
+	}
+	return git.ConflictError{Mode: op.mode, Files: slices.Clone(op.conflicts)}
+}
+
+func (f *Fake) IntegrationInProgress(_ context.Context, path string) (git.IntegrateMode, bool, error) {
+	if path == "" {
+		return "", false, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("IntegrationInProgress", path, ""); err != nil {
+		return "", false, err
+	}
+	_, wt := f.locate(path)
+	if wt == nil {
+		return "", false, errNotRepo
+	}
+	if wt.op == nil {
+		return "", false, nil
+	}
+	return wt.op.mode, true, nil
+}
+
+func (f *Fake) ContinueIntegration(_ context.Context, path string, mode git.IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if mode != git.IntegrateRebase && mode != git.IntegrateMerge {
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("ContinueIntegration", path, string(mode)); err != nil {
+		return err
+	}
+	r, wt := f.locate(path)
+	if wt == nil {
+		return errNotRepo
+	}
+	op := wt.op
+	if op == nil || op.mode != mode {
+		return fmt.Errorf("fatal: no %s in progress", mode)
+	}
+	if len(op.conflicts) > 0 {
+		return git.ConflictError{Mode: mode, Files: slices.Clone(op.conflicts)}
+	}
+	if mode == git.IntegrateMerge {
+		wt.op = nil
+		f.moveHead(r, wt, f.newCommit([]string{op.origHead, op.theirs}, op.tree, "Merge commit", DefaultAuthor))
+		return nil
+	}
+	c := f.commits[op.pending[0]]
+	op.tip = f.newCommit([]string{op.tip}, op.tree, c.message, c.author)
+	op.pending = op.pending[1:]
+	return f.replay(r, wt, op)
+}
+
+func (f *Fake) AbortIntegration(_ context.Context, path string, mode git.IntegrateMode) error {
+	if path == "" {
+		return errors.New("repo path required")
+	}
+	if mode != git.IntegrateRebase && mode != git.IntegrateMerge {
+		return fmt.Errorf("unsupported integrate mode %q", mode)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("AbortIntegration", path, string(mode)); err != nil {
+		return err
+	}
+	r, wt := f.locate(path)
+	if wt == nil {
+		return errNotRepo
+	}
+	op := wt.op
+	if op == nil || op.mode != mode {
+		return fmt.Errorf("fatal: no %s in progress", mode)
+	}
+	wt.op = nil
+	wt.changes = op.saved
+	f.moveHead(r, wt, op.origHead)
+	return nil
+}
+
+// merge3 merges the changes from base to ours and from base to theirs file
+// by file, returning the merged tree and the sorted files both sides changed
+// differently. Conflicted files keep ours in the tree.
+func merge3(base, ours, theirs map[string]string) (map[string]string, []string) {
+	merged := maps.Clone(ours)
+	conflicts := []string{}
+	for _, name := range changedFiles(base, theirs) {
+		baseContent, baseOK := base[name]
+		ourContent, ourOK := ours[name]
+		theirContent, theirOK := theirs[name]
+		switch {
+		case ourOK == theirOK && ourContent == theirContent:
+		case ourOK == baseOK && ourContent == baseContent:
+			if theirOK {
+				merged[name] = theirContent
+			} else {
+				delete(merged, name)
+			}
+		default:
+			conflicts = append(conflicts, name)
+		}
+	}
+	return merged, conflicts
+}
+
+// changedFiles returns the sorted names whose presence or content differs
+// between two trees.
+func changedFiles(before, after map[string]string) []string {
+	names := []string{}
+	for name, content := range after {
+		if old, ok := before[name]; !ok || old != content {
+			names = append(names, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
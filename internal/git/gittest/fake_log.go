@@ -0,0 +1,103 @@
+package gittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+func (f *Fake) Log(_ context.Context, repoPath string, opts git.LogOptions) ([]git.Commit, error) {
+	if repoPath == "" {
+		return nil, errors.New("repo path required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("Log", repoPath, opts.Ref); err != nil {
+		return nil, err
+	}
+	r, wt := f.locate(repoPath)
+	if r == nil {
+		return nil, errNotRepo
+	}
+	ref := opts.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	head, ok := f.resolve(r, wt, ref)
+	if !ok {
+		return nil, fmt.Errorf("fatal: ambiguous argument '%s': unknown revision", ref)
+	}
+	exclude := ""
+	if opts.Exclude != "" {
+		if exclude, ok = f.resolve(r, wt, opts.Exclude); !ok {
+			return nil, fmt.Errorf("fatal: ambiguous argument '%s': unknown revision", opts.Exclude)
+		}
+	}
+	var author *regexp.Regexp
+	if opts.Author != "" {
+		var err error
+		if author, err = regexp.Compile(opts.Author); err != nil {
+			return nil, fmt.Errorf("fatal: invalid --author pattern: %w", err)
+		}
+	}
+	selected := f.only(head, exclude)
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].seq > selected[j].seq })
+	commits := []git.Commit{}
+	for _, c := range selected {
+		if !opts.Since.IsZero() && c.when.Before(opts.Since) {
+			continue
+		}
+		if author != nil && !author.MatchString(c.author) {
+			continue
+		}
+		name, email := authorParts(c.author)
+		subject, _, _ := strings.Cut(c.message, "\n")
+		entry := git.Commit{
+			SHA:         c.id,
+			Author:      name,
+			AuthorEmail: email,
+			Time:        c.when,
+			Subject:     subject,
+		}
+		if opts.Patch && len(c.parents) < 2 {
+			entry.Patch = f.patch(c)
+		}
+		commits = append(commits, entry)
+	}
+	return commits, nil
+}
+
+// patch renders c's change against its first parent as a minimal unified
+// diff: every old line removed, every new line added.
+func (f *Fake) patch(c *commit) string {
+	before := map[string]string{}
+	if len(c.parents) > 0 {
+		before = f.commits[c.parents[0]].tree
+	}
+	var b strings.Builder
+	for _, name := range changedFiles(before, c.tree) {
+		old, oldOK := before[name]
+		next, nextOK := c.tree[name]
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", name, name)
+		from, to := "a/"+name, "b/"+name
+		if !oldOK {
+			from = "/dev/null"
+		}
+		if !nextOK {
+			to = "/dev/null"
+		}
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+		for line := range strings.Lines(old) {
+			b.WriteString("-" + strings.TrimSuffix(line, "\n") + "\n")
+		}
+		for line := range strings.Lines(next) {
+			b.WriteString("+" + strings.TrimSuffix(line, "\n") + "\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
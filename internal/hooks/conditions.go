@@ -0,0 +1,117 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultRetryBackoff = time.Second
+
+// skipReason evaluates a hook's when clause and returns why it should not run,
+// or an empty string when every clause matches.
+func skipReason(when *When, ctx Context) string {
+	if when == nil {
+		return ""
+	}
+	tokens := ctx.TokenMap()
+	if len(when.Repos) > 0 && !slices.Contains(when.Repos, ctx.RepoName) {
+		return fmt.Sprintf("repo %s not in %s", ctx.RepoName, strings.Join(when.Repos, ", "))
+	}
+	if len(when.Branch) > 0 {
+		matched := false
+		for _, pattern := range when.Branch {
+			if ok, err := path.Match(pattern, ctx.Branch); err == nil && ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("branch %s does not match %s", ctx.Branch, strings.Join(when.Branch, ", "))
+		}
+	}
+	for _, file := range when.FileExists {
+		resolved := interpolateValue(file, tokens)
+		if !filepath.IsAbs(resolved) {
+			resolved = filepath.Join(ctx.RepoPath, resolved)
+		}
+		if _, err := os.Stat(resolved); err != nil {
+			return fmt.Sprintf("file %s does not exist", file)
+		}
+	}
+	return ""
+}
+
+func validateWhen(hook Hook) error {
+	if hook.When == nil {
+		return nil
+	}
+	for _, pattern := range hook.When.Branch {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("hook %s: invalid when.branch pattern %q", hook.ID, pattern)
+		}
+	}
+	return nil
+}
+
+func parseHookDuration(hookID, field, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("hook %s: invalid %s %q", hookID, field, value)
+	}
+	return duration, nil
+}
+
+// runAttempts runs a hook command up to 1+hook.Retries times, enforcing the
+// per-attempt timeout and backing off between attempts. Attempt failures are
+// written to the log so retries are visible after the fact.
+func runAttempts(ctx context.Context, runner Runner, hook Hook, req RunRequest, log func(format string, args ...any)) (attempts int, timedOut bool, err error) {
+	timeout, _ := parseHookDuration(hook.ID, "timeout", hook.Timeout)
+	backoff, _ := parseHookDuration(hook.ID, "retry_backoff", hook.RetryBackoff)
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxAttempts := 1 + max(hook.Retries, 0)
+	for attempts = 1; attempts <= maxAttempts; attempts++ {
+		if attempts > 1 {
+			log("retrying in %s (attempt %d/%d)\n", backoff, attempts, maxAttempts)
+			select {
+			case <-ctx.Done():
+				return attempts - 1, timedOut, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		attemptCtx := ctx
+		cancel := func() {}
+		if timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err = runner.Run(attemptCtx, req)
+		timedOut = err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if timedOut {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		if err == nil {
+			return attempts, false, nil
+		}
+		if attempts < maxAttempts {
+			log("attempt %d failed: %s\n", attempts, err)
+		}
+		if ctx.Err() != nil {
+			return attempts, timedOut, err
+		}
+	}
+	return maxAttempts, timedOut, err
+}
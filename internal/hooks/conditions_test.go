@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type flakyRunner struct {
+	calls    int
+	failures int
+	block    bool
+}
+
+func (r *flakyRunner) Run(ctx context.Context, _ RunRequest) error {
+	r.calls++
+	if r.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if r.calls <= r.failures {
+		return errors.New("flaky")
+	}
+	return nil
+}
+
+func runSingleHook(t *testing.T, runner Runner, hook Hook, ctxPayload Context) (RunReport, error) {
+	t.Helper()
+	hook.ID = "bootstrap"
+	hook.On = []Event{EventWorktreeCreated}
+	hook.Run = []string{"make", "bootstrap"}
+	if ctxPayload.RepoPath == "" {
+		ctxPayload.RepoPath = t.TempDir()
+	}
+	engine := Engine{Runner: runner}
+	return engine.Run(context.Background(), RunInput{
+		Event:   EventWorktreeCreated,
+		Hooks:   []Hook{hook},
+		LogRoot: filepath.Join(t.TempDir(), "logs"),
+		Context: ctxPayload,
+	})
+}
+
+func readHookLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	return string(data)
+}
+
+func TestEngineRetriesFailedHook(t *testing.T) {
+	runner := &flakyRunner{failures: 2}
+	report, err := runSingleHook(t, runner, Hook{Retries: 2, RetryBackoff: "1ms"}, Context{})
+	if err != nil {
+		t.Fatalf("run hooks: %v", err)
+	}
+	result := report.Results[0]
+	if result.Status != RunStatusOK || result.Attempts != 3 || runner.calls != 3 {
+		t.Fatalf("unexpected result: %+v calls=%d", result, runner.calls)
+	}
+	log := readHookLog(t, result.LogPath)
+	if !strings.Contains(log, "retries: 2") || !strings.Contains(log, "attempt 2 failed: flaky") || !strings.Contains(log, "attempts: 3") {
+		t.Fatalf("expected retry details in log:\n%s", log)
+	}
+}
+
+func TestEngineTimesOutHook(t *testing.T) {
+	runner := &flakyRunner{block: true}
+	report, err := runSingleHook(t, runner, Hook{Timeout: "20ms", OnError: OnErrorWarn}, Context{})
+	if err != nil {
+		t.Fatalf("run hooks: %v", err)
+	}
+	result := report.Results[0]
+	if result.Status != RunStatusFailed || !result.TimedOut {
+		t.Fatalf("expected timed out failure, got %+v", result)
+	}
+	if !strings.Contains(result.Err.Error(), "timed out after 20ms") {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	log := readHookLog(t, result.LogPath)
+	if !strings.Contains(log, "timeout: 20ms") || !strings.Contains(log, "timed_out: true") {
+		t.Fatalf("expected timeout details in log:\n%s", log)
+	}
+}
+
+func TestEngineSkipsHookWhenConditionsFail(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoPath, "package.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	cases := []struct {
+		name   string
+		when   When
+		reason string
+	}{
+		{name: "match", when: When{FileExists: []string{"package.json"}, Branch: []string{"feature/*"}, Repos: []string{"web"}}},
+		{name: "repo", when: When{Repos: []string{"api"}}, reason: "repo web not in api"},
+		{name: "branch", when: When{Branch: []string{"release/*"}}, reason: "branch feature/login does not match release/*"},
+		{name: "file", when: When{FileExists: []string{"go.mod"}}, reason: "file go.mod does not exist"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := &flakyRunner{}
+			report, err := runSingleHook(t, runner, Hook{When: &tc.when}, Context{
+				RepoName: "web",
+				RepoPath: repoPath,
+				Branch:   "feature/login",
+			})
+			if err != nil {
+				t.Fatalf("run hooks: %v", err)
+			}
+			result := report.Results[0]
+			if tc.reason == "" {
+				if result.Status != RunStatusOK || runner.calls != 1 {
+					t.Fatalf("expected hook to run, got %+v", result)
+				}
+				return
+			}
+			if result.Status != RunStatusSkipped || result.SkipReason != tc.reason || runner.calls != 0 {
+				t.Fatalf("expected skip %q, got %+v calls=%d", tc.reason, result, runner.calls)
+			}
+			if log := readHookLog(t, result.LogPath); !strings.Contains(log, "skipped: "+tc.reason) {
+				t.Fatalf("expected skip reason in log:\n%s", log)
+			}
+		})
+	}
+}
+
+func TestEngineRejectsInvalidTimeout(t *testing.T) {
+	_, err := runSingleHook(t, &flakyRunner{}, Hook{Timeout: "soon"}, Context{})
+	if err == nil || !strings.Contains(err.Error(), `invalid timeout "soon"`) {
+		t.Fatalf("expected invalid timeout error, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"fmt"
+	"strconv"
 )
 
 type Context struct {
@@ -15,20 +16,30 @@ type Context struct {
 	Branch          string
 	Event           Event
 	Reason          string
+	PreviousName    string
+	BaseBranch      string
+	CommitSHA       string
+	PullRequestURL  string
+	PullRequestNum  int
 }
 
 func (c Context) TokenMap() map[string]string {
 	values := map[string]string{
-		"{workspace.root}":   c.WorkspaceRoot,
-		"{workspace.name}":   c.WorkspaceName,
-		"{workspace.config}": c.WorkspaceConfig,
-		"{repo.name}":        c.RepoName,
-		"{repo.dir}":         c.RepoDir,
-		"{repo.path}":        c.RepoPath,
-		"{worktree.path}":    c.WorktreePath,
-		"{branch}":           c.Branch,
-		"{event}":            string(c.Event),
-		"{reason}":           c.Reason,
+		"{workspace.root}":          c.WorkspaceRoot,
+		"{workspace.name}":          c.WorkspaceName,
+		"{workspace.config}":        c.WorkspaceConfig,
+		"{repo.name}":               c.RepoName,
+		"{repo.dir}":                c.RepoDir,
+		"{repo.path}":               c.RepoPath,
+		"{worktree.path}":           c.WorktreePath,
+		"{branch}":                  c.Branch,
+		"{event}":                   string(c.Event),
+		"{reason}":                  c.Reason,
+		"{workspace.previous_name}": c.PreviousName,
+		"{base_branch}":             c.BaseBranch,
+		"{commit}":                  c.CommitSHA,
+		"{pr.url}":                  c.PullRequestURL,
+		"{pr.number}":               c.pullRequestNumber(),
 	}
 	for key, value := range values {
 		if value == "" {
@@ -38,6 +49,11 @@ func (c Context) TokenMap() map[string]string {
 	return values
 }
 
+// Expand replaces the context's tokens in value, as hook commands do.
+func (c Context) Expand(value string) string {
+	return interpolateValue(value, c.TokenMap())
+}
+
 func (c Context) Env() []string {
 	env := []string{
 		"WORKSET_ROOT=" + c.WorkspaceRoot,
@@ -50,6 +66,18 @@ func (c Context) Env() []string {
 		"WORKSET_BRANCH=" + c.Branch,
 		fmt.Sprintf("WORKSET_EVENT=%s", c.Event),
 		"WORKSET_REASON=" + c.Reason,
+		"WORKSET_PREVIOUS_WORKSPACE=" + c.PreviousName,
+		"WORKSET_BASE_BRANCH=" + c.BaseBranch,
+		"WORKSET_COMMIT=" + c.CommitSHA,
+		"WORKSET_PR_URL=" + c.PullRequestURL,
+		"WORKSET_PR_NUMBER=" + c.pullRequestNumber(),
 	}
 	return env
 }
+
+func (c Context) pullRequestNumber() string {
+	if c.PullRequestNum <= 0 {
+		return ""
+	}
+	return strconv.Itoa(c.PullRequestNum)
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,8 +26,18 @@ type RunInput struct {
 	LogRoot        string
 	Context        Context
 	Observer       RunObserver
+	// Cwd is used for hooks without their own cwd. It defaults to the repo
+	// path, which no longer exists for events like worktree.removed.
+	Cwd string
+	// MaxParallel bounds how many independent hooks run at once. Values
+	// below one use DefaultMaxParallel.
+	MaxParallel int
 }
 
+// Run executes the hooks matching input.Event. Hooks run in declaration order
+// unless depends_on or a parallel group lets them overlap; results are reported
+// in declaration order. A failing hook with on_error fail stops new hooks from
+// starting and its HookFailedError is returned once running hooks finish.
 func (e Engine) Run(ctx context.Context, input RunInput) (RunReport, error) {
 	if input.Event == "" {
 		return RunReport{}, errors.New("hook event required")
@@ -43,96 +55,124 @@ func (e Engine) Run(ctx context.Context, input RunInput) (RunReport, error) {
 	if onErrorDefault == "" {
 		onErrorDefault = OnErrorFail
 	}
+	limit := input.MaxParallel
+	if limit < 1 {
+		limit = DefaultMaxParallel
+	}
 
-	for _, hook := range input.Hooks {
-		if !hookMatchesEvent(hook, input.Event) {
-			if strings.TrimSpace(hook.ID) != "" {
-				report.Results = append(report.Results, RunResult{
-					HookID: hook.ID,
-					Status: RunStatusSkipped,
-				})
-			}
-			continue
+	nodes, err := planHooks(input.Hooks, input.Event)
+	if err != nil {
+		return report, err
+	}
+
+	type outcome struct {
+		node   int
+		result RunResult
+		err    error
+	}
+	var (
+		observerMu sync.Mutex
+		results    = make([]*RunResult, len(input.Hooks))
+		done       = make([]bool, len(nodes))
+		started    = make([]bool, len(nodes))
+		outcomes   = make(chan outcome)
+		running    int
+		runErr     error
+	)
+	observer := lockedObserver{observer: input.Observer, mu: &observerMu}
+	for i, hook := range input.Hooks {
+		if !hookMatchesEvent(hook, input.Event) && strings.TrimSpace(hook.ID) != "" {
+			results[i] = &RunResult{HookID: hook.ID, Status: RunStatusSkipped}
 		}
-		if err := validateHook(hook); err != nil {
-			return report, err
+	}
+	ready := func(n int) bool {
+		for _, dep := range nodes[n].deps {
+			if !done[dep] {
+				return false
+			}
 		}
-		onError := normalizeOnError(hook.OnError)
-		if onError == "" {
-			onError = onErrorDefault
+		return true
+	}
+	for {
+		if runErr == nil {
+			for n := range nodes {
+				if running >= limit {
+					break
+				}
+				if started[n] || !ready(n) {
+					continue
+				}
+				started[n] = true
+				running++
+				go func(n int) {
+					hook := nodes[n].hook
+					onError := normalizeOnError(hook.OnError)
+					if onError == "" {
+						onError = onErrorDefault
+					}
+					result, err := e.runHook(ctx, runner, clock, input, hook, onError, observer)
+					outcomes <- outcome{node: n, result: result, err: err}
+				}(n)
+			}
 		}
-
-		command := interpolateArgs(hook.Run, input.Context.TokenMap())
-		cwd := interpolateValue(hook.Cwd, input.Context.TokenMap())
-		if cwd == "" {
-			cwd = input.Context.RepoPath
+		if running == 0 {
+			break
 		}
-
-		logPath, file, err := openHookLog(input.LogRoot, input.Event, hook.ID, clock())
-		if err != nil {
-			return report, err
+		out := <-outcomes
+		running--
+		done[out.node] = true
+		if out.result.HookID != "" {
+			result := out.result
+			results[nodes[out.node].index] = &result
 		}
-		result := RunResult{HookID: hook.ID, Status: RunStatusOK, LogPath: logPath}
-		notifyObserver(input.Observer, HookProgress{
-			Phase:         HookPhaseStarted,
-			Event:         input.Event,
-			HookID:        hook.ID,
-			WorkspaceName: input.Context.WorkspaceName,
-			RepoName:      input.Context.RepoName,
-			WorktreePath:  input.Context.WorktreePath,
-			Reason:        input.Context.Reason,
-			LogPath:       logPath,
-		})
-
-		if err := writeHookHeader(file, hook, input.Event, input.Context, command, cwd, clock()); err != nil {
-			_ = file.Close()
-			return report, err
+		if out.err != nil && runErr == nil {
+			runErr = out.err
 		}
-		env := append(os.Environ(), input.Context.Env()...)
-		for key, value := range hook.Env {
-			envValue := interpolateValue(value, input.Context.TokenMap())
-			env = append(env, fmt.Sprintf("%s=%s", key, envValue))
+	}
+	for _, result := range results {
+		if result != nil {
+			report.Results = append(report.Results, *result)
 		}
+	}
+	return report, runErr
+}
 
-		runErr := runner.Run(ctx, RunRequest{
-			Command: command,
-			Cwd:     cwd,
-			Env:     env,
-			Stdout:  file,
-			Stderr:  file,
-		})
+// runHook executes a single hook and writes its log. The returned error is
+// non-nil for log failures and for failing hooks whose on_error is fail.
+func (e Engine) runHook(
+	ctx context.Context,
+	runner Runner,
+	clock func() time.Time,
+	input RunInput,
+	hook Hook,
+	onError string,
+	observer RunObserver,
+) (RunResult, error) {
+	command := interpolateArgs(hook.Run, input.Context.TokenMap())
+	cwd := interpolateValue(hook.Cwd, input.Context.TokenMap())
+	if cwd == "" {
+		cwd = input.Cwd
+	}
+	if cwd == "" {
+		cwd = input.Context.RepoPath
+	}
 
-		if err := writeHookFooter(file, runErr, clock()); err != nil {
+	logPath, file, err := openHookLog(input.LogRoot, input.Event, input.Context.RepoName, hook.ID, clock())
+	if err != nil {
+		return RunResult{}, err
+	}
+	result := RunResult{HookID: hook.ID, Status: RunStatusOK, LogPath: logPath}
+	if reason := skipReason(hook.When, input.Context); reason != "" {
+		result.Status = RunStatusSkipped
+		result.SkipReason = reason
+		if err := writeHookHeader(file, hook, input.Event, input.Context, command, cwd, reason, clock()); err != nil {
 			_ = file.Close()
-			return report, err
-		}
-		if closeErr := file.Close(); closeErr != nil {
-			return report, closeErr
+			return RunResult{}, err
 		}
-
-		if runErr != nil {
-			result.Status = RunStatusFailed
-			result.Err = runErr
-			report.Results = append(report.Results, result)
-			notifyObserver(input.Observer, HookProgress{
-				Phase:         HookPhaseFinished,
-				Event:         input.Event,
-				HookID:        hook.ID,
-				WorkspaceName: input.Context.WorkspaceName,
-				RepoName:      input.Context.RepoName,
-				WorktreePath:  input.Context.WorktreePath,
-				Reason:        input.Context.Reason,
-				Status:        result.Status,
-				LogPath:       logPath,
-				Err:           runErr,
-			})
-			if onError == OnErrorFail {
-				return report, HookFailedError{HookID: hook.ID, LogPath: logPath, Err: runErr}
-			}
-			continue
+		if err := file.Close(); err != nil {
+			return RunResult{}, err
 		}
-		report.Results = append(report.Results, result)
-		notifyObserver(input.Observer, HookProgress{
+		notifyObserver(observer, HookProgress{
 			Phase:         HookPhaseFinished,
 			Event:         input.Event,
 			HookID:        hook.ID,
@@ -140,11 +180,88 @@ func (e Engine) Run(ctx context.Context, input RunInput) (RunReport, error) {
 			RepoName:      input.Context.RepoName,
 			WorktreePath:  input.Context.WorktreePath,
 			Reason:        input.Context.Reason,
+			SkipReason:    reason,
 			Status:        result.Status,
 			LogPath:       logPath,
 		})
+		return result, nil
+	}
+	notifyObserver(observer, HookProgress{
+		Phase:         HookPhaseStarted,
+		Event:         input.Event,
+		HookID:        hook.ID,
+		WorkspaceName: input.Context.WorkspaceName,
+		RepoName:      input.Context.RepoName,
+		WorktreePath:  input.Context.WorktreePath,
+		Reason:        input.Context.Reason,
+		LogPath:       logPath,
+	})
+
+	if err := writeHookHeader(file, hook, input.Event, input.Context, command, cwd, "", clock()); err != nil {
+		_ = file.Close()
+		return RunResult{}, err
+	}
+	env := append(os.Environ(), input.Context.Env()...)
+	for key, value := range hook.Env {
+		envValue := interpolateValue(value, input.Context.TokenMap())
+		env = append(env, fmt.Sprintf("%s=%s", key, envValue))
+	}
+
+	attempts, timedOut, runErr := runAttempts(ctx, runner, hook, RunRequest{
+		Command: command,
+		Cwd:     cwd,
+		Env:     env,
+		Stdout:  file,
+		Stderr:  file,
+	}, func(format string, args ...any) {
+		_, _ = fmt.Fprintf(file, format, args...)
+	})
+	result.Attempts = attempts
+	result.TimedOut = timedOut
+
+	if err := writeHookFooter(file, runErr, result, clock()); err != nil {
+		_ = file.Close()
+		return RunResult{}, err
+	}
+	if closeErr := file.Close(); closeErr != nil {
+		return RunResult{}, closeErr
+	}
+
+	if runErr != nil {
+		result.Status = RunStatusFailed
+		result.Err = runErr
+	}
+	notifyObserver(observer, HookProgress{
+		Phase:         HookPhaseFinished,
+		Event:         input.Event,
+		HookID:        hook.ID,
+		WorkspaceName: input.Context.WorkspaceName,
+		RepoName:      input.Context.RepoName,
+		WorktreePath:  input.Context.WorktreePath,
+		Reason:        input.Context.Reason,
+		Status:        result.Status,
+		LogPath:       logPath,
+		Err:           runErr,
+	})
+	if runErr != nil && onError == OnErrorFail {
+		return result, HookFailedError{HookID: hook.ID, LogPath: logPath, Err: runErr}
+	}
+	return result, nil
+}
+
+// lockedObserver serializes progress callbacks from concurrently running hooks.
+type lockedObserver struct {
+	observer RunObserver
+	mu       *sync.Mutex
+}
+
+func (o lockedObserver) OnHookProgress(progress HookProgress) {
+	if o.observer == nil {
+		return
 	}
-	return report, nil
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observer.OnHookProgress(progress)
 }
 
 func notifyObserver(observer RunObserver, progress HookProgress) {
@@ -171,7 +288,16 @@ func validateHook(hook Hook) error {
 	if hook.OnError != "" && normalizeOnError(hook.OnError) == "" {
 		return fmt.Errorf("hook %s: invalid on_error value %q", hook.ID, hook.OnError)
 	}
-	return nil
+	if _, err := parseHookDuration(hook.ID, "timeout", hook.Timeout); err != nil {
+		return err
+	}
+	if _, err := parseHookDuration(hook.ID, "retry_backoff", hook.RetryBackoff); err != nil {
+		return err
+	}
+	if hook.Retries < 0 {
+		return fmt.Errorf("hook %s: retries must not be negative", hook.ID)
+	}
+	return validateWhen(hook)
 }
 
 func normalizeOnError(value string) string {
@@ -208,7 +334,10 @@ func interpolateValue(value string, tokens map[string]string) string {
 	return replacer.Replace(value)
 }
 
-func openHookLog(root string, event Event, hookID string, now time.Time) (string, *os.File, error) {
+// openHookLog creates <root>/<event>/<timestamp>-<repo>-<hook>.log. The same
+// hook often runs for several repos, or twice within a second, so the name
+// carries the repo and a counter is added rather than reuse an existing log.
+func openHookLog(root string, event Event, repoName, hookID string, now time.Time) (string, *os.File, error) {
 	if root == "" {
 		return "", nil, errors.New("hook log root required")
 	}
@@ -219,14 +348,26 @@ func openHookLog(root string, event Event, hookID string, now time.Time) (string
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return "", nil, err
 	}
-	safeID := sanitizeFilename(hookID)
-	timestamp := now.UTC().Format("20060102-150405")
-	path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", timestamp, safeID))
-	file, err := os.Create(path)
-	if err != nil {
-		return "", nil, err
+	base := now.UTC().Format("20060102-150405")
+	if safeRepo := sanitizeFilename(repoName); safeRepo != "" {
+		base += "-" + safeRepo
+	}
+	base += "-" + sanitizeFilename(hookID)
+	for attempt := 1; ; attempt++ {
+		name := base + ".log"
+		if attempt > 1 {
+			name = fmt.Sprintf("%s-%d.log", base, attempt)
+		}
+		path := filepath.Join(dir, name)
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return path, file, nil
 	}
-	return path, file, nil
 }
 
 func sanitizeFilename(value string) string {
@@ -236,7 +377,7 @@ func sanitizeFilename(value string) string {
 	return value
 }
 
-func writeHookHeader(w io.Writer, hook Hook, event Event, ctx Context, command []string, cwd string, now time.Time) error {
+func writeHookHeader(w io.Writer, hook Hook, event Event, ctx Context, command []string, cwd, skipped string, now time.Time) error {
 	_, err := fmt.Fprintf(w, "workset hook %s\n", hook.ID)
 	if err != nil {
 		return err
@@ -268,6 +409,20 @@ func writeHookHeader(w io.Writer, hook Hook, event Event, ctx Context, command [
 			return err
 		}
 	}
+	if hook.Timeout != "" {
+		if _, err := fmt.Fprintf(w, "timeout: %s\n", hook.Timeout); err != nil {
+			return err
+		}
+	}
+	if hook.Retries > 0 {
+		if _, err := fmt.Fprintf(w, "retries: %d\n", hook.Retries); err != nil {
+			return err
+		}
+	}
+	if skipped != "" {
+		_, err := fmt.Fprintf(w, "skipped: %s\n", skipped)
+		return err
+	}
 	if _, err := fmt.Fprintf(w, "started: %s\n", now.UTC().Format(time.RFC3339)); err != nil {
 		return err
 	}
@@ -275,10 +430,20 @@ func writeHookHeader(w io.Writer, hook Hook, event Event, ctx Context, command [
 	return err
 }
 
-func writeHookFooter(w io.Writer, runErr error, now time.Time) error {
+func writeHookFooter(w io.Writer, runErr error, result RunResult, now time.Time) error {
 	if _, err := fmt.Fprintln(w, "----"); err != nil {
 		return err
 	}
+	if result.Attempts > 1 {
+		if _, err := fmt.Fprintf(w, "attempts: %d\n", result.Attempts); err != nil {
+			return err
+		}
+	}
+	if result.TimedOut {
+		if _, err := fmt.Fprintln(w, "timed_out: true"); err != nil {
+			return err
+		}
+	}
 	if runErr != nil {
 		if _, err := fmt.Fprintf(w, "error: %s\n", runErr); err != nil {
 			return err
@@ -101,3 +101,63 @@ func TestEngineRunsHookAndLogs(t *testing.T) {
 		t.Fatalf("expected observer log path")
 	}
 }
+
+func TestEngineUsesInputCwdAndLifecycleTokens(t *testing.T) {
+	root := t.TempDir()
+	runner := &stubRunner{}
+	engine := Engine{Runner: runner}
+
+	_, err := engine.Run(context.Background(), RunInput{
+		Event: EventPullRequestCreated,
+		Hooks: []Hook{
+			{ID: "notify", On: []Event{EventPullRequestCreated}, Run: []string{"notify", "{pr.number}", "{pr.url}"}},
+		},
+		LogRoot: filepath.Join(root, "logs"),
+		Context: Context{
+			RepoName:       "repo-a",
+			RepoPath:       filepath.Join(root, "missing"),
+			PullRequestNum: 42,
+			PullRequestURL: "https://example.test/pr/42",
+		},
+		Cwd: root,
+	})
+	if err != nil {
+		t.Fatalf("run hooks: %v", err)
+	}
+	if runner.last.Cwd != root {
+		t.Fatalf("expected input cwd, got %q", runner.last.Cwd)
+	}
+	if got := runner.last.Command; got[1] != "42" || got[2] != "https://example.test/pr/42" {
+		t.Fatalf("expected interpolated pr tokens, got %v", got)
+	}
+	if !EventWorktreeRemoving.IsPre() || EventWorktreeRemoved.IsPre() {
+		t.Fatalf("unexpected pre event classification")
+	}
+}
+
+func TestEngineKeepsLogsOfSameSecondRunsApart(t *testing.T) {
+	logRoot := t.TempDir()
+	engine := Engine{
+		Runner: &stubRunner{},
+		Clock: func() time.Time {
+			return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		},
+	}
+	seen := map[string]bool{}
+	for _, repo := range []string{"repo-a", "repo-b", "repo-a"} {
+		report, err := engine.Run(context.Background(), RunInput{
+			Event:   EventWorktreeCreated,
+			Hooks:   []Hook{{ID: "bootstrap", On: []Event{EventWorktreeCreated}, Run: []string{"true"}}},
+			LogRoot: logRoot,
+			Context: Context{RepoName: repo, RepoPath: logRoot},
+		})
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		path := report.Results[0].LogPath
+		if seen[path] || !strings.Contains(filepath.Base(path), repo) {
+			t.Fatalf("expected a new log named for %s, got %s", repo, path)
+		}
+		seen[path] = true
+	}
+}
@@ -16,6 +16,7 @@ type HookProgress struct {
 	RepoName      string
 	WorktreePath  string
 	Reason        string
+	SkipReason    string
 	Status        RunStatus
 	LogPath       string
 	Err           error
@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxParallel bounds concurrent hooks when RunInput.MaxParallel is unset.
+const DefaultMaxParallel = 4
+
+type hookNode struct {
+	index int
+	hook  Hook
+	deps  []int
+}
+
+// planHooks builds the dependency graph for hooks matching an event. A hook
+// without depends_on waits for every earlier matching hook that also lacks
+// depends_on and is outside its parallel group, which keeps plain hook files
+// running strictly in order. Hooks with depends_on are ordered only by their
+// dependencies; dependencies on hooks that do not match the event are ignored.
+func planHooks(hooks []Hook, event Event) ([]hookNode, error) {
+	allIDs := map[string]bool{}
+	for _, hook := range hooks {
+		if id := strings.TrimSpace(hook.ID); id != "" {
+			allIDs[id] = true
+		}
+	}
+	nodes := []hookNode{}
+	byID := map[string]int{}
+	for i, hook := range hooks {
+		if !hookMatchesEvent(hook, event) {
+			continue
+		}
+		if err := validateHook(hook); err != nil {
+			return nil, err
+		}
+		if _, dup := byID[hook.ID]; !dup {
+			byID[hook.ID] = len(nodes)
+		}
+		nodes = append(nodes, hookNode{index: i, hook: hook})
+	}
+
+	for n := range nodes {
+		hook := nodes[n].hook
+		if len(hook.DependsOn) == 0 {
+			for prev := range n {
+				prevHook := nodes[prev].hook
+				if len(prevHook.DependsOn) > 0 {
+					continue
+				}
+				if hook.Parallel != "" && prevHook.Parallel == hook.Parallel {
+					continue
+				}
+				nodes[n].deps = append(nodes[n].deps, prev)
+			}
+			continue
+		}
+		for _, dep := range hook.DependsOn {
+			dep = strings.TrimSpace(dep)
+			if dep == hook.ID {
+				return nil, fmt.Errorf("hook %s: cannot depend on itself", hook.ID)
+			}
+			if !allIDs[dep] {
+				return nil, fmt.Errorf("hook %s: depends on unknown hook %q", hook.ID, dep)
+			}
+			if idx, ok := byID[dep]; ok {
+				nodes[n].deps = append(nodes[n].deps, idx)
+			}
+		}
+	}
+	if err := checkHookCycles(nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func checkHookCycles(nodes []hookNode) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(nodes))
+	var visit func(n int) error
+	visit = func(n int) error {
+		switch state[n] {
+		case visiting:
+			return fmt.Errorf("hook %s: dependency cycle", nodes[n].hook.ID)
+		case visited:
+			return nil
+		}
+		state[n] = visiting
+		for _, dep := range nodes[n].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[n] = visited
+		return nil
+	}
+	for n := range nodes {
+		if err := visit(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
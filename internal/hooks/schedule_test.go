@@ -0,0 +1,183 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateRunner records start/finish order and can block hooks until released.
+type gateRunner struct {
+	mu       sync.Mutex
+	order    []string
+	active   int
+	peak     int
+	gates    map[string]chan struct{}
+	failures map[string]error
+}
+
+func (g *gateRunner) Run(_ context.Context, req RunRequest) error {
+	id := req.Command[0]
+	g.mu.Lock()
+	g.order = append(g.order, "start:"+id)
+	g.active++
+	if g.active > g.peak {
+		g.peak = g.active
+	}
+	gate := g.gates[id]
+	g.mu.Unlock()
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-time.After(5 * time.Second):
+			return errors.New("gate timeout")
+		}
+	}
+	g.mu.Lock()
+	g.active--
+	g.order = append(g.order, "end:"+id)
+	err := g.failures[id]
+	g.mu.Unlock()
+	return err
+}
+
+func scheduleHook(id string, mods ...func(*Hook)) Hook {
+	hook := Hook{ID: id, On: []Event{EventWorktreeCreated}, Run: []string{id}}
+	for _, mod := range mods {
+		mod(&hook)
+	}
+	return hook
+}
+
+func inGroup(group string) func(*Hook) {
+	return func(h *Hook) { h.Parallel = group }
+}
+
+func dependsOn(ids ...string) func(*Hook) {
+	return func(h *Hook) { h.DependsOn = ids }
+}
+
+func runSchedule(t *testing.T, runner Runner, observer RunObserver, limit int, hooks ...Hook) (RunReport, error) {
+	t.Helper()
+	root := t.TempDir()
+	engine := Engine{Runner: runner}
+	return engine.Run(context.Background(), RunInput{
+		Event:       EventWorktreeCreated,
+		Hooks:       hooks,
+		LogRoot:     filepath.Join(root, "logs"),
+		Context:     Context{RepoName: "repo-a", RepoPath: root},
+		Observer:    observer,
+		MaxParallel: limit,
+	})
+}
+
+func TestEngineRunsParallelGroupConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	runner := &gateRunner{gates: map[string]chan struct{}{"a": release, "b": release}}
+	observer := &stubObserver{}
+	go func() {
+		deadline := time.After(5 * time.Second)
+		for {
+			runner.mu.Lock()
+			active := runner.active
+			runner.mu.Unlock()
+			if active == 2 {
+				close(release)
+				return
+			}
+			select {
+			case <-deadline:
+				close(release)
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	report, err := runSchedule(t, runner, observer, 4,
+		scheduleHook("a", inGroup("deps")),
+		scheduleHook("b", inGroup("deps")),
+		scheduleHook("c"),
+	)
+	if err != nil {
+		t.Fatalf("run hooks: %v", err)
+	}
+	if runner.peak != 2 {
+		t.Fatalf("expected grouped hooks to overlap, peak=%d order=%v", runner.peak, runner.order)
+	}
+	if last := runner.order[len(runner.order)-2]; last != "start:c" {
+		t.Fatalf("expected c to start after the group finished, order=%v", runner.order)
+	}
+	ids := []string{}
+	for _, result := range report.Results {
+		ids = append(ids, result.HookID)
+	}
+	if strings.Join(ids, ",") != "a,b,c" {
+		t.Fatalf("expected results in declaration order, got %v", ids)
+	}
+	if len(observer.events) != 6 {
+		t.Fatalf("expected 6 progress events, got %d", len(observer.events))
+	}
+}
+
+func TestEngineRespectsDependsOnAndLimit(t *testing.T) {
+	runner := &gateRunner{}
+	_, err := runSchedule(t, runner, nil, 1,
+		scheduleHook("install", dependsOn("fetch")),
+		scheduleHook("fetch", inGroup("io")),
+		scheduleHook("lint", inGroup("io")),
+	)
+	if err != nil {
+		t.Fatalf("run hooks: %v", err)
+	}
+	if runner.peak != 1 {
+		t.Fatalf("expected limit of 1 to serialize hooks, peak=%d", runner.peak)
+	}
+	order := strings.Join(runner.order, " ")
+	if strings.Index(order, "end:fetch") > strings.Index(order, "start:install") {
+		t.Fatalf("expected install after fetch, order=%v", runner.order)
+	}
+}
+
+func TestEngineStopsSchedulingAfterFailure(t *testing.T) {
+	runner := &gateRunner{failures: map[string]error{"a": errors.New("boom")}}
+	report, err := runSchedule(t, runner, nil, 4,
+		scheduleHook("a"),
+		scheduleHook("b"),
+	)
+	var failed HookFailedError
+	if !errors.As(err, &failed) || failed.HookID != "a" {
+		t.Fatalf("expected hook a failure, got %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Status != RunStatusFailed {
+		t.Fatalf("unexpected results: %+v", report.Results)
+	}
+	if strings.Contains(strings.Join(runner.order, " "), "start:b") {
+		t.Fatalf("expected b not to run, order=%v", runner.order)
+	}
+}
+
+func TestEngineRejectsInvalidDependencies(t *testing.T) {
+	cases := map[string][]Hook{
+		"unknown": {scheduleHook("a", dependsOn("missing"))},
+		"cycle": {
+			scheduleHook("a", dependsOn("b")),
+			scheduleHook("b", dependsOn("a")),
+		},
+	}
+	for name, hooks := range cases {
+		t.Run(name, func(t *testing.T) {
+			runner := &gateRunner{}
+			if _, err := runSchedule(t, runner, nil, 4, hooks...); err == nil {
+				t.Fatalf("expected error")
+			}
+			if len(runner.order) != 0 {
+				t.Fatalf("expected no hooks to run, order=%v", runner.order)
+			}
+		})
+	}
+}
@@ -3,9 +3,24 @@ package hooks
 type Event string
 
 const (
-	EventWorktreeCreated Event = "worktree.created"
+	EventWorktreeCreated     Event = "worktree.created"
+	EventWorktreeRemoving    Event = "worktree.removing"
+	EventWorktreeRemoved     Event = "worktree.removed"
+	EventThreadCreated       Event = "thread.created"
+	EventThreadArchived      Event = "thread.archived"
+	EventThreadRenamed       Event = "thread.renamed"
+	EventRepoAdded           Event = "repo.added"
+	EventPullRequestCreated  Event = "pr.created"
+	EventCommitPushed        Event = "commit.pushed"
+	EventLocalMergeCompleted Event = "local_merge.completed"
 )
 
+// IsPre reports whether the event fires before its operation runs. Failing
+// hooks with on_error: fail on a pre event veto the operation.
+func (e Event) IsPre() bool {
+	return e == EventWorktreeRemoving
+}
+
 const (
 	OnErrorFail = "fail"
 	OnErrorWarn = "warn"
@@ -18,6 +33,30 @@ type Hook struct {
 	Cwd     string            `yaml:"cwd,omitempty" json:"cwd,omitempty"`
 	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
 	OnError string            `yaml:"on_error,omitempty" json:"on_error,omitempty"`
+	// DependsOn lists hook IDs that must finish before this hook starts.
+	// When set it replaces the default of waiting for earlier hooks.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	// Parallel names a group whose members may run at the same time.
+	Parallel string `yaml:"parallel,omitempty" json:"parallel,omitempty"`
+	// Timeout bounds each attempt (Go duration, e.g. "10m").
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Retries is the number of extra attempts after a failure.
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty"`
+	// RetryBackoff is the delay before the first retry; it doubles per retry.
+	RetryBackoff string `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
+	// When restricts the hook to matching repos, branches, or files.
+	When *When `yaml:"when,omitempty" json:"when,omitempty"`
+}
+
+// When holds hook conditions. Every populated clause must match for the hook
+// to run; within a clause any listed value may match.
+type When struct {
+	// FileExists lists paths (relative to the repo path) that must all exist.
+	FileExists []string `yaml:"file_exists,omitempty" json:"file_exists,omitempty"`
+	// Branch lists glob patterns matched against the thread branch.
+	Branch []string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	// Repos lists repo names the hook applies to.
+	Repos []string `yaml:"repos,omitempty" json:"repos,omitempty"`
 }
 
 type File struct {
@@ -25,10 +64,14 @@ type File struct {
 }
 
 type RunResult struct {
-	HookID  string
-	Status  RunStatus
-	LogPath string
-	Err     error
+	HookID   string
+	Status   RunStatus
+	LogPath  string
+	Err      error
+	Attempts int
+	TimedOut bool
+	// SkipReason explains why a matching hook did not run.
+	SkipReason string
 }
 
 type RunStatus string
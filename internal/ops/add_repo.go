@@ -9,9 +9,11 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/strantalis/workset/internal/config"
 	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/repostore"
 	"github.com/strantalis/workset/internal/workspace"
 )
 
@@ -25,21 +27,65 @@ type AddRepoInput struct {
 	Remote        string
 	DefaultBranch string
 	AllowFallback bool
-	Git           git.Client
+	// FromRef starts the new branch from this ref (branch, tag, commit, or
+	// <remote>/<branch>) instead of the default branch.
+	FromRef string
+	// ExistingBranch checks out the branch as it already exists on Remote
+	// instead of creating it.
+	ExistingBranch bool
+	// Branch checks out this branch instead of the thread branch.
+	Branch string
+	// BaseBranch is recorded as the repo's base in the workspace config.
+	// When empty it is derived from FromRef.
+	BaseBranch string
+	Git        git.Client
+	// Now stamps and ages repo store fetches; nil uses time.Now.
+	Now func() time.Time
+}
+
+// PreparedRepo is a repo whose clone and worktree exist but which is not yet
+// recorded in the workspace config. See PrepareRepo and RegisterRepos.
+type PreparedRepo struct {
+	Repo     config.RepoConfig
+	Remote   string
+	Warnings []string
+	// GitDir, WorktreeName and WorktreePath locate the worktree PrepareRepo
+	// added. They are empty when the worktree already existed.
+	GitDir       string
+	WorktreeName string
+	WorktreePath string
+	// CreatedBranch is the branch PrepareRepo created for the worktree, or
+	// empty when the branch already existed.
+	CreatedBranch string
 }
 
 func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, string, []string, error) {
+	prepared, err := PrepareRepo(ctx, input)
+	if err != nil {
+		return config.WorkspaceConfig{}, "", nil, err
+	}
+	wsConfig, err := RegisterRepos(input.WorkspaceRoot, input.Defaults, prepared)
+	if err != nil {
+		return config.WorkspaceConfig{}, "", nil, err
+	}
+	return wsConfig, prepared.Remote, prepared.Warnings, nil
+}
+
+// PrepareRepo clones (or resolves) the repo and adds its worktree without
+// touching the workspace config, so several repos can be prepared
+// concurrently and then recorded together with RegisterRepos.
+func PrepareRepo(ctx context.Context, input AddRepoInput) (PreparedRepo, error) {
 	if input.WorkspaceRoot == "" {
-		return config.WorkspaceConfig{}, "", nil, errors.New("workspace root required")
+		return PreparedRepo{}, errors.New("workspace root required")
 	}
 	if input.Name == "" {
-		return config.WorkspaceConfig{}, "", nil, errors.New("repo name required")
+		return PreparedRepo{}, errors.New("repo name required")
 	}
 	if input.URL == "" && input.SourcePath == "" {
-		return config.WorkspaceConfig{}, "", nil, errors.New("repo url or local path required")
+		return PreparedRepo{}, errors.New("repo url or local path required")
 	}
 	if input.Git == nil {
-		return config.WorkspaceConfig{}, "", nil, errors.New("git client required")
+		return PreparedRepo{}, errors.New("git client required")
 	}
 
 	if input.SourcePath == "" && looksLikeLocalPath(input.URL) {
@@ -49,19 +95,19 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	if input.SourcePath != "" {
 		resolved, err := resolveLocalPath(input.SourcePath)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 		input.SourcePath = resolved
 	}
 
 	ws, err := workspace.Load(input.WorkspaceRoot, input.Defaults)
 	if err != nil {
-		return config.WorkspaceConfig{}, "", nil, err
+		return PreparedRepo{}, err
 	}
 
 	for _, repo := range ws.Config.Repos {
 		if repo.Name == input.Name {
-			return config.WorkspaceConfig{}, "", nil, fmt.Errorf("repo %q already exists in workspace", input.Name)
+			return PreparedRepo{}, fmt.Errorf("repo %q already exists in workspace", input.Name)
 		}
 	}
 
@@ -84,27 +130,26 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	var gitDirPath string
 	if input.SourcePath != "" {
 		if ok, err := input.Git.IsRepo(input.SourcePath); err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		} else if !ok {
-			return config.WorkspaceConfig{}, "", nil, fmt.Errorf("local repo not found at %s", input.SourcePath)
+			return PreparedRepo{}, fmt.Errorf("local repo not found at %s", input.SourcePath)
 		}
 		repo.LocalPath = input.SourcePath
 		gitDirPath = input.SourcePath
 	} else {
 		if input.Defaults.RepoStoreRoot == "" {
-			return config.WorkspaceConfig{}, "", nil, errors.New("defaults.repo_store_root required for URL clones")
+			return PreparedRepo{}, errors.New("defaults.repo_store_root required for URL clones")
 		}
 		target := filepath.Join(input.Defaults.RepoStoreRoot, repo.Name)
 		target, err := filepath.Abs(target)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
-		if ok, err := input.Git.IsRepo(target); err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
-		} else if !ok {
-			if err := input.Git.Clone(ctx, input.URL, target, remote); err != nil {
-				return config.WorkspaceConfig{}, "", nil, fmt.Errorf("clone %s: %w", input.URL, err)
+		if _, _, ok := repostore.GitDir(target); !ok {
+			if err := input.Git.CloneBare(ctx, input.URL, target, remote); err != nil {
+				return PreparedRepo{}, fmt.Errorf("clone %s: %w", input.URL, err)
 			}
+			_ = repostore.RecordFetch(target, input.URL, input.now())
 		}
 		repo.LocalPath = target
 		repo.Managed = true
@@ -112,7 +157,7 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	}
 	resolvedGitDir, err := resolveGitDirPath(gitDirPath)
 	if err != nil {
-		return config.WorkspaceConfig{}, "", nil, err
+		return PreparedRepo{}, err
 	}
 	gitDirPath = resolvedGitDir
 
@@ -120,7 +165,7 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	if input.SourcePath != "" {
 		resolvedRemote, warn, err := resolveRemoteForLocalRepo(gitDirPath, input.Git, remote, input.AllowFallback)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 		remote = resolvedRemote
 		if warn != "" {
@@ -132,29 +177,37 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	if targetBranch == "" {
 		targetBranch = defaultBranch
 	}
+	branchName := targetBranch
+	if branch := strings.TrimSpace(input.Branch); branch != "" {
+		branchName = branch
+	}
+	fromRef := strings.TrimSpace(input.FromRef)
+	if fromRef != "" && input.ExistingBranch {
+		return PreparedRepo{}, errors.New("a start point and an existing branch cannot be combined")
+	}
 
 	worktreePath := workspace.RepoWorktreePath(input.WorkspaceRoot, targetBranch, repo.RepoDir)
 	worktreeExists := false
 	if _, err := os.Stat(worktreePath); err == nil {
 		ok, err := input.Git.IsRepo(worktreePath)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 		if !ok {
-			return config.WorkspaceConfig{}, "", nil, fmt.Errorf("worktree path %q exists but is not a git repo", worktreePath)
+			return PreparedRepo{}, fmt.Errorf("worktree path %q exists but is not a git repo", worktreePath)
 		}
 		worktreeExists = true
 	}
 
-	if !worktreeExists && targetBranch != "" {
+	if !worktreeExists && branchName != "" && !looksLikeBareRepo(gitDirPath) {
 		branch, ok, err := input.Git.CurrentBranch(gitDirPath)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
-		if ok && branch == targetBranch {
-			return config.WorkspaceConfig{}, "", nil, fmt.Errorf(
+		if ok && branch == branchName {
+			return PreparedRepo{}, fmt.Errorf(
 				"branch %q already checked out in %s; git only allows a branch in one worktree",
-				targetBranch,
+				branchName,
 				repo.LocalPath,
 			)
 		}
@@ -164,10 +217,10 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	if useBranchDirs {
 		branchPath := workspace.WorktreeBranchPath(input.WorkspaceRoot, targetBranch)
 		if err := os.MkdirAll(branchPath, 0o755); err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 		if err := workspace.WriteBranchMeta(input.WorkspaceRoot, targetBranch); err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 	}
 
@@ -175,16 +228,29 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 	if remote != "" {
 		exists, err := input.Git.RemoteExists(gitDirPath, remote)
 		if err != nil {
-			return config.WorkspaceConfig{}, "", nil, err
+			return PreparedRepo{}, err
 		}
 		if exists {
 			startRemote = remote
 		}
 	}
 
+	baseBranch := strings.TrimSpace(input.BaseBranch)
+	var added PreparedRepo
 	if !worktreeExists {
-		if startRemote != "" && defaultBranch != "" {
-			if err := input.Git.Fetch(ctx, gitDirPath, startRemote); err != nil {
+		startRef := ""
+		if fromRef != "" || input.ExistingBranch {
+			start, err := resolveStartPoint(ctx, input.Git, gitDirPath, startRemote, branchName, fromRef, input.ExistingBranch)
+			if err != nil {
+				return PreparedRepo{}, err
+			}
+			startRef = start.Ref
+			warnings = append(warnings, start.Warnings...)
+			if baseBranch == "" {
+				baseBranch = start.Base
+			}
+		} else if startRemote != "" && defaultBranch != "" {
+			if err := fetchStartRemote(ctx, input, repo.Managed, gitDirPath, startRemote); err != nil {
 				warnings = append(warnings, fmt.Sprintf("fetch %s failed: %v", startRemote, err))
 			} else {
 				localRef := "refs/heads/" + defaultBranch
@@ -238,27 +304,90 @@ func AddRepo(ctx context.Context, input AddRepoInput) (config.WorkspaceConfig, s
 			}
 		}
 
+		branchExists, err := input.Git.ReferenceExists(ctx, gitDirPath, "refs/heads/"+branchName)
+		if err != nil {
+			return PreparedRepo{}, err
+		}
 		worktreeName := workspace.WorktreeName(targetBranch)
 		if err := input.Git.WorktreeAdd(ctx, git.WorktreeAddOptions{
 			RepoPath:     gitDirPath,
 			WorktreePath: worktreePath,
 			WorktreeName: worktreeName,
-			BranchName:   targetBranch,
+			BranchName:   branchName,
 			StartRemote:  startRemote,
 			StartBranch:  defaultBranch,
+			StartRef:     startRef,
 		}); err != nil {
-			return config.WorkspaceConfig{}, "", nil, fmt.Errorf("add worktree: %w", err)
+			return PreparedRepo{}, fmt.Errorf("add worktree: %w", err)
+		}
+		added.GitDir = gitDirPath
+		added.WorktreeName = worktreeName
+		added.WorktreePath = worktreePath
+		if !branchExists {
+			added.CreatedBranch = branchName
 		}
 	}
+	if baseBranch != "" && baseBranch != defaultBranch {
+		repo.BaseBranch = baseBranch
+	}
+	if branchName != targetBranch {
+		repo.Branch = branchName
+	}
 
-	ws.Config.Repos = append(ws.Config.Repos, repo)
-	if err := config.SaveWorkspace(workspace.WorksetFile(input.WorkspaceRoot), ws.Config); err != nil {
-		return config.WorkspaceConfig{}, "", nil, err
+	added.Repo = repo
+	added.Remote = remote
+	added.Warnings = warnings
+	return added, nil
+}
+
+// fetchStartRemote refreshes the remote a new worktree starts from. Repos
+// served from the repo store skip the network when the mirror was fetched
+// within defaults.repo_store.fetch_max_age, and record the fetch otherwise.
+func fetchStartRemote(ctx context.Context, input AddRepoInput, managed bool, gitDirPath, remote string) error {
+	if managed {
+		maxAge, _ := repostore.ParseInterval(input.Defaults.RepoStore.FetchMaxAge)
+		if repostore.Fresh(gitDirPath, maxAge, input.now()) {
+			return nil
+		}
+	}
+	if err := input.Git.Fetch(ctx, gitDirPath, remote); err != nil {
+		return err
+	}
+	if managed {
+		_ = repostore.RecordFetch(gitDirPath, input.URL, input.now())
+	}
+	return nil
+}
+
+func (input AddRepoInput) now() time.Time {
+	if input.Now != nil {
+		return input.Now()
+	}
+	return time.Now()
+}
+
+// RegisterRepos records prepared repos in the workspace config, in order, and
+// refreshes the workspace agents file.
+func RegisterRepos(workspaceRoot string, defaults config.Defaults, prepared ...PreparedRepo) (config.WorkspaceConfig, error) {
+	ws, err := workspace.Load(workspaceRoot, defaults)
+	if err != nil {
+		return config.WorkspaceConfig{}, err
+	}
+	for _, entry := range prepared {
+		for _, repo := range ws.Config.Repos {
+			if repo.Name == entry.Repo.Name {
+				return config.WorkspaceConfig{}, fmt.Errorf("repo %q already exists in workspace", entry.Repo.Name)
+			}
+		}
+		ws.Config.Repos = append(ws.Config.Repos, entry.Repo)
+	}
+	if err := config.SaveWorkspace(workspace.WorksetFile(workspaceRoot), ws.Config); err != nil {
+		return config.WorkspaceConfig{}, err
 	}
-	if err := workspace.UpdateAgentsFile(input.WorkspaceRoot, ws.Config, ws.State); err != nil {
-		return config.WorkspaceConfig{}, "", nil, fmt.Errorf("update agents: %w", err)
+	if err := workspace.UpdateAgentsFile(workspaceRoot, ws.Config, ws.State); err != nil {
+		return config.WorkspaceConfig{}, fmt.Errorf("update agents: %w", err)
 	}
-	return ws.Config, remote, warnings, nil
+	return ws.Config, nil
 }
 
 func DeriveRepoNameFromURL(url string) string {
@@ -359,6 +359,87 @@ func TestAddRepoKeepsExistingWorkspaceBranchWhenNotFastForwardable(t *testing.T)
 	}
 }
 
+func setupStartPointRepo(t *testing.T) (string, string, string, config.Defaults, *fakeGitClient) {
+	t.Helper()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(filepath.Join(repoRoot, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir repo: %v", err)
+	}
+	root := filepath.Join(t.TempDir(), "ws")
+	defaults := config.DefaultConfig().Defaults
+	if _, err := workspace.Init(root, "demo", defaults); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	gitDir, err := filepath.EvalSymlinks(filepath.Join(repoRoot, ".git"))
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	fake := newFakeGitClient()
+	fake.remotes[gitDir] = []string{defaults.Remote}
+	fake.remoteExists[key(gitDir, defaults.Remote)] = true
+	return repoRoot, root, gitDir, defaults, fake
+}
+
+func TestAddRepoFromRefRecordsBase(t *testing.T) {
+	repoRoot, root, gitDir, defaults, fake := setupStartPointRepo(t)
+	fake.refs[key(gitDir, "refs/remotes/"+defaults.Remote+"/release/1.2")] = true
+
+	wsConfig, _, _, err := AddRepo(context.Background(), AddRepoInput{
+		WorkspaceRoot: root,
+		Name:          "demo-repo",
+		SourcePath:    repoRoot,
+		Defaults:      defaults,
+		Remote:        defaults.Remote,
+		DefaultBranch: defaults.BaseBranch,
+		FromRef:       "release/1.2",
+		Git:           fake,
+	})
+	if err != nil {
+		t.Fatalf("AddRepo: %v", err)
+	}
+	if len(fake.worktreeAdds) != 1 || fake.worktreeAdds[0].StartRef != defaults.Remote+"/release/1.2" {
+		t.Fatalf("unexpected worktree adds: %+v", fake.worktreeAdds)
+	}
+	if len(fake.updateCalls) != 0 {
+		t.Fatalf("expected default branch left alone, got %+v", fake.updateCalls)
+	}
+	if got := wsConfig.Repos[0].BaseBranch; got != "release/1.2" {
+		t.Fatalf("expected base_branch release/1.2, got %q", got)
+	}
+}
+
+func TestAddRepoExistingBranch(t *testing.T) {
+	repoRoot, root, gitDir, defaults, fake := setupStartPointRepo(t)
+	input := AddRepoInput{
+		WorkspaceRoot:  root,
+		Name:           "demo-repo",
+		SourcePath:     repoRoot,
+		Defaults:       defaults,
+		Remote:         defaults.Remote,
+		DefaultBranch:  defaults.BaseBranch,
+		ExistingBranch: true,
+		Branch:         "fix/login",
+		Git:            fake,
+	}
+	if _, _, _, err := AddRepo(context.Background(), input); err == nil || !strings.Contains(err.Error(), "not found on remote") {
+		t.Fatalf("expected missing remote branch error, got %v", err)
+	}
+
+	fake.refs[key(gitDir, "refs/remotes/"+defaults.Remote+"/fix/login")] = true
+	wsConfig, _, _, err := AddRepo(context.Background(), input)
+	if err != nil {
+		t.Fatalf("AddRepo: %v", err)
+	}
+	if len(fake.worktreeAdds) != 1 ||
+		fake.worktreeAdds[0].BranchName != "fix/login" ||
+		fake.worktreeAdds[0].StartRef != defaults.Remote+"/fix/login" {
+		t.Fatalf("unexpected worktree adds: %+v", fake.worktreeAdds)
+	}
+	if got := wsConfig.Repos[0]; got.Branch != "fix/login" || got.BaseBranch != "" {
+		t.Fatalf("unexpected repo config: %+v", got)
+	}
+}
+
 func setupRepo(t *testing.T) string {
 	t.Helper()
 	root := filepath.Join(t.TempDir(), "source")
@@ -383,6 +464,7 @@ type fakeGitClient struct {
 	remotes      map[string][]string
 	remoteExists map[string]bool
 	ancestors    map[string]bool
+	worktreeAdds []git.WorktreeAddOptions
 }
 
 type fetchCall struct {
@@ -449,6 +531,26 @@ func (f *fakeGitClient) Status(_ string) (git.StatusSummary, error) {
 	return git.StatusSummary{}, nil
 }
 
+func (f *fakeGitClient) StatusDetail(_ context.Context, _, _ string) (git.StatusDetail, error) {
+	return git.StatusDetail{}, nil
+}
+
+func (f *fakeGitClient) Integrate(_ context.Context, _, _ string, _ git.IntegrateMode) error {
+	return nil
+}
+
+func (f *fakeGitClient) IntegrationInProgress(_ context.Context, _ string) (git.IntegrateMode, bool, error) {
+	return "", false, nil
+}
+
+func (f *fakeGitClient) ContinueIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return nil
+}
+
+func (f *fakeGitClient) AbortIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return nil
+}
+
 func (f *fakeGitClient) IsRepo(_ string) (bool, error) {
 	return true, nil
 }
@@ -475,7 +577,8 @@ func (f *fakeGitClient) RemoteExists(repoPath, remoteName string) (bool, error)
 	return false, nil
 }
 
-func (f *fakeGitClient) WorktreeAdd(_ context.Context, _ git.WorktreeAddOptions) error {
+func (f *fakeGitClient) WorktreeAdd(_ context.Context, opts git.WorktreeAddOptions) error {
+	f.worktreeAdds = append(f.worktreeAdds, opts)
 	return nil
 }
 
@@ -483,10 +586,30 @@ func (f *fakeGitClient) WorktreeRemove(_ git.WorktreeRemoveOptions) error {
 	return nil
 }
 
+func (f *fakeGitClient) WorktreeMove(_ context.Context, _, _, _ string) error {
+	return nil
+}
+
+func (f *fakeGitClient) WorktreeRepair(_ context.Context, _ string) error {
+	return nil
+}
+
 func (f *fakeGitClient) WorktreeList(_ string) ([]string, error) {
 	return nil, nil
 }
 
+func (f *fakeGitClient) GC(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeGitClient) Fsck(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeGitClient) Log(_ context.Context, _ string, _ git.LogOptions) ([]git.Commit, error) {
+	return nil, nil
+}
+
 func key(repoPath, ref string) string {
 	return repoPath + "::" + ref
 }
@@ -0,0 +1,34 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+// RepairWorkspaceWorktrees points the repo metadata of every linked worktree
+// under root at its current location. Call it after a thread directory was
+// moved as a whole, so git does not prune the worktrees as missing.
+func RepairWorkspaceWorktrees(ctx context.Context, root string, client git.Client) error {
+	if root == "" {
+		return errors.New("workspace root required")
+	}
+	if client == nil {
+		return errors.New("git client required")
+	}
+	paths, err := findWorktreePaths(root)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, _, ok, err := worktreeAdminFromPath(path); err != nil || !ok {
+			continue
+		}
+		if err := client.WorktreeRepair(ctx, path); err != nil {
+			return fmt.Errorf("repair worktree %s: %w", path, err)
+		}
+	}
+	return nil
+}
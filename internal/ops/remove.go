@@ -91,7 +91,7 @@ func CheckRepoSafety(ctx context.Context, input RepoSafetyInput) (RepoSafetyRepo
 
 		status, err := input.Git.Status(worktreePath)
 		entry := RepoBranchSafety{
-			Branch:  branch,
+			Branch:  checkedOutBranch(repo, branch),
 			Path:    worktreePath,
 			Dirty:   status.Dirty,
 			Missing: status.Missing,
@@ -301,6 +301,24 @@ func RemoveRepo(ctx context.Context, input RemoveRepoInput) (config.WorkspaceCon
 		}
 		for _, branch := range branches {
 			worktreePath := workspace.RepoWorktreePath(input.WorkspaceRoot, branch, repo.RepoDir)
+			// A checkout adopted in place belongs to the user; only the
+			// thread's link to it goes.
+			if linked, err := isSymlink(worktreePath); err != nil {
+				return config.WorkspaceConfig{}, err
+			} else if linked || repo.Adopted == config.RepoAdoptedInPlace {
+				if input.Logf != nil {
+					input.Logf("repo remove: unlinking adopted checkout at %s", worktreePath)
+				}
+				if linked {
+					if err := os.Remove(worktreePath); err != nil {
+						return config.WorkspaceConfig{}, err
+					}
+					if err := removeIfEmpty(filepath.Dir(worktreePath)); err != nil {
+						return config.WorkspaceConfig{}, err
+					}
+				}
+				continue
+			}
 			pathExists := true
 			if _, err := os.Stat(worktreePath); err != nil {
 				if os.IsNotExist(err) {
@@ -312,6 +330,9 @@ func RemoveRepo(ctx context.Context, input RemoveRepoInput) (config.WorkspaceCon
 					return config.WorkspaceConfig{}, err
 				}
 			}
+			// Worktrees are placed and named by the thread branch even when
+			// they check out the repo's own branch; that branch is left to
+			// its owner, like the thread branch.
 			worktreeName := workspace.WorktreeName(branch)
 			removeGitDir := repoGitDir
 			if resolvedDir, resolvedName, ok, err := worktreeAdminFromPath(worktreePath); err == nil && ok {
@@ -511,6 +532,16 @@ func CheckWorkspaceSafety(ctx context.Context, input WorkspaceSafetyInput) (Work
 	return report, nil
 }
 
+// checkedOutBranch is the branch checked out in repo's worktree under the
+// thread branch: the repo's own branch, such as a pull request head, when it
+// has one. The worktree itself is still found by the thread branch.
+func checkedOutBranch(repo config.RepoConfig, threadBranch string) string {
+	if branch := strings.TrimSpace(repo.Branch); branch != "" {
+		return branch
+	}
+	return threadBranch
+}
+
 func listBranches(root string, defaults config.Defaults) ([]string, error) {
 	entries, err := os.ReadDir(workspace.WorktreesPath(root))
 	if err != nil {
@@ -542,6 +573,17 @@ func listBranches(root string, defaults config.Defaults) ([]string, error) {
 	return branches, nil
 }
 
+func isSymlink(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return info.Mode()&os.ModeSymlink != 0, nil
+}
+
 func removeIfEmpty(path string) error {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -64,6 +64,22 @@ func (f *fakeGit) Status(path string) (git.StatusSummary, error) {
 	}
 	return git.StatusSummary{}, nil
 }
+func (f *fakeGit) StatusDetail(_ context.Context, path, _ string) (git.StatusDetail, error) {
+	status, err := f.Status(path)
+	return git.StatusDetail{StatusSummary: status}, err
+}
+func (f *fakeGit) Integrate(_ context.Context, _, _ string, _ git.IntegrateMode) error {
+	return nil
+}
+func (f *fakeGit) IntegrationInProgress(_ context.Context, _ string) (git.IntegrateMode, bool, error) {
+	return "", false, nil
+}
+func (f *fakeGit) ContinueIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return nil
+}
+func (f *fakeGit) AbortIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return nil
+}
 func (f *fakeGit) IsRepo(_ string) (bool, error) { return true, nil }
 func (f *fakeGit) IsAncestor(repoPath, ancestorRef, descendantRef string) (bool, error) {
 	if ok, exists := f.ancestors[repoPath+"|"+ancestorRef+"->"+descendantRef]; exists {
@@ -85,7 +101,14 @@ func (f *fakeGit) WorktreeRemove(opts git.WorktreeRemoveOptions) error {
 	f.worktreeRemoveCalls = append(f.worktreeRemoveCalls, opts)
 	return f.worktreeRemoveErr
 }
-func (f *fakeGit) WorktreeList(_ string) ([]string, error) { return nil, nil }
+func (f *fakeGit) WorktreeMove(_ context.Context, _, _, _ string) error { return nil }
+func (f *fakeGit) WorktreeRepair(_ context.Context, _ string) error     { return nil }
+func (f *fakeGit) WorktreeList(_ string) ([]string, error)              { return nil, nil }
+func (f *fakeGit) GC(_ context.Context, _ string) error                 { return nil }
+func (f *fakeGit) Fsck(_ context.Context, _ string) error               { return nil }
+func (f *fakeGit) Log(_ context.Context, _ string, _ git.LogOptions) ([]git.Commit, error) {
+	return nil, nil
+}
 
 func TestListBranchesUsesWorkspaceStateWhenMissingWorktrees(t *testing.T) {
 	root := t.TempDir()
@@ -328,6 +351,42 @@ func TestCheckRepoSafetyHappyPath(t *testing.T) {
 	}
 }
 
+func TestCheckRepoSafetyChecksPullRequestHeadBranch(t *testing.T) {
+	root := t.TempDir()
+	defaults := config.DefaultConfig().Defaults
+	if _, err := workspace.Init(root, "demo", defaults); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	repoDir := filepath.Join(workspace.WorktreesPath(root), "feature", "repo")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatalf("mkdir repo: %v", err)
+	}
+	fake := newFakeGit()
+	fake.remoteExists = true
+	baseRef := "refs/remotes/" + defaults.Remote + "/" + defaults.BaseBranch
+	fake.ancestors[repoDir+"|refs/heads/pr-head->"+baseRef] = false
+	report, err := CheckRepoSafety(context.Background(), RepoSafetyInput{
+		WorkspaceRoot: root,
+		Repo:          config.RepoConfig{Name: "repo", RepoDir: "repo", Branch: "pr-head"},
+		Defaults:      defaults,
+		RepoDefaults:  RepoDefaults{Remote: defaults.Remote, DefaultBranch: defaults.BaseBranch},
+		Git:           fake,
+	})
+	if err != nil {
+		t.Fatalf("CheckRepoSafety: %v", err)
+	}
+	if len(report.Branches) != 1 {
+		t.Fatalf("expected 1 branch, got %d", len(report.Branches))
+	}
+	entry := report.Branches[0]
+	if entry.Branch != "pr-head" || entry.Path != repoDir {
+		t.Fatalf("expected the pull request head checked out at %s, got %+v", repoDir, entry)
+	}
+	if !entry.Unmerged {
+		t.Fatalf("expected the unmerged pull request head to be reported, got %+v", entry)
+	}
+}
+
 func TestCheckRepoSafetyMissingRemote(t *testing.T) {
 	root := t.TempDir()
 	defaults := config.Defaults{}
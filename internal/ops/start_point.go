@@ -0,0 +1,93 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strantalis/workset/internal/git"
+)
+
+type startPoint struct {
+	// Ref is passed to WorktreeAdd as StartRef; empty checks out an existing
+	// local branch as is.
+	Ref string
+	// Base is the branch named by the start point, if any.
+	Base     string
+	Warnings []string
+}
+
+// resolveStartPoint picks where branchName starts when a repo is added with
+// an explicit start ref or an existing branch. Remote-tracking branches win
+// over local ones so a fresh fetch is used as the start point.
+func resolveStartPoint(
+	ctx context.Context,
+	client git.Client,
+	repoPath string,
+	remote string,
+	branchName string,
+	fromRef string,
+	existing bool,
+) (startPoint, error) {
+	var start startPoint
+	if remote != "" {
+		if err := client.Fetch(ctx, repoPath, remote); err != nil {
+			if existing {
+				return startPoint{}, fmt.Errorf("fetch %s: %w", remote, err)
+			}
+			start.Warnings = append(start.Warnings, fmt.Sprintf("fetch %s failed: %v", remote, err))
+		}
+	}
+	localExists, err := client.ReferenceExists(ctx, repoPath, "refs/heads/"+branchName)
+	if err != nil {
+		return startPoint{}, err
+	}
+
+	if existing {
+		if remote == "" {
+			return startPoint{}, fmt.Errorf("remote required to check out existing branch %q", branchName)
+		}
+		remoteExists, err := client.ReferenceExists(ctx, repoPath, "refs/remotes/"+remote+"/"+branchName)
+		if err != nil {
+			return startPoint{}, err
+		}
+		switch {
+		case localExists:
+		case remoteExists:
+			start.Ref = remote + "/" + branchName
+		default:
+			return startPoint{}, fmt.Errorf("branch %q not found on remote %q", branchName, remote)
+		}
+		return start, nil
+	}
+
+	if localExists {
+		return startPoint{}, fmt.Errorf("branch %q already exists; a start point only applies to new branches", branchName)
+	}
+	if remote != "" {
+		if ok, err := client.ReferenceExists(ctx, repoPath, "refs/remotes/"+remote+"/"+fromRef); err != nil {
+			return startPoint{}, err
+		} else if ok {
+			start.Ref, start.Base = remote+"/"+fromRef, fromRef
+			return start, nil
+		}
+	}
+	if ok, err := client.ReferenceExists(ctx, repoPath, "refs/heads/"+fromRef); err != nil {
+		return startPoint{}, err
+	} else if ok {
+		start.Ref, start.Base = fromRef, fromRef
+		return start, nil
+	}
+	if remote != "" && strings.HasPrefix(fromRef, remote+"/") {
+		if ok, err := client.ReferenceExists(ctx, repoPath, "refs/remotes/"+fromRef); err != nil {
+			return startPoint{}, err
+		} else if ok {
+			start.Ref, start.Base = fromRef, strings.TrimPrefix(fromRef, remote+"/")
+			return start, nil
+		}
+	}
+	// Tags and commits start the branch but leave the base unchanged;
+	// WorktreeAdd reports refs that do not resolve.
+	start.Ref = fromRef
+	return start, nil
+}
@@ -3,6 +3,7 @@ package ops
 import (
 	"context"
 	"errors"
+	"os"
 
 	"github.com/strantalis/workset/internal/config"
 	"github.com/strantalis/workset/internal/git"
@@ -15,13 +16,24 @@ type RepoStatus struct {
 	Dirty   bool
 	Missing bool
 	Err     error
+	// Detail is set when StatusInput.Detailed is true and the worktree exists.
+	Detail *git.StatusDetail
+	// FetchErr records a failed remote refresh; status is still reported.
+	FetchErr error
 }
 
 type StatusInput struct {
 	WorkspaceRoot       string
 	Defaults            config.Defaults
 	RepoDefaultBranches map[string]string
-	Git                 git.Client
+	// RepoDefaults supplies the remote and base branch used for Fetch and
+	// base ahead/behind counts.
+	RepoDefaults map[string]RepoDefaults
+	// Detailed requests branch, upstream, stash, and conflict details.
+	Detailed bool
+	// Fetch refreshes each repo's remote before reading status.
+	Fetch bool
+	Git   git.Client
 }
 
 func Status(ctx context.Context, input StatusInput) ([]RepoStatus, error) {
@@ -39,17 +51,7 @@ func Status(ctx context.Context, input StatusInput) ([]RepoStatus, error) {
 
 	var results []RepoStatus
 	for _, repo := range ws.Config.Repos {
-		config.ApplyRepoDefaults(&repo, input.Defaults)
-		branch := ws.State.CurrentBranch
-		if branch == "" {
-			if input.RepoDefaultBranches != nil {
-				branch = input.RepoDefaultBranches[repo.Name]
-			}
-			if branch == "" {
-				branch = input.Defaults.BaseBranch
-			}
-		}
-		path := workspace.RepoWorktreePath(ws.Root, branch, repo.RepoDir)
+		path := repoWorktreePath(ws, repo, input.Defaults, input.RepoDefaultBranches)
 		if path == "" {
 			results = append(results, RepoStatus{
 				Name: repo.Name,
@@ -57,6 +59,10 @@ func Status(ctx context.Context, input StatusInput) ([]RepoStatus, error) {
 			})
 			continue
 		}
+		if input.Detailed || input.Fetch {
+			results = append(results, detailedStatus(ctx, input, repo.Name, path))
+			continue
+		}
 		status, err := input.Git.Status(path)
 		if err != nil && !status.Missing {
 			results = append(results, RepoStatus{
@@ -76,3 +82,74 @@ func Status(ctx context.Context, input StatusInput) ([]RepoStatus, error) {
 
 	return results, nil
 }
+
+// repoWorktreePath returns the thread worktree for repo, falling back to the
+// repo's default branch when the thread state has no current branch.
+func repoWorktreePath(ws workspace.Workspace, repo config.RepoConfig, defaults config.Defaults, defaultBranches map[string]string) string {
+	config.ApplyRepoDefaults(&repo, defaults)
+	branch := ws.State.CurrentBranch
+	if branch == "" {
+		if defaultBranches != nil {
+			branch = defaultBranches[repo.Name]
+		}
+		if branch == "" {
+			branch = defaults.BaseBranch
+		}
+	}
+	return workspace.RepoWorktreePath(ws.Root, branch, repo.RepoDir)
+}
+
+func detailedStatus(ctx context.Context, input StatusInput, repoName, path string) RepoStatus {
+	result := RepoStatus{Name: repoName, Path: path}
+	defaults := input.RepoDefaults[repoName]
+	remote := defaults.Remote
+	if remote == "" {
+		remote = input.Defaults.Remote
+	}
+	baseBranch := defaults.DefaultBranch
+	if baseBranch == "" {
+		baseBranch = input.Defaults.BaseBranch
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			result.Missing = true
+			return result
+		}
+		result.Err = err
+		return result
+	}
+	if input.Fetch && remote != "" {
+		if err := input.Git.Fetch(ctx, path, remote); err != nil {
+			result.FetchErr = err
+		}
+	}
+
+	detail, err := input.Git.StatusDetail(ctx, path, BaseRef(ctx, input.Git, path, remote, baseBranch))
+	if err != nil && !detail.Missing {
+		result.Err = err
+		return result
+	}
+	result.Dirty = detail.Dirty
+	result.Missing = detail.Missing
+	if !detail.Missing {
+		result.Detail = &detail
+	}
+	return result
+}
+
+// BaseRef prefers the remote-tracking base branch so ahead/behind counts and
+// thread logs reflect what a pull request would target, falling back to the
+// local branch.
+func BaseRef(ctx context.Context, client git.Client, path, remote, baseBranch string) string {
+	if baseBranch == "" {
+		return ""
+	}
+	if remote != "" {
+		ref := "refs/remotes/" + remote + "/" + baseBranch
+		if exists, err := client.ReferenceExists(ctx, path, ref); err == nil && exists {
+			return ref
+		}
+	}
+	return "refs/heads/" + baseBranch
+}
@@ -0,0 +1,209 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+// SyncAction selects whether Sync starts integrating upstream changes or
+// resolves an integration stopped by a previous run.
+type SyncAction string
+
+const (
+	SyncStart    SyncAction = ""
+	SyncContinue SyncAction = "continue"
+	SyncAbort    SyncAction = "abort"
+)
+
+// SyncOutcome describes what Sync did to a single repo.
+type SyncOutcome string
+
+const (
+	SyncSynced     SyncOutcome = "synced"
+	SyncUpToDate   SyncOutcome = "up_to_date"
+	SyncConflicted SyncOutcome = "conflicted"
+	SyncAborted    SyncOutcome = "aborted"
+	SyncSkipped    SyncOutcome = "skipped"
+	SyncFailed     SyncOutcome = "failed"
+)
+
+type RepoSync struct {
+	Name    string
+	Path    string
+	Outcome SyncOutcome
+	// Mode is the rebase or merge that ran, or that was found in progress.
+	Mode    git.IntegrateMode
+	BaseRef string
+	// Conflicts lists unmerged paths when Outcome is SyncConflicted.
+	Conflicts []string
+	// Reason explains SyncSkipped and in-progress conflicts.
+	Reason string
+	Err    error
+}
+
+type SyncInput struct {
+	WorkspaceRoot       string
+	Defaults            config.Defaults
+	RepoDefaultBranches map[string]string
+	// RepoDefaults supplies the remote and base branch each repo syncs with.
+	RepoDefaults map[string]RepoDefaults
+	Mode         git.IntegrateMode
+	Action       SyncAction
+	Git          git.Client
+}
+
+// Sync brings each thread worktree up to date with <remote>/<default_branch>.
+// Repos are handled independently: a conflict stops that repo's rebase or
+// merge in place and is reported, while the remaining repos still sync.
+// SyncContinue and SyncAbort act only on repos with a stopped integration.
+func Sync(ctx context.Context, input SyncInput) ([]RepoSync, error) {
+	if input.WorkspaceRoot == "" {
+		return nil, errors.New("workspace root required")
+	}
+	if input.Git == nil {
+		return nil, errors.New("git client required")
+	}
+	if input.Action == SyncStart && input.Mode != git.IntegrateRebase && input.Mode != git.IntegrateMerge {
+		return nil, fmt.Errorf("unsupported sync mode %q", input.Mode)
+	}
+
+	ws, err := workspace.Load(input.WorkspaceRoot, input.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RepoSync, 0, len(ws.Config.Repos))
+	for _, repo := range ws.Config.Repos {
+		result := RepoSync{
+			Name: repo.Name,
+			Path: repoWorktreePath(ws, repo, input.Defaults, input.RepoDefaultBranches),
+		}
+		switch {
+		case result.Path == "":
+			result.Outcome = SyncFailed
+			result.Err = errors.New("local_path missing")
+		default:
+			if _, err := os.Stat(result.Path); err != nil {
+				result.Outcome = SyncFailed
+				result.Err = err
+				if os.IsNotExist(err) {
+					result.Err = errors.New("worktree missing")
+				}
+				break
+			}
+			switch input.Action {
+			case SyncContinue, SyncAbort:
+				resolveStoppedSync(ctx, input, &result)
+			default:
+				startSync(ctx, input, &result)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func startSync(ctx context.Context, input SyncInput, result *RepoSync) {
+	if mode, inProgress, err := input.Git.IntegrationInProgress(ctx, result.Path); err != nil {
+		result.Outcome, result.Err = SyncFailed, err
+		return
+	} else if inProgress {
+		result.Outcome = SyncConflicted
+		result.Mode = mode
+		result.Reason = fmt.Sprintf("%s already in progress; continue or abort it first", mode)
+		if detail, err := input.Git.StatusDetail(ctx, result.Path, ""); err == nil {
+			result.Conflicts = detail.Conflicts
+		}
+		return
+	}
+	detail, err := input.Git.StatusDetail(ctx, result.Path, "")
+	if err != nil {
+		result.Outcome, result.Err = SyncFailed, err
+		return
+	}
+	switch {
+	case detail.Dirty:
+		result.Outcome, result.Reason = SyncSkipped, "uncommitted changes"
+		return
+	case detail.Detached:
+		result.Outcome, result.Reason = SyncSkipped, "detached HEAD"
+		return
+	}
+
+	defaults := input.RepoDefaults[result.Name]
+	remote := defaults.Remote
+	if remote == "" {
+		remote = input.Defaults.Remote
+	}
+	baseBranch := defaults.DefaultBranch
+	if baseBranch == "" {
+		baseBranch = input.Defaults.BaseBranch
+	}
+	if remote == "" || baseBranch == "" {
+		result.Outcome, result.Err = SyncFailed, errors.New("remote and default branch required")
+		return
+	}
+	if err := input.Git.Fetch(ctx, result.Path, remote); err != nil {
+		result.Outcome, result.Err = SyncFailed, fmt.Errorf("fetch %s: %w", remote, err)
+		return
+	}
+	result.BaseRef = "refs/remotes/" + remote + "/" + baseBranch
+	if exists, err := input.Git.ReferenceExists(ctx, result.Path, result.BaseRef); err != nil {
+		result.Outcome, result.Err = SyncFailed, err
+		return
+	} else if !exists {
+		result.Outcome, result.Err = SyncFailed, fmt.Errorf("%s/%s not found", remote, baseBranch)
+		return
+	}
+	if merged, err := input.Git.IsAncestor(result.Path, result.BaseRef, "HEAD"); err != nil {
+		result.Outcome, result.Err = SyncFailed, err
+		return
+	} else if merged {
+		result.Outcome = SyncUpToDate
+		return
+	}
+
+	result.Mode = input.Mode
+	applySyncResult(result, input.Git.Integrate(ctx, result.Path, result.BaseRef, input.Mode))
+}
+
+func resolveStoppedSync(ctx context.Context, input SyncInput, result *RepoSync) {
+	mode, inProgress, err := input.Git.IntegrationInProgress(ctx, result.Path)
+	if err != nil {
+		result.Outcome, result.Err = SyncFailed, err
+		return
+	}
+	if !inProgress {
+		result.Outcome, result.Reason = SyncSkipped, "no rebase or merge in progress"
+		return
+	}
+	result.Mode = mode
+	if input.Action == SyncAbort {
+		if err := input.Git.AbortIntegration(ctx, result.Path, mode); err != nil {
+			result.Outcome, result.Err = SyncFailed, err
+			return
+		}
+		result.Outcome = SyncAborted
+		return
+	}
+	applySyncResult(result, input.Git.ContinueIntegration(ctx, result.Path, mode))
+}
+
+func applySyncResult(result *RepoSync, err error) {
+	var conflict git.ConflictError
+	switch {
+	case err == nil:
+		result.Outcome = SyncSynced
+	case errors.As(err, &conflict):
+		result.Outcome = SyncConflicted
+		result.Conflicts = conflict.Files
+	default:
+		result.Outcome, result.Err = SyncFailed, err
+	}
+}
@@ -0,0 +1,109 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func setupSyncWorkspace(t *testing.T) (string, string, string, config.Defaults) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	source := setupRepo(t)
+	addRemote(t, source, "origin", source)
+	root := filepath.Join(t.TempDir(), "ws")
+	defaults := config.DefaultConfig().Defaults
+	if _, err := workspace.Init(root, "demo", defaults); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, _, _, err := AddRepo(context.Background(), AddRepoInput{
+		WorkspaceRoot: root,
+		Name:          "demo-repo",
+		URL:           source,
+		Defaults:      defaults,
+		Remote:        defaults.Remote,
+		DefaultBranch: defaults.BaseBranch,
+		Git:           git.NewCLIClient(),
+	}); err != nil {
+		t.Fatalf("AddRepo: %v", err)
+	}
+	worktree := workspace.RepoWorktreePath(root, "demo", "demo-repo")
+	runGit(t, worktree, "config", "user.name", "Tester")
+	runGit(t, worktree, "config", "user.email", "tester@example.com")
+	return source, root, worktree, defaults
+}
+
+func commitSyncFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "update "+name)
+}
+
+func runSync(t *testing.T, root string, defaults config.Defaults, mode git.IntegrateMode, action SyncAction) RepoSync {
+	t.Helper()
+	results, err := Sync(context.Background(), SyncInput{
+		WorkspaceRoot:       root,
+		Defaults:            defaults,
+		RepoDefaultBranches: map[string]string{"demo-repo": defaults.BaseBranch},
+		Mode:                mode,
+		Action:              action,
+		Git:                 git.NewCLIClient(),
+	})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	return results[0]
+}
+
+func TestSyncRebasesOntoUpdatedBase(t *testing.T) {
+	source, root, worktree, defaults := setupSyncWorkspace(t)
+	commitSyncFile(t, worktree, "feature.txt", "feature")
+	commitSyncFile(t, source, "upstream.txt", "upstream")
+
+	result := runSync(t, root, defaults, git.IntegrateRebase, SyncStart)
+	if result.Outcome != SyncSynced || result.Err != nil || result.BaseRef != "refs/remotes/origin/main" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(worktree, "upstream.txt")); err != nil {
+		t.Fatalf("expected upstream change in worktree: %v", err)
+	}
+
+	if again := runSync(t, root, defaults, git.IntegrateRebase, SyncStart); again.Outcome != SyncUpToDate {
+		t.Fatalf("expected up to date, got %+v", again)
+	}
+}
+
+func TestSyncReportsConflictsAndAborts(t *testing.T) {
+	source, root, worktree, defaults := setupSyncWorkspace(t)
+	commitSyncFile(t, worktree, "README.md", "feature")
+	commitSyncFile(t, source, "README.md", "upstream")
+
+	result := runSync(t, root, defaults, git.IntegrateMerge, SyncStart)
+	if result.Outcome != SyncConflicted || result.Mode != git.IntegrateMerge || strings.Join(result.Conflicts, ",") != "README.md" {
+		t.Fatalf("expected merge conflict on README.md, got %+v", result)
+	}
+	if blocked := runSync(t, root, defaults, git.IntegrateRebase, SyncStart); blocked.Outcome != SyncConflicted || blocked.Reason == "" {
+		t.Fatalf("expected in-progress merge to block a new sync, got %+v", blocked)
+	}
+	if aborted := runSync(t, root, defaults, "", SyncAbort); aborted.Outcome != SyncAborted {
+		t.Fatalf("expected abort, got %+v", aborted)
+	}
+	if skipped := runSync(t, root, defaults, "", SyncContinue); skipped.Outcome != SyncSkipped {
+		t.Fatalf("expected nothing to continue, got %+v", skipped)
+	}
+}
@@ -104,6 +104,24 @@ func TestPrintStatusPlain(t *testing.T) {
 	}
 }
 
+func TestPrintStatusPlainExtendedColumns(t *testing.T) {
+	var buf bytes.Buffer
+	styles := Styles{Enabled: false}
+	rows := []StatusRow{
+		{Name: "repo1", State: "dirty", Branch: "feature", Sync: "+1/-0 origin/feature", PR: "#7 open", Detail: "/tmp/repo1"},
+		{Name: "repo2", State: "missing", Detail: "/tmp/repo2"},
+	}
+	if err := PrintStatus(&buf, styles, rows); err != nil {
+		t.Fatalf("PrintStatus failed: %v", err)
+	}
+	want := "REPO\tSTATE\tBRANCH\tSYNC\tPR\tDETAIL\n" +
+		"repo1\tdirty\tfeature\t+1/-0 origin/feature\t#7 open\t/tmp/repo1\n" +
+		"repo2\tmissing\t\t\t\t/tmp/repo2\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected status output: got %q want %q", buf.String(), want)
+	}
+}
+
 func TestPrintWorkspaceCreatedPlain(t *testing.T) {
 	var buf bytes.Buffer
 	info := WorkspaceCreated{
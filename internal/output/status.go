@@ -5,13 +5,26 @@ import (
 	"io"
 )
 
+// StatusRow is a single repo line in status output. Branch, Sync, and PR are
+// optional; the extra columns are rendered only when some row sets them.
 type StatusRow struct {
 	Name   string
 	State  string
+	Branch string
+	Sync   string
+	PR     string
 	Detail string
 }
 
 func PrintStatus(w io.Writer, styles Styles, rows []StatusRow) error {
+	extended := false
+	for _, row := range rows {
+		if row.Branch != "" || row.Sync != "" || row.PR != "" {
+			extended = true
+			break
+		}
+	}
+
 	tableRows := make([][]string, 0, len(rows))
 	for _, row := range rows {
 		name := row.Name
@@ -25,7 +38,7 @@ func PrintStatus(w io.Writer, styles Styles, rows []StatusRow) error {
 				state = styles.Render(styles.Success, state)
 			case "dirty":
 				state = styles.Render(styles.Warn, state)
-			case "missing":
+			case "missing", "conflicted":
 				state = styles.Render(styles.Error, state)
 			case "error":
 				state = styles.Render(styles.Error, state)
@@ -34,10 +47,18 @@ func PrintStatus(w io.Writer, styles Styles, rows []StatusRow) error {
 			}
 		}
 
+		if extended {
+			tableRows = append(tableRows, []string{name, state, row.Branch, row.Sync, row.PR, detail})
+			continue
+		}
 		tableRows = append(tableRows, []string{name, state, detail})
 	}
 
-	rendered := RenderTable(styles, []string{"REPO", "STATE", "DETAIL"}, tableRows)
+	headers := []string{"REPO", "STATE", "DETAIL"}
+	if extended {
+		headers = []string{"REPO", "STATE", "BRANCH", "SYNC", "PR", "DETAIL"}
+	}
+	rendered := RenderTable(styles, headers, tableRows)
 	_, err := fmt.Fprint(w, rendered)
 	return err
 }
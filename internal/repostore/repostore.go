@@ -0,0 +1,138 @@
+// Package repostore manages the repo store: one bare mirror per URL-registered
+// repo under defaults.repo_store_root, shared by the worktrees of every
+// thread that uses the repo.
+package repostore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metadataFile lives in a mirror's git dir, next to HEAD, so it never shows
+// up as an untracked file.
+const metadataFile = "workset-store.json"
+
+// Metadata is what the store records about a mirror beyond what git keeps.
+type Metadata struct {
+	URL       string    `json:"url,omitempty"`
+	LastFetch time.Time `json:"last_fetch"`
+}
+
+// MirrorPath returns where the mirror for a registered repo lives.
+func MirrorPath(root, name string) string {
+	return filepath.Join(root, name)
+}
+
+// GitDir returns the git dir of a store entry: the entry itself for a bare
+// mirror, or its .git directory for a clone made before the store switched to
+// bare mirrors. ok is false when path is not a repo.
+func GitDir(path string) (gitDir string, bare bool, ok bool) {
+	if isBare(path) {
+		return path, true, true
+	}
+	dotGit := filepath.Join(path, ".git")
+	if info, err := os.Stat(dotGit); err == nil && info.IsDir() {
+		return dotGit, false, true
+	}
+	return "", false, false
+}
+
+func isBare(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(path, "objects"))
+	return err == nil && info.IsDir()
+}
+
+// ReadMetadata loads a mirror's metadata. A mirror that was never fetched
+// through the store has zero metadata and no error.
+func ReadMetadata(gitDir string) (Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, metadataFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Metadata{}, nil
+		}
+		return Metadata{}, err
+	}
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return Metadata{}, fmt.Errorf("parse %s: %w", metadataFile, err)
+	}
+	return meta, nil
+}
+
+// RecordFetch stamps a mirror as fetched from url at the given time.
+func RecordFetch(gitDir, url string, at time.Time) error {
+	meta, err := ReadMetadata(gitDir)
+	if err != nil {
+		meta = Metadata{}
+	}
+	if url != "" {
+		meta.URL = url
+	}
+	meta.LastFetch = at.UTC()
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(gitDir, metadataFile+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(gitDir, metadataFile))
+}
+
+// Fresh reports whether the mirror was fetched within maxAge of now. A zero
+// maxAge is never fresh.
+func Fresh(gitDir string, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	meta, err := ReadMetadata(gitDir)
+	if err != nil || meta.LastFetch.IsZero() {
+		return false
+	}
+	return now.Sub(meta.LastFetch) < maxAge
+}
+
+// ParseInterval parses a repo_store duration setting. Empty and "0" mean off.
+func ParseInterval(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("duration %q must not be negative", value)
+	}
+	return duration, nil
+}
+
+// Size returns the bytes used on disk by the files under path.
+func Size(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
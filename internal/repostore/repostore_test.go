@@ -0,0 +1,83 @@
+package repostore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitDirDetectsBareAndLegacyClones(t *testing.T) {
+	root := t.TempDir()
+	bare := filepath.Join(root, "bare")
+	if err := os.MkdirAll(filepath.Join(bare, "objects"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bare, "HEAD"), []byte("ref: refs/heads/main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(root, "legacy")
+	if err := os.MkdirAll(filepath.Join(legacy, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if gitDir, isBare, ok := GitDir(bare); !ok || !isBare || gitDir != bare {
+		t.Fatalf("bare mirror: got %q bare=%v ok=%v", gitDir, isBare, ok)
+	}
+	if gitDir, isBare, ok := GitDir(legacy); !ok || isBare || gitDir != filepath.Join(legacy, ".git") {
+		t.Fatalf("legacy clone: got %q bare=%v ok=%v", gitDir, isBare, ok)
+	}
+	if _, _, ok := GitDir(filepath.Join(root, "missing")); ok {
+		t.Fatalf("expected missing entry to not be a repo")
+	}
+}
+
+func TestRecordFetchAndFresh(t *testing.T) {
+	gitDir := t.TempDir()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if Fresh(gitDir, time.Hour, now) {
+		t.Fatalf("expected never-fetched mirror to be stale")
+	}
+	if err := RecordFetch(gitDir, "https://example.com/demo.git", now.Add(-10*time.Minute)); err != nil {
+		t.Fatalf("RecordFetch: %v", err)
+	}
+	meta, err := ReadMetadata(gitDir)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if meta.URL != "https://example.com/demo.git" {
+		t.Fatalf("unexpected url %q", meta.URL)
+	}
+	if !Fresh(gitDir, 15*time.Minute, now) {
+		t.Fatalf("expected mirror fetched 10m ago to be fresh for 15m")
+	}
+	if Fresh(gitDir, 5*time.Minute, now) {
+		t.Fatalf("expected mirror fetched 10m ago to be stale for 5m")
+	}
+	if Fresh(gitDir, 0, now) {
+		t.Fatalf("expected zero max age to never be fresh")
+	}
+
+	if err := RecordFetch(gitDir, "", now); err != nil {
+		t.Fatalf("RecordFetch: %v", err)
+	}
+	meta, _ = ReadMetadata(gitDir)
+	if meta.URL != "https://example.com/demo.git" || !meta.LastFetch.Equal(now) {
+		t.Fatalf("expected url kept and fetch time updated, got %+v", meta)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": 0, "0": 0, "15m": 15 * time.Minute, " 1h ": time.Hour} {
+		got, err := ParseInterval(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseInterval(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"soon", "-5m"} {
+		if _, err := ParseInterval(value); err == nil {
+			t.Fatalf("expected ParseInterval(%q) to fail", value)
+		}
+	}
+}
@@ -24,3 +24,13 @@ func DefaultShell() string {
 	}
 	return "/bin/sh"
 }
+
+// ShellCommand returns the command that runs a command line through the
+// platform's shell: cmd.exe /C on Windows and /bin/sh -c elsewhere, whatever
+// the user's interactive shell is.
+func ShellCommand(line string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return DefaultShell(), []string{"/C", line}
+	}
+	return "/bin/sh", []string{"-c", line}
+}
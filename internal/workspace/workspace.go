@@ -29,27 +29,36 @@ type Workspace struct {
 }
 
 type State struct {
-	CurrentBranch string                      `json:"current_branch"`
-	PullRequests  map[string]PullRequestState `json:"pull_requests,omitempty"`
+	CurrentBranch string                      `yaml:"current_branch" json:"current_branch"`
+	PullRequests  map[string]PullRequestState `yaml:"pull_requests,omitempty" json:"pull_requests,omitempty"`
+	// PullRequestGroup links the thread's pull requests so each one carries a
+	// "Related PRs" section pointing at the others.
+	PullRequestGroup *PullRequestGroup `yaml:"pull_request_group,omitempty" json:"pull_request_group,omitempty"`
+}
+
+// PullRequestGroup records which repos' tracked pull requests are linked.
+type PullRequestGroup struct {
+	Repos     []string `yaml:"repos" json:"repos"`
+	UpdatedAt string   `yaml:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
 
 type PullRequestState struct {
-	Repo                string `json:"repo"`
-	Number              int    `json:"number"`
-	URL                 string `json:"url"`
-	Title               string `json:"title"`
-	Body                string `json:"body,omitempty"`
-	State               string `json:"state"`
-	Draft               bool   `json:"draft"`
-	Merged              bool   `json:"merged"`
-	BaseRepo            string `json:"base_repo"`
-	BaseBranch          string `json:"base_branch"`
-	HeadRepo            string `json:"head_repo"`
-	HeadBranch          string `json:"head_branch"`
-	UpdatedAt           string `json:"updated_at,omitempty"`
-	Author              string `json:"author,omitempty"`
-	CommentsCount       int    `json:"comments_count,omitempty"`
-	ReviewCommentsCount int    `json:"review_comments_count,omitempty"`
+	Repo                string `yaml:"repo" json:"repo"`
+	Number              int    `yaml:"number" json:"number"`
+	URL                 string `yaml:"url" json:"url"`
+	Title               string `yaml:"title" json:"title"`
+	Body                string `yaml:"body,omitempty" json:"body,omitempty"`
+	State               string `yaml:"state" json:"state"`
+	Draft               bool   `yaml:"draft" json:"draft"`
+	Merged              bool   `yaml:"merged" json:"merged"`
+	BaseRepo            string `yaml:"base_repo" json:"base_repo"`
+	BaseBranch          string `yaml:"base_branch" json:"base_branch"`
+	HeadRepo            string `yaml:"head_repo" json:"head_repo"`
+	HeadBranch          string `yaml:"head_branch" json:"head_branch"`
+	UpdatedAt           string `yaml:"updated_at,omitempty" json:"updated_at,omitempty"`
+	Author              string `yaml:"author,omitempty" json:"author,omitempty"`
+	CommentsCount       int    `yaml:"comments_count,omitempty" json:"comments_count,omitempty"`
+	ReviewCommentsCount int    `yaml:"review_comments_count,omitempty" json:"review_comments_count,omitempty"`
 }
 
 func WorksetFile(root string) string {
@@ -0,0 +1,16 @@
+package terminalservice
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// ansiSequence matches CSI and OSC sequences, then any other escape with
+// its intermediate bytes, such as charset selection or keypad modes.
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[ -/]*[0-~]`)
+
+// StripANSI removes terminal escape sequences and carriage returns, leaving
+// the text a terminal would show line by line.
+func StripANSI(data []byte) []byte {
+	return bytes.ReplaceAll(ansiSequence.ReplaceAll(data, nil), []byte("\r"), nil)
+}
@@ -0,0 +1,10 @@
+package terminalservice
+
+import "testing"
+
+func TestStripANSI(t *testing.T) {
+	input := "\x1b[1;32mready\x1b[0m on \x1b]8;;http://localhost\x07port\x1b]8;;\x07 3000\r\n\x1b=done"
+	if got := string(StripANSI([]byte(input))); got != "ready on port 3000\ndone" {
+		t.Fatalf("unexpected stripped output %q", got)
+	}
+}
@@ -31,6 +31,14 @@ func (c *Client) Create(ctx context.Context, sessionID, cwd string) (CreateRespo
 	return resp, err
 }
 
+// CreateSession is Create with a command, environment, initial size and
+// restart policy.
+func (c *Client) CreateSession(ctx context.Context, req CreateRequest) (CreateResponse, error) {
+	var resp CreateResponse
+	err := c.call(ctx, "create", req, &resp)
+	return resp, err
+}
+
 func (c *Client) Send(ctx context.Context, sessionID, data string) error {
 	return c.call(ctx, "send", SendRequest{SessionID: sessionID, Data: data}, nil)
 }
@@ -39,6 +47,12 @@ func (c *Client) Resize(ctx context.Context, sessionID string, cols, rows int) e
 	return c.call(ctx, "resize", ResizeRequest{SessionID: sessionID, Cols: cols, Rows: rows}, nil)
 }
 
+// Handoff gives input ownership of a session to the attached stream
+// streamID, or releases it when streamID is empty.
+func (c *Client) Handoff(ctx context.Context, sessionID, streamID string) error {
+	return c.call(ctx, "handoff", HandoffRequest{SessionID: sessionID, StreamID: streamID}, nil)
+}
+
 func (c *Client) Stop(ctx context.Context, sessionID string) error {
 	return c.call(ctx, "stop", StopRequest{SessionID: sessionID}, nil)
 }
@@ -49,6 +63,18 @@ func (c *Client) Inspect(ctx context.Context, sessionID string) (InspectResponse
 	return resp, err
 }
 
+func (c *Client) Read(ctx context.Context, req ReadRequest) (ReadResponse, error) {
+	var resp ReadResponse
+	err := c.call(ctx, "read", req, &resp)
+	return resp, err
+}
+
+func (c *Client) Search(ctx context.Context, req SearchRequest) (SearchResponse, error) {
+	var resp SearchResponse
+	err := c.call(ctx, "search", req, &resp)
+	return resp, err
+}
+
 func (c *Client) List(ctx context.Context) (ListResponse, error) {
 	var resp ListResponse
 	err := c.call(ctx, "list", struct{}{}, &resp)
@@ -14,6 +14,7 @@ type Options struct {
 	RecordDir               string
 	IdleTimeout             time.Duration
 	IdleTimeoutSet          bool
+	RestartDelay            time.Duration
 	BufferBytes             int
 	TranscriptMaxBytes      int64
 	TranscriptTrimThreshold int64
@@ -29,6 +30,7 @@ func DefaultOptions() Options {
 	return Options{
 		WebSocketHost:           "127.0.0.1",
 		IdleTimeout:             30 * time.Minute,
+		RestartDelay:            time.Second,
 		BufferBytes:             512 * 1024,
 		TranscriptMaxBytes:      5 * 1024 * 1024,
 		TranscriptTrimThreshold: 6 * 1024 * 1024,
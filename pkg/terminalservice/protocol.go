@@ -2,7 +2,9 @@ package terminalservice
 
 import "encoding/json"
 
-const ProtocolVersion = 2
+// ProtocolVersion is bumped whenever the wire format changes so that stale
+// clients fail with a protocol mismatch instead of misreading messages.
+const ProtocolVersion = 3
 
 type ControlRequest struct {
 	ProtocolVersion int             `json:"protocolVersion"`
@@ -16,9 +18,51 @@ type ControlResponse struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// Restart policies for CreateRequest.Restart.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+// Resize policies for CreateRequest.ResizePolicy. With ResizeSmallest the
+// terminal takes the smallest size any stream that can type asked for, so
+// every client sees all of it; with ResizeOwner the owning stream's size
+// wins and the smallest size applies while no stream owns the session.
+const (
+	ResizeSmallest = "smallest"
+	ResizeOwner    = "owner"
+)
+
+// Attach modes for AttachRequest.Mode. An interactive stream types and
+// resizes alongside other interactive streams while no stream owns the
+// session. An owner stream takes the session's input for itself, demoting
+// any previous owner; the others keep watching until it detaches or hands
+// off. A read-only stream only watches unless ownership is handed to it.
+const (
+	AttachOwner       = "owner"
+	AttachInteractive = "interactive"
+	AttachReadOnly    = "readonly"
+)
+
+// CreateRequest starts a session. Command defaults to the user's login
+// shell; Env entries override the inherited environment; Cols and Rows set
+// the initial terminal size. Restart decides whether the command is started
+// again when it exits and defaults to RestartNever. ResizePolicy decides
+// which attached stream sizes the terminal and defaults to ResizeSmallest.
 type CreateRequest struct {
-	SessionID string `json:"sessionId"`
-	Cwd       string `json:"cwd"`
+	SessionID string            `json:"sessionId"`
+	Cwd       string            `json:"cwd"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Cols      int               `json:"cols,omitempty"`
+	Rows      int               `json:"rows,omitempty"`
+	Restart   string            `json:"restart,omitempty"`
+	// KeepAlive exempts the session from the idle timeout, for supervised
+	// processes that run without anyone typing into them.
+	KeepAlive    bool   `json:"keepAlive,omitempty"`
+	ResizePolicy string `json:"resizePolicy,omitempty"`
 }
 
 type CreateResponse struct {
@@ -37,6 +81,13 @@ type ResizeRequest struct {
 	Rows      int    `json:"rows"`
 }
 
+// HandoffRequest gives input ownership of a session to the attached stream
+// StreamID, or releases it when StreamID is empty.
+type HandoffRequest struct {
+	SessionID string `json:"sessionId"`
+	StreamID  string `json:"streamId,omitempty"`
+}
+
 type StopRequest struct {
 	SessionID string `json:"sessionId"`
 }
@@ -45,6 +96,63 @@ type InspectRequest struct {
 	SessionID string `json:"sessionId"`
 }
 
+// ReadRequest reads a session's buffered output from Offset, or from the
+// oldest byte still buffered when Offset is older. MaxBytes keeps only the
+// newest bytes of the result when set.
+type ReadRequest struct {
+	SessionID string `json:"sessionId"`
+	Offset    int64  `json:"offset,omitempty"`
+	MaxBytes  int    `json:"maxBytes,omitempty"`
+}
+
+// ReadResponse carries output starting at Offset and ending at NextOffset.
+// Truncated reports that output between the requested and returned offsets
+// is no longer buffered.
+type ReadResponse struct {
+	SessionID  string `json:"sessionId"`
+	DataB64    string `json:"dataB64,omitempty"`
+	Offset     int64  `json:"offset"`
+	NextOffset int64  `json:"nextOffset"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+// SearchRequest finds lines of terminal output containing Query, or matching
+// it as a regular expression when Regex is set. Escape sequences are
+// stripped before matching. SessionID and Workspace narrow the search, and
+// Context asks for that many lines around each match.
+type SearchRequest struct {
+	Query      string `json:"query"`
+	Regex      bool   `json:"regex,omitempty"`
+	IgnoreCase bool   `json:"ignoreCase,omitempty"`
+	SessionID  string `json:"sessionId,omitempty"`
+	Workspace  string `json:"workspace,omitempty"`
+	Context    int    `json:"context,omitempty"`
+	MaxMatches int    `json:"maxMatches,omitempty"`
+}
+
+// SearchMatch is one matching line. For a live session Offset is the stream
+// offset of the line, usable as an attach StartOffset; for the transcript of
+// a session that has ended it is the byte offset in the transcript. Timestamp
+// is when the line was written, to about a second, or for an ended session
+// when its transcript was last written.
+type SearchMatch struct {
+	SessionID string   `json:"sessionId"`
+	Workspace string   `json:"workspace,omitempty"`
+	Offset    int64    `json:"offset"`
+	Timestamp string   `json:"timestamp,omitempty"`
+	Live      bool     `json:"live"`
+	Line      string   `json:"line"`
+	Before    []string `json:"before,omitempty"`
+	After     []string `json:"after,omitempty"`
+}
+
+// SearchResponse lists matches in session order; Truncated reports that
+// MaxMatches cut the list short.
+type SearchResponse struct {
+	Matches   []SearchMatch `json:"matches"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
 type ShutdownRequest struct {
 	Source     string `json:"source,omitempty"`
 	Reason     string `json:"reason,omitempty"`
@@ -52,12 +160,26 @@ type ShutdownRequest struct {
 	Executable string `json:"executable,omitempty"`
 }
 
+// SessionInfo describes a session. Running stays true while a restart is
+// pending; ExitCode and ExitedAt describe the command's most recent exit.
 type SessionInfo struct {
 	SessionID  string `json:"sessionId"`
 	Cwd        string `json:"cwd"`
 	StartedAt  string `json:"startedAt"`
 	LastActive string `json:"lastActive"`
 	Running    bool   `json:"running"`
+	Command    string `json:"command,omitempty"`
+	Restart    string `json:"restart,omitempty"`
+	Restarts   int    `json:"restarts,omitempty"`
+	ExitCode   *int   `json:"exitCode,omitempty"`
+	ExitedAt   string `json:"exitedAt,omitempty"`
+	// Restored is set when the session was recreated after the service
+	// restarted, with the earlier session's scrollback replayed.
+	Restored     bool   `json:"restored,omitempty"`
+	ResizePolicy string `json:"resizePolicy,omitempty"`
+	// Owner is the stream that holds the session's input, if any.
+	Owner   string           `json:"owner,omitempty"`
+	Streams []AttachedStream `json:"streams,omitempty"`
 }
 
 type InspectResponse struct {
@@ -66,6 +188,28 @@ type InspectResponse struct {
 	StartedAt  string `json:"startedAt"`
 	LastActive string `json:"lastActive"`
 	Running    bool   `json:"running"`
+	Command    string `json:"command,omitempty"`
+	Restart    string `json:"restart,omitempty"`
+	Restarts   int    `json:"restarts,omitempty"`
+	ExitCode   *int   `json:"exitCode,omitempty"`
+	ExitedAt   string `json:"exitedAt,omitempty"`
+	// Restored is set when the session was recreated after the service
+	// restarted, with the earlier session's scrollback replayed.
+	Restored     bool   `json:"restored,omitempty"`
+	ResizePolicy string `json:"resizePolicy,omitempty"`
+	// Owner is the stream that holds the session's input, if any.
+	Owner   string           `json:"owner,omitempty"`
+	Streams []AttachedStream `json:"streams,omitempty"`
+}
+
+// AttachedStream describes a stream attached to a session. Mode is
+// AttachOwner for the owning stream; Cols and Rows are the size it last
+// asked for.
+type AttachedStream struct {
+	StreamID string `json:"streamId"`
+	Mode     string `json:"mode"`
+	Cols     int    `json:"cols,omitempty"`
+	Rows     int    `json:"rows,omitempty"`
 }
 
 type ListResponse struct {
@@ -79,6 +223,8 @@ type AttachRequest struct {
 	StreamID        string `json:"streamId,omitempty"`
 	Token           string `json:"token,omitempty"`
 	StartOffset     int64  `json:"startOffset,omitempty"`
+	// Mode is one of the Attach modes and defaults to AttachInteractive.
+	Mode string `json:"mode,omitempty"`
 }
 
 type WebsocketControlRequest struct {
@@ -87,6 +233,8 @@ type WebsocketControlRequest struct {
 	Data            string `json:"data,omitempty"`
 	Cols            int    `json:"cols,omitempty"`
 	Rows            int    `json:"rows,omitempty"`
+	// StreamID names the stream a handoff gives ownership to.
+	StreamID string `json:"streamId,omitempty"`
 }
 
 type InfoResponse struct {
@@ -104,4 +252,13 @@ type StreamMessage struct {
 	Len        int    `json:"len,omitempty"`
 	NextOffset int64  `json:"nextOffset,omitempty"`
 	Error      string `json:"error,omitempty"`
+	ExitCode   *int   `json:"exitCode,omitempty"`
+	Restarting bool   `json:"restarting,omitempty"`
+	// Restored marks the ready message of a restored session.
+	Restored bool `json:"restored,omitempty"`
+	// Mode and Owner, sent with ready and with "mode" messages when
+	// ownership changes, are the stream's current mode and the stream that
+	// owns the session's input.
+	Mode  string `json:"mode,omitempty"`
+	Owner string `json:"owner,omitempty"`
 }
@@ -0,0 +1,246 @@
+package terminalservice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSearchMaxMatches = 200
+	// maxOutputMarks bounds the output timeline kept per session; when it
+	// fills up the older half is dropped.
+	maxOutputMarks = 4096
+)
+
+// outputMark records that output from offset on was written at or after at.
+type outputMark struct {
+	offset int64
+	at     time.Time
+}
+
+// markOutput extends the session's output timeline, at most once a second.
+func (s *Session) markOutput(offset int64, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.marks); n > 0 && now.Sub(s.marks[n-1].at) < time.Second {
+		return
+	}
+	if len(s.marks) >= maxOutputMarks {
+		s.marks = slices.Clone(s.marks[len(s.marks)/2:])
+	}
+	s.marks = append(s.marks, outputMark{offset: offset, at: now})
+}
+
+// outputTime returns about when the output at offset was written, or the
+// zero time when it predates the timeline.
+func (s *Session) outputTime(offset int64) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.marks), func(i int) bool {
+		return s.marks[i].offset > offset
+	})
+	if i == 0 {
+		return time.Time{}
+	}
+	return s.marks[i-1].at
+}
+
+// searchData returns the session's output that is still available, from the
+// transcript when it reaches further back than the buffer, and the stream
+// offset it starts at.
+func (s *Session) searchData() ([]byte, int64) {
+	s.outputMu.Lock()
+	defer s.outputMu.Unlock()
+	oldest, current := s.buffer.SnapshotOffsets()
+	data, _, _ := s.buffer.ReadSince(0)
+	start := oldest
+	if oldest > 0 {
+		if transcript, transcriptStart, _, err := s.readTranscriptSince(current, 0); err == nil && transcriptStart < oldest {
+			data, start = transcript, transcriptStart
+		}
+	}
+	return data, start
+}
+
+// search finds req.Query in the output of live sessions and in the
+// transcripts of sessions that have ended, live sessions first.
+func (s *Server) search(req SearchRequest) (SearchResponse, error) {
+	match, err := newSearchMatcher(req)
+	if err != nil {
+		return SearchResponse{}, err
+	}
+	limit := req.MaxMatches
+	if limit <= 0 {
+		limit = defaultSearchMaxMatches
+	}
+	resp := SearchResponse{Matches: []SearchMatch{}}
+	add := func(m SearchMatch) bool {
+		if len(resp.Matches) >= limit {
+			resp.Truncated = true
+			return false
+		}
+		resp.Matches = append(resp.Matches, m)
+		return true
+	}
+	wanted := func(id string) bool {
+		return (req.SessionID == "" || id == req.SessionID) &&
+			(req.Workspace == "" || SessionWorkspace(id) == req.Workspace)
+	}
+
+	s.mu.Lock()
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].id < sessions[j].id })
+
+	live := map[string]bool{}
+	for _, session := range sessions {
+		live[sanitizeID(session.id)] = true
+		if !wanted(session.id) || resp.Truncated {
+			continue
+		}
+		data, start := session.searchData()
+		searchLines(data, start, match, req.Context, func(offset int64, line string, before, after []string) bool {
+			m := SearchMatch{
+				SessionID: session.id,
+				Workspace: SessionWorkspace(session.id),
+				Offset:    offset,
+				Live:      true,
+				Line:      line,
+				Before:    before,
+				After:     after,
+			}
+			if at := session.outputTime(offset); !at.IsZero() {
+				m.Timestamp = at.Format(time.RFC3339)
+			}
+			return add(m)
+		})
+	}
+	if resp.Truncated || s.opts.TranscriptDir == "" {
+		return resp, nil
+	}
+
+	entries, err := os.ReadDir(s.opts.TranscriptDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return resp, nil
+		}
+		return SearchResponse{}, err
+	}
+	for _, entry := range entries {
+		base, ok := strings.CutSuffix(entry.Name(), ".log")
+		if !ok || entry.IsDir() || live[base] {
+			continue
+		}
+		path := filepath.Join(s.opts.TranscriptDir, entry.Name())
+		id := base
+		if meta, err := loadSessionMeta(filepath.Join(s.opts.TranscriptDir, base+sessionMetaSuffix)); err == nil {
+			id = meta.SessionID
+		}
+		if !wanted(id) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// Transcripts keep no timeline; the last write bounds every line.
+		modified := info.ModTime().Format(time.RFC3339)
+		searchLines(data, 0, match, req.Context, func(offset int64, line string, before, after []string) bool {
+			return add(SearchMatch{
+				SessionID: id,
+				Workspace: SessionWorkspace(id),
+				Offset:    offset,
+				Timestamp: modified,
+				Line:      line,
+				Before:    before,
+				After:     after,
+			})
+		})
+		if resp.Truncated {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// SessionWorkspace returns the workspace a session belongs to from its id,
+// which the desktop app and thread processes build as "<workspace>::<name>".
+func SessionWorkspace(sessionID string) string {
+	workspace, _, ok := strings.Cut(sessionID, "::")
+	if !ok {
+		return ""
+	}
+	return workspace
+}
+
+func newSearchMatcher(req SearchRequest) (func(string) bool, error) {
+	query := req.Query
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("search query required")
+	}
+	if req.Regex {
+		if req.IgnoreCase {
+			query = "(?i)" + query
+		}
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	if req.IgnoreCase {
+		query = strings.ToLower(query)
+		return func(line string) bool {
+			return strings.Contains(strings.ToLower(line), query)
+		}, nil
+	}
+	return func(line string) bool {
+		return strings.Contains(line, query)
+	}, nil
+}
+
+// searchLines matches data line by line with escape sequences stripped and
+// calls emit with the offset of each matching line's first byte, counted
+// from base, and up to context lines around it. It stops when emit returns
+// false.
+func searchLines(data []byte, base int64, match func(string) bool, context int, emit func(offset int64, line string, before, after []string) bool) {
+	var lines []string
+	var offsets []int64
+	for pos := 0; pos < len(data); {
+		end := bytes.IndexByte(data[pos:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += pos
+		}
+		lines = append(lines, string(StripANSI(data[pos:end])))
+		offsets = append(offsets, base+int64(pos))
+		pos = end + 1
+	}
+	context = max(context, 0)
+	for i, line := range lines {
+		if !match(line) {
+			continue
+		}
+		before := lines[max(0, i-context):i]
+		after := lines[i+1 : min(len(lines), i+1+context)]
+		if !emit(offsets[i], line, slices.Clone(before), slices.Clone(after)) {
+			return
+		}
+	}
+}
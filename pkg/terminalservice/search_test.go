@@ -0,0 +1,104 @@
+package terminalservice
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchLinesStripsEscapesAndReportsOffsets(t *testing.T) {
+	data := []byte("one\r\n\x1b[31mError:\x1b[0m disk full\r\ntwo\nthree\n")
+	match, err := newSearchMatcher(SearchRequest{Query: "error: DISK", IgnoreCase: true})
+	if err != nil {
+		t.Fatalf("matcher: %v", err)
+	}
+	var got []SearchMatch
+	searchLines(data, 100, match, 1, func(offset int64, line string, before, after []string) bool {
+		got = append(got, SearchMatch{Offset: offset, Line: line, Before: before, After: after})
+		return true
+	})
+	if len(got) != 1 {
+		t.Fatalf("expected one match, got %+v", got)
+	}
+	m := got[0]
+	if m.Offset != 105 || m.Line != "Error: disk full" || !slices.Equal(m.Before, []string{"one"}) || !slices.Equal(m.After, []string{"two"}) {
+		t.Fatalf("unexpected match %+v", m)
+	}
+
+	if _, err := newSearchMatcher(SearchRequest{Query: "("}); err != nil {
+		t.Fatalf("literal query should not be parsed as a pattern: %v", err)
+	}
+	if _, err := newSearchMatcher(SearchRequest{Query: "(", Regex: true}); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+	if _, err := newSearchMatcher(SearchRequest{Query: "  "}); err == nil {
+		t.Fatal("expected empty query error")
+	}
+}
+
+func TestSessionWorkspace(t *testing.T) {
+	for id, want := range map[string]string{
+		"demo::term-1":      "demo",
+		"demo::process:api": "demo",
+		"standalone":        "",
+	} {
+		if got := SessionWorkspace(id); got != want {
+			t.Fatalf("SessionWorkspace(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestSearchFindsLiveAndEndedSessions(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, id := range []string{"alpha::term-1", "beta::term-1"} {
+		if _, err := client.CreateSession(ctx, CreateRequest{SessionID: id, Cwd: "/tmp"}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if err := client.Send(ctx, "alpha::term-1", "printf 'before\\n\\033[31mpanic: boom\\033[0m\\nafter\\n'\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := client.Send(ctx, "beta::term-1", "echo panic: $((1+1))\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	waitForReadOutput(t, client, "alpha::term-1", 0, "after\r\n")
+	waitForReadOutput(t, client, "beta::term-1", 0, "panic: 2")
+	if err := client.Stop(ctx, "beta::term-1"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	resp, err := client.Search(ctx, SearchRequest{Query: `^panic: \w+$`, Regex: true, Context: 1})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(resp.Matches) != 2 {
+		t.Fatalf("expected a match in each session, got %+v", resp.Matches)
+	}
+	live, ended := resp.Matches[0], resp.Matches[1]
+	if live.SessionID != "alpha::term-1" || live.Workspace != "alpha" || !live.Live || live.Line != "panic: boom" ||
+		!slices.Equal(live.Before, []string{"before"}) || !slices.Equal(live.After, []string{"after"}) || live.Timestamp == "" {
+		t.Fatalf("unexpected live match %+v", live)
+	}
+	if ended.SessionID != "beta::term-1" || ended.Workspace != "beta" || ended.Live || ended.Line != "panic: 2" || ended.Timestamp == "" {
+		t.Fatalf("unexpected ended-session match %+v", ended)
+	}
+
+	replay := waitForReadOutput(t, client, "alpha::term-1", live.Offset, "panic")
+	if !strings.HasPrefix(string(StripANSI([]byte(replay))), "panic: boom") {
+		t.Fatalf("expected the match offset to start at the matching line, got %q", replay)
+	}
+
+	resp, err = client.Search(ctx, SearchRequest{Query: "panic", Workspace: "beta", MaxMatches: 1})
+	if err != nil {
+		t.Fatalf("search workspace: %v", err)
+	}
+	if len(resp.Matches) != 1 || resp.Matches[0].SessionID != "beta::term-1" {
+		t.Fatalf("expected only beta matches, got %+v", resp.Matches)
+	}
+}
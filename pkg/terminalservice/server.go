@@ -68,6 +68,9 @@ func NewServer(opts Options) *Server {
 	if opts.IdleTimeout == 0 && !opts.IdleTimeoutSet {
 		opts.IdleTimeout = DefaultOptions().IdleTimeout
 	}
+	if opts.RestartDelay == 0 {
+		opts.RestartDelay = DefaultOptions().RestartDelay
+	}
 	if opts.ProtocolLogEnabled && opts.ProtocolLogger == nil {
 		logger, err := unifiedlog.Open("terminal-service", opts.ProtocolLogDir)
 		if err != nil {
@@ -133,6 +136,7 @@ func (s *Server) Listen(ctx context.Context) error {
 		_ = os.Remove(s.opts.SocketPath)
 		s.closeAll()
 	}()
+	s.restoreSessions(ctx)
 
 	go func() {
 		<-ctx.Done()
@@ -198,7 +202,26 @@ func (s *Server) handleControl(ctx context.Context, conn net.Conn, line []byte)
 			s.writeError(conn, err)
 			return
 		}
-		session, existing, err := s.getOrCreate(ctx, params.SessionID, params.Cwd)
+		restart, err := normalizeRestartPolicy(params.Restart)
+		if err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		resizePolicy, err := normalizeResizePolicy(params.ResizePolicy)
+		if err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		session, existing, err := s.getOrCreate(ctx, params.SessionID, params.Cwd, sessionSpec{
+			command:      strings.TrimSpace(params.Command),
+			args:         params.Args,
+			env:          params.Env,
+			cols:         params.Cols,
+			rows:         params.Rows,
+			restart:      restart,
+			keepAlive:    params.KeepAlive,
+			resizePolicy: resizePolicy,
+		})
 		if err != nil {
 			s.writeError(conn, err)
 			return
@@ -238,6 +261,30 @@ func (s *Server) handleControl(ctx context.Context, conn net.Conn, line []byte)
 			return
 		}
 		_ = json.NewEncoder(conn).Encode(ControlResponse{OK: true, Result: session.inspect()})
+	case "read":
+		var params ReadRequest
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		session := s.get(params.SessionID)
+		if session == nil {
+			s.writeError(conn, errors.New("session not found"))
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(ControlResponse{OK: true, Result: session.read(params.Offset, params.MaxBytes)})
+	case "search":
+		var params SearchRequest
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		result, err := s.search(params)
+		if err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(ControlResponse{OK: true, Result: result})
 	case "resize":
 		var params ResizeRequest
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -254,6 +301,22 @@ func (s *Server) handleControl(ctx context.Context, conn net.Conn, line []byte)
 			return
 		}
 		_ = json.NewEncoder(conn).Encode(ControlResponse{OK: true})
+	case "handoff":
+		var params HandoffRequest
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		session := s.get(params.SessionID)
+		if session == nil {
+			s.writeError(conn, errors.New("session not found"))
+			return
+		}
+		if err := session.handoff(nil, params.StreamID); err != nil {
+			s.writeError(conn, err)
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(ControlResponse{OK: true})
 	case "stop":
 		var params StopRequest
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -358,19 +421,28 @@ func (s *Server) handleAttach(conn net.Conn, line []byte) {
 		_ = enc.Encode(StreamMessage{Type: "error", Error: "session not running"})
 		return
 	}
+	mode, err := normalizeAttachMode(req.Mode)
+	if err != nil {
+		_ = enc.Encode(StreamMessage{Type: "error", Error: err.Error()})
+		return
+	}
 	streamID := strings.TrimSpace(req.StreamID)
 	if streamID == "" {
 		streamID = newStreamID()
 	}
 	session.outputMu.Lock()
 	snapshot := session.snapshotAttachLocked(req.StartOffset)
-	sub := session.subscribe(streamID, snapshot.replayNext)
+	sub := session.attach(streamID, snapshot.replayNext, mode)
 	session.outputMu.Unlock()
 	defer session.unsubscribe(sub)
+	mode, owner := session.streamMode(sub)
 	if err := enc.Encode(StreamMessage{
 		Type:      "ready",
 		SessionID: req.SessionID,
 		StreamID:  streamID,
+		Restored:  session.wasRestored(),
+		Mode:      mode,
+		Owner:     owner,
 	}); err != nil {
 		return
 	}
@@ -386,23 +458,50 @@ func (s *Server) handleAttach(conn net.Conn, line []byte) {
 			return
 		}
 	}
-	for range sub.notify {
+	writeData := func() error {
 		data, nextOffset, _ := session.pullBuffer(sub)
 		if len(data) == 0 {
-			continue
+			return nil
 		}
-		if err := enc.Encode(StreamMessage{
+		return enc.Encode(StreamMessage{
 			Type:       "data",
 			SessionID:  req.SessionID,
 			StreamID:   streamID,
 			DataB64:    base64.StdEncoding.EncodeToString(data),
 			Len:        len(data),
 			NextOffset: nextOffset,
-		}); err != nil {
-			return
+		})
+	}
+	for {
+		select {
+		case _, ok := <-sub.notify:
+			if !ok {
+				// The session closed; deliver what it queued before going.
+				if err := writeData(); err != nil {
+					return
+				}
+				for _, message := range sub.pendingEvents() {
+					message.StreamID = streamID
+					if err := enc.Encode(message); err != nil {
+						return
+					}
+				}
+				_ = enc.Encode(StreamMessage{Type: "closed", SessionID: req.SessionID, StreamID: streamID})
+				return
+			}
+			if err := writeData(); err != nil {
+				return
+			}
+		case message := <-sub.events:
+			if err := writeData(); err != nil {
+				return
+			}
+			message.StreamID = streamID
+			if err := enc.Encode(message); err != nil {
+				return
+			}
 		}
 	}
-	_ = enc.Encode(StreamMessage{Type: "closed", SessionID: req.SessionID, StreamID: streamID})
 }
 
 func (s *Server) writeError(conn net.Conn, err error) {
@@ -445,7 +544,9 @@ func (s *Server) closeAll() {
 	}
 }
 
-func (s *Server) getOrCreate(ctx context.Context, id, cwd string) (*Session, bool, error) {
+// getOrCreate returns the running session id, starting one from spec when
+// there is none. A session whose command exited for good is replaced.
+func (s *Server) getOrCreate(ctx context.Context, id, cwd string, spec sessionSpec) (*Session, bool, error) {
 	if id == "" {
 		return nil, false, errors.New("session id required")
 	}
@@ -462,6 +563,7 @@ func (s *Server) getOrCreate(ctx context.Context, id, cwd string) (*Session, boo
 				continue
 			}
 			if !existing.isRunning() {
+				existing.closeWithReason("replaced")
 				s.remove(id)
 				continue
 			}
@@ -488,7 +590,13 @@ func (s *Server) getOrCreate(ctx context.Context, id, cwd string) (*Session, boo
 		s.mu.Unlock()
 
 		session := newSession(s.opts, id, cwd)
+		session.configure(spec)
 		session.onClose = s.onSessionClosed
+		if s.opts.TranscriptDir != "" {
+			if meta, err := loadSessionMeta(sessionFileBase(s.opts.TranscriptDir, id) + sessionMetaSuffix); err == nil && !meta.Closed {
+				session.restoreScrollback(meta)
+			}
+		}
 		err := session.start(ctx)
 
 		s.mu.Lock()
@@ -516,7 +624,13 @@ func (s *Server) onSessionClosed(session *Session) {
 	if session == nil {
 		return
 	}
-	s.remove(session.id)
+	// Only drop the entry if it still belongs to this session; a replaced
+	// session can close after its successor is registered.
+	s.mu.Lock()
+	if s.sessions[session.id] == session {
+		delete(s.sessions, session.id)
+	}
+	s.mu.Unlock()
 }
 
 func bytesTrimSpace(input []byte) []byte {
@@ -2,6 +2,7 @@ package terminalservice
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"os"
 	"os/exec"
@@ -15,10 +16,21 @@ import (
 type Session struct {
 	id   string
 	cwd  string
+	spec sessionSpec
 	cmd  *exec.Cmd
 	pty  *os.File
 	opts Options
 
+	// procDone is closed once readLoop has reaped cmd.
+	procDone chan struct{}
+	cols     int
+	rows     int
+	restarts int
+	exitCode *int
+	exitedAt time.Time
+	// exited is set once the command has exited and will not be restarted.
+	exited bool
+
 	mu             sync.Mutex
 	outputMu       sync.Mutex
 	buffer         *terminalBuffer
@@ -39,10 +51,37 @@ type Session struct {
 	subscribers    map[*subscriber]struct{}
 	streams        map[string]*subscriber
 	subscribersMu  sync.Mutex
+	// owner is the stream holding the session's input, if any. Protected
+	// by subscribersMu.
+	owner          string
 	debugInputSeq  atomic.Uint64
 	debugOutputSeq atomic.Uint64
 	modeState      terminalModeState
 	modeParser     terminalModeParser
+	// savedModes is the mode state last written to the session's metadata.
+	savedModes terminalModeState
+	metaMu     sync.Mutex
+	metaSaved  bool
+	// marks is the output timeline search uses for timestamps.
+	marks []outputMark
+	// restored is set when the session was seeded with the scrollback of a
+	// session from an earlier run of the service.
+	restored bool
+}
+
+// sessionSpec is what a session runs; the zero value runs the login shell
+// once.
+type sessionSpec struct {
+	command string
+	args    []string
+	env     map[string]string
+	cols    int
+	rows    int
+	restart string
+	// keepAlive skips the idle timer.
+	keepAlive bool
+	// resizePolicy decides which attached stream sizes the terminal.
+	resizePolicy string
 }
 
 func newSession(opts Options, id, cwd string) *Session {
@@ -58,28 +97,81 @@ func newSession(opts Options, id, cwd string) *Session {
 	}
 }
 
+// configure sets what the session runs; call it before start.
+func (s *Session) configure(spec sessionSpec) {
+	s.spec = spec
+	s.cols, s.rows = spec.cols, spec.rows
+}
+
 func (s *Session) info() SessionInfo {
+	owner, streams := s.attachedStreams()
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return SessionInfo{
-		SessionID:  s.id,
-		Cwd:        s.cwd,
-		StartedAt:  s.startedAt.Format(time.RFC3339),
-		LastActive: s.lastActivity.Format(time.RFC3339),
-		Running:    s.cmd != nil && !s.closed,
+	info := SessionInfo{
+		SessionID:    s.id,
+		Cwd:          s.cwd,
+		StartedAt:    s.startedAt.Format(time.RFC3339),
+		LastActive:   s.lastActivity.Format(time.RFC3339),
+		Running:      s.runningLocked(),
+		Command:      s.spec.command,
+		Restart:      s.spec.restart,
+		Restarts:     s.restarts,
+		Restored:     s.restored,
+		ResizePolicy: s.spec.resizePolicy,
+		Owner:        owner,
+		Streams:      streams,
 	}
+	if s.exitCode != nil {
+		code := *s.exitCode
+		info.ExitCode = &code
+		info.ExitedAt = s.exitedAt.Format(time.RFC3339)
+	}
+	return info
 }
 
 func (s *Session) inspect() InspectResponse {
+	info := s.info()
+	return InspectResponse{
+		SessionID:    info.SessionID,
+		Cwd:          info.Cwd,
+		StartedAt:    info.StartedAt,
+		LastActive:   info.LastActive,
+		Running:      info.Running,
+		Command:      info.Command,
+		Restart:      info.Restart,
+		Restarts:     info.Restarts,
+		ExitCode:     info.ExitCode,
+		ExitedAt:     info.ExitedAt,
+		Restored:     info.Restored,
+		ResizePolicy: info.ResizePolicy,
+		Owner:        info.Owner,
+		Streams:      info.Streams,
+	}
+}
+
+func (s *Session) wasRestored() bool {
 	s.mu.Lock()
-	resp := InspectResponse{
+	defer s.mu.Unlock()
+	return s.restored
+}
+
+// read returns buffered output from offset, keeping at most maxBytes of the
+// newest output when maxBytes is positive.
+func (s *Session) read(offset int64, maxBytes int) ReadResponse {
+	data, next, truncated := s.buffer.ReadSince(offset)
+	if maxBytes > 0 && len(data) > maxBytes {
+		data = data[len(data)-maxBytes:]
+		truncated = true
+	}
+	resp := ReadResponse{
 		SessionID:  s.id,
-		Cwd:        s.cwd,
-		StartedAt:  s.startedAt.Format(time.RFC3339),
-		LastActive: s.lastActivity.Format(time.RFC3339),
-		Running:    s.cmd != nil && !s.closed,
+		Offset:     next - int64(len(data)),
+		NextOffset: next,
+		Truncated:  truncated,
+	}
+	if len(data) > 0 {
+		resp.DataB64 = base64.StdEncoding.EncodeToString(data)
 	}
-	s.mu.Unlock()
 	return resp
 }
 
@@ -89,6 +181,9 @@ func (s *Session) write(ctx context.Context, data string) error {
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.exited {
+		return errors.New("session exited")
+	}
 	if s.pty == nil {
 		return errors.New("terminal not started")
 	}
@@ -113,8 +208,8 @@ func (s *Session) write(ctx context.Context, data string) error {
 
 func (s *Session) resize(cols, rows int) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if s.pty == nil {
+		s.mu.Unlock()
 		return errors.New("terminal not started")
 	}
 	if cols < 2 {
@@ -123,9 +218,13 @@ func (s *Session) resize(cols, rows int) error {
 	if rows < 1 {
 		rows = 1
 	}
+	// Remembered so a restarted command starts at the client's size.
+	s.cols, s.rows = cols, rows
 	err := resizePTY(s.pty, cols, rows)
+	s.mu.Unlock()
 	if err == nil {
 		debugLogf("session_resize id=%s cols=%d rows=%d", s.id, cols, rows)
+		s.saveMeta()
 	}
 	return err
 }
@@ -144,11 +243,15 @@ func (s *Session) isClosed() bool {
 
 func (s *Session) isRunning() bool {
 	s.mu.Lock()
-	running := s.cmd != nil && !s.closed
+	running := s.runningLocked()
 	s.mu.Unlock()
 	return running
 }
 
+func (s *Session) runningLocked() bool {
+	return s.cmd != nil && !s.closed && !s.exited
+}
+
 func (s *Session) bumpActivityLocked() {
 	s.lastActivity = time.Now()
 	if s.idleTimer != nil {
@@ -157,20 +260,20 @@ func (s *Session) bumpActivityLocked() {
 	}
 }
 
-func (s *Session) readLoop(ctx context.Context) {
+// readLoop copies one process's output until its pty closes, then reaps the
+// process and hands its exit to handleExit.
+func (s *Session) readLoop(ctx context.Context, cmd *exec.Cmd, ptmx *os.File, done chan struct{}) {
 	buf := make([]byte, 4096)
 	for {
-		if ctx.Err() != nil {
-			s.closeWithReason("context_done")
-			return
-		}
-		n, err := s.pty.Read(buf)
+		n, err := ptmx.Read(buf)
 		if n > 0 {
 			s.handleProtocolOutput(ctx, buf[:n])
 		}
 		if err != nil {
-			s.closeWithReason("closed")
-			return
+			break
 		}
 	}
+	waitErr := cmd.Wait()
+	close(done)
+	s.handleExit(ctx, cmd, ptmx, exitCodeFromWait(waitErr))
 }
@@ -0,0 +1,224 @@
+package terminalservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var errReadOnlyStream = errors.New("stream is read-only")
+
+func normalizeAttachMode(mode string) (string, error) {
+	switch mode = strings.TrimSpace(mode); mode {
+	case "", AttachInteractive:
+		return AttachInteractive, nil
+	case AttachOwner, AttachReadOnly:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown attach mode %q", mode)
+	}
+}
+
+func normalizeResizePolicy(policy string) (string, error) {
+	switch policy = strings.TrimSpace(policy); policy {
+	case "", ResizeSmallest:
+		return ResizeSmallest, nil
+	case ResizeOwner:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown resize policy %q", policy)
+	}
+}
+
+// attach subscribes a stream in mode, which must be normalized. An owner
+// stream takes the session's input from the current owner, and the other
+// streams are told.
+func (s *Session) attach(streamID string, startOffset int64, mode string) *subscriber {
+	sub := s.subscribe(streamID, startOffset)
+	s.subscribersMu.Lock()
+	if mode == AttachReadOnly {
+		sub.mode = AttachReadOnly
+	}
+	if mode == AttachOwner {
+		s.owner = sub.streamID
+	}
+	s.subscribersMu.Unlock()
+	if mode == AttachOwner {
+		debugServerf("session_owner id=%s owner=%s reason=attach", s.id, sub.streamID)
+		s.notifyModes(sub)
+	}
+	return sub
+}
+
+// streamMode returns the mode sub acts in and the stream that owns the
+// session's input.
+func (s *Session) streamMode(sub *subscriber) (string, string) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	return s.streamModeLocked(sub), s.owner
+}
+
+// streamModeLocked is AttachOwner while sub owns the session and the mode
+// it attached with otherwise.
+func (s *Session) streamModeLocked(sub *subscriber) string {
+	if s.owner != "" && s.owner == sub.streamID {
+		return AttachOwner
+	}
+	return sub.mode
+}
+
+// checkInput reports why sub may not type into the session. A nil sub is a
+// control socket client, which always may.
+func (s *Session) checkInput(sub *subscriber) error {
+	if sub == nil {
+		return nil
+	}
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	switch {
+	case s.streamModeLocked(sub) == AttachOwner:
+		return nil
+	case sub.mode == AttachReadOnly:
+		return errReadOnlyStream
+	case s.owner != "":
+		return fmt.Errorf("input is owned by stream %s", s.owner)
+	default:
+		return nil
+	}
+}
+
+// writeFrom writes input typed into the stream sub.
+func (s *Session) writeFrom(ctx context.Context, sub *subscriber, data string) error {
+	if err := s.checkInput(sub); err != nil {
+		return err
+	}
+	return s.write(ctx, data)
+}
+
+// resizeFrom records the size the stream sub asked for and resizes the
+// terminal to the size the session's resize policy picks from all streams.
+func (s *Session) resizeFrom(sub *subscriber, cols, rows int) error {
+	if sub == nil {
+		return s.resize(cols, rows)
+	}
+	s.subscribersMu.Lock()
+	sub.cols, sub.rows = cols, rows
+	cols, rows, ok := s.sizeLocked()
+	s.subscribersMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.applySize(cols, rows)
+}
+
+// sizeLocked picks the terminal size from the sizes streams asked for: the
+// owner's under ResizeOwner, otherwise the smallest of the streams that are
+// not read-only. ok is false when no stream that counts asked for a size.
+func (s *Session) sizeLocked() (cols, rows int, ok bool) {
+	if s.spec.resizePolicy == ResizeOwner && s.owner != "" {
+		if owner := s.streams[s.owner]; owner != nil && owner.cols > 0 && owner.rows > 0 {
+			return owner.cols, owner.rows, true
+		}
+	}
+	for sub := range s.subscribers {
+		if s.streamModeLocked(sub) == AttachReadOnly || sub.cols <= 0 || sub.rows <= 0 {
+			continue
+		}
+		if !ok || sub.cols < cols {
+			cols = sub.cols
+		}
+		if !ok || sub.rows < rows {
+			rows = sub.rows
+		}
+		ok = true
+	}
+	return cols, rows, ok
+}
+
+// applySize resizes the terminal unless it already has that size.
+func (s *Session) applySize(cols, rows int) error {
+	s.mu.Lock()
+	same := s.pty != nil && s.cols == cols && s.rows == rows
+	s.mu.Unlock()
+	if same {
+		return nil
+	}
+	return s.resize(cols, rows)
+}
+
+// handoff gives input ownership to the stream target, or releases it when
+// target is empty. from is the stream asking, or nil for a control socket
+// client; a stream may hand off ownership it holds, or ownership nobody
+// holds unless it is read-only.
+func (s *Session) handoff(from *subscriber, target string) error {
+	target = strings.TrimSpace(target)
+	s.subscribersMu.Lock()
+	var err error
+	switch {
+	case from != nil && s.owner != "" && s.owner != from.streamID:
+		err = fmt.Errorf("input is owned by stream %s", s.owner)
+	case from != nil && s.owner == "" && from.mode == AttachReadOnly:
+		err = errReadOnlyStream
+	case target != "" && s.streams[target] == nil:
+		err = fmt.Errorf("stream %s not attached", target)
+	}
+	if err != nil {
+		s.subscribersMu.Unlock()
+		return err
+	}
+	changed := s.owner != target
+	s.owner = target
+	cols, rows, sized := s.sizeLocked()
+	s.subscribersMu.Unlock()
+	if !changed {
+		return nil
+	}
+	debugServerf("session_owner id=%s owner=%s reason=handoff", s.id, target)
+	s.notifyModes(nil)
+	if !sized {
+		return nil
+	}
+	return s.applySize(cols, rows)
+}
+
+// notifyModes tells every stream but skip its mode and the session's
+// owner. Like broadcast it never blocks.
+func (s *Session) notifyModes(skip *subscriber) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	for sub := range s.subscribers {
+		if sub == skip {
+			continue
+		}
+		message := StreamMessage{
+			Type:      "mode",
+			SessionID: s.id,
+			Mode:      s.streamModeLocked(sub),
+			Owner:     s.owner,
+		}
+		select {
+		case sub.events <- message:
+		default:
+			debugServerf("ws_event_dropped session=%s stream=%s type=%s", s.id, sub.streamID, message.Type)
+		}
+	}
+}
+
+// attachedStreams describes the streams attached to the session.
+func (s *Session) attachedStreams() (string, []AttachedStream) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	streams := make([]AttachedStream, 0, len(s.streams))
+	for _, sub := range s.streams {
+		streams = append(streams, AttachedStream{
+			StreamID: sub.streamID,
+			Mode:     s.streamModeLocked(sub),
+			Cols:     sub.cols,
+			Rows:     sub.rows,
+		})
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].StreamID < streams[j].StreamID })
+	return s.owner, streams
+}
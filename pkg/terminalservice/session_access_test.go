@@ -0,0 +1,206 @@
+package terminalservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAttachModesGateInputAndHandoff(t *testing.T) {
+	client, server, cleanup := startTestServerWithOptionsAndServer(t, nil)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Create(ctx, "shared", "/tmp"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	session := server.get("shared")
+
+	typist := session.attach("typist", 0, AttachInteractive)
+	watcher := session.attach("watcher", 0, AttachReadOnly)
+	if err := session.writeFrom(ctx, watcher, "echo nope\n"); !errors.Is(err, errReadOnlyStream) {
+		t.Fatalf("expected a read-only stream to be refused, got %v", err)
+	}
+	if err := session.writeFrom(ctx, typist, "echo typed-$((1+1))\n"); err != nil {
+		t.Fatalf("interactive write: %v", err)
+	}
+	waitForReadOutput(t, client, "shared", 0, "typed-2")
+
+	agent := session.attach("agent", 0, AttachOwner)
+	if msg := nextModeMessage(t, typist); msg.Mode != AttachInteractive || msg.Owner != "agent" {
+		t.Fatalf("expected the typist to learn the agent owns input, got %+v", msg)
+	}
+	if msg := nextModeMessage(t, watcher); msg.Mode != AttachReadOnly || msg.Owner != "agent" {
+		t.Fatalf("expected the watcher to learn the agent owns input, got %+v", msg)
+	}
+	if err := session.writeFrom(ctx, typist, "echo nope\n"); err == nil {
+		t.Fatal("expected input from a non-owner to be refused while the session is owned")
+	}
+	if err := session.writeFrom(ctx, agent, "true\n"); err != nil {
+		t.Fatalf("owner write: %v", err)
+	}
+	if err := session.handoff(typist, "typist"); err == nil {
+		t.Fatal("expected a non-owner to be unable to take ownership")
+	}
+
+	if err := session.handoff(agent, "watcher"); err != nil {
+		t.Fatalf("handoff: %v", err)
+	}
+	if msg := nextModeMessage(t, watcher); msg.Mode != AttachOwner {
+		t.Fatalf("expected the watcher to become owner, got %+v", msg)
+	}
+	if msg := nextModeMessage(t, typist); msg.Owner != "watcher" {
+		t.Fatalf("expected the typist to learn of the handoff, got %+v", msg)
+	}
+	if err := session.writeFrom(ctx, watcher, "true\n"); err != nil {
+		t.Fatalf("write after handoff: %v", err)
+	}
+	if err := session.writeFrom(ctx, agent, "true\n"); err == nil {
+		t.Fatal("expected the previous owner to lose input")
+	}
+	info, err := client.Inspect(ctx, "shared")
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if info.Owner != "watcher" || len(info.Streams) != 3 || info.Streams[2].StreamID != "watcher" || info.Streams[2].Mode != AttachOwner {
+		t.Fatalf("unexpected owner and streams %+v", info)
+	}
+
+	session.unsubscribe(watcher)
+	if msg := nextModeMessage(t, typist); msg.Owner != "" {
+		t.Fatalf("expected ownership to lapse when the owner detaches, got %+v", msg)
+	}
+	if err := session.writeFrom(ctx, typist, "true\n"); err != nil {
+		t.Fatalf("write after the owner detached: %v", err)
+	}
+
+	if err := client.Handoff(ctx, "shared", "missing"); err == nil {
+		t.Fatal("expected a handoff to an unknown stream to fail")
+	}
+	if err := client.Handoff(ctx, "shared", "agent"); err != nil {
+		t.Fatalf("control socket handoff: %v", err)
+	}
+	if mode, owner := session.streamMode(agent); mode != AttachOwner || owner != "agent" {
+		t.Fatalf("expected the control socket to hand ownership to the agent, got %s %s", mode, owner)
+	}
+}
+
+func TestResizeArbitration(t *testing.T) {
+	client, server, cleanup := startTestServerWithOptionsAndServer(t, nil)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.Create(ctx, "smallest", "/tmp"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	session := server.get("smallest")
+	wide := session.attach("wide", 0, AttachInteractive)
+	tall := session.attach("tall", 0, AttachInteractive)
+	watcher := session.attach("watcher", 0, AttachReadOnly)
+	for _, resize := range []struct {
+		sub        *subscriber
+		cols, rows int
+	}{{wide, 120, 40}, {tall, 100, 50}, {watcher, 20, 5}} {
+		if err := session.resizeFrom(resize.sub, resize.cols, resize.rows); err != nil {
+			t.Fatalf("resize %s: %v", resize.sub.streamID, err)
+		}
+	}
+	requireSessionSize(t, session, 100, 40)
+	session.unsubscribe(tall)
+	requireSessionSize(t, session, 120, 40)
+
+	if _, err := client.CreateSession(ctx, CreateRequest{SessionID: "owned", Cwd: "/tmp", ResizePolicy: ResizeOwner}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	session = server.get("owned")
+	small := session.attach("small", 0, AttachInteractive)
+	owner := session.attach("owner", 0, AttachOwner)
+	if err := session.resizeFrom(small, 80, 20); err != nil {
+		t.Fatalf("resize: %v", err)
+	}
+	if err := session.resizeFrom(owner, 150, 50); err != nil {
+		t.Fatalf("resize: %v", err)
+	}
+	requireSessionSize(t, session, 150, 50)
+	if err := session.handoff(owner, ""); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	requireSessionSize(t, session, 80, 20)
+
+	if _, err := client.CreateSession(ctx, CreateRequest{SessionID: "bad", Cwd: "/tmp", ResizePolicy: "largest"}); err == nil {
+		t.Fatal("expected an unknown resize policy to be rejected")
+	}
+}
+
+func TestAttachReadyReportsMode(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Create(ctx, "modes", "/tmp"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for mode, want := range map[string]string{
+		"":             AttachInteractive,
+		AttachReadOnly: AttachReadOnly,
+		AttachOwner:    AttachOwner,
+		"spectator":    "",
+	} {
+		conn, err := net.Dial("unix", client.socketPath)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if err := json.NewEncoder(conn).Encode(AttachRequest{
+			ProtocolVersion: ProtocolVersion,
+			Type:            "attach",
+			SessionID:       "modes",
+			Mode:            mode,
+		}); err != nil {
+			t.Fatalf("attach: %v", err)
+		}
+		var msg StreamMessage
+		if err := json.NewDecoder(conn).Decode(&msg); err != nil {
+			t.Fatalf("attach reply: %v", err)
+		}
+		_ = conn.Close()
+		if want == "" {
+			if msg.Type != "error" {
+				t.Fatalf("expected attach mode %q to be rejected, got %+v", mode, msg)
+			}
+			continue
+		}
+		requireAttachReady(t, msg)
+		if msg.Mode != want {
+			t.Fatalf("attach mode %q: expected ready mode %q, got %+v", mode, want, msg)
+		}
+	}
+}
+
+func nextModeMessage(t *testing.T, sub *subscriber) StreamMessage {
+	t.Helper()
+	select {
+	case msg := <-sub.events:
+		if msg.Type != "mode" {
+			t.Fatalf("expected a mode message, got %+v", msg)
+		}
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatalf("stream %s got no mode message", sub.streamID)
+		return StreamMessage{}
+	}
+}
+
+func requireSessionSize(t *testing.T, session *Session, cols, rows int) {
+	t.Helper()
+	session.mu.Lock()
+	gotCols, gotRows := session.cols, session.rows
+	session.mu.Unlock()
+	if gotCols != cols || gotRows != rows {
+		t.Fatalf("expected a %dx%d terminal, got %dx%d", cols, rows, gotCols, gotRows)
+	}
+}
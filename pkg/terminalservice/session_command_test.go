@@ -0,0 +1,267 @@
+package terminalservice
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateCommandReportsExit(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.CreateSession(ctx, CreateRequest{
+		SessionID: "command-exit",
+		Cwd:       "/tmp",
+		Command:   "sh",
+		Args:      []string{"-c", "stty size; echo greeting=$GREETING; read line; exit 3"},
+		Env:       map[string]string{"GREETING": "hi"},
+		Cols:      100,
+		Rows:      40,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	dec, _ := attachTestStream(t, client, "command-exit")
+	if err := client.Send(ctx, "command-exit", "\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	var output strings.Builder
+	var exit StreamMessage
+	for exit.Type != "exit" {
+		exit = StreamMessage{}
+		if err := dec.Decode(&exit); err != nil {
+			t.Fatalf("read stream: %v (output %q)", err, output.String())
+		}
+		if exit.Type == "data" {
+			payload, _ := base64.StdEncoding.DecodeString(exit.DataB64)
+			output.Write(payload)
+		}
+	}
+	if exit.ExitCode == nil || *exit.ExitCode != 3 || exit.Restarting {
+		t.Fatalf("expected exit 3 without restart, got %+v", exit)
+	}
+	for _, want := range []string{"40 100", "greeting=hi"} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("expected output to contain %q, got %q", want, output.String())
+		}
+	}
+
+	info, err := client.Inspect(ctx, "command-exit")
+	if err != nil {
+		t.Fatalf("inspect exited session: %v", err)
+	}
+	if info.Running || info.ExitCode == nil || *info.ExitCode != 3 || info.Command != "sh" || info.ExitedAt == "" {
+		t.Fatalf("expected exited session with status 3, got %+v", info)
+	}
+	if err := client.Send(ctx, "command-exit", "ignored\n"); err == nil {
+		t.Fatal("expected send to an exited session to fail")
+	}
+
+	created, err := client.CreateSession(ctx, CreateRequest{
+		SessionID: "command-exit",
+		Cwd:       "/tmp",
+		Command:   "sh",
+		Args:      []string{"-c", "read line"},
+	})
+	if err != nil || created.Existing {
+		t.Fatalf("expected create to replace the exited session, got %+v err=%v", created, err)
+	}
+	if info, err := client.Inspect(ctx, "command-exit"); err != nil || !info.Running || info.ExitCode != nil {
+		t.Fatalf("expected a fresh running session, got %+v err=%v", info, err)
+	}
+}
+
+func TestShellExitReachesAttachedStream(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Create(ctx, "shell-exit", "/tmp"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	dec, _ := attachTestStream(t, client, "shell-exit")
+	if err := client.Send(ctx, "shell-exit", "exit 4\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	var exit *StreamMessage
+	for {
+		var msg StreamMessage
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		if msg.Type == "exit" {
+			exit = &msg
+		}
+		if msg.Type == "closed" {
+			break
+		}
+	}
+	if exit == nil || exit.ExitCode == nil || *exit.ExitCode != 4 {
+		t.Fatalf("expected an exit 4 frame before the stream closed, got %+v", exit)
+	}
+}
+
+func TestCreateRestartsOnFailure(t *testing.T) {
+	client, cleanup := startTestServerWithOptions(t, func(opts *Options) {
+		opts.RestartDelay = 10 * time.Millisecond
+	})
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.CreateSession(ctx, CreateRequest{
+		SessionID: "crash-loop",
+		Cwd:       "/tmp",
+		Command:   "sh",
+		Args:      []string{"-c", "exit 1"},
+		Restart:   RestartOnFailure,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		info, err := client.Inspect(ctx, "crash-loop")
+		if err != nil {
+			t.Fatalf("inspect: %v", err)
+		}
+		if info.Restarts >= 2 {
+			if !info.Running || info.ExitCode == nil || *info.ExitCode != 1 || info.Restart != RestartOnFailure {
+				t.Fatalf("expected a restarting session that last exited 1, got %+v", info)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the command to be restarted, got %+v", info)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err := client.Stop(ctx, "crash-loop"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if !waitForSessionGone(t, client, "crash-loop", 2*time.Second) {
+		t.Fatal("expected stopped session to stay gone")
+	}
+}
+
+func TestCreateRejectsUnknownRestartPolicy(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := client.CreateSession(ctx, CreateRequest{SessionID: "bad-policy", Cwd: "/tmp", Restart: "sometimes"})
+	if err == nil || !strings.Contains(err.Error(), "restart policy") {
+		t.Fatalf("expected restart policy error, got %v", err)
+	}
+}
+
+func TestShouldRestart(t *testing.T) {
+	cases := []struct {
+		policy string
+		code   int
+		want   bool
+	}{
+		{RestartNever, 1, false},
+		{RestartOnFailure, 0, false},
+		{RestartOnFailure, -1, true},
+		{RestartAlways, 0, true},
+	}
+	for _, tc := range cases {
+		if got := shouldRestart(tc.policy, tc.code); got != tc.want {
+			t.Fatalf("shouldRestart(%q, %d) = %t, want %t", tc.policy, tc.code, got, tc.want)
+		}
+	}
+}
+
+func attachTestStream(t *testing.T, client *Client, sessionID string) (*json.Decoder, StreamMessage) {
+	t.Helper()
+	conn, err := net.Dial("unix", client.socketPath)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	if err := json.NewEncoder(conn).Encode(AttachRequest{
+		ProtocolVersion: ProtocolVersion,
+		Type:            "attach",
+		SessionID:       sessionID,
+	}); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	dec := json.NewDecoder(conn)
+	var ready StreamMessage
+	if err := dec.Decode(&ready); err != nil {
+		t.Fatalf("attach ready: %v", err)
+	}
+	requireAttachReady(t, ready)
+	return dec, ready
+}
+
+func TestReadReturnsOutputSinceOffset(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.CreateSession(ctx, CreateRequest{
+		SessionID: "read-output",
+		Cwd:       "/tmp",
+		Command:   "sh",
+		Args:      []string{"-c", "echo first; read line; echo second; read line"},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	readUntil := func(offset int64, want string) ReadResponse {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			resp, err := client.Read(ctx, ReadRequest{SessionID: "read-output", Offset: offset})
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			data, _ := base64.StdEncoding.DecodeString(resp.DataB64)
+			if strings.Contains(string(data), want) {
+				return resp
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q after offset %d, got %q", want, offset, data)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	first := readUntil(0, "first")
+	if first.Offset != 0 || first.NextOffset == 0 {
+		t.Fatalf("unexpected offsets %+v", first)
+	}
+	if err := client.Send(ctx, "read-output", "\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	second := readUntil(first.NextOffset, "second")
+	data, _ := base64.StdEncoding.DecodeString(second.DataB64)
+	if strings.Contains(string(data), "first") {
+		t.Fatalf("expected only output after offset %d, got %q", first.NextOffset, data)
+	}
+
+	tail, err := client.Read(ctx, ReadRequest{SessionID: "read-output", MaxBytes: 4})
+	if err != nil {
+		t.Fatalf("read tail: %v", err)
+	}
+	if tail.NextOffset-tail.Offset != 4 {
+		t.Fatalf("expected the newest 4 bytes, got %+v", tail)
+	}
+	if _, err := client.Read(ctx, ReadRequest{SessionID: "missing"}); err == nil {
+		t.Fatal("expected read of a missing session to fail")
+	}
+}
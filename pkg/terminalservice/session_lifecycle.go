@@ -2,6 +2,8 @@ package terminalservice
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"os/user"
@@ -11,12 +13,7 @@ import (
 )
 
 func (s *Session) start(ctx context.Context) error {
-	execName, execArgs := resolveShellCommand()
-	cmd := exec.CommandContext(ctx, execName, execArgs...)
-	cmd.Dir = s.cwd
-	cmd.Env = buildSessionEnv(execName, s.id, s.cwd)
-
-	ptmx, err := startPTY(cmd)
+	cmd, ptmx, err := s.launch(ctx)
 	if err != nil {
 		return err
 	}
@@ -27,22 +24,164 @@ func (s *Session) start(ctx context.Context) error {
 	s.openRecord()
 
 	s.mu.Lock()
-	s.cmd = cmd
-	s.pty = ptmx
 	s.startedAt = time.Now()
 	s.lastActivity = s.startedAt
 	s.mu.Unlock()
-	debugLogf("session_start id=%s cwd=%s", s.id, s.cwd)
+	debugLogf("session_start id=%s cwd=%s command=%q", s.id, s.cwd, s.spec.command)
+	s.saveMeta()
 
-	if s.opts.IdleTimeout > 0 {
+	if s.opts.IdleTimeout > 0 && !s.spec.keepAlive {
 		s.idleTimer = time.AfterFunc(s.opts.IdleTimeout, func() {
 			s.closeWithReason("idle")
 		})
 	}
-	go s.readLoop(ctx)
+	s.run(ctx, cmd, ptmx)
 	return nil
 }
 
+// launch starts the session's command on a new pty at the last known size.
+func (s *Session) launch(ctx context.Context) (*exec.Cmd, *os.File, error) {
+	execName, execArgs := s.spec.command, s.spec.args
+	shellPath := ""
+	if execName == "" {
+		execName, execArgs = resolveShellCommand()
+		shellPath = execName
+	}
+	cmd := exec.CommandContext(ctx, execName, execArgs...)
+	cmd.Dir = s.cwd
+	cmd.Env = buildSessionEnv(shellPath, s.id, s.cwd)
+	for key, value := range s.spec.env {
+		cmd.Env = setEnv(cmd.Env, key, value)
+	}
+
+	ptmx, err := startPTY(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.mu.Lock()
+	cols, rows := s.cols, s.rows
+	s.mu.Unlock()
+	if cols > 0 && rows > 0 {
+		_ = resizePTY(ptmx, cols, rows)
+	}
+	return cmd, ptmx, nil
+}
+
+// run makes cmd the session's process and starts reading its output. It
+// reports false, killing cmd, when the session closed in the meantime.
+func (s *Session) run(ctx context.Context, cmd *exec.Cmd, ptmx *os.File) bool {
+	done := make(chan struct{})
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = ptmx.Close()
+		if cmd.Process != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}
+		return false
+	}
+	s.cmd = cmd
+	s.pty = ptmx
+	s.procDone = done
+	s.exited = false
+	s.mu.Unlock()
+	go s.readLoop(ctx, cmd, ptmx, done)
+	return true
+}
+
+// handleExit reports a process exit to attached clients and applies the
+// restart policy. Login shells close the session as before; other commands
+// leave it in place so callers can inspect the exit status until it is
+// stopped, recreated or idles out.
+func (s *Session) handleExit(ctx context.Context, cmd *exec.Cmd, ptmx *os.File, code int) {
+	_ = ptmx.Close()
+	s.mu.Lock()
+	if s.closed || s.cmd != cmd {
+		s.mu.Unlock()
+		return
+	}
+	s.pty = nil
+	s.exitCode = &code
+	s.exitedAt = time.Now()
+	restart := ctx.Err() == nil && shouldRestart(s.spec.restart, code)
+	s.exited = !restart
+	shell := s.spec.command == ""
+	s.mu.Unlock()
+	debugLogf("session_exit id=%s code=%d restart=%t", s.id, code, restart)
+
+	s.broadcast(StreamMessage{Type: "exit", SessionID: s.id, ExitCode: &code, Restarting: restart})
+	switch {
+	case ctx.Err() != nil:
+		s.closeWithReason("context_done")
+	case restart:
+		time.AfterFunc(s.opts.RestartDelay, func() {
+			s.restart(ctx)
+		})
+	case shell:
+		s.closeWithReason("closed")
+	default:
+		s.retireMeta()
+	}
+}
+
+func (s *Session) restart(ctx context.Context) {
+	if s.isClosed() || ctx.Err() != nil {
+		return
+	}
+	cmd, ptmx, err := s.launch(ctx)
+	if err != nil {
+		logServerf("session_restart_failed id=%s err=%v", s.id, err)
+		s.mu.Lock()
+		s.exited = true
+		s.mu.Unlock()
+		return
+	}
+	if !s.run(ctx, cmd, ptmx) {
+		return
+	}
+	s.mu.Lock()
+	s.restarts++
+	restarts := s.restarts
+	s.mu.Unlock()
+	debugLogf("session_restart id=%s restarts=%d", s.id, restarts)
+}
+
+func shouldRestart(policy string, code int) bool {
+	switch policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return code != 0
+	default:
+		return false
+	}
+}
+
+func normalizeRestartPolicy(policy string) (string, error) {
+	switch policy = strings.TrimSpace(policy); policy {
+	case "", RestartNever:
+		return RestartNever, nil
+	case RestartOnFailure, RestartAlways:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown restart policy %q", policy)
+	}
+}
+
+// exitCodeFromWait returns the process exit code, or -1 when it was killed
+// by a signal or could not be waited on.
+func exitCodeFromWait(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
 func (s *Session) closeWithReason(reason string) {
 	s.mu.Lock()
 	if s.closed {
@@ -64,6 +203,7 @@ func (s *Session) closeWithReason(reason string) {
 	recordFile := s.recordFile
 	s.recordFile = nil
 	cmd := s.cmd
+	procDone := s.procDone
 	s.mu.Unlock()
 	if idleTimer != nil {
 		_ = idleTimer.Stop()
@@ -79,7 +219,15 @@ func (s *Session) closeWithReason(reason string) {
 	}
 	if cmd != nil && cmd.Process != nil {
 		_ = cmd.Process.Kill()
-		waitForCommandExit(cmd, 2*time.Second)
+		if procDone != nil {
+			// readLoop reaps the process once the closed pty unblocks it.
+			waitForDone(procDone, 2*time.Second)
+		} else {
+			waitForCommandExit(cmd, 2*time.Second)
+		}
+	}
+	if !keepsMeta(reason) {
+		s.retireMeta()
 	}
 	if onClose != nil {
 		onClose(s)
@@ -108,6 +256,15 @@ func waitForCommandExit(cmd *exec.Cmd, timeout time.Duration) {
 	}
 }
 
+func waitForDone(done <-chan struct{}, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+}
+
 func resolveShellCommand() (string, []string) {
 	if runtime.GOOS == "windows" {
 		if shell := os.Getenv("COMSPEC"); shell != "" {
@@ -193,7 +350,9 @@ func envValue(env []string, key string) string {
 
 func buildSessionEnv(shellPath, workspaceID, cwd string) []string {
 	env := append([]string(nil), os.Environ()...)
-	env = setEnv(env, "SHELL", shellPath)
+	if shellPath != "" {
+		env = setEnv(env, "SHELL", shellPath)
+	}
 	env = setEnv(env, "WORKSET_WORKSPACE", workspaceID)
 	env = setEnv(env, "WORKSET_ROOT", cwd)
 	if strings.TrimSpace(envValue(env, "TERM")) == "" {
@@ -12,6 +12,7 @@ func (s *Session) recordOutput(data []byte) int64 {
 	nextOffset := int64(0)
 	if s.buffer != nil {
 		nextOffset = s.buffer.Append(data)
+		s.markOutput(nextOffset-int64(len(data)), time.Now())
 	}
 	s.mu.Lock()
 	file := s.transcriptFile
@@ -32,14 +33,10 @@ func (s *Session) openTranscript() error {
 	if s.opts.TranscriptDir == "" {
 		return nil
 	}
-	safe := sanitizeID(s.id)
-	if safe == "" {
-		safe = "session"
-	}
 	if err := os.MkdirAll(s.opts.TranscriptDir, 0o755); err != nil {
 		return err
 	}
-	path := filepath.Join(s.opts.TranscriptDir, safe+".log")
+	path := sessionFileBase(s.opts.TranscriptDir, s.id) + ".log"
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return err
@@ -25,6 +25,7 @@ func (s *Session) handleProtocolOutput(ctx context.Context, raw []byte) {
 		return
 	}
 	s.trackTerminalModes(sanitized)
+	s.noteModes()
 	s.logProtocol(ctx, "out", sanitized)
 	s.mu.Lock()
 	s.bumpActivityLocked()
@@ -0,0 +1,253 @@
+package terminalservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// sessionMetaSuffix names the file kept next to a session's transcript that
+// says which session wrote it and, until the session ends for good, lets a
+// restarted service bring it back. It holds the session's command line,
+// environment, directory and terminal state; since the environment may carry
+// secrets it is readable by its owner only.
+const sessionMetaSuffix = ".session.json"
+
+// restoredBanner follows the replayed scrollback of a restored session.
+const restoredBanner = "\r\n\x1b[2m--- session restored; previous output above ---\x1b[0m\r\n"
+
+type sessionMeta struct {
+	SessionID string            `json:"sessionId"`
+	Cwd       string            `json:"cwd"`
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Cols      int               `json:"cols,omitempty"`
+	Rows      int               `json:"rows,omitempty"`
+	Restart   string            `json:"restart,omitempty"`
+	KeepAlive bool              `json:"keepAlive,omitempty"`
+	// ResizePolicy is missing from metadata written before resize
+	// policies existed; those sessions get the default.
+	ResizePolicy string `json:"resizePolicy,omitempty"`
+	// Modes are the DEC private modes the terminal had enabled.
+	Modes []int `json:"modes,omitempty"`
+	// Closed marks a session that ended for good. Its metadata stays to
+	// name its transcript but it is not restored.
+	Closed  bool   `json:"closed,omitempty"`
+	SavedAt string `json:"savedAt"`
+}
+
+// spec is what the restored session runs. A command that is never
+// restarted is not run again behind its user's back: the session comes back
+// as a login shell with the command's scrollback.
+func (m sessionMeta) spec() sessionSpec {
+	resizePolicy, err := normalizeResizePolicy(m.ResizePolicy)
+	if err != nil {
+		resizePolicy = ResizeSmallest
+	}
+	spec := sessionSpec{
+		command:      m.Command,
+		args:         m.Args,
+		env:          m.Env,
+		cols:         m.Cols,
+		rows:         m.Rows,
+		restart:      m.Restart,
+		keepAlive:    m.KeepAlive,
+		resizePolicy: resizePolicy,
+	}
+	if restart, err := normalizeRestartPolicy(m.Restart); err != nil || restart == RestartNever {
+		spec.command, spec.args, spec.restart = "", nil, RestartNever
+	}
+	return spec
+}
+
+func sessionFileBase(dir, id string) string {
+	safe := sanitizeID(id)
+	if safe == "" {
+		safe = "session"
+	}
+	return filepath.Join(dir, safe)
+}
+
+func loadSessionMeta(path string) (sessionMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sessionMeta{}, err
+	}
+	var meta sessionMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return sessionMeta{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if meta.SessionID == "" {
+		return sessionMeta{}, fmt.Errorf("decode %s: session id missing", path)
+	}
+	return meta, nil
+}
+
+// saveMeta records what the session runs and its terminal state. Closed
+// sessions and commands that exited for good are not saved.
+func (s *Session) saveMeta() {
+	s.writeMeta(false)
+}
+
+// retireMeta marks the session's metadata closed so the session is not
+// restored.
+func (s *Session) retireMeta() {
+	s.writeMeta(true)
+}
+
+func (s *Session) writeMeta(closed bool) {
+	if s.opts.TranscriptDir == "" {
+		return
+	}
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	s.mu.Lock()
+	if (closed && !s.metaSaved) || (!closed && (s.closed || s.exited)) {
+		s.mu.Unlock()
+		return
+	}
+	meta := sessionMeta{
+		SessionID: s.id,
+		Cwd:       s.cwd,
+		Command:   s.spec.command,
+		Args:      s.spec.args,
+		Env:       s.spec.env,
+		Cols:      s.cols,
+		Rows:      s.rows,
+		Restart:   s.spec.restart,
+		KeepAlive: s.spec.keepAlive,
+		Modes:     s.savedModes.privateModes(),
+		Closed:    closed,
+		SavedAt:   time.Now().Format(time.RFC3339),
+	}
+	meta.ResizePolicy = s.spec.resizePolicy
+	s.mu.Unlock()
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+	path := sessionFileBase(s.opts.TranscriptDir, s.id) + sessionMetaSuffix
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		logServerf("session_meta_write_failed id=%s err=%v", s.id, err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		logServerf("session_meta_write_failed id=%s err=%v", s.id, err)
+		return
+	}
+	s.mu.Lock()
+	s.metaSaved = true
+	s.mu.Unlock()
+}
+
+// noteModes saves the session again when output changed its terminal modes.
+func (s *Session) noteModes() {
+	s.mu.Lock()
+	changed := s.savedModes != s.modeState
+	s.savedModes = s.modeState
+	s.mu.Unlock()
+	if changed {
+		s.saveMeta()
+	}
+}
+
+// restoreScrollback seeds a new session with the transcript tail of the
+// session it replaces, filtered like an attach replay and followed by a
+// reset of the modes that session left enabled and a restored banner. The
+// transcript is rewritten to the seed so its offsets line up with the
+// buffer's.
+func (s *Session) restoreScrollback(meta sessionMeta) {
+	if s.opts.TranscriptDir == "" {
+		return
+	}
+	s.transcriptPath = sessionFileBase(s.opts.TranscriptDir, s.id) + ".log"
+	tail, _, err := s.readTranscriptTail(s.opts.TranscriptTailBytes)
+	if err != nil {
+		logServerf("session_restore_read_failed id=%s err=%v", s.id, err)
+	}
+	seed := stripReplayQueries(tail)
+	for _, mode := range meta.Modes {
+		seed = fmt.Appendf(seed, "\x1b[?%dl", mode)
+	}
+	seed = append(seed, "\x1b[0m\x1b[?25h"...)
+	seed = append(seed, restoredBanner...)
+	if err := os.WriteFile(s.transcriptPath, seed, 0o644); err != nil {
+		logServerf("session_restore_write_failed id=%s err=%v", s.id, err)
+	}
+	s.buffer.Append(seed)
+	s.mu.Lock()
+	s.restored = true
+	s.mu.Unlock()
+}
+
+// restoreSessions recreates the sessions a previous run of the service left
+// behind. A session that cannot be started again is forgotten.
+func (s *Server) restoreSessions(ctx context.Context) {
+	if s.opts.TranscriptDir == "" {
+		return
+	}
+	entries, err := os.ReadDir(s.opts.TranscriptDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), sessionMetaSuffix) {
+			continue
+		}
+		path := filepath.Join(s.opts.TranscriptDir, entry.Name())
+		meta, err := loadSessionMeta(path)
+		if err == nil && meta.Closed {
+			continue
+		}
+		if err == nil {
+			_, _, err = s.getOrCreate(ctx, meta.SessionID, meta.Cwd, meta.spec())
+		}
+		if err != nil {
+			logServerf("session_restore_failed file=%s err=%v", entry.Name(), err)
+			_ = os.Remove(path)
+			continue
+		}
+		debugServerf("session_restored id=%s", meta.SessionID)
+	}
+}
+
+// keepsMeta reports whether a session closed for reason should come back
+// when the service starts again: it was still alive when the service went
+// away, rather than being closed by its user, its own exit or the idle
+// timeout.
+func keepsMeta(reason string) bool {
+	switch reason {
+	case "shutdown", "context_done":
+		return true
+	default:
+		return false
+	}
+}
+
+func (m terminalModeState) privateModes() []int {
+	var modes []int
+	for _, mode := range []struct {
+		on   bool
+		code int
+	}{
+		{m.altScreen, 1049},
+		{m.mouse1000, 1000},
+		{m.mouse1002, 1002},
+		{m.mouse1003, 1003},
+		{m.mouse1005, 1005},
+		{m.mouse1006, 1006},
+		{m.mouse1015, 1015},
+	} {
+		if mode.on {
+			modes = append(modes, mode.code)
+		}
+	}
+	return modes
+}
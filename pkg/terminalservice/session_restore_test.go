@@ -0,0 +1,139 @@
+package terminalservice
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionsRestoredAfterServiceRestart(t *testing.T) {
+	transcripts := t.TempDir()
+	useTranscripts := func(opts *Options) {
+		opts.TranscriptDir = transcripts
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, cleanup := startTestServerWithOptions(t, useTranscripts)
+	if _, err := client.CreateSession(ctx, CreateRequest{SessionID: "keep-me", Cwd: "/tmp", Cols: 90, Rows: 30}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := client.CreateSession(ctx, CreateRequest{SessionID: "close-me", Cwd: "/tmp"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := client.CreateSession(ctx, CreateRequest{
+		SessionID: "run-once",
+		Cwd:       "/tmp",
+		Command:   "sh",
+		Args:      []string{"-c", "echo ran-once; read line"},
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	waitForReadOutput(t, client, "run-once", 0, "ran-once")
+	if err := client.Send(ctx, "keep-me", "echo before-restart; printf '\\033[?1000h'\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	waitForReadOutput(t, client, "keep-me", 0, "before-restart")
+	if err := client.Stop(ctx, "close-me"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if stat, err := os.Stat(filepath.Join(transcripts, "keep-me"+sessionMetaSuffix)); err != nil || stat.Mode().Perm() != 0o600 {
+		t.Fatalf("expected metadata readable by its owner only, got %v err=%v", stat, err)
+	}
+	cleanup()
+
+	client, cleanup = startTestServerWithOptions(t, useTranscripts)
+	defer cleanup()
+	info, err := client.Inspect(ctx, "keep-me")
+	if err != nil {
+		t.Fatalf("inspect restored session: %v", err)
+	}
+	if !info.Running || !info.Restored {
+		t.Fatalf("expected a running restored session, got %+v", info)
+	}
+	if _, err := client.Inspect(ctx, "close-me"); err == nil {
+		t.Fatal("expected a stopped session not to be restored")
+	}
+	info, err = client.Inspect(ctx, "run-once")
+	if err != nil {
+		t.Fatalf("inspect restored command session: %v", err)
+	}
+	if !info.Running || !info.Restored || info.Command != "" {
+		t.Fatalf("expected the command session to come back as a login shell, got %+v", info)
+	}
+	if replay := waitForReadOutput(t, client, "run-once", 0, "session restored"); strings.Count(replay, "ran-once") != 1 {
+		t.Fatalf("expected the command's scrollback without running it again, got %q", replay)
+	}
+
+	replay := waitForReadOutput(t, client, "keep-me", 0, "session restored")
+	if !strings.Contains(replay, "before-restart") || !strings.Contains(replay, "\x1b[?1000l") {
+		t.Fatalf("expected the old scrollback and a mouse mode reset, got %q", replay)
+	}
+	if err := client.Send(ctx, "keep-me", "stty size\n"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	waitForReadOutput(t, client, "keep-me", int64(len(replay)), "30 90")
+
+	if _, ready := attachTestStream(t, client, "keep-me"); !ready.Restored {
+		t.Fatalf("expected the ready message to mark the session restored, got %+v", ready)
+	}
+
+	if err := client.Stop(ctx, "keep-me"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if meta, err := loadSessionMeta(filepath.Join(transcripts, "keep-me"+sessionMetaSuffix)); err != nil || !meta.Closed {
+		t.Fatalf("expected metadata to be marked closed on stop, got %+v err=%v", meta, err)
+	}
+}
+
+func TestIdleSessionsNotRestored(t *testing.T) {
+	transcripts := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, cleanup := startTestServerWithOptions(t, func(opts *Options) {
+		opts.TranscriptDir = transcripts
+		opts.IdleTimeout = 100 * time.Millisecond
+		opts.IdleTimeoutSet = true
+	})
+	if _, err := client.CreateSession(ctx, CreateRequest{SessionID: "idler", Cwd: "/tmp"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !waitForSessionGone(t, client, "idler", 3*time.Second) {
+		t.Fatal("expected the session to idle out")
+	}
+	cleanup()
+
+	client, cleanup = startTestServerWithOptions(t, func(opts *Options) {
+		opts.TranscriptDir = transcripts
+	})
+	defer cleanup()
+	if _, err := client.Inspect(ctx, "idler"); err == nil {
+		t.Fatal("expected an idled out session not to be restored")
+	}
+}
+
+func waitForReadOutput(t *testing.T, client *Client, sessionID string, offset int64, want string) string {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		resp, err := client.Read(ctx, ReadRequest{SessionID: sessionID, Offset: offset})
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		data, _ := base64.StdEncoding.DecodeString(resp.DataB64)
+		if strings.Contains(string(data), want) {
+			return string(data)
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %q in output, got %q", want, data)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
@@ -6,12 +6,22 @@ import (
 )
 
 type subscriber struct {
-	notify   chan struct{}
+	notify chan struct{}
+	// events carries control messages such as process exits. It is never
+	// closed; readers stop on notify closing instead.
+	events   chan StreamMessage
 	streamID string
 	done     chan struct{}
 	closed   bool
 	closeMu  sync.Mutex
 
+	// mode is AttachInteractive or AttachReadOnly, and cols and rows the
+	// size the stream last asked for; the session tracks which stream owns
+	// it. Protected by the session's subscribersMu.
+	mode string
+	cols int
+	rows int
+
 	// offset tracks the last buffer position this subscriber consumed.
 	// Protected by the subscriber's own mutex so the WebSocket writer
 	// goroutine can update it without holding the session lock.
@@ -26,16 +36,43 @@ type streamState struct {
 
 func (s *subscriber) close() {
 	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
 	if s.closed {
-		s.closeMu.Unlock()
 		return
 	}
 	s.closed = true
-	s.closeMu.Unlock()
 	close(s.done)
 	close(s.notify)
 }
 
+// signal wakes the subscriber's reader unless it already has a pending
+// signal or the subscriber was closed since the caller looked it up.
+func (s *subscriber) signal() {
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// Already has a pending notification — subscriber will catch up.
+	}
+}
+
+// pendingEvents drains the control messages queued for the subscriber.
+func (s *subscriber) pendingEvents() []StreamMessage {
+	var messages []StreamMessage
+	for {
+		select {
+		case message := <-s.events:
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
 func (s *subscriber) getOffset() int64 {
 	s.offsetMu.Lock()
 	v := s.offset
@@ -52,7 +89,9 @@ func (s *subscriber) setOffset(v int64) {
 func newSubscriber(streamID string, startOffset int64) *subscriber {
 	return &subscriber{
 		notify:   make(chan struct{}, 1),
+		events:   make(chan StreamMessage, 8),
 		streamID: streamID,
+		mode:     AttachInteractive,
 		done:     make(chan struct{}),
 		offset:   startOffset,
 	}
@@ -80,17 +119,32 @@ func (s *Session) unsubscribeWithReason(sub *subscriber, reason string) {
 	s.subscribersMu.Lock()
 	_, ok := s.subscribers[sub]
 	state := streamState{}
+	released, sized := false, false
+	var cols, rows int
 	if ok {
 		delete(s.subscribers, sub)
 		if sub.streamID != "" {
 			delete(s.streams, sub.streamID)
 		}
+		if s.owner != "" && s.owner == sub.streamID {
+			s.owner = ""
+			released = true
+		}
+		cols, rows, sized = s.sizeLocked()
 		state = streamStateFromMaps(s.subscribers, s.streams)
 	}
 	s.subscribersMu.Unlock()
 	if !ok {
 		return
 	}
+	// The streams left behind may type again, or fit a larger terminal.
+	if released {
+		debugServerf("session_owner id=%s owner= reason=%s", s.id, reason)
+		s.notifyModes(nil)
+	}
+	if sized && !s.isClosed() {
+		_ = s.applySize(cols, rows)
+	}
 	debugServerf(
 		"ws_unsubscribe session=%s stream=%s reason=%s offset=%d subscribers=%d streams=%q",
 		s.id,
@@ -111,6 +165,7 @@ func (s *Session) closeSubscribers() {
 	}
 	s.subscribers = make(map[*subscriber]struct{})
 	s.streams = make(map[string]*subscriber)
+	s.owner = ""
 	s.subscribersMu.Unlock()
 	debugServerf("ws_close_subscribers session=%s count=%d", s.id, len(subs))
 	for _, sub := range subs {
@@ -130,10 +185,21 @@ func (s *Session) notifySubscribers() {
 	}
 	s.subscribersMu.Unlock()
 	for _, sub := range subs {
+		sub.signal()
+	}
+}
+
+// broadcast queues a control message for every subscriber. Like
+// notifySubscribers it never blocks; a subscriber with a full queue misses
+// the message.
+func (s *Session) broadcast(message StreamMessage) {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	for sub := range s.subscribers {
 		select {
-		case sub.notify <- struct{}{}:
+		case sub.events <- message:
 		default:
-			// Already has a pending notification — subscriber will catch up.
+			debugServerf("ws_event_dropped session=%s stream=%s type=%s", s.id, sub.streamID, message.Type)
 		}
 	}
 }
@@ -108,6 +108,13 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mode, err := normalizeAttachMode(req.Mode)
+	if err != nil {
+		_ = s.writeWebsocketControl(ctx, conn, StreamMessage{Type: "error", Error: err.Error()})
+		_ = conn.Close(websocket.StatusPolicyViolation, "invalid attach mode")
+		return
+	}
+
 	streamID := strings.TrimSpace(req.StreamID)
 	if streamID == "" {
 		streamID = newStreamID()
@@ -115,13 +122,14 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 
 	session.outputMu.Lock()
 	snapshot := session.snapshotAttachLocked(req.StartOffset)
-	sub := session.subscribe(streamID, snapshot.replayNext)
+	sub := session.attach(streamID, snapshot.replayNext, mode)
 	session.outputMu.Unlock()
 	state := session.getStreamState()
 	debugServerf(
-		"ws_attach_open session=%s stream=%s remote=%s subscribers=%d streams=%q replay_next=%d replay_bytes=%d replay_truncated=%t replay_skipped=%t client_offset=%d",
+		"ws_attach_open session=%s stream=%s mode=%s remote=%s subscribers=%d streams=%q replay_next=%d replay_bytes=%d replay_truncated=%t replay_skipped=%t client_offset=%d",
 		req.SessionID,
 		streamID,
+		mode,
 		r.RemoteAddr,
 		state.Count,
 		state.StreamIDs,
@@ -184,10 +192,14 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 		return s.writeWebsocketBinary(ctx, conn, nextOffset, data)
 	}
 
+	currentMode, owner := session.streamMode(sub)
 	if err := writeControl(streamCtx, StreamMessage{
 		Type:      "ready",
 		SessionID: req.SessionID,
 		StreamID:  streamID,
+		Restored:  session.wasRestored(),
+		Mode:      currentMode,
+		Owner:     owner,
 	}); err != nil {
 		setClose("write_ready_failed", websocket.StatusNormalClosure, err.Error())
 		return
@@ -241,7 +253,7 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 				})
 				continue
 			}
-			response, err := s.handleWebsocketControlRequest(streamCtx, session, controlReq)
+			response, err := s.handleWebsocketControlRequest(streamCtx, session, sub, controlReq)
 			if err != nil {
 				_ = writeControl(streamCtx, StreamMessage{Type: "error", Error: err.Error()})
 				logServerf(
@@ -284,6 +296,21 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 			return
 		case _, ok := <-sub.notify:
 			if !ok {
+				// The session closed; deliver what it queued before going,
+				// such as the exit of its shell.
+				if data, nextOffset, _ := session.pullBuffer(sub); len(data) > 0 {
+					if err := writeBinary(streamCtx, nextOffset, data); err != nil {
+						setClose("write_chunk_failed", websocket.StatusNormalClosure, err.Error())
+						return
+					}
+				}
+				for _, message := range sub.pendingEvents() {
+					message.StreamID = streamID
+					if err := writeControl(streamCtx, message); err != nil {
+						setClose("write_event_failed", websocket.StatusNormalClosure, err.Error())
+						return
+					}
+				}
 				_ = writeControl(streamCtx, StreamMessage{
 					Type:      "closed",
 					SessionID: req.SessionID,
@@ -300,25 +327,53 @@ func (s *Server) handleWebsocketAttach(w http.ResponseWriter, r *http.Request) {
 				setClose("write_chunk_failed", websocket.StatusNormalClosure, err.Error())
 				return
 			}
+		case message := <-sub.events:
+			// Flush output the process wrote before exiting so the event
+			// lands after it.
+			if data, nextOffset, _ := session.pullBuffer(sub); len(data) > 0 {
+				if err := writeBinary(streamCtx, nextOffset, data); err != nil {
+					setClose("write_chunk_failed", websocket.StatusNormalClosure, err.Error())
+					return
+				}
+			}
+			message.StreamID = streamID
+			if err := writeControl(streamCtx, message); err != nil {
+				setClose("write_event_failed", websocket.StatusNormalClosure, err.Error())
+				return
+			}
 		}
 	}
 }
 
+// handleWebsocketControlRequest runs a control request from the stream sub,
+// which is nil for callers that are not attached.
 func (s *Server) handleWebsocketControlRequest(
 	ctx context.Context,
 	session *Session,
+	sub *subscriber,
 	req WebsocketControlRequest,
 ) (*StreamMessage, error) {
 	switch req.Type {
 	case "input":
-		return nil, session.write(ctx, req.Data)
+		return nil, session.writeFrom(ctx, sub, req.Data)
 	case "resize":
-		err := session.resize(req.Cols, req.Rows)
+		err := session.resizeFrom(sub, req.Cols, req.Rows)
 		if err == nil {
 			debugServerf("ws_control session=%s type=resize cols=%d rows=%d", session.id, req.Cols, req.Rows)
 		}
 		return nil, err
+	case "handoff":
+		if err := session.handoff(sub, req.StreamID); err != nil {
+			return nil, err
+		}
+		debugServerf("ws_control session=%s type=handoff stream=%q", session.id, req.StreamID)
+		return nil, nil
 	case "stop":
+		// Stopping ends the session for everyone, so it takes the same
+		// right as typing into it.
+		if err := session.checkInput(sub); err != nil {
+			return nil, err
+		}
 		if err := session.stop(); err != nil {
 			return nil, err
 		}
@@ -10,7 +10,7 @@ func TestHandleWebsocketControlRequestStopRemovesSession(t *testing.T) {
 	session := newSession(DefaultOptions(), "ws-control", "/tmp")
 	server.sessions[session.id] = session
 
-	if _, err := server.handleWebsocketControlRequest(context.Background(), session, WebsocketControlRequest{
+	if _, err := server.handleWebsocketControlRequest(context.Background(), session, nil, WebsocketControlRequest{
 		Type: "stop",
 	}); err != nil {
 		t.Fatalf("stop session: %v", err)
@@ -24,7 +24,7 @@ func TestHandleWebsocketControlRequestRejectsUnsupportedMessages(t *testing.T) {
 	server := NewServer(DefaultOptions())
 	session := newSession(DefaultOptions(), "ws-control", "/tmp")
 
-	_, err := server.handleWebsocketControlRequest(context.Background(), session, WebsocketControlRequest{
+	_, err := server.handleWebsocketControlRequest(context.Background(), session, nil, WebsocketControlRequest{
 		Type: "unknown",
 	})
 	if err == nil {
@@ -0,0 +1,419 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/ops"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+// adoptScanDepth bounds how far below each adopt directory checkouts are
+// searched for.
+const adoptScanDepth = 3
+
+type adoptCandidate struct {
+	path string
+	// repoPath is the repository owning the checkout: the main worktree for
+	// linked worktrees, the checkout itself for plain clones.
+	repoPath string
+	linked   bool
+	alias    string
+	branch   string
+}
+
+// AdoptWorkspace builds a new thread around existing git checkouts. Checkouts
+// found under input.Dirs are matched to registered repos by remote URL and
+// either moved into the thread or linked from it in place; unmatched
+// checkouts are reported as skipped.
+func (s *Service) AdoptWorkspace(ctx context.Context, input WorkspaceAdoptInput) (WorkspaceAdoptResult, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return WorkspaceAdoptResult{}, ValidationError{Message: "thread name required"}
+	}
+	worksetName := strings.TrimSpace(input.Workset)
+	if worksetName == "" {
+		worksetName = name
+	}
+	if len(input.Dirs) == 0 {
+		return WorkspaceAdoptResult{}, ValidationError{Message: "at least one directory required"}
+	}
+
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return WorkspaceAdoptResult{}, err
+	}
+	if err := workspaceCreateConflict(cfg, name, ""); err != nil {
+		return WorkspaceAdoptResult{}, err
+	}
+	root, err := newThreadRoot(cfg, name, worksetName, input.Path)
+	if err != nil {
+		return WorkspaceAdoptResult{}, err
+	}
+
+	checkouts, err := discoverCheckouts(input.Dirs)
+	if err != nil {
+		return WorkspaceAdoptResult{}, err
+	}
+	if len(checkouts) == 0 {
+		return WorkspaceAdoptResult{}, NotFoundError{Message: "no git checkouts found in " + strings.Join(input.Dirs, ", ")}
+	}
+	candidates, reports := s.matchAdoptCandidates(cfg, checkouts, input.Move)
+	if len(candidates) == 0 {
+		return WorkspaceAdoptResult{Repos: reports, Config: info}, ValidationError{
+			Message: "no checkouts match a registered repo; register them with `workset repo registry add` first",
+		}
+	}
+
+	_, statErr := os.Stat(root)
+	rootCreated := errors.Is(statErr, os.ErrNotExist)
+	ws, err := s.workspaces.Init(ctx, root, name, cfg.Defaults)
+	if err != nil {
+		return WorkspaceAdoptResult{}, err
+	}
+	branch := ws.State.CurrentBranch
+
+	var undo []func() error
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil && s.logf != nil {
+				s.logf("adopt rollback: %v", err)
+			}
+		}
+		if rootCreated {
+			_ = os.RemoveAll(root)
+		}
+	}
+	warnings := []string{}
+	prepared := make([]ops.PreparedRepo, 0, len(candidates))
+	for _, candidate := range candidates {
+		dest := workspace.RepoWorktreePath(root, branch, candidate.alias)
+		revert, err := s.placeAdoptedCheckout(ctx, candidate, dest, input.Move)
+		if err != nil {
+			rollback()
+			return WorkspaceAdoptResult{}, fmt.Errorf("adopt %s: %w", candidate.path, err)
+		}
+		undo = append(undo, revert)
+
+		repo := config.RepoConfig{
+			Name:      candidate.alias,
+			RepoDir:   candidate.alias,
+			LocalPath: candidate.repoPath,
+		}
+		if input.Move && !candidate.linked {
+			repo.LocalPath = dest
+		}
+		if !input.Move {
+			repo.Adopted = config.RepoAdoptedInPlace
+		}
+		switch {
+		case candidate.branch == "":
+			warnings = append(warnings, fmt.Sprintf("%s has a detached HEAD", candidate.path))
+		case candidate.branch != branch:
+			repo.Branch = candidate.branch
+		}
+		prepared = append(prepared, ops.PreparedRepo{Repo: repo})
+		for i := range reports {
+			if reports[i].Source == candidate.path {
+				reports[i].Path = dest
+			}
+		}
+	}
+	if _, err := ops.RegisterRepos(root, cfg.Defaults, prepared...); err != nil {
+		rollback()
+		return WorkspaceAdoptResult{}, err
+	}
+
+	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, loadInfo config.GlobalConfigLoadInfo) error {
+		info = loadInfo
+		if err := workspaceCreateConflict(*cfg, name, root); err != nil {
+			return err
+		}
+		registerWorkspace(cfg, name, root, s.clock(), worksetName)
+		s.rebuildWorksetRepoModel(ctx, cfg)
+		return nil
+	}); err != nil {
+		rollback()
+		return WorkspaceAdoptResult{}, err
+	}
+
+	warnings = append(warnings, warnOutsideWorkspaceRoot(root, cfg.Defaults.WorksetRoot)...)
+	return WorkspaceAdoptResult{
+		Workspace: WorkspaceCreatedJSON{
+			Name:    name,
+			Path:    root,
+			Workset: worksetName,
+			Branch:  branch,
+			Next:    fmt.Sprintf("workset status -t %s", shellArg(name)),
+		},
+		Repos:    reports,
+		Warnings: warnings,
+		Config:   info,
+	}, nil
+}
+
+// matchAdoptCandidates pairs checkouts with registered repos by remote URL.
+// Every checkout gets a report; only matched ones become candidates.
+func (s *Service) matchAdoptCandidates(cfg config.GlobalConfig, checkouts []string, move bool) ([]adoptCandidate, []AdoptedRepoJSON) {
+	index := s.registeredRemoteIndex(cfg)
+	adopted := map[string]string{}
+	candidates := []adoptCandidate{}
+	reports := make([]AdoptedRepoJSON, 0, len(checkouts))
+	for _, path := range checkouts {
+		report := AdoptedRepoJSON{Source: path, Status: "skipped"}
+		candidate, reason := s.adoptCandidateFor(cfg, path, index, move)
+		if reason == "" {
+			if previous, ok := adopted[candidate.alias]; ok {
+				reason = fmt.Sprintf("repo %s already adopted from %s", candidate.alias, previous)
+			}
+		}
+		report.Name = candidate.alias
+		report.Branch = candidate.branch
+		if reason != "" {
+			report.Reason = reason
+			reports = append(reports, report)
+			continue
+		}
+		adopted[candidate.alias] = path
+		candidates = append(candidates, candidate)
+		report.Status = "adopted"
+		reports = append(reports, report)
+	}
+	return candidates, reports
+}
+
+func (s *Service) adoptCandidateFor(cfg config.GlobalConfig, path string, index map[string]string, move bool) (adoptCandidate, string) {
+	candidate := adoptCandidate{path: path}
+	if threadRoot, err := workspace.FindRoot(path); err == nil {
+		return candidate, "already part of the thread at " + threadRoot
+	}
+	repoPath, linked, err := checkoutRepoPath(path)
+	if err != nil {
+		return candidate, err.Error()
+	}
+	candidate.repoPath, candidate.linked = repoPath, linked
+
+	remotes, err := s.git.RemoteNames(path)
+	if err != nil {
+		return candidate, fmt.Sprintf("read remotes: %v", err)
+	}
+	for _, remote := range remotes {
+		urls, err := s.git.RemoteURLs(path, remote)
+		if err != nil {
+			continue
+		}
+		for _, remoteURL := range urls {
+			if alias, ok := index[normalizeRemoteURL(remoteURL)]; ok {
+				candidate.alias = alias
+				break
+			}
+		}
+		if candidate.alias != "" {
+			break
+		}
+	}
+	if candidate.alias == "" {
+		return candidate, "no registered repo matches its remotes"
+	}
+	if move && !linked && samePath(path, cfg.Repos[candidate.alias].Path) {
+		return candidate, fmt.Sprintf("checkout is the registered path of repo %s; adopt it in place", candidate.alias)
+	}
+	if branch, ok, err := s.git.CurrentBranch(path); err == nil && ok {
+		candidate.branch = branch
+	}
+	return candidate, ""
+}
+
+// placeAdoptedCheckout moves the checkout to dest or links dest to it,
+// returning a function that undoes the placement.
+func (s *Service) placeAdoptedCheckout(ctx context.Context, candidate adoptCandidate, dest string, move bool) (func() error, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return nil, err
+	}
+	if !move {
+		if err := os.Symlink(candidate.path, dest); err != nil {
+			return nil, err
+		}
+		return func() error { return os.Remove(dest) }, nil
+	}
+	if candidate.linked {
+		if err := s.git.WorktreeMove(ctx, candidate.repoPath, candidate.path, dest); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return s.git.WorktreeMove(context.WithoutCancel(ctx), candidate.repoPath, dest, candidate.path)
+		}, nil
+	}
+	if err := os.Rename(candidate.path, dest); err != nil {
+		return nil, err
+	}
+	return func() error { return os.Rename(dest, candidate.path) }, nil
+}
+
+// registeredRemoteIndex maps normalized remote URLs to registered repo names.
+// Repos registered by path contribute the URLs of their remotes.
+func (s *Service) registeredRemoteIndex(cfg config.GlobalConfig) map[string]string {
+	names := make([]string, 0, len(cfg.Repos))
+	for name := range cfg.Repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	index := map[string]string{}
+	add := func(raw, name string) {
+		if key := normalizeRemoteURL(raw); key != "" {
+			if _, taken := index[key]; !taken {
+				index[key] = name
+			}
+		}
+	}
+	for _, name := range names {
+		repo := cfg.Repos[name]
+		add(repo.URL, name)
+		if repo.Path == "" {
+			continue
+		}
+		remotes, err := s.git.RemoteNames(repo.Path)
+		if err != nil {
+			continue
+		}
+		for _, remote := range remotes {
+			urls, err := s.git.RemoteURLs(repo.Path, remote)
+			if err != nil {
+				continue
+			}
+			for _, remoteURL := range urls {
+				add(remoteURL, name)
+			}
+		}
+	}
+	return index
+}
+
+// normalizeRemoteURL reduces a remote URL to a lowercased host/path so the
+// SSH, HTTPS, and scp-style forms of one remote compare equal; hosting
+// providers match owner and repo names case-insensitively. Local paths are
+// cleaned.
+func normalizeRemoteURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if trimmed, ok := strings.CutPrefix(raw, "file://"); ok {
+		return filepath.Clean(trimmed)
+	}
+	if looksLikeLocalPath(raw) {
+		return filepath.Clean(raw)
+	}
+	var host, path string
+	if parsed, err := url.Parse(raw); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		host, path = parsed.Hostname(), parsed.Path
+	} else {
+		rest := raw
+		if _, after, ok := strings.Cut(rest, "@"); ok {
+			rest = after
+		}
+		var ok bool
+		host, path, ok = strings.Cut(rest, ":")
+		if !ok {
+			return ""
+		}
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+	return strings.ToLower(host + "/" + path)
+}
+
+// discoverCheckouts lists git checkouts at or below dirs, without descending
+// into checkouts or hidden directories.
+func discoverCheckouts(dirs []string) ([]string, error) {
+	seen := map[string]bool{}
+	checkouts := []string{}
+	for _, dir := range dirs {
+		base, err := resolveLocalPathInput(dir)
+		if err != nil {
+			return nil, err
+		}
+		err = filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if path == base {
+					return err
+				}
+				return nil
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			if path != base && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+				if !seen[path] {
+					seen[path] = true
+					checkouts = append(checkouts, path)
+				}
+				return fs.SkipDir
+			}
+			if rel, err := filepath.Rel(base, path); err == nil && rel != "." &&
+				strings.Count(rel, string(filepath.Separator))+1 >= adoptScanDepth {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return checkouts, nil
+}
+
+// checkoutRepoPath resolves the repository owning a checkout. Linked worktrees
+// point at their main repository through the commondir of their git dir.
+func checkoutRepoPath(path string) (string, bool, error) {
+	dotGit := filepath.Join(path, ".git")
+	stat, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false, err
+	}
+	if stat.IsDir() {
+		return path, false, nil
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", false, err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false, fmt.Errorf("invalid .git file in %s", path)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	common, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Submodules and other gitdir indirections own their history.
+			return path, false, nil
+		}
+		return "", false, err
+	}
+	commonDir := strings.TrimSpace(string(common))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+	if filepath.Base(commonDir) == ".git" {
+		return filepath.Dir(commonDir), true, nil
+	}
+	return commonDir, true, nil
+}
@@ -0,0 +1,187 @@
+package worksetapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func createCheckout(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(path, ".git"), 0o755); err != nil {
+		t.Fatalf("create checkout: %v", err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	return resolved
+}
+
+func registerAPIRepo(env *testEnv) {
+	cfg := env.loadConfig()
+	cfg.Repos = map[string]config.RegisteredRepo{"api": {URL: "git@github.com:acme/api.git"}}
+	env.saveConfig(cfg)
+}
+
+func TestAdoptWorkspaceLinksCheckoutsInPlace(t *testing.T) {
+	env := newTestEnv(t)
+	registerAPIRepo(env)
+	src := filepath.Join(env.root, "src")
+	api := createCheckout(t, filepath.Join(src, "api"))
+	other := createCheckout(t, filepath.Join(src, "nested", "other"))
+	env.git.remoteURLs[api] = map[string][]string{"origin": {"https://github.com/Acme/api/"}}
+	env.git.currentBranch[api] = "fix/login"
+	env.git.currentOK[api] = true
+
+	result, err := env.svc.AdoptWorkspace(context.Background(), WorkspaceAdoptInput{
+		Name: "demo",
+		Dirs: []string{src},
+	})
+	if err != nil {
+		t.Fatalf("AdoptWorkspace: %v", err)
+	}
+	root := result.Workspace.Path
+	dest := workspace.RepoWorktreePath(root, result.Workspace.Branch, "api")
+	if target, err := os.Readlink(dest); err != nil || target != api {
+		t.Fatalf("expected %s linked to %s, got %q (%v)", dest, api, target, err)
+	}
+	if _, err := os.Stat(api); err != nil {
+		t.Fatalf("expected checkout left in place: %v", err)
+	}
+
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	if len(wsCfg.Repos) != 1 {
+		t.Fatalf("expected one repo, got %+v", wsCfg.Repos)
+	}
+	if got := wsCfg.Repos[0]; got.Name != "api" || got.LocalPath != api || got.Branch != "fix/login" {
+		t.Fatalf("unexpected repo config: %+v", got)
+	}
+	if _, ok := env.loadConfig().Workspaces["demo"]; !ok {
+		t.Fatalf("expected thread registered")
+	}
+	if len(result.Repos) != 2 || result.Repos[0].Status != "adopted" || result.Repos[1].Source != other || result.Repos[1].Status != "skipped" {
+		t.Fatalf("unexpected checkout reports: %+v", result.Repos)
+	}
+}
+
+// createLinkedWorktree lays out a main checkout of the api repo with a
+// linked worktree next to it and returns both paths.
+func createLinkedWorktree(t *testing.T, env *testEnv) (string, string) {
+	t.Helper()
+	mainRepo := createCheckout(t, filepath.Join(env.root, "src", "api"))
+	adminDir := filepath.Join(mainRepo, ".git", "worktrees", "feature")
+	if err := os.MkdirAll(adminDir, 0o755); err != nil {
+		t.Fatalf("create worktree admin dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, "commondir"), []byte("../..\n"), 0o644); err != nil {
+		t.Fatalf("write commondir: %v", err)
+	}
+	feature := filepath.Join(env.root, "src", "api-feature")
+	if err := os.MkdirAll(feature, 0o755); err != nil {
+		t.Fatalf("create worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(feature, ".git"), []byte("gitdir: "+adminDir+"\n"), 0o644); err != nil {
+		t.Fatalf("write .git file: %v", err)
+	}
+	env.git.remoteURLs[feature] = map[string][]string{"origin": {"git@github.com:acme/api.git"}}
+	return mainRepo, feature
+}
+
+func TestDeleteAdoptedInPlaceKeepsOriginalWorktree(t *testing.T) {
+	env := newTestEnv(t)
+	registerAPIRepo(env)
+	_, feature := createLinkedWorktree(t, env)
+	ctx := context.Background()
+
+	result, err := env.svc.AdoptWorkspace(ctx, WorkspaceAdoptInput{
+		Name: "demo",
+		Dirs: []string{feature},
+	})
+	if err != nil {
+		t.Fatalf("AdoptWorkspace: %v", err)
+	}
+	root := result.Workspace.Path
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	if got := wsCfg.Repos[0].Adopted; got != config.RepoAdoptedInPlace {
+		t.Fatalf("expected repo recorded as adopted in place, got %q", got)
+	}
+
+	if _, err := env.svc.DeleteWorkspace(ctx, WorkspaceDeleteInput{
+		Selector:    WorkspaceSelector{Value: "demo"},
+		DeleteFiles: true,
+		Purge:       true,
+		Force:       true,
+		Confirmed:   true,
+	}); err != nil {
+		t.Fatalf("DeleteWorkspace: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(feature, ".git")); err != nil {
+		t.Fatalf("expected the adopted worktree to survive thread delete: %v", err)
+	}
+	if len(env.git.worktreeRemovs) != 0 {
+		t.Fatalf("expected no worktree removals, got %+v", env.git.worktreeRemovs)
+	}
+	if _, err := os.Lstat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected thread directory removed, got %v", err)
+	}
+}
+
+func TestAdoptWorkspaceMovesLinkedWorktree(t *testing.T) {
+	env := newTestEnv(t)
+	registerAPIRepo(env)
+	mainRepo, feature := createLinkedWorktree(t, env)
+
+	result, err := env.svc.AdoptWorkspace(context.Background(), WorkspaceAdoptInput{
+		Name: "demo",
+		Dirs: []string{feature},
+		Move: true,
+	})
+	if err != nil {
+		t.Fatalf("AdoptWorkspace: %v", err)
+	}
+	dest := workspace.RepoWorktreePath(result.Workspace.Path, result.Workspace.Branch, "api")
+	if len(env.git.worktreeMoves) != 1 || env.git.worktreeMoves[0] != [2]string{feature, dest} {
+		t.Fatalf("unexpected worktree moves: %v", env.git.worktreeMoves)
+	}
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(result.Workspace.Path))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	if got := wsCfg.Repos[0].LocalPath; got != mainRepo {
+		t.Fatalf("expected main repo %s as local path, got %s", mainRepo, got)
+	}
+}
+
+func TestAdoptWorkspaceRequiresMatchingRepo(t *testing.T) {
+	env := newTestEnv(t)
+	registerAPIRepo(env)
+	src := filepath.Join(env.root, "src")
+	createCheckout(t, filepath.Join(src, "unknown"))
+
+	result, err := env.svc.AdoptWorkspace(context.Background(), WorkspaceAdoptInput{
+		Name: "demo",
+		Dirs: []string{src},
+	})
+	_ = requireErrorType[ValidationError](t, err)
+	if len(result.Repos) != 1 || result.Repos[0].Status != "skipped" {
+		t.Fatalf("expected skipped checkout report, got %+v", result.Repos)
+	}
+	if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+		t.Fatalf("thread registered without adopted repos")
+	}
+	root := filepath.Join(env.root, "worksets", workspace.WorkspaceDirName("demo"), workspace.WorkspaceDirName("demo"))
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected no thread directory, stat err: %v", err)
+	}
+}
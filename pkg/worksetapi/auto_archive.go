@@ -0,0 +1,273 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/ops"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+const (
+	autoArchiveActionArchived = "archived"
+	autoArchiveActionDryRun   = "would_archive"
+	autoArchiveActionKept     = "kept"
+
+	gcActionPruned = "pruned"
+	gcActionDryRun = "would_prune"
+	gcActionKept   = "kept"
+)
+
+// AutoArchive applies defaults.auto_archive: threads whose tracked pull
+// requests are all merged or closed, or that have not been used for
+// stale_days and have no dirty or unpushed repos, are archived. Pinned and
+// already archived threads are left alone. With input.DryRun nothing is
+// archived and the report says what would be.
+func (s *Service) AutoArchive(ctx context.Context, input AutoArchiveInput) (AutoArchiveResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return AutoArchiveResult{}, err
+	}
+	policy := cfg.Defaults.AutoArchive
+	now := s.clock()
+	threads := []AutoArchiveThreadJSON{}
+	var warnings []string
+
+	names := make([]string, 0, len(cfg.Workspaces))
+	for name := range cfg.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ref := cfg.Workspaces[name]
+		if ref.ArchivedAt != "" || ref.Pinned || ref.Path == "" {
+			continue
+		}
+		reason, kept, threadWarnings := s.autoArchiveDecision(ctx, cfg, name, ref, policy, input, now)
+		warnings = append(warnings, threadWarnings...)
+		if reason == "" {
+			continue
+		}
+		entry := AutoArchiveThreadJSON{Name: name, Path: ref.Path, Reason: reason}
+		switch {
+		case kept:
+			entry.Action = autoArchiveActionKept
+		case input.DryRun:
+			entry.Action = autoArchiveActionDryRun
+		default:
+			if _, _, err := s.ArchiveWorkspace(ctx, WorkspaceSelector{Value: name}, "auto-archive: "+reason); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: archive: %v", name, err))
+				continue
+			}
+			entry.Action = autoArchiveActionArchived
+		}
+		threads = append(threads, entry)
+	}
+	return AutoArchiveResult{Threads: threads, Warnings: warnings, Config: info}, nil
+}
+
+// autoArchiveDecision returns why a thread matches the policy, or an empty
+// reason when it does not. kept reports a match that is held back because
+// the thread still has local work.
+func (s *Service) autoArchiveDecision(
+	ctx context.Context,
+	cfg config.GlobalConfig,
+	name string,
+	ref config.WorkspaceRef,
+	policy config.AutoArchiveConfig,
+	input AutoArchiveInput,
+	now time.Time,
+) (string, bool, []string) {
+	var warnings []string
+	if policy.Merged {
+		done, prWarnings := s.trackedPullRequestsDone(ctx, name, ref.Path, input)
+		warnings = append(warnings, prWarnings...)
+		if done {
+			return "pull requests merged or closed", false, warnings
+		}
+	}
+	if policy.StaleDays <= 0 {
+		return "", false, warnings
+	}
+	lastUsed := ref.LastUsed
+	if lastUsed == "" {
+		lastUsed = ref.CreatedAt
+	}
+	usedAt, err := time.Parse(time.RFC3339, lastUsed)
+	if err != nil || now.Sub(usedAt) < time.Duration(policy.StaleDays)*24*time.Hour {
+		return "", false, warnings
+	}
+	reason := fmt.Sprintf("unused for %d days", policy.StaleDays)
+	if blocked := s.threadLocalWork(ctx, cfg, ref.Path); blocked != "" {
+		return reason + "; " + blocked, true, warnings
+	}
+	return reason, false, warnings
+}
+
+// trackedPullRequestsDone reports whether a thread tracks at least one pull
+// request and all of them are merged or closed. Unless offline, open ones are
+// looked up on the provider first; the tracked state is only updated from
+// the lookup when this is not a dry run.
+func (s *Service) trackedPullRequestsDone(ctx context.Context, name, root string, input AutoArchiveInput) (bool, []string) {
+	state, err := s.workspaces.LoadState(ctx, root)
+	if err != nil || len(state.PullRequests) == 0 {
+		return false, nil
+	}
+	repos := make([]string, 0, len(state.PullRequests))
+	for repo := range state.PullRequests {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		pr := state.PullRequests[repo]
+		if pullRequestFinished(pr.State, pr.Merged) {
+			continue
+		}
+		if input.Offline {
+			return false, nil
+		}
+		remote, headInfo, baseInfo, _, resolution, err := s.resolvePullRequest(ctx, PullRequestStatusInput{
+			Workspace: WorkspaceSelector{Value: name},
+			Repo:      repo,
+			Number:    pr.Number,
+		})
+		if err != nil {
+			return false, []string{fmt.Sprintf("%s: refresh pull request %s#%d: %v", name, repo, pr.Number, err)}
+		}
+		if !input.DryRun {
+			s.reconcileTrackedPullRequest(ctx, resolution, remote, baseInfo, headInfo)
+		}
+		if !pullRequestFinished(remote.State, remote.Merged) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func pullRequestFinished(state string, merged bool) bool {
+	return merged || strings.EqualFold(state, "closed")
+}
+
+// threadLocalWork describes dirty or unpushed repos in a thread, or returns
+// an empty string when it is safe to put away.
+func (s *Service) threadLocalWork(ctx context.Context, cfg config.GlobalConfig, root string) string {
+	wsConfig, err := s.workspaces.LoadConfig(ctx, root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ""
+		}
+		return fmt.Sprintf("safety check failed: %v", err)
+	}
+	report, err := ops.CheckWorkspaceSafety(ctx, ops.WorkspaceSafetyInput{
+		WorkspaceRoot: root,
+		Defaults:      cfg.Defaults,
+		RepoDefaults:  repoDefaultsMap(wsConfig, cfg),
+		Git:           s.git,
+	})
+	if err != nil {
+		return fmt.Sprintf("safety check failed: %v", err)
+	}
+	dirty, _, unpushed, _ := summarizeWorkspaceSafety(report)
+	var parts []string
+	if len(dirty) > 0 {
+		parts = append(parts, "dirty: "+strings.Join(dirty, ", "))
+	}
+	if len(unpushed) > 0 {
+		parts = append(parts, "unpushed: "+strings.Join(unpushed, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GC runs the auto-archive policy and, with input.PruneWorktrees, removes the
+// worktrees of archived threads that have no dirty or unpushed repos. Pruned
+// threads stay registered and archived with their workset.yaml and state.
+func (s *Service) GC(ctx context.Context, input GCInput) (GCResult, error) {
+	archived, err := s.AutoArchive(ctx, AutoArchiveInput{DryRun: input.DryRun, Offline: input.Offline})
+	if err != nil {
+		return GCResult{}, err
+	}
+	result := GCResult{
+		Archived: archived.Threads,
+		Pruned:   []GCPruneJSON{},
+		Warnings: archived.Warnings,
+		Config:   archived.Config,
+	}
+	if !input.PruneWorktrees {
+		return result, nil
+	}
+	cfg, _, err := s.loadGlobal(ctx)
+	if err != nil {
+		return GCResult{}, err
+	}
+	archiving := map[string]bool{}
+	for _, thread := range archived.Threads {
+		if thread.Action != autoArchiveActionKept {
+			archiving[thread.Name] = true
+		}
+	}
+	names := make([]string, 0, len(cfg.Workspaces))
+	for name, ref := range cfg.Workspaces {
+		if ref.Path != "" && (ref.ArchivedAt != "" || archiving[name]) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry, warning := s.pruneThreadWorktrees(ctx, cfg, name, cfg.Workspaces[name].Path, input.DryRun)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+			continue
+		}
+		if entry.Action != "" {
+			result.Pruned = append(result.Pruned, entry)
+		}
+	}
+	return result, nil
+}
+
+// pruneThreadWorktrees removes a thread's repo worktrees but keeps its
+// workset.yaml, so the repos stay listed and the workset keeps its repo set.
+func (s *Service) pruneThreadWorktrees(ctx context.Context, cfg config.GlobalConfig, name, root string, dryRun bool) (GCPruneJSON, string) {
+	ws, err := s.workspaces.Load(ctx, root, cfg.Defaults)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return GCPruneJSON{}, ""
+		}
+		return GCPruneJSON{}, fmt.Sprintf("%s: load workset.yaml: %v", name, err)
+	}
+	wsConfig := ws.Config
+	entry := GCPruneJSON{Name: name, Path: root}
+	for _, repo := range wsConfig.Repos {
+		config.ApplyRepoDefaults(&repo, cfg.Defaults)
+		if _, err := os.Stat(workspace.RepoWorktreePath(root, ws.State.CurrentBranch, repo.RepoDir)); err == nil {
+			entry.Repos = append(entry.Repos, repo.Name)
+		}
+	}
+	if len(entry.Repos) == 0 {
+		return GCPruneJSON{}, ""
+	}
+	if blocked := s.threadLocalWork(ctx, cfg, root); blocked != "" {
+		entry.Action = gcActionKept
+		entry.Reason = blocked
+		return entry, ""
+	}
+	if dryRun {
+		entry.Action = gcActionDryRun
+		return entry, ""
+	}
+	if err := s.removeWorkspaceRepoWorktrees(ctx, root, cfg.Defaults, false); err != nil {
+		_ = s.workspaces.SaveConfig(ctx, root, wsConfig)
+		return GCPruneJSON{}, fmt.Sprintf("%s: prune worktrees: %v", name, err)
+	}
+	if err := s.workspaces.SaveConfig(ctx, root, wsConfig); err != nil {
+		return GCPruneJSON{}, fmt.Sprintf("%s: restore workset.yaml: %v", name, err)
+	}
+	entry.Action = gcActionPruned
+	return entry, ""
+}
@@ -0,0 +1,248 @@
+package worksetapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func trackPullRequest(t *testing.T, root, repo string, pr workspace.PullRequestState) {
+	t.Helper()
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if state.PullRequests == nil {
+		state.PullRequests = map[string]workspace.PullRequestState{}
+	}
+	pr.Repo = repo
+	state.PullRequests[repo] = pr
+	if err := workspace.SaveState(root, state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+}
+
+func setAutoArchivePolicy(t *testing.T, env *testEnv, policy config.AutoArchiveConfig) {
+	t.Helper()
+	cfg := env.loadConfig()
+	cfg.Defaults.AutoArchive = policy
+	env.saveConfig(cfg)
+}
+
+func TestAutoArchiveArchivesThreadsWithFinishedPullRequests(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	setAutoArchivePolicy(t, env, config.AutoArchiveConfig{Merged: true})
+	merged := env.createWorkspace(ctx, "merged")
+	trackPullRequest(t, merged, "repo-a", workspace.PullRequestState{Number: 1, State: "closed", Merged: true})
+	trackPullRequest(t, merged, "repo-b", workspace.PullRequestState{Number: 2, State: "closed"})
+	open := env.createWorkspace(ctx, "open")
+	trackPullRequest(t, open, "repo-a", workspace.PullRequestState{Number: 3, State: "open"})
+	env.createWorkspace(ctx, "untracked")
+
+	dryRun, err := env.svc.AutoArchive(ctx, AutoArchiveInput{DryRun: true, Offline: true})
+	if err != nil {
+		t.Fatalf("AutoArchive dry run: %v", err)
+	}
+	if len(dryRun.Threads) != 1 || dryRun.Threads[0].Name != "merged" || dryRun.Threads[0].Action != autoArchiveActionDryRun {
+		t.Fatalf("unexpected dry run report: %+v", dryRun.Threads)
+	}
+	if ref := env.loadConfig().Workspaces["merged"]; ref.ArchivedAt != "" {
+		t.Fatalf("expected dry run to leave thread active, got %+v", ref)
+	}
+
+	result, err := env.svc.AutoArchive(ctx, AutoArchiveInput{Offline: true})
+	if err != nil {
+		t.Fatalf("AutoArchive: %v", err)
+	}
+	if len(result.Threads) != 1 || result.Threads[0].Action != autoArchiveActionArchived {
+		t.Fatalf("unexpected report: %+v", result.Threads)
+	}
+	cfg := env.loadConfig()
+	if ref := cfg.Workspaces["merged"]; ref.ArchivedReason != "auto-archive: pull requests merged or closed" {
+		t.Fatalf("expected merged thread archived, got %+v", ref)
+	}
+	for _, name := range []string{"open", "untracked"} {
+		if cfg.Workspaces[name].ArchivedAt != "" {
+			t.Fatalf("expected %s to stay active", name)
+		}
+	}
+}
+
+func TestAutoArchiveRefreshesOpenPullRequests(t *testing.T) {
+	env, root, repoPath := setupGitHubServiceRepo(t)
+	ctx := context.Background()
+	setAutoArchivePolicy(t, env, config.AutoArchiveConfig{Merged: true})
+	env.git.remoteURLs[repoPath] = map[string][]string{"origin": {"git@github.com:acme/repo-a.git"}}
+	env.git.remoteExists[repoPath] = map[string]bool{"upstream": false}
+	trackPullRequest(t, root, "repo-a", workspace.PullRequestState{Number: 7, State: "open"})
+	env.svc.github = &readHelpersGitHubProvider{client: &readHelpersGitHubClient{
+		getPullRequestFunc: func(_ context.Context, _ string, _ string, number int) (GitHubPullRequest, error) {
+			return GitHubPullRequest{Number: number, State: "closed", Merged: true, BaseRef: "main", HeadRef: "demo"}, nil
+		},
+	}}
+
+	result, err := env.svc.AutoArchive(ctx, AutoArchiveInput{})
+	if err != nil {
+		t.Fatalf("AutoArchive: %v", err)
+	}
+	if len(result.Threads) != 1 || result.Threads[0].Action != autoArchiveActionArchived {
+		t.Fatalf("expected refreshed thread archived, got %+v (warnings %v)", result.Threads, result.Warnings)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if !state.PullRequests["repo-a"].Merged {
+		t.Fatalf("expected merge recorded, got %+v", state.PullRequests["repo-a"])
+	}
+}
+
+func TestAutoArchiveDryRunLeavesTrackedPullRequestsAlone(t *testing.T) {
+	env, root, repoPath := setupGitHubServiceRepo(t)
+	ctx := context.Background()
+	setAutoArchivePolicy(t, env, config.AutoArchiveConfig{Merged: true})
+	env.git.remoteURLs[repoPath] = map[string][]string{"origin": {"git@github.com:acme/repo-a.git"}}
+	env.git.remoteExists[repoPath] = map[string]bool{"upstream": false}
+	trackPullRequest(t, root, "repo-a", workspace.PullRequestState{Number: 7, State: "open"})
+	env.svc.github = &readHelpersGitHubProvider{client: &readHelpersGitHubClient{
+		getPullRequestFunc: func(_ context.Context, _ string, _ string, number int) (GitHubPullRequest, error) {
+			return GitHubPullRequest{Number: number, State: "closed", BaseRef: "main", HeadRef: "demo"}, nil
+		},
+	}}
+	statePath := workspace.StatePath(root)
+	before, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+
+	result, err := env.svc.AutoArchive(ctx, AutoArchiveInput{DryRun: true})
+	if err != nil {
+		t.Fatalf("AutoArchive: %v", err)
+	}
+	if len(result.Threads) != 1 || result.Threads[0].Action != autoArchiveActionDryRun {
+		t.Fatalf("expected the closed pull request to be reported, got %+v (warnings %v)", result.Threads, result.Warnings)
+	}
+	after, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("expected dry run to leave state.json unchanged:\nbefore %s\nafter %s", before, after)
+	}
+}
+
+func TestAutoArchiveKeepsStaleThreadsWithLocalWork(t *testing.T) {
+	env := newTestEnv(t)
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		Clock:      func() time.Time { return env.now },
+		Logf:       func(string, ...any) {},
+	})
+	registerTestRepos(env, 1, "repo-a")
+	setAutoArchivePolicy(t, env, config.AutoArchiveConfig{StaleDays: 7})
+	ctx := context.Background()
+	for _, name := range []string{"clean", "dirty", "pinned"} {
+		if _, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: name, Repos: []string{"repo-a"}}); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+	cfg := env.loadConfig()
+	pinned := cfg.Workspaces["pinned"]
+	pinned.Pinned = true
+	cfg.Workspaces["pinned"] = pinned
+	env.saveConfig(cfg)
+	env.git.status[filepath.Join(cfg.Workspaces["dirty"].Path, "repo-a")] = git.StatusSummary{Dirty: true}
+	env.createWorkspace(ctx, "recent")
+	env.now = env.now.Add(8 * 24 * time.Hour)
+	env.createWorkspace(ctx, "fresh")
+
+	result, err := env.svc.AutoArchive(ctx, AutoArchiveInput{Offline: true})
+	if err != nil {
+		t.Fatalf("AutoArchive: %v", err)
+	}
+	actions := map[string]string{}
+	for _, thread := range result.Threads {
+		actions[thread.Name] = thread.Action
+	}
+	want := map[string]string{
+		"clean":  autoArchiveActionArchived,
+		"dirty":  autoArchiveActionKept,
+		"recent": autoArchiveActionArchived,
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("unexpected report: %+v", result.Threads)
+	}
+	for name, action := range want {
+		if actions[name] != action {
+			t.Fatalf("expected %s to be %s, got %+v", name, action, result.Threads)
+		}
+	}
+	if ref := env.loadConfig().Workspaces["clean"]; ref.ArchivedReason != "auto-archive: unused for 7 days" {
+		t.Fatalf("unexpected archive reason: %+v", ref)
+	}
+}
+
+func TestGCPrunesWorktreesOfArchivedThreads(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a")
+	ctx := context.Background()
+	roots := map[string]string{}
+	for _, name := range []string{"done", "dirty", "active"} {
+		created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: name, Repos: []string{"repo-a"}})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		roots[name] = created.Workspace.Path
+	}
+	for _, name := range []string{"done", "dirty"} {
+		if _, _, err := env.svc.ArchiveWorkspace(ctx, WorkspaceSelector{Value: name}, "finished"); err != nil {
+			t.Fatalf("archive %s: %v", name, err)
+		}
+	}
+	env.git.status[filepath.Join(roots["dirty"], "repo-a")] = git.StatusSummary{Dirty: true}
+
+	dryRun, err := env.svc.GC(ctx, GCInput{DryRun: true, Offline: true, PruneWorktrees: true})
+	if err != nil {
+		t.Fatalf("GC dry run: %v", err)
+	}
+	if len(dryRun.Pruned) != 2 || dryRun.Pruned[0].Action != gcActionKept || dryRun.Pruned[1].Action != gcActionDryRun {
+		t.Fatalf("unexpected dry run report: %+v", dryRun.Pruned)
+	}
+	if _, err := os.Stat(filepath.Join(roots["done"], "repo-a")); err != nil {
+		t.Fatalf("expected dry run to keep worktree: %v", err)
+	}
+
+	result, err := env.svc.GC(ctx, GCInput{Offline: true, PruneWorktrees: true})
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if len(result.Pruned) != 2 || result.Pruned[1].Name != "done" || result.Pruned[1].Action != gcActionPruned {
+		t.Fatalf("unexpected report: %+v", result.Pruned)
+	}
+	if _, err := os.Stat(filepath.Join(roots["done"], "repo-a")); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree pruned, stat err: %v", err)
+	}
+	for _, name := range []string{"dirty", "active"} {
+		if _, err := os.Stat(filepath.Join(roots[name], "repo-a")); err != nil {
+			t.Fatalf("expected %s worktree kept: %v", name, err)
+		}
+	}
+	wsConfig, err := config.LoadWorkspace(workspace.WorksetFile(roots["done"]))
+	if err != nil {
+		t.Fatalf("load workset.yaml: %v", err)
+	}
+	if len(wsConfig.Repos) != 1 || wsConfig.Repos[0].Name != "repo-a" {
+		t.Fatalf("expected repo kept in workset.yaml, got %+v", wsConfig.Repos)
+	}
+	if ref, ok := env.loadConfig().Workspaces["done"]; !ok || ref.ArchivedAt == "" {
+		t.Fatalf("expected pruned thread to stay archived, got %+v", ref)
+	}
+}
@@ -256,6 +256,11 @@ func findWorksetFiles(root string) ([]string, error) {
 			return nil
 		}
 		if entry.Name() == "workset.yaml" {
+			// Deleted threads in the trash are restored with `workset trash
+			// restore`, not recovered in place.
+			if _, err := os.Stat(filepath.Join(filepath.Dir(path), trashRecordFile)); err == nil {
+				return nil
+			}
 			paths = append(paths, path)
 		}
 		return nil
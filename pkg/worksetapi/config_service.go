@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/repostore"
 )
 
 // GetConfig loads the global config and its load metadata.
@@ -81,6 +82,48 @@ func setGlobalDefault(cfg *config.GlobalConfig, key, value string) error {
 			return err
 		}
 		cfg.Defaults.TerminalCursorBlink = normalized
+	case "defaults.provision_parallelism":
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || parsed < 1 || parsed > maxProvisionParallelism {
+			return fmt.Errorf("%s must be an integer between 1 and %d", key, maxProvisionParallelism)
+		}
+		cfg.Defaults.ProvisionParallelism = parsed
+	case "defaults.trash_retention_days":
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || parsed < 1 || parsed > maxTrashRetentionDays {
+			return fmt.Errorf("%s must be an integer between 1 and %d", key, maxTrashRetentionDays)
+		}
+		cfg.Defaults.TrashRetentionDays = parsed
+	case "defaults.auto_archive.enabled":
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		cfg.Defaults.AutoArchive.Enabled = parsed
+	case "defaults.auto_archive.merged":
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		cfg.Defaults.AutoArchive.Merged = parsed
+	case "defaults.auto_archive.stale_days":
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || parsed < 0 || parsed > maxAutoArchiveStaleDays {
+			return fmt.Errorf("%s must be an integer between 0 and %d", key, maxAutoArchiveStaleDays)
+		}
+		cfg.Defaults.AutoArchive.StaleDays = parsed
+	case "defaults.repo_store.prefetch_interval":
+		normalized, err := normalizeRepoStoreInterval(value, key)
+		if err != nil {
+			return err
+		}
+		cfg.Defaults.RepoStore.PrefetchInterval = normalized
+	case "defaults.repo_store.fetch_max_age":
+		normalized, err := normalizeRepoStoreInterval(value, key)
+		if err != nil {
+			return err
+		}
+		cfg.Defaults.RepoStore.FetchMaxAge = normalized
 	case "defaults.remotes.base", "defaults.remotes.write":
 		return fmt.Errorf("%s was removed; set defaults.remote or alias remote instead", key)
 	case "defaults.parallelism":
@@ -92,10 +135,25 @@ func setGlobalDefault(cfg *config.GlobalConfig, key, value string) error {
 }
 
 const (
-	minTerminalFontSize = 8
-	maxTerminalFontSize = 28
+	minTerminalFontSize     = 8
+	maxTerminalFontSize     = 28
+	maxProvisionParallelism = 32
+	maxTrashRetentionDays   = 3650
+	maxAutoArchiveStaleDays = 3650
 )
 
+func normalizeRepoStoreInterval(value, key string) (string, error) {
+	value = strings.TrimSpace(value)
+	duration, err := repostore.ParseInterval(value)
+	if err != nil {
+		return "", fmt.Errorf("%s must be a duration such as 15m, or 0 to disable", key)
+	}
+	if duration == 0 {
+		return "0", nil
+	}
+	return value, nil
+}
+
 func normalizeTerminalFontSize(value string) (string, error) {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
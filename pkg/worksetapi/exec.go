@@ -2,6 +2,7 @@ package worksetapi
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,32 +13,47 @@ import (
 	"github.com/strantalis/workset/internal/workspace"
 )
 
+type execTarget struct {
+	name     string
+	root     string
+	cfg      config.GlobalConfig
+	wsConfig config.WorkspaceConfig
+}
+
 // Exec runs a command inside the workspace root with standard env variables set.
 func (s *Service) Exec(ctx context.Context, input ExecInput) error {
-	cfg, _, err := s.loadGlobal(ctx)
+	target, err := s.resolveExecTarget(ctx, input.Workspace)
 	if err != nil {
 		return err
 	}
+	return s.exec(ctx, target.root, input.Command, execEnv(target))
+}
+
+func (s *Service) resolveExecTarget(ctx context.Context, selector WorkspaceSelector) (execTarget, error) {
+	cfg, _, err := s.loadGlobal(ctx)
+	if err != nil {
+		return execTarget{}, err
+	}
 
-	threadArg := strings.TrimSpace(input.Workspace.Value)
+	threadArg := strings.TrimSpace(selector.Value)
 	if threadArg == "" {
 		threadArg = strings.TrimSpace(cfg.Defaults.Thread)
 	}
 	if threadArg == "" {
-		return ValidationError{Message: "thread required"}
+		return execTarget{}, ValidationError{Message: "thread required"}
 	}
 
 	name, root, err := resolveThreadTarget(threadArg, &cfg)
 	if err != nil {
-		return err
+		return execTarget{}, err
 	}
 
 	wsConfig, err := s.workspaces.LoadConfig(ctx, root)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return NotFoundError{Message: "workset.yaml not found at " + workspace.WorksetFile(root)}
+			return execTarget{}, NotFoundError{Message: "workset.yaml not found at " + workspace.WorksetFile(root)}
 		}
-		return err
+		return execTarget{}, err
 	}
 
 	wsName := wsConfig.Name
@@ -53,19 +69,22 @@ func (s *Service) Exec(ctx context.Context, input ExecInput) error {
 			registerWorkspace(cfg, wsName, root, s.clock(), "")
 			return nil
 		}); err != nil {
-			return err
+			return execTarget{}, err
 		}
 	}
 
+	return execTarget{name: wsName, root: root, cfg: cfg, wsConfig: wsConfig}, nil
+}
+
+func execEnv(target execTarget) []string {
 	env := append(os.Environ(),
-		"WORKSET_ROOT="+root,
-		"WORKSET_CONFIG="+workspace.WorksetFile(root),
+		"WORKSET_ROOT="+target.root,
+		"WORKSET_CONFIG="+workspace.WorksetFile(target.root),
 	)
-	if wsName != "" {
-		env = append(env, "WORKSET_WORKSPACE="+wsName)
+	if target.name != "" {
+		env = append(env, "WORKSET_WORKSPACE="+target.name)
 	}
-
-	return s.exec(ctx, root, input.Command, env)
+	return env
 }
 
 func runExecCommand(ctx context.Context, root string, command []string, env []string) error {
@@ -78,3 +97,13 @@ func runExecCommand(ctx context.Context, root string, command []string, env []st
 	execCmd.Env = env
 	return execCmd.Run()
 }
+
+func runRepoExecCommand(ctx context.Context, dir string, command []string, env []string, stdout, stderr io.Writer) error {
+	execName, execArgs := session.ResolveExecCommand(command)
+	execCmd := exec.CommandContext(ctx, execName, execArgs...)
+	execCmd.Dir = dir
+	execCmd.Stdout = stdout
+	execCmd.Stderr = stderr
+	execCmd.Env = env
+	return execCmd.Run()
+}
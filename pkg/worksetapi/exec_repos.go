@@ -0,0 +1,233 @@
+package worksetapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+// ExecRepoFunc runs a command in a repo worktree and streams output to the provided writers.
+type ExecRepoFunc func(ctx context.Context, dir string, command []string, env []string, stdout, stderr io.Writer) error
+
+const (
+	ExecRepoStatusOK      = "ok"
+	ExecRepoStatusFailed  = "failed"
+	ExecRepoStatusMissing = "missing"
+)
+
+type execRepoTarget struct {
+	repo         config.RepoConfig
+	worktreePath string
+}
+
+// ExecEachRepo runs a command once per repo worktree in a thread.
+// Output lines are prefixed with the repo name; failures are reported per repo
+// rather than returned as an error so callers can render a combined summary.
+func (s *Service) ExecEachRepo(ctx context.Context, input ExecEachRepoInput) (ExecEachRepoResult, error) {
+	if len(input.Command) == 0 {
+		return ExecEachRepoResult{}, ValidationError{Message: "command required"}
+	}
+	target, err := s.resolveExecTarget(ctx, input.Workspace)
+	if err != nil {
+		return ExecEachRepoResult{}, err
+	}
+	state, err := s.workspaces.LoadState(ctx, target.root)
+	if err != nil {
+		return ExecEachRepoResult{}, err
+	}
+	branch := state.CurrentBranch
+	if branch == "" {
+		branch = target.cfg.Defaults.BaseBranch
+	}
+	targets, err := selectExecRepos(target.root, branch, target.wsConfig, input.Repos)
+	if err != nil {
+		return ExecEachRepoResult{}, err
+	}
+
+	stdout := input.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	stderr := input.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	outMu := &sync.Mutex{}
+
+	results := make([]ExecRepoResultJSON, len(targets))
+	parallel := max(input.Parallel, 1)
+	baseEnv := execEnv(target)
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for index := range targets {
+		// Acquire before spawning so repos start in config order.
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(targetIndex int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[targetIndex] = s.execInRepo(ctx, targets[targetIndex], input.Command, baseEnv, stdout, stderr, outMu)
+		}(index)
+	}
+	wg.Wait()
+
+	payload := ExecEachRepoJSON{
+		Workspace: target.name,
+		Command:   append([]string(nil), input.Command...),
+		Repos:     results,
+	}
+	for _, result := range results {
+		if result.Status == ExecRepoStatusOK {
+			payload.Succeeded++
+		} else {
+			payload.Failed++
+		}
+	}
+	payload.Total = len(results)
+	return ExecEachRepoResult{Payload: payload}, nil
+}
+
+func (s *Service) execInRepo(
+	ctx context.Context,
+	target execRepoTarget,
+	command []string,
+	baseEnv []string,
+	stdout io.Writer,
+	stderr io.Writer,
+	outMu *sync.Mutex,
+) ExecRepoResultJSON {
+	result := ExecRepoResultJSON{
+		Repo: target.repo.Name,
+		Path: target.worktreePath,
+	}
+	if info, err := os.Stat(target.worktreePath); err != nil || !info.IsDir() {
+		result.Status = ExecRepoStatusMissing
+		result.ExitCode = -1
+		result.Error = "worktree not found at " + target.worktreePath
+		return result
+	}
+
+	env := append(append([]string(nil), baseEnv...),
+		"WORKSET_REPO="+target.repo.Name,
+		"WORKSET_WORKTREE="+target.worktreePath,
+	)
+	prefix := "[" + target.repo.Name + "] "
+	out := &prefixWriter{prefix: prefix, dst: stdout, mu: outMu}
+	errOut := &prefixWriter{prefix: prefix, dst: stderr, mu: outMu}
+
+	started := s.clock()
+	runErr := s.execRepo(ctx, target.worktreePath, command, env, out, errOut)
+	result.DurationMS = s.clock().Sub(started).Milliseconds()
+	_ = out.Flush()
+	_ = errOut.Flush()
+
+	if runErr != nil {
+		result.Status = ExecRepoStatusFailed
+		result.ExitCode = exitCodeFromError(runErr)
+		result.Error = runErr.Error()
+		return result
+	}
+	result.Status = ExecRepoStatusOK
+	return result
+}
+
+func selectExecRepos(root, branch string, wsConfig config.WorkspaceConfig, names []string) ([]execRepoTarget, error) {
+	repos := wsConfig.Repos
+	if len(names) > 0 {
+		repos = make([]config.RepoConfig, 0, len(names))
+		seen := map[string]bool{}
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			repo, ok := findWorkspaceRepo(wsConfig, name)
+			if !ok {
+				return nil, NotFoundError{Message: fmt.Sprintf("repo %q not found in workspace", name)}
+			}
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return nil, ValidationError{Message: "no repos in thread"}
+	}
+	targets := make([]execRepoTarget, 0, len(repos))
+	for _, repo := range repos {
+		repoDir := repo.RepoDir
+		if repoDir == "" {
+			repoDir = repo.Name
+		}
+		targets = append(targets, execRepoTarget{
+			repo:         repo,
+			worktreePath: workspace.RepoWorktreePath(root, branch, repoDir),
+		})
+	}
+	return targets, nil
+}
+
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// prefixWriter writes complete lines to dst with a prefix, holding mu so
+// concurrent repos never interleave partial lines.
+type prefixWriter struct {
+	prefix string
+	dst    io.Writer
+	mu     *sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the partial line buffered until the next newline or Flush.
+			w.buf.Reset()
+			w.buf.Write(line)
+			break
+		}
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered partial line.
+func (w *prefixWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	line := append([]byte(nil), w.buf.Bytes()...)
+	line = append(line, '\n')
+	w.buf.Reset()
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.dst, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.dst.Write(line)
+	return err
+}
@@ -57,6 +57,29 @@ func gitCommitMessage(ctx context.Context, repoPath, message string, runner Comm
 	return nil
 }
 
+// gitUndoLastCommit drops HEAD's commit while keeping its changes staged.
+// A repo's first commit has no parent to reset to, so its branch is deleted
+// instead, which leaves the branch unborn with the changes still staged.
+func gitUndoLastCommit(ctx context.Context, repoPath string, runner CommandRunner) error {
+	command := []string{"git", "reset", "--soft", "HEAD~1"}
+	parents, err := runner(ctx, repoPath, []string{"git", "log", "-1", "--format=%P", "HEAD"}, os.Environ(), "")
+	if err == nil && parents.ExitCode == 0 && strings.TrimSpace(parents.Stdout) == "" {
+		command = []string{"git", "update-ref", "-d", "HEAD"}
+	}
+	result, err := runner(ctx, repoPath, command, os.Environ(), "")
+	if err != nil || result.ExitCode != 0 {
+		msg := strings.TrimSpace(result.Stderr)
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		if msg == "" {
+			msg = "git reset failed"
+		}
+		return ValidationError{Message: msg}
+	}
+	return nil
+}
+
 func gitPushBranch(ctx context.Context, repoPath, remote, branch string, runner CommandRunner) error {
 	if strings.TrimSpace(remote) == "" {
 		return ValidationError{Message: "remote name required to push head branch"}
@@ -234,6 +257,22 @@ func gitAheadBehind(ctx context.Context, repoPath, branch string, runner Command
 	return ahead, behind, nil
 }
 
+// gitCommitsAhead counts commits on HEAD that are not reachable from baseRef.
+func gitCommitsAhead(ctx context.Context, repoPath, baseRef string, runner CommandRunner) (int, error) {
+	result, err := runner(ctx, repoPath, []string{"git", "rev-list", "--count", baseRef + "..HEAD"}, os.Environ(), "")
+	if err != nil || result.ExitCode != 0 {
+		message := strings.TrimSpace(result.Stderr)
+		if message == "" && err != nil {
+			message = err.Error()
+		}
+		if message == "" {
+			message = "unable to compare HEAD with " + baseRef
+		}
+		return 0, ValidationError{Message: message}
+	}
+	return parseCount(result.Stdout)
+}
+
 func parseCount(output string) (int, error) {
 	output = strings.TrimSpace(output)
 	if output == "" {
@@ -73,7 +73,15 @@ func (s *Service) resolveRepo(ctx context.Context, input RepoSelectionInput) (re
 		if repoPath == "" {
 			return repoResolution{}, ValidationError{Message: fmt.Sprintf("repo path unavailable for %q", repo.Name)}
 		}
-		repoDefaults := resolveRepoDefaults(cfg, repo.Name)
+		repoDefaults := resolveRepoDefaults(cfg, repo)
+		defaults := cfg.Defaults
+		threadName := wsConfig.Name
+		if threadName == "" {
+			threadName = threadNameByPath(&cfg, wsRoot)
+		}
+		if template, ok := worksetTemplateForThread(cfg, threadName); ok {
+			defaults = withTemplateAgent(defaults, template)
+		}
 		return repoResolution{
 			ConfigInfo:    info,
 			WorkspaceName: wsConfig.Name,
@@ -82,7 +90,7 @@ func (s *Service) resolveRepo(ctx context.Context, input RepoSelectionInput) (re
 			Repo:          repo,
 			RepoPath:      repoPath,
 			Branch:        branch,
-			Defaults:      cfg.Defaults,
+			Defaults:      defaults,
 			RepoDefaults:  repoDefaults,
 		}, nil
 	}
@@ -191,10 +199,12 @@ func parseHostPath(host, path string) (remoteInfo, error) {
 	if len(parts) < 2 {
 		return remoteInfo{}, fmt.Errorf("invalid repo path: %s", path)
 	}
+	// GitLab nests projects under subgroups, so the owner is the full
+	// namespace path and the repo is the last segment.
 	return remoteInfo{
-		Host:  host,
-		Owner: parts[0],
-		Repo:  parts[1],
+		Host:  strings.ToLower(host),
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Repo:  parts[len(parts)-1],
 	}, nil
 }
 
@@ -13,6 +13,7 @@ func TestParseGitHubRemoteURL(t *testing.T) {
 		{"ssh", "git@github.com:acme/widgets.git", "github.com", "acme", "widgets"},
 		{"https", "https://github.com/acme/widgets", "github.com", "acme", "widgets"},
 		{"ssh-url", "ssh://git@github.com/acme/widgets.git", "github.com", "acme", "widgets"},
+		{"gitlab-subgroup", "git@gitlab.example.com:group/sub/widgets.git", "gitlab.example.com", "group/sub", "widgets"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -36,6 +36,7 @@ type GitHubAuthModeProvider interface {
 type GitHubClient interface {
 	CreatePullRequest(ctx context.Context, owner, repo string, pr GitHubNewPullRequest) (GitHubPullRequest, error)
 	GetPullRequest(ctx context.Context, owner, repo string, number int) (GitHubPullRequest, error)
+	UpdatePullRequestBody(ctx context.Context, owner, repo string, number int, body string) (GitHubPullRequest, error)
 	ListPullRequests(ctx context.Context, owner, repo, head, state string, page, perPage int) ([]GitHubPullRequest, int, error)
 	SearchRepositories(ctx context.Context, query string, perPage int) ([]GitHubRepositorySearchResult, error)
 	ListReviewComments(ctx context.Context, owner, repo string, number, page, perPage int) ([]PullRequestReviewCommentJSON, int, error)
@@ -106,6 +106,19 @@ func (c *githubCLIClient) GetPullRequest(ctx context.Context, owner, repo string
 	return mapPullRequestREST(response), nil
 }
 
+func (c *githubCLIClient) UpdatePullRequestBody(ctx context.Context, owner, repo string, number int, body string) (GitHubPullRequest, error) {
+	payload, err := json.Marshal(map[string]any{"body": body})
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	var response pullRequestREST
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, number)
+	if err := c.rest.DoWithContext(ctx, http.MethodPatch, path, bytes.NewReader(payload), &response); err != nil {
+		return GitHubPullRequest{}, wrapAuthError(err)
+	}
+	return mapPullRequestREST(response), nil
+}
+
 func (c *githubCLIClient) ListPullRequests(ctx context.Context, owner, repo, head, state string, page, perPage int) ([]GitHubPullRequest, int, error) {
 	query := url.Values{}
 	if state != "" {
@@ -159,6 +159,16 @@ func (c *githubPATClient) GetPullRequest(ctx context.Context, owner, repo string
 	return mapPullRequest(pr), nil
 }
 
+func (c *githubPATClient) UpdatePullRequestBody(ctx context.Context, owner, repo string, number int, body string) (GitHubPullRequest, error) {
+	updated, _, err := c.client.PullRequests.Edit(ctx, owner, repo, number, &github.PullRequest{
+		Body: github.Ptr(body),
+	})
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	return mapPullRequest(updated), nil
+}
+
 func (c *githubPATClient) ListPullRequests(ctx context.Context, owner, repo, head, state string, page, perPage int) ([]GitHubPullRequest, int, error) {
 	opts := &github.PullRequestListOptions{
 		State:       state,
@@ -0,0 +1,175 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
+)
+
+type threadCommitPlan struct {
+	entry      CommitAndPushRepoJSON
+	resolution repoResolution
+	branch     string
+	remote     string
+}
+
+// CommitAndPushThread commits and pushes every dirty repo in a thread.
+//
+// The operation runs in three phases so a mistake in one repo does not leave
+// the thread half-committed: every dirty repo is first resolved, preflighted,
+// and given a commit message; if any of that fails nothing is committed.
+// Repos are then committed, and if a commit fails the commits already made are
+// undone with a soft reset so their changes stay staged. Only when every
+// commit succeeds are the repos pushed; push failures are reported per repo.
+func (s *Service) CommitAndPushThread(ctx context.Context, input CommitAndPushThreadInput) (CommitAndPushThreadResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return CommitAndPushThreadResult{}, err
+	}
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, input.Workspace)
+	if err != nil {
+		return CommitAndPushThreadResult{}, err
+	}
+	if len(wsConfig.Repos) == 0 {
+		return CommitAndPushThreadResult{}, ValidationError{Message: "thread has no repos"}
+	}
+	selector := WorkspaceSelector{Value: wsRoot}
+	emitter := func(repo string) func(CommitAndPushStage) {
+		return func(stage CommitAndPushStage) {
+			if input.OnStage != nil {
+				input.OnStage(repo, stage)
+			}
+		}
+	}
+
+	plans := make([]*threadCommitPlan, 0, len(wsConfig.Repos))
+	failedRepo := ""
+	for _, repo := range wsConfig.Repos {
+		plan := s.planThreadCommit(ctx, selector, repo.Name, input.Message, emitter(repo.Name))
+		if plan.entry.Status == CommitAndPushRepoStatusFailed && failedRepo == "" {
+			failedRepo = repo.Name
+		}
+		plans = append(plans, plan)
+	}
+	if failedRepo != "" {
+		return threadCommitResult(plans, info, "not committed: "+failedRepo+" failed preflight"), nil
+	}
+
+	committed := []*threadCommitPlan{}
+	for _, plan := range plans {
+		if plan.entry.Status != "" {
+			continue
+		}
+		sha, err := s.stageAndCommit(ctx, plan.resolution, plan.entry.Message, emitter(plan.entry.Repo))
+		if err != nil {
+			plan.entry.Status = CommitAndPushRepoStatusFailed
+			plan.entry.Error = err.Error()
+			failedRepo = plan.entry.Repo
+			break
+		}
+		plan.entry.Committed = true
+		plan.entry.SHA = sha
+		committed = append(committed, plan)
+	}
+	if failedRepo != "" {
+		for _, plan := range committed {
+			plan.entry.Committed = false
+			plan.entry.SHA = ""
+			plan.entry.Status = CommitAndPushRepoStatusSkipped
+			plan.entry.Error = "commit rolled back: " + failedRepo + " failed to commit"
+			if err := gitUndoLastCommit(ctx, plan.resolution.RepoPath, s.commands); err != nil {
+				plan.entry.Committed = true
+				plan.entry.Status = CommitAndPushRepoStatusFailed
+				plan.entry.Error = fmt.Sprintf("%s failed to commit and rollback failed: %s", failedRepo, err.Error())
+			}
+		}
+		return threadCommitResult(plans, info, "not committed: "+failedRepo+" failed to commit"), nil
+	}
+
+	for _, plan := range committed {
+		emitter(plan.entry.Repo)(CommitAndPushStagePushing)
+		if err := gitPushBranch(ctx, plan.resolution.RepoPath, plan.remote, plan.branch, s.commands); err != nil {
+			plan.entry.Status = CommitAndPushRepoStatusFailed
+			plan.entry.Error = err.Error()
+			continue
+		}
+		plan.entry.Pushed = true
+		plan.entry.Status = CommitAndPushRepoStatusPushed
+		s.logHookWarnings(s.runRepoLifecycleHooks(ctx, plan.resolution, hooks.EventCommitPushed, "commit.push", hooks.Context{
+			CommitSHA: plan.entry.SHA,
+		}))
+	}
+	return threadCommitResult(plans, info, ""), nil
+}
+
+// planThreadCommit resolves a repo and prepares its commit without changing
+// anything. Clean repos come back with status clean, preflight errors with
+// status failed, and repos ready to commit with an empty status.
+func (s *Service) planThreadCommit(
+	ctx context.Context,
+	selector WorkspaceSelector,
+	repo string,
+	message string,
+	emitStage func(CommitAndPushStage),
+) *threadCommitPlan {
+	plan := &threadCommitPlan{entry: CommitAndPushRepoJSON{Repo: repo}}
+	err := func() error {
+		resolution, err := s.resolveRepo(ctx, RepoSelectionInput{Workspace: selector, Repo: repo})
+		if err != nil {
+			return err
+		}
+		plan.resolution = resolution
+		dirty, err := gitHasUncommittedChanges(ctx, resolution.RepoPath, s.commands)
+		if err != nil {
+			return err
+		}
+		if !dirty {
+			plan.entry.Status = CommitAndPushRepoStatusClean
+			return nil
+		}
+		plan.branch, err = s.resolveCurrentBranch(resolution)
+		if err != nil {
+			return err
+		}
+		if err := s.preflightSSHAuth(ctx, resolution); err != nil {
+			return err
+		}
+		headInfo, _, err := s.resolveRemoteInfo(ctx, resolution, "")
+		if err != nil {
+			return err
+		}
+		plan.remote = headInfo.Remote
+		plan.entry.Message = strings.TrimSpace(message)
+		if plan.entry.Message == "" {
+			emitStage(CommitAndPushStageGeneratingMessage)
+			plan.entry.Message, err = s.generateCommitMessage(ctx, resolution, resolution.RepoPath, plan.branch)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		plan.entry.Status = CommitAndPushRepoStatusFailed
+		plan.entry.Error = err.Error()
+	}
+	return plan
+}
+
+// threadCommitResult collects plan entries, marking repos that never ran as
+// skipped with reason.
+func threadCommitResult(plans []*threadCommitPlan, info config.GlobalConfigLoadInfo, reason string) CommitAndPushThreadResult {
+	repos := make([]CommitAndPushRepoJSON, 0, len(plans))
+	for _, plan := range plans {
+		entry := plan.entry
+		if entry.Status == "" {
+			entry.Status = CommitAndPushRepoStatusSkipped
+			entry.Error = reason
+		}
+		repos = append(repos, entry)
+	}
+	return CommitAndPushThreadResult{Repos: repos, Config: info}
+}
@@ -0,0 +1,190 @@
+package worksetapi
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type threadCommitRunner struct {
+	dirty      map[string]bool
+	failCommit map[string]bool
+	rootCommit map[string]bool
+	calls      []string
+}
+
+func (r *threadCommitRunner) run(_ context.Context, dir string, command []string, _ []string, _ string) (CommandResult, error) {
+	repo := filepath.Base(dir)
+	if len(command) < 2 || command[0] != "git" {
+		return CommandResult{}, nil
+	}
+	r.calls = append(r.calls, repo+": "+strings.Join(command[1:], " "))
+	switch command[1] {
+	case "status":
+		if r.dirty[repo] {
+			return CommandResult{Stdout: " M main.go\n"}, nil
+		}
+	case "diff":
+		return CommandResult{Stdout: "main.go\n"}, nil
+	case "commit":
+		if r.failCommit[repo] {
+			return CommandResult{Stderr: "pre-commit hook failed", ExitCode: 1}, nil
+		}
+	case "rev-parse":
+		return CommandResult{Stdout: "sha-" + repo + "\n"}, nil
+	case "log":
+		if r.rootCommit[repo] {
+			return CommandResult{Stdout: "\n"}, nil
+		}
+		return CommandResult{Stdout: "parent-" + repo + "\n"}, nil
+	}
+	return CommandResult{}, nil
+}
+
+func (r *threadCommitRunner) ran(prefix string) bool {
+	for _, call := range r.calls {
+		if strings.HasPrefix(call, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func setupThreadCommit(t *testing.T, runner *threadCommitRunner) (*testEnv, string) {
+	t.Helper()
+	ctx := context.Background()
+	env, root, repoAPath := setupGitHubServiceRepo(t)
+	paths := []string{repoAPath}
+	for _, name := range []string{"repo-b", "repo-c"} {
+		result, err := env.svc.AddRepo(ctx, RepoAddInput{
+			Workspace:  WorkspaceSelector{Value: root},
+			Name:       name,
+			NameSet:    true,
+			SourcePath: env.createLocalRepo(name),
+		})
+		if err != nil {
+			t.Fatalf("AddRepo %s: %v", name, err)
+		}
+		paths = append(paths, result.WorktreePath)
+	}
+	for _, path := range paths {
+		env.git.remoteURLs[path] = map[string][]string{"origin": {"https://github.com/acme/" + filepath.Base(path) + ".git"}}
+		env.git.remoteExists[path] = map[string]bool{"upstream": false}
+		env.git.currentBranch[path] = "feature"
+		env.git.currentOK[path] = true
+	}
+	env.svc.commands = runner.run
+	return env, root
+}
+
+func TestCommitAndPushThreadCommitsDirtyRepos(t *testing.T) {
+	runner := &threadCommitRunner{dirty: map[string]bool{"repo-a": true, "repo-c": true}}
+	env, root := setupThreadCommit(t, runner)
+	stages := []string{}
+
+	result, err := env.svc.CommitAndPushThread(context.Background(), CommitAndPushThreadInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Message:   "Add login",
+		OnStage: func(repo string, stage CommitAndPushStage) {
+			stages = append(stages, repo+":"+string(stage))
+		},
+	})
+	if err != nil {
+		t.Fatalf("CommitAndPushThread: %v", err)
+	}
+	got := map[string]CommitAndPushRepoJSON{}
+	for _, entry := range result.Repos {
+		got[entry.Repo] = entry
+	}
+	if a := got["repo-a"]; a.Status != CommitAndPushRepoStatusPushed || !a.Committed || !a.Pushed || a.SHA != "sha-repo-a" {
+		t.Fatalf("unexpected repo-a result: %+v", a)
+	}
+	if got["repo-b"].Status != CommitAndPushRepoStatusClean || got["repo-c"].Status != CommitAndPushRepoStatusPushed {
+		t.Fatalf("unexpected results: %+v", result.Repos)
+	}
+	if !runner.ran("repo-c: push -u origin feature") || runner.ran("repo-b: commit") {
+		t.Fatalf("unexpected git calls: %v", runner.calls)
+	}
+	want := "repo-a:staging,repo-a:committing,repo-c:staging,repo-c:committing,repo-a:pushing,repo-c:pushing"
+	if strings.Join(stages, ",") != want {
+		t.Fatalf("unexpected stages: %v", stages)
+	}
+}
+
+func TestCommitAndPushThreadRollsBackWhenCommitFails(t *testing.T) {
+	runner := &threadCommitRunner{
+		dirty:      map[string]bool{"repo-a": true, "repo-b": true, "repo-c": true},
+		failCommit: map[string]bool{"repo-b": true},
+	}
+	env, root := setupThreadCommit(t, runner)
+
+	result, err := env.svc.CommitAndPushThread(context.Background(), CommitAndPushThreadInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Message:   "Add login",
+	})
+	if err != nil {
+		t.Fatalf("CommitAndPushThread: %v", err)
+	}
+	statuses := []string{}
+	for _, entry := range result.Repos {
+		statuses = append(statuses, entry.Repo+"="+string(entry.Status))
+		if entry.Committed || entry.Pushed {
+			t.Fatalf("expected nothing committed or pushed, got %+v", entry)
+		}
+	}
+	if strings.Join(statuses, ",") != "repo-a=skipped,repo-b=failed,repo-c=skipped" {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if result.Repos[1].Error != "pre-commit hook failed" {
+		t.Fatalf("unexpected repo-b error: %q", result.Repos[1].Error)
+	}
+	if !runner.ran("repo-a: reset --soft HEAD~1") || runner.ran("repo-c: commit") || runner.ran("repo-a: push") {
+		t.Fatalf("unexpected git calls: %v", runner.calls)
+	}
+}
+
+func TestCommitAndPushThreadRollsBackFirstCommit(t *testing.T) {
+	runner := &threadCommitRunner{
+		dirty:      map[string]bool{"repo-a": true, "repo-b": true},
+		failCommit: map[string]bool{"repo-b": true},
+		rootCommit: map[string]bool{"repo-a": true},
+	}
+	env, root := setupThreadCommit(t, runner)
+
+	if _, err := env.svc.CommitAndPushThread(context.Background(), CommitAndPushThreadInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Message:   "Initial commit",
+	}); err != nil {
+		t.Fatalf("CommitAndPushThread: %v", err)
+	}
+	if !runner.ran("repo-a: update-ref -d HEAD") || runner.ran("repo-a: reset") {
+		t.Fatalf("expected the first commit undone by deleting HEAD, got %v", runner.calls)
+	}
+}
+
+func TestCommitAndPushThreadCommitsNothingWhenPreflightFails(t *testing.T) {
+	runner := &threadCommitRunner{dirty: map[string]bool{"repo-a": true, "repo-b": true}}
+	env, root := setupThreadCommit(t, runner)
+
+	result, err := env.svc.CommitAndPushThread(context.Background(), CommitAndPushThreadInput{
+		Workspace: WorkspaceSelector{Value: root},
+	})
+	if err != nil {
+		t.Fatalf("CommitAndPushThread: %v", err)
+	}
+	for _, entry := range result.Repos {
+		if entry.Repo == "repo-c" {
+			if entry.Status != CommitAndPushRepoStatusClean {
+				t.Fatalf("expected repo-c clean, got %+v", entry)
+			}
+			continue
+		}
+		if entry.Status != CommitAndPushRepoStatusFailed || entry.Error == "" {
+			t.Fatalf("expected message generation failure, got %+v", entry)
+		}
+	}
+	if runner.ran("repo-a: add") || runner.ran("repo-a: commit") {
+		t.Fatalf("expected no changes staged, calls: %v", runner.calls)
+	}
+}
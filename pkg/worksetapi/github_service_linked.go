@@ -0,0 +1,360 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+const (
+	relatedPullRequestsStart = "<!-- workset:related-prs:start -->"
+	relatedPullRequestsEnd   = "<!-- workset:related-prs:end -->"
+)
+
+// CreateLinkedPullRequests opens a pull request for every repo in a thread
+// that has commits ahead of its base branch, records the repos as a linked
+// group in the thread state, and appends a Related PRs section to each body
+// that cross-links the other pull requests in the group.
+func (s *Service) CreateLinkedPullRequests(ctx context.Context, input LinkedPullRequestsInput) (LinkedPullRequestsResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return LinkedPullRequestsResult{}, err
+	}
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, input.Workspace)
+	if err != nil {
+		return LinkedPullRequestsResult{}, err
+	}
+	if len(wsConfig.Repos) == 0 {
+		return LinkedPullRequestsResult{}, ValidationError{Message: "thread has no repos"}
+	}
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil {
+		return LinkedPullRequestsResult{}, err
+	}
+	input.Workspace = WorkspaceSelector{Value: wsRoot}
+
+	result := LinkedPullRequestsResult{Config: info}
+	grouped := []string{}
+	for _, repo := range wsConfig.Repos {
+		entry := s.createLinkedPullRequest(ctx, input, repo.Name, state.PullRequests)
+		result.PullRequests = append(result.PullRequests, entry)
+		if entry.Status == LinkedPullRequestStatusCreated || entry.Status == LinkedPullRequestStatusExisting {
+			grouped = append(grouped, repo.Name)
+		}
+	}
+	if len(grouped) == 0 {
+		return result, nil
+	}
+	if err := s.addToPullRequestGroup(ctx, wsRoot, grouped...); err != nil {
+		return result, err
+	}
+	result.Warnings = s.syncLinkedPullRequests(ctx, wsRoot)
+
+	if state, err := s.workspaces.LoadState(ctx, wsRoot); err == nil {
+		for i, entry := range result.PullRequests {
+			if entry.PullRequest == nil {
+				continue
+			}
+			if tracked, ok := state.PullRequests[entry.Repo]; ok && tracked.Number == entry.PullRequest.Number {
+				result.PullRequests[i].PullRequest.Body = tracked.Body
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) createLinkedPullRequest(
+	ctx context.Context,
+	input LinkedPullRequestsInput,
+	repoName string,
+	tracked map[string]workspace.PullRequestState,
+) LinkedPullRequestJSON {
+	entry := LinkedPullRequestJSON{Repo: repoName}
+	fail := func(err error) LinkedPullRequestJSON {
+		entry.Status = LinkedPullRequestStatusFailed
+		entry.Reason = err.Error()
+		return entry
+	}
+	if pr, ok := tracked[repoName]; ok && strings.EqualFold(pr.State, "open") && !pr.Merged {
+		payload := trackedPullRequestPayload(pr)
+		entry.Status = LinkedPullRequestStatusExisting
+		entry.PullRequest = &payload
+		return entry
+	}
+
+	resolution, err := s.resolveRepo(ctx, RepoSelectionInput{Workspace: input.Workspace, Repo: repoName})
+	if err != nil {
+		return fail(err)
+	}
+	_, baseInfo, err := s.resolveRemoteInfo(ctx, resolution, "")
+	if err != nil {
+		return fail(err)
+	}
+	client, err := s.githubClient(ctx, baseInfo.Host)
+	if err != nil {
+		return fail(err)
+	}
+	base := strings.TrimSpace(input.Base)
+	if base == "" {
+		base, err = s.resolveDefaultBranch(ctx, client, baseInfo, resolution)
+		if err != nil {
+			return fail(err)
+		}
+	}
+	baseRef := baseInfo.Remote + "/" + base
+	ahead, err := gitCommitsAhead(ctx, resolution.RepoPath, baseRef, s.commands)
+	if err != nil {
+		return fail(err)
+	}
+	if ahead == 0 {
+		entry.Status = LinkedPullRequestStatusSkipped
+		entry.Reason = "no commits ahead of " + baseRef
+		return entry
+	}
+
+	title, body := strings.TrimSpace(input.Title), input.Body
+	if title == "" {
+		generated, err := s.GeneratePullRequestText(ctx, PullRequestGenerateInput{
+			Workspace: input.Workspace,
+			Repo:      repoName,
+			Base:      base,
+		})
+		if err != nil {
+			return fail(err)
+		}
+		title = generated.Payload.Title
+		if strings.TrimSpace(body) == "" {
+			body = generated.Payload.Body
+		}
+	}
+	created, err := s.createPullRequest(ctx, PullRequestCreateInput{
+		Workspace: input.Workspace,
+		Repo:      repoName,
+		Base:      base,
+		Title:     title,
+		Body:      body,
+		Draft:     input.Draft,
+		AutoPush:  input.AutoPush,
+	}, false)
+	if err != nil {
+		return fail(err)
+	}
+	entry.Status = LinkedPullRequestStatusCreated
+	entry.PullRequest = &created.Payload
+	return entry
+}
+
+// joinLinkedPullRequests adds a newly created pull request to the thread's
+// linked group, if one exists, and refreshes every Related PRs section.
+func (s *Service) joinLinkedPullRequests(ctx context.Context, resolution repoResolution) {
+	state, err := s.workspaces.LoadState(ctx, resolution.WorkspaceRoot)
+	if err != nil || state.PullRequestGroup == nil {
+		return
+	}
+	if err := s.addToPullRequestGroup(ctx, resolution.WorkspaceRoot, resolution.Repo.Name); err != nil {
+		s.logLinkedWarnings([]string{err.Error()})
+		return
+	}
+	s.logLinkedWarnings(s.syncLinkedPullRequests(ctx, resolution.WorkspaceRoot))
+}
+
+// refreshLinkedPullRequestsIfChanged re-syncs the linked group when
+// reconciliation changed a member's tracked pull request.
+func (s *Service) refreshLinkedPullRequestsIfChanged(
+	ctx context.Context,
+	resolution repoResolution,
+	before workspace.State,
+) {
+	if before.PullRequestGroup == nil || !slices.Contains(before.PullRequestGroup.Repos, resolution.Repo.Name) {
+		return
+	}
+	after, err := s.workspaces.LoadState(ctx, resolution.WorkspaceRoot)
+	if err != nil {
+		return
+	}
+	prev, hadPrev := before.PullRequests[resolution.Repo.Name]
+	next, hasNext := after.PullRequests[resolution.Repo.Name]
+	if hadPrev == hasNext && prev.Number == next.Number && prev.State == next.State &&
+		prev.Merged == next.Merged && prev.Draft == next.Draft && prev.Title == next.Title {
+		return
+	}
+	s.logLinkedWarnings(s.syncLinkedPullRequests(ctx, resolution.WorkspaceRoot))
+}
+
+func (s *Service) addToPullRequestGroup(ctx context.Context, wsRoot string, repos ...string) error {
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil {
+		return err
+	}
+	if state.PullRequestGroup == nil {
+		state.PullRequestGroup = &workspace.PullRequestGroup{}
+	}
+	for _, repo := range repos {
+		if !slices.Contains(state.PullRequestGroup.Repos, repo) {
+			state.PullRequestGroup.Repos = append(state.PullRequestGroup.Repos, repo)
+		}
+	}
+	state.PullRequestGroup.UpdatedAt = s.clock().Format(time.RFC3339)
+	return s.workspaces.SaveState(ctx, wsRoot, state)
+}
+
+// syncLinkedPullRequests rewrites the Related PRs section of every open pull
+// request in the thread's linked group. It returns warnings for bodies that
+// could not be read or updated; failures never abort the sync. Each pull
+// request is updated through the client for the host in its URL, so groups
+// can span GitHub and GitLab.
+func (s *Service) syncLinkedPullRequests(ctx context.Context, wsRoot string) []string {
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil {
+		return []string{fmt.Sprintf("unable to load thread state for linked PRs: %v", err)}
+	}
+	if state.PullRequestGroup == nil {
+		return nil
+	}
+	members := []workspace.PullRequestState{}
+	names := []string{}
+	for _, repo := range state.PullRequestGroup.Repos {
+		if pr, ok := state.PullRequests[repo]; ok {
+			members = append(members, pr)
+			names = append(names, repo)
+		}
+	}
+
+	warnings := []string{}
+	changed := false
+	clients := map[string]GitHubClient{}
+	for i, pr := range members {
+		if !strings.EqualFold(pr.State, "open") || pr.Merged {
+			continue
+		}
+		slash := strings.LastIndex(pr.BaseRepo, "/")
+		if slash <= 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown base repo %q", names[i], pr.BaseRepo))
+			continue
+		}
+		owner, repo := pr.BaseRepo[:slash], pr.BaseRepo[slash+1:]
+		host := pullRequestHost(pr.URL)
+		client, ok := clients[host]
+		if !ok {
+			client, err = s.githubClient(ctx, host)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", names[i], err.Error()))
+				continue
+			}
+			clients[host] = client
+		}
+		current, err := client.GetPullRequest(ctx, owner, repo, pr.Number)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: read PR #%d: %s", names[i], pr.Number, formatGitHubAPIError(err)))
+			continue
+		}
+		body := withRelatedPullRequests(current.Body, relatedPullRequestsSection(members, i))
+		if body == current.Body {
+			continue
+		}
+		updated, err := client.UpdatePullRequestBody(ctx, owner, repo, pr.Number, body)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: update PR #%d: %s", names[i], pr.Number, formatGitHubAPIError(err)))
+			continue
+		}
+		pr.Body = updated.Body
+		state.PullRequests[names[i]] = pr
+		changed = true
+	}
+	if changed {
+		state.PullRequestGroup.UpdatedAt = s.clock().Format(time.RFC3339)
+		if err := s.workspaces.SaveState(ctx, wsRoot, state); err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to save thread state for linked PRs: %v", err))
+		}
+	}
+	return warnings
+}
+
+// pullRequestHost returns the lowercased host of a pull request URL,
+// defaulting to github.com.
+func pullRequestHost(raw string) string {
+	if parsed, err := url.Parse(raw); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	return defaultGitHubHost
+}
+
+func (s *Service) logLinkedWarnings(warnings []string) {
+	if s.logf == nil {
+		return
+	}
+	for _, warning := range warnings {
+		s.logf("warning: %s", warning)
+	}
+}
+
+// relatedPullRequestsSection renders the marker-delimited block listing every
+// group member except the one at index self. It is empty when there are no
+// other members.
+func relatedPullRequestsSection(members []workspace.PullRequestState, self int) string {
+	lines := []string{}
+	for i, pr := range members {
+		if i == self {
+			continue
+		}
+		line := fmt.Sprintf("- [%s#%d](%s): %s", pr.BaseRepo, pr.Number, pr.URL, pr.Title)
+		switch {
+		case pr.Merged:
+			line += " (merged)"
+		case !strings.EqualFold(pr.State, "open"):
+			line += " (closed)"
+		case pr.Draft:
+			line += " (draft)"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return relatedPullRequestsStart + "\n### Related PRs\n\n" + strings.Join(lines, "\n") + "\n" + relatedPullRequestsEnd
+}
+
+// withRelatedPullRequests replaces any existing Related PRs block in body with
+// section, or removes it when section is empty.
+func withRelatedPullRequests(body, section string) string {
+	stripped := body
+	if start := strings.Index(body, relatedPullRequestsStart); start >= 0 {
+		if end := strings.Index(body[start:], relatedPullRequestsEnd); end >= 0 {
+			stripped = body[:start] + body[start+end+len(relatedPullRequestsEnd):]
+		}
+	}
+	stripped = strings.TrimSpace(stripped)
+	switch {
+	case section == "":
+		return stripped
+	case stripped == "":
+		return section
+	default:
+		return stripped + "\n\n" + section
+	}
+}
+
+func trackedPullRequestPayload(pr workspace.PullRequestState) PullRequestCreatedJSON {
+	return PullRequestCreatedJSON{
+		Repo:                pr.Repo,
+		Number:              pr.Number,
+		URL:                 pr.URL,
+		Title:               pr.Title,
+		Body:                pr.Body,
+		Draft:               pr.Draft,
+		State:               pr.State,
+		Merged:              pr.Merged,
+		BaseRepo:            pr.BaseRepo,
+		BaseBranch:          pr.BaseBranch,
+		HeadRepo:            pr.HeadRepo,
+		HeadBranch:          pr.HeadBranch,
+		Author:              pr.Author,
+		CommentsCount:       pr.CommentsCount,
+		ReviewCommentsCount: pr.ReviewCommentsCount,
+	}
+}
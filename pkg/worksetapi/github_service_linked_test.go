@@ -0,0 +1,190 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type linkedPullRequestFixture struct {
+	env    *testEnv
+	root   string
+	client *readHelpersGitHubClient
+	bodies map[int]string
+}
+
+func setupLinkedPullRequests(t *testing.T, ahead map[string]string) linkedPullRequestFixture {
+	t.Helper()
+	ctx := context.Background()
+	env, root, repoAPath := setupGitHubServiceRepo(t)
+	paths := map[string]string{"repo-a": repoAPath}
+	for _, name := range []string{"repo-b", "repo-c"} {
+		result, err := env.svc.AddRepo(ctx, RepoAddInput{
+			Workspace:  WorkspaceSelector{Value: root},
+			Name:       name,
+			NameSet:    true,
+			SourcePath: env.createLocalRepo(name),
+		})
+		if err != nil {
+			t.Fatalf("AddRepo %s: %v", name, err)
+		}
+		paths[name] = result.WorktreePath
+	}
+	for name, path := range paths {
+		env.git.remoteURLs[path] = map[string][]string{"origin": {"git@github.com:acme/" + name + ".git"}}
+		env.git.remoteExists[path] = map[string]bool{"upstream": false}
+		env.git.currentBranch[path] = "feature"
+		env.git.currentOK[path] = true
+	}
+	env.svc.commands = func(_ context.Context, dir string, command []string, _ []string, _ string) (CommandResult, error) {
+		switch {
+		case len(command) > 2 && command[1] == "rev-list":
+			return CommandResult{Stdout: ahead[filepath.Base(dir)] + "\n"}, nil
+		case len(command) > 2 && command[1] == "ls-remote":
+			return CommandResult{Stdout: "abc123\trefs/heads/feature\n"}, nil
+		}
+		return CommandResult{}, nil
+	}
+
+	fixture := linkedPullRequestFixture{env: env, root: root, bodies: map[int]string{}}
+	numbers := map[string]int{"repo-a": 11, "repo-b": 12, "repo-c": 13}
+	fixture.client = &readHelpersGitHubClient{
+		getRepoDefaultBranchFunc: func(context.Context, string, string) (string, error) {
+			return "main", nil
+		},
+		createPullRequestFunc: func(_ context.Context, _ string, repo string, pr GitHubNewPullRequest) (GitHubPullRequest, error) {
+			number := numbers[repo]
+			fixture.bodies[number] = pr.Body
+			return GitHubPullRequest{
+				Number: number,
+				URL:    fmt.Sprintf("https://github.com/acme/%s/pull/%d", repo, number),
+				Title:  pr.Title,
+				Body:   pr.Body,
+				State:  "open",
+			}, nil
+		},
+		getPullRequestFunc: func(_ context.Context, _ string, _ string, number int) (GitHubPullRequest, error) {
+			return GitHubPullRequest{Number: number, Body: fixture.bodies[number], State: "open"}, nil
+		},
+	}
+	env.svc.github = &readHelpersGitHubProvider{client: fixture.client}
+	return fixture
+}
+
+func TestCreateLinkedPullRequestsCrossLinksBodies(t *testing.T) {
+	fixture := setupLinkedPullRequests(t, map[string]string{"repo-a": "2", "repo-b": "1", "repo-c": "0"})
+	ctx := context.Background()
+
+	result, err := fixture.env.svc.CreateLinkedPullRequests(ctx, LinkedPullRequestsInput{
+		Workspace: WorkspaceSelector{Value: fixture.root},
+		Title:     "Ship login",
+		Body:      "Adds login.",
+	})
+	if err != nil {
+		t.Fatalf("CreateLinkedPullRequests: %v", err)
+	}
+	statuses := map[string]LinkedPullRequestStatus{}
+	for _, entry := range result.PullRequests {
+		statuses[entry.Repo] = entry.Status
+	}
+	if statuses["repo-a"] != LinkedPullRequestStatusCreated || statuses["repo-b"] != LinkedPullRequestStatusCreated {
+		t.Fatalf("expected repo-a and repo-b created, got %+v", result.PullRequests)
+	}
+	if statuses["repo-c"] != LinkedPullRequestStatusSkipped {
+		t.Fatalf("expected repo-c skipped, got %+v", result.PullRequests)
+	}
+	if len(result.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", result.Warnings)
+	}
+
+	state, err := fixture.env.svc.workspaces.LoadState(ctx, fixture.root)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if state.PullRequestGroup == nil || strings.Join(state.PullRequestGroup.Repos, ",") != "repo-a,repo-b" {
+		t.Fatalf("unexpected group: %+v", state.PullRequestGroup)
+	}
+
+	calls := fixture.client.updateBodyCalls
+	if len(calls) != 2 {
+		t.Fatalf("expected two body updates, got %+v", calls)
+	}
+	for _, call := range calls {
+		other := "acme/repo-b#12"
+		if call.number == 12 {
+			other = "acme/repo-a#11"
+		}
+		if !strings.HasPrefix(call.body, "Adds login.\n\n"+relatedPullRequestsStart) ||
+			!strings.Contains(call.body, "- ["+other+"]") {
+			t.Fatalf("unexpected body for #%d:\n%s", call.number, call.body)
+		}
+		if strings.Contains(call.body, fmt.Sprintf("#%d]", call.number)) {
+			t.Fatalf("expected PR #%d not to link itself:\n%s", call.number, call.body)
+		}
+		if state.PullRequests[call.repo].Body != call.body {
+			t.Fatalf("expected tracked body for %s to be updated", call.repo)
+		}
+	}
+}
+
+func TestReconcileLinkedPullRequestRefreshesGroupOnMerge(t *testing.T) {
+	fixture := setupLinkedPullRequests(t, map[string]string{"repo-a": "1", "repo-b": "1", "repo-c": "0"})
+	ctx := context.Background()
+	if _, err := fixture.env.svc.CreateLinkedPullRequests(ctx, LinkedPullRequestsInput{
+		Workspace: WorkspaceSelector{Value: fixture.root},
+		Title:     "Ship login",
+	}); err != nil {
+		t.Fatalf("CreateLinkedPullRequests: %v", err)
+	}
+	for _, call := range fixture.client.updateBodyCalls {
+		fixture.bodies[call.number] = call.body
+	}
+	fixture.client.updateBodyCalls = nil
+
+	fixture.client.getPullRequestFunc = func(_ context.Context, _ string, _ string, number int) (GitHubPullRequest, error) {
+		if number == 12 {
+			return GitHubPullRequest{
+				Number:  12,
+				URL:     "https://github.com/acme/repo-b/pull/12",
+				Title:   "Ship login",
+				Body:    fixture.bodies[12],
+				State:   "closed",
+				Merged:  true,
+				BaseRef: "main",
+				HeadRef: "feature",
+			}, nil
+		}
+		return GitHubPullRequest{Number: number, Body: fixture.bodies[number], State: "open"}, nil
+	}
+	if _, err := fixture.env.svc.GetPullRequestStatus(ctx, PullRequestStatusInput{
+		Workspace: WorkspaceSelector{Value: fixture.root},
+		Repo:      "repo-b",
+		Number:    12,
+	}); err != nil {
+		t.Fatalf("GetPullRequestStatus: %v", err)
+	}
+
+	calls := fixture.client.updateBodyCalls
+	if len(calls) != 1 || calls[0].number != 11 {
+		t.Fatalf("expected only repo-a's body to be refreshed, got %+v", calls)
+	}
+	if !strings.Contains(calls[0].body, "- [acme/repo-b#12](https://github.com/acme/repo-b/pull/12): Ship login (merged)") {
+		t.Fatalf("expected merged marker in body:\n%s", calls[0].body)
+	}
+}
+
+func TestWithRelatedPullRequestsReplacesSection(t *testing.T) {
+	section := relatedPullRequestsStart + "\n### Related PRs\n\n- new\n" + relatedPullRequestsEnd
+	body := "Intro\n\n" + relatedPullRequestsStart + "\n- old\n" + relatedPullRequestsEnd + "\n"
+	if got := withRelatedPullRequests(body, section); got != "Intro\n\n"+section {
+		t.Fatalf("unexpected body:\n%s", got)
+	}
+	if got := withRelatedPullRequests(body, ""); got != "Intro" {
+		t.Fatalf("expected section removed, got %q", got)
+	}
+	if got := withRelatedPullRequests("", section); got != section {
+		t.Fatalf("expected section only, got %q", got)
+	}
+}
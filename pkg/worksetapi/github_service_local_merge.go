@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/hooks"
 )
 
 type localMergeContext struct {
@@ -55,6 +56,10 @@ func (s *Service) LocalMerge(ctx context.Context, input LocalMergeInput) (LocalM
 	if err != nil {
 		return LocalMergeResult{}, err
 	}
+	s.logHookWarnings(s.runRepoLifecycleHooks(ctx, mergeCtx.resolution, hooks.EventLocalMergeCompleted, "local_merge", hooks.Context{
+		BaseBranch: mergeCtx.baseBranch,
+		CommitSHA:  finalSHA,
+	}))
 	return buildLocalMergeResult(mergeCtx, baseMessage, finalSHA), nil
 }
 
@@ -30,8 +30,8 @@ func (s *Service) resolveRemoteInfo(ctx context.Context, resolution repoResoluti
 	if headInfo.Host != baseInfo.Host {
 		return remoteInfo{}, remoteInfo{}, ValidationError{Message: "head and base remotes must share the same GitHub host"}
 	}
-	if headInfo.Host != defaultGitHubHost {
-		return remoteInfo{}, remoteInfo{}, ValidationError{Message: fmt.Sprintf("unsupported GitHub host %q: only github.com is supported in this release", headInfo.Host)}
+	if err := s.validateRemoteHost(ctx, headInfo.Host); err != nil {
+		return remoteInfo{}, remoteInfo{}, err
 	}
 	return headInfo, baseInfo, nil
 }
@@ -70,7 +70,16 @@ func (s *Service) resolveCurrentBranch(resolution repoResolution) (string, error
 	return branch, nil
 }
 
+// githubClient returns the hosting client for host: GitLab when the host is
+// configured with provider gitlab, otherwise the GitHub provider's client.
 func (s *Service) githubClient(ctx context.Context, host string) (GitHubClient, error) {
+	hostCfg, err := s.hostConfig(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if hostCfg.Provider == hostProviderGitLab {
+		return newGitLabHostClient(strings.ToLower(host), hostCfg)
+	}
 	if s.github == nil {
 		return nil, AuthRequiredError{Message: "GitHub authentication required"}
 	}
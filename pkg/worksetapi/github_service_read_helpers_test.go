@@ -32,6 +32,13 @@ type readHelpersGetFileContentCall struct {
 	ref   string
 }
 
+type readHelpersUpdateBodyCall struct {
+	owner  string
+	repo   string
+	number int
+	body   string
+}
+
 type readHelpersPRCall struct {
 	owner   string
 	repo    string
@@ -59,6 +66,7 @@ type readHelpersGitHubClient struct {
 	getRepoDefaultBranchCalls []readHelpersGetRepoDefaultBranchCall
 	getCheckAnnotationsCalls  []readHelpersGetCheckRunAnnotationsCall
 	getFileContentCalls       []readHelpersGetFileContentCall
+	updateBodyCalls           []readHelpersUpdateBodyCall
 	listPullRequestsCalls     []readHelpersPRCall
 	listCheckRunsCalls        []readHelpersCheckRunCall
 	searchRepositoriesCalls   []readHelpersRepoSearchCall
@@ -67,6 +75,7 @@ type readHelpersGitHubClient struct {
 
 	getPullRequestFunc         func(ctx context.Context, owner, repo string, number int) (GitHubPullRequest, error)
 	getRepoDefaultBranchFunc   func(ctx context.Context, owner, repo string) (string, error)
+	createPullRequestFunc      func(ctx context.Context, owner, repo string, pr GitHubNewPullRequest) (GitHubPullRequest, error)
 	getCheckRunAnnotationsFunc func(ctx context.Context, owner, repo string, checkRunID int64) ([]CheckAnnotationJSON, error)
 	getFileContentFunc         func(ctx context.Context, owner, repo, path, ref string) ([]byte, bool, error)
 	listPullRequestsFunc       func(ctx context.Context, owner, repo, head, state string, page, perPage int) ([]GitHubPullRequest, int, error)
@@ -76,8 +85,21 @@ type readHelpersGitHubClient struct {
 	listCurrentUserOrgsFunc    func(ctx context.Context) ([]string, error)
 }
 
-func (c *readHelpersGitHubClient) CreatePullRequest(_ context.Context, _ string, _ string, _ GitHubNewPullRequest) (GitHubPullRequest, error) {
-	return GitHubPullRequest{}, nil
+func (c *readHelpersGitHubClient) CreatePullRequest(ctx context.Context, owner, repo string, pr GitHubNewPullRequest) (GitHubPullRequest, error) {
+	if c.createPullRequestFunc == nil {
+		return GitHubPullRequest{}, nil
+	}
+	return c.createPullRequestFunc(ctx, owner, repo, pr)
+}
+
+func (c *readHelpersGitHubClient) UpdatePullRequestBody(_ context.Context, owner, repo string, number int, body string) (GitHubPullRequest, error) {
+	c.updateBodyCalls = append(c.updateBodyCalls, readHelpersUpdateBodyCall{
+		owner:  owner,
+		repo:   repo,
+		number: number,
+		body:   body,
+	})
+	return GitHubPullRequest{Number: number, Body: body, State: "open"}, nil
 }
 
 func (c *readHelpersGitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (GitHubPullRequest, error) {
@@ -60,6 +60,9 @@ func (s *Service) reconcileTrackedPullRequest(
 	baseInfo remoteInfo,
 	headInfo remoteInfo,
 ) {
+	if before, err := s.workspaces.LoadState(ctx, resolution.WorkspaceRoot); err == nil {
+		defer s.refreshLinkedPullRequestsIfChanged(ctx, resolution, before)
+	}
 	totalComments := pr.CommentsCount + pr.ReviewCommentsCount
 	if strings.EqualFold(pr.State, "open") {
 		s.recordPullRequest(ctx, resolution, PullRequestCreatedJSON{
@@ -167,24 +170,8 @@ func (s *Service) GetTrackedPullRequest(ctx context.Context, input PullRequestTr
 	}
 	return PullRequestTrackedResult{
 		Payload: PullRequestTrackedJSON{
-			Found: true,
-			PullRequest: PullRequestCreatedJSON{
-				Repo:                pr.Repo,
-				Number:              pr.Number,
-				URL:                 pr.URL,
-				Title:               pr.Title,
-				Body:                pr.Body,
-				Draft:               pr.Draft,
-				State:               pr.State,
-				Merged:              pr.Merged,
-				BaseRepo:            pr.BaseRepo,
-				BaseBranch:          pr.BaseBranch,
-				HeadRepo:            pr.HeadRepo,
-				HeadBranch:          pr.HeadBranch,
-				Author:              pr.Author,
-				CommentsCount:       pr.CommentsCount,
-				ReviewCommentsCount: pr.ReviewCommentsCount,
-			},
+			Found:       true,
+			PullRequest: trackedPullRequestPayload(pr),
 		},
 		Config: resolution.ConfigInfo,
 	}, nil
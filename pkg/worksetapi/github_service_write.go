@@ -4,10 +4,18 @@ import (
 	"context"
 	"fmt"
 	"strings"
+
+	"github.com/strantalis/workset/internal/hooks"
 )
 
 // CreatePullRequest opens a pull request against the resolved upstream repo.
+// When the thread already has a linked pull request group, the new PR joins
+// it and every Related PRs section is refreshed.
 func (s *Service) CreatePullRequest(ctx context.Context, input PullRequestCreateInput) (PullRequestCreateResult, error) {
+	return s.createPullRequest(ctx, input, true)
+}
+
+func (s *Service) createPullRequest(ctx context.Context, input PullRequestCreateInput, joinGroup bool) (PullRequestCreateResult, error) {
 	resolution, err := s.resolveRepo(ctx, RepoSelectionInput{
 		Workspace: input.Workspace,
 		Repo:      input.Repo,
@@ -98,6 +106,14 @@ func (s *Service) CreatePullRequest(ctx context.Context, input PullRequestCreate
 		ReviewCommentsCount: pr.ReviewCommentsCount,
 	}
 	s.recordPullRequest(ctx, resolution, payload)
+	s.logHookWarnings(s.runRepoLifecycleHooks(ctx, resolution, hooks.EventPullRequestCreated, "pr.create", hooks.Context{
+		BaseBranch:     baseBranch,
+		PullRequestURL: pr.URL,
+		PullRequestNum: pr.Number,
+	}))
+	if joinGroup {
+		s.joinLinkedPullRequests(ctx, resolution)
+	}
 	return PullRequestCreateResult{Payload: payload, Config: resolution.ConfigInfo}, nil
 }
 
@@ -339,47 +355,16 @@ func (s *Service) CommitAndPush(ctx context.Context, input CommitAndPushInput) (
 	message := strings.TrimSpace(input.Message)
 	if message == "" {
 		emitStage(CommitAndPushStageGeneratingMessage)
-		agent := strings.TrimSpace(resolution.Defaults.Agent)
-		if agent == "" {
-			return CommitAndPushResult{}, ValidationError{Message: "defaults.agent is not configured; cannot auto-generate commit message"}
-		}
-		patch, err := buildRepoPatch(ctx, resolution.RepoPath, defaultDiffLimit, s.commands)
-		if err != nil {
-			return CommitAndPushResult{}, err
-		}
-		prompt := formatCommitPrompt(resolution.Repo.Name, branch, patch)
-		message, err = s.runCommitMessageWithModel(ctx, resolution.RepoPath, agent, prompt, resolution.Defaults.AgentModel)
+		message, err = s.generateCommitMessage(ctx, resolution, resolution.RepoPath, branch)
 		if err != nil {
 			return CommitAndPushResult{}, err
 		}
 	}
 
-	// Stage all changes
-	emitStage(CommitAndPushStageStaging)
-	if err := gitAddAll(ctx, resolution.RepoPath, s.commands); err != nil {
-		return CommitAndPushResult{}, err
-	}
-
-	// Verify staged changes exist
-	hasStaged, err := gitHasStagedChanges(ctx, resolution.RepoPath, s.commands)
+	sha, err := s.stageAndCommit(ctx, resolution, message, emitStage)
 	if err != nil {
 		return CommitAndPushResult{}, err
 	}
-	if !hasStaged {
-		return CommitAndPushResult{}, ValidationError{Message: "no changes staged after git add"}
-	}
-
-	// Commit
-	emitStage(CommitAndPushStageCommitting)
-	if err := gitCommitMessage(ctx, resolution.RepoPath, message, s.commands); err != nil {
-		return CommitAndPushResult{}, err
-	}
-
-	// Get the new commit SHA
-	sha, err := gitHeadSHA(ctx, resolution.RepoPath, s.commands)
-	if err != nil {
-		sha = ""
-	}
 
 	// Resolve remote for push
 	headInfo, _, err := s.resolveRemoteInfo(ctx, resolution, "")
@@ -408,6 +393,9 @@ func (s *Service) CommitAndPush(ctx context.Context, input CommitAndPushInput) (
 			Config: resolution.ConfigInfo,
 		}, err
 	}
+	s.logHookWarnings(s.runRepoLifecycleHooks(ctx, resolution, hooks.EventCommitPushed, "commit.push", hooks.Context{
+		CommitSHA: sha,
+	}))
 
 	return CommitAndPushResult{
 		Payload: CommitAndPushResultJSON{
@@ -419,3 +407,40 @@ func (s *Service) CommitAndPush(ctx context.Context, input CommitAndPushInput) (
 		Config: resolution.ConfigInfo,
 	}, nil
 }
+
+// stageAndCommit stages every change in the repo, commits it with message, and
+// returns the new HEAD SHA (empty when it cannot be read).
+func (s *Service) stageAndCommit(
+	ctx context.Context,
+	resolution repoResolution,
+	message string,
+	emitStage func(CommitAndPushStage),
+) (string, error) {
+	// Stage all changes
+	emitStage(CommitAndPushStageStaging)
+	if err := gitAddAll(ctx, resolution.RepoPath, s.commands); err != nil {
+		return "", err
+	}
+
+	// Verify staged changes exist
+	hasStaged, err := gitHasStagedChanges(ctx, resolution.RepoPath, s.commands)
+	if err != nil {
+		return "", err
+	}
+	if !hasStaged {
+		return "", ValidationError{Message: "no changes staged after git add"}
+	}
+
+	// Commit
+	emitStage(CommitAndPushStageCommitting)
+	if err := gitCommitMessage(ctx, resolution.RepoPath, message, s.commands); err != nil {
+		return "", err
+	}
+
+	// Get the new commit SHA
+	sha, err := gitHeadSHA(ctx, resolution.RepoPath, s.commands)
+	if err != nil {
+		sha = ""
+	}
+	return sha, nil
+}
@@ -22,6 +22,44 @@ type PullRequestCreateInput struct {
 	AutoPush   bool
 }
 
+// LinkedPullRequestsInput describes inputs for CreateLinkedPullRequests.
+// An empty Title generates a title and body per repo with defaults.agent;
+// an empty Base uses each repo's remote default branch.
+type LinkedPullRequestsInput struct {
+	Workspace WorkspaceSelector
+	Base      string
+	Title     string
+	Body      string
+	Draft     bool
+	AutoPush  bool
+}
+
+// LinkedPullRequestStatus describes the outcome for one repo in a linked PR run.
+type LinkedPullRequestStatus string
+
+const (
+	LinkedPullRequestStatusCreated  LinkedPullRequestStatus = "created"
+	LinkedPullRequestStatusExisting LinkedPullRequestStatus = "existing"
+	LinkedPullRequestStatusSkipped  LinkedPullRequestStatus = "skipped"
+	LinkedPullRequestStatusFailed   LinkedPullRequestStatus = "failed"
+)
+
+// LinkedPullRequestJSON reports a single repo's pull request in a linked group.
+type LinkedPullRequestJSON struct {
+	Repo        string                  `json:"repo"`
+	Status      LinkedPullRequestStatus `json:"status"`
+	PullRequest *PullRequestCreatedJSON `json:"pull_request,omitempty"`
+	Reason      string                  `json:"reason,omitempty"`
+}
+
+// LinkedPullRequestsResult wraps per-repo outcomes with config metadata.
+// Warnings report Related PRs sections that could not be updated.
+type LinkedPullRequestsResult struct {
+	PullRequests []LinkedPullRequestJSON
+	Warnings     []string
+	Config       config.GlobalConfigLoadInfo
+}
+
 // ListRemotesInput describes inputs for listing repo remotes.
 type ListRemotesInput struct {
 	Workspace WorkspaceSelector
@@ -229,7 +267,7 @@ type DeleteReviewCommentResult struct {
 type ResolveReviewThreadInput struct {
 	Workspace WorkspaceSelector
 	Repo      string
-	ThreadID  string // GraphQL node ID
+	ThreadID  string // GraphQL node ID, or <namespace>/<project>!<iid>#<discussion> on GitLab
 	Resolve   bool   // true = resolve, false = unresolve
 }
 
@@ -367,6 +405,43 @@ type CommitAndPushResult struct {
 	Config  config.GlobalConfigLoadInfo
 }
 
+// CommitAndPushThreadInput describes inputs for committing and pushing every
+// dirty repo in a thread. An empty Message generates one per repo with
+// defaults.agent. OnStage reports progress per repo.
+type CommitAndPushThreadInput struct {
+	Workspace WorkspaceSelector
+	Message   string
+	OnStage   func(repo string, stage CommitAndPushStage)
+}
+
+// CommitAndPushRepoStatus describes the outcome for one repo in a thread-wide
+// commit and push.
+type CommitAndPushRepoStatus string
+
+const (
+	CommitAndPushRepoStatusPushed  CommitAndPushRepoStatus = "pushed"
+	CommitAndPushRepoStatusClean   CommitAndPushRepoStatus = "clean"
+	CommitAndPushRepoStatusFailed  CommitAndPushRepoStatus = "failed"
+	CommitAndPushRepoStatusSkipped CommitAndPushRepoStatus = "skipped"
+)
+
+// CommitAndPushRepoJSON reports a single repo's commit and push outcome.
+type CommitAndPushRepoJSON struct {
+	Repo      string                  `json:"repo"`
+	Status    CommitAndPushRepoStatus `json:"status"`
+	Committed bool                    `json:"committed"`
+	Pushed    bool                    `json:"pushed"`
+	Message   string                  `json:"message,omitempty"`
+	SHA       string                  `json:"sha,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+// CommitAndPushThreadResult wraps per-repo outcomes with config metadata.
+type CommitAndPushThreadResult struct {
+	Repos  []CommitAndPushRepoJSON
+	Config config.GlobalConfigLoadInfo
+}
+
 // LocalMergeInput describes inputs for merging a workspace branch into the base branch locally.
 type LocalMergeInput struct {
 	Workspace WorkspaceSelector
@@ -0,0 +1,617 @@
+package worksetapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const gitlabDraftPrefix = "Draft: "
+
+// gitlabClient implements GitHubClient against the GitLab REST API (v4).
+// Merge requests stand in for pull requests, the latest pipeline's jobs for
+// check runs, and merge request discussions for review threads. Owner is the
+// full namespace path, so nested groups work.
+type gitlabClient struct {
+	host   string
+	apiURL string
+	token  string
+	http   *http.Client
+}
+
+func newGitLabClient(host, apiURL, token string, httpClient *http.Client) *gitlabClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &gitlabClient{
+		host:   host,
+		apiURL: strings.TrimRight(apiURL, "/"),
+		token:  token,
+		http:   httpClient,
+	}
+}
+
+// gitlabAPIError reports a non-2xx GitLab API response.
+type gitlabAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e gitlabAPIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("GitLab API error (%d)", e.StatusCode)
+	}
+	return fmt.Sprintf("GitLab API error (%d): %s", e.StatusCode, e.Message)
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+}
+
+type gitlabMergeRequest struct {
+	IID                 int        `json:"iid"`
+	WebURL              string     `json:"web_url"`
+	Title               string     `json:"title"`
+	Description         string     `json:"description"`
+	Draft               bool       `json:"draft"`
+	State               string     `json:"state"`
+	SourceBranch        string     `json:"source_branch"`
+	TargetBranch        string     `json:"target_branch"`
+	SHA                 string     `json:"sha"`
+	HasConflicts        bool       `json:"has_conflicts"`
+	DetailedMergeStatus string     `json:"detailed_merge_status"`
+	UserNotesCount      int        `json:"user_notes_count"`
+	Author              gitlabUser `json:"author"`
+}
+
+type gitlabDiscussion struct {
+	ID    string       `json:"id"`
+	Notes []gitlabNote `json:"notes"`
+}
+
+type gitlabNote struct {
+	ID         int64           `json:"id"`
+	Body       string          `json:"body"`
+	Author     gitlabUser      `json:"author"`
+	CreatedAt  string          `json:"created_at"`
+	UpdatedAt  string          `json:"updated_at"`
+	System     bool            `json:"system"`
+	Resolvable bool            `json:"resolvable"`
+	Resolved   bool            `json:"resolved"`
+	Position   *gitlabPosition `json:"position"`
+}
+
+type gitlabPosition struct {
+	NewPath string `json:"new_path"`
+	OldPath string `json:"old_path"`
+	NewLine int    `json:"new_line"`
+	OldLine int    `json:"old_line"`
+	HeadSHA string `json:"head_sha"`
+}
+
+type gitlabJob struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	WebURL       string `json:"web_url"`
+	StartedAt    string `json:"started_at"`
+	FinishedAt   string `json:"finished_at"`
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+type gitlabProject struct {
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+	Visibility        string `json:"visibility"`
+	Archived          bool   `json:"archived"`
+	Namespace         struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+}
+
+func (c *gitlabClient) CreatePullRequest(ctx context.Context, owner, repo string, pr GitHubNewPullRequest) (GitHubPullRequest, error) {
+	source := pr.Head
+	if headOwner, branch, ok := strings.Cut(pr.Head, ":"); ok {
+		if headOwner != owner {
+			return GitHubPullRequest{}, ValidationError{Message: "GitLab merge requests from forks are not supported"}
+		}
+		source = branch
+	}
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, gitlabDraftPrefix) {
+		title = gitlabDraftPrefix + title
+	}
+	var mr gitlabMergeRequest
+	payload := map[string]any{
+		"source_branch": source,
+		"target_branch": pr.Base,
+		"title":         title,
+		"description":   pr.Body,
+	}
+	if _, err := c.do(ctx, http.MethodPost, gitlabProjectPath(owner, repo, "merge_requests"), nil, payload, &mr); err != nil {
+		return GitHubPullRequest{}, err
+	}
+	return mapGitLabMergeRequest(mr), nil
+}
+
+func (c *gitlabClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (GitHubPullRequest, error) {
+	var mr gitlabMergeRequest
+	path := gitlabProjectPath(owner, repo, "merge_requests/"+strconv.Itoa(number))
+	if _, err := c.do(ctx, http.MethodGet, path, nil, nil, &mr); err != nil {
+		return GitHubPullRequest{}, err
+	}
+	return mapGitLabMergeRequest(mr), nil
+}
+
+func (c *gitlabClient) UpdatePullRequestBody(ctx context.Context, owner, repo string, number int, body string) (GitHubPullRequest, error) {
+	var mr gitlabMergeRequest
+	path := gitlabProjectPath(owner, repo, "merge_requests/"+strconv.Itoa(number))
+	if _, err := c.do(ctx, http.MethodPut, path, nil, map[string]any{"description": body}, &mr); err != nil {
+		return GitHubPullRequest{}, err
+	}
+	return mapGitLabMergeRequest(mr), nil
+}
+
+func (c *gitlabClient) ListPullRequests(ctx context.Context, owner, repo, head, state string, page, perPage int) ([]GitHubPullRequest, int, error) {
+	query := gitlabPageQuery(page, perPage)
+	if _, branch, ok := strings.Cut(head, ":"); ok {
+		head = branch
+	}
+	if head != "" {
+		query.Set("source_branch", head)
+	}
+	switch state {
+	case "", "open":
+		query.Set("state", "opened")
+	case "all":
+		query.Set("state", "all")
+	default:
+		query.Set("state", state)
+	}
+	var items []gitlabMergeRequest
+	header, err := c.do(ctx, http.MethodGet, gitlabProjectPath(owner, repo, "merge_requests"), query, nil, &items)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]GitHubPullRequest, 0, len(items))
+	for _, mr := range items {
+		out = append(out, mapGitLabMergeRequest(mr))
+	}
+	return out, gitlabNextPage(header), nil
+}
+
+func (c *gitlabClient) SearchRepositories(ctx context.Context, query string, perPage int) ([]GitHubRepositorySearchResult, error) {
+	trimmed := strings.TrimSpace(query)
+	if trimmed == "" {
+		return []GitHubRepositorySearchResult{}, nil
+	}
+	params := gitlabPageQuery(1, perPage)
+	params.Set("search", trimmed)
+	params.Set("membership", "true")
+	params.Set("order_by", "last_activity_at")
+	var items []gitlabProject
+	if _, err := c.do(ctx, http.MethodGet, "projects", params, nil, &items); err != nil {
+		return nil, err
+	}
+	results := make([]GitHubRepositorySearchResult, 0, len(items))
+	for _, item := range items {
+		results = append(results, GitHubRepositorySearchResult{
+			Name:          item.Name,
+			FullName:      item.PathWithNamespace,
+			Owner:         item.Namespace.FullPath,
+			DefaultBranch: item.DefaultBranch,
+			CloneURL:      item.HTTPURLToRepo,
+			SSHURL:        item.SSHURLToRepo,
+			Private:       item.Visibility != "public",
+			Archived:      item.Archived,
+			Host:          c.host,
+		})
+	}
+	return results, nil
+}
+
+// ListReviewComments flattens merge request discussions into comments. Paging
+// is by discussion, and system notes (pushes, label changes) are dropped.
+func (c *gitlabClient) ListReviewComments(ctx context.Context, owner, repo string, number, page, perPage int) ([]PullRequestReviewCommentJSON, int, error) {
+	discussions, next, err := c.listDiscussions(ctx, owner, repo, number, page, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := []PullRequestReviewCommentJSON{}
+	for _, discussion := range discussions {
+		out = append(out, mapGitLabDiscussion(owner+"/"+repo, number, discussion)...)
+	}
+	return out, next, nil
+}
+
+// CreateReplyComment adds a note to the discussion containing commentID.
+func (c *gitlabClient) CreateReplyComment(ctx context.Context, owner, repo string, number int, commentID int64, body string) (PullRequestReviewCommentJSON, error) {
+	discussion, err := c.findDiscussion(ctx, owner, repo, number, commentID)
+	if err != nil {
+		return PullRequestReviewCommentJSON{}, err
+	}
+	var note gitlabNote
+	path := gitlabProjectPath(owner, repo, fmt.Sprintf("merge_requests/%d/discussions/%s/notes", number, discussion.ID))
+	if _, err := c.do(ctx, http.MethodPost, path, nil, map[string]any{"body": strings.TrimSpace(body)}, &note); err != nil {
+		return PullRequestReviewCommentJSON{}, err
+	}
+	discussion.Notes = append(discussion.Notes, note)
+	comments := mapGitLabDiscussion(owner+"/"+repo, number, discussion)
+	return comments[len(comments)-1], nil
+}
+
+func (c *gitlabClient) EditReviewComment(context.Context, string, string, int64, string) (PullRequestReviewCommentJSON, error) {
+	return PullRequestReviewCommentJSON{}, ValidationError{Message: "editing review comments is not supported for GitLab merge requests"}
+}
+
+func (c *gitlabClient) DeleteReviewComment(context.Context, string, string, int64) error {
+	return ValidationError{Message: "deleting review comments is not supported for GitLab merge requests"}
+}
+
+// ListCheckRuns reports the jobs of the most recent pipeline for ref.
+func (c *gitlabClient) ListCheckRuns(ctx context.Context, owner, repo, ref string, page, perPage int) ([]PullRequestCheckJSON, int, error) {
+	query := url.Values{}
+	query.Set("sha", ref)
+	query.Set("order_by", "id")
+	query.Set("sort", "desc")
+	query.Set("per_page", "1")
+	var pipelines []struct {
+		ID int64 `json:"id"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, gitlabProjectPath(owner, repo, "pipelines"), query, nil, &pipelines); err != nil {
+		return nil, 0, err
+	}
+	if len(pipelines) == 0 {
+		return []PullRequestCheckJSON{}, 0, nil
+	}
+	var jobs []gitlabJob
+	path := gitlabProjectPath(owner, repo, fmt.Sprintf("pipelines/%d/jobs", pipelines[0].ID))
+	header, err := c.do(ctx, http.MethodGet, path, gitlabPageQuery(page, perPage), nil, &jobs)
+	if err != nil {
+		return nil, 0, err
+	}
+	checks := make([]PullRequestCheckJSON, 0, len(jobs))
+	for _, job := range jobs {
+		checks = append(checks, mapGitLabJob(job))
+	}
+	return checks, gitlabNextPage(header), nil
+}
+
+func (c *gitlabClient) GetCheckRunAnnotations(context.Context, string, string, int64) ([]CheckAnnotationJSON, error) {
+	return nil, ValidationError{Message: "check annotations are not available for GitLab pipelines"}
+}
+
+func (c *gitlabClient) GetRepoDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	var project gitlabProject
+	if _, err := c.do(ctx, http.MethodGet, gitlabProjectPath(owner, repo, ""), nil, nil, &project); err != nil {
+		return "", err
+	}
+	return project.DefaultBranch, nil
+}
+
+func (c *gitlabClient) GetCurrentUser(ctx context.Context) (GitHubUserJSON, []string, error) {
+	var user gitlabUser
+	if _, err := c.do(ctx, http.MethodGet, "user", nil, nil, &user); err != nil {
+		return GitHubUserJSON{}, nil, err
+	}
+	return GitHubUserJSON{ID: user.ID, Login: user.Username, Name: user.Name, Email: user.Email}, nil, nil
+}
+
+func (c *gitlabClient) ListCurrentUserOrganizations(ctx context.Context) ([]string, error) {
+	query := gitlabPageQuery(1, 100)
+	query.Set("min_access_level", "10")
+	var groups []struct {
+		FullPath string `json:"full_path"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, "groups", query, nil, &groups); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, group.FullPath)
+	}
+	return out, nil
+}
+
+// ReviewThreadMap returns an empty map: ListReviewComments already sets the
+// thread ID and resolved state on every resolvable note.
+func (c *gitlabClient) ReviewThreadMap(context.Context, string, string, int) (map[string]threadInfo, error) {
+	return map[string]threadInfo{}, nil
+}
+
+func (c *gitlabClient) GetReviewThreadID(context.Context, string) (string, error) {
+	return "", ValidationError{Message: "GitLab discussions are resolved by thread ID, not comment node ID"}
+}
+
+// ResolveReviewThread resolves or reopens the discussion named by a thread ID
+// of the form <namespace>/<project>!<iid>#<discussion>.
+func (c *gitlabClient) ResolveReviewThread(ctx context.Context, threadID string, resolve bool) (bool, error) {
+	project, number, discussionID, err := parseGitLabThreadID(threadID)
+	if err != nil {
+		return false, err
+	}
+	query := url.Values{}
+	query.Set("resolved", strconv.FormatBool(resolve))
+	var discussion gitlabDiscussion
+	path := "projects/" + url.PathEscape(project) + fmt.Sprintf("/merge_requests/%d/discussions/%s", number, discussionID)
+	if _, err := c.do(ctx, http.MethodPut, path, query, nil, &discussion); err != nil {
+		return false, err
+	}
+	resolved := false
+	for _, note := range discussion.Notes {
+		if note.Resolvable {
+			resolved = note.Resolved
+		}
+	}
+	return resolved, nil
+}
+
+func (c *gitlabClient) GetFileContent(ctx context.Context, owner, repo, path, ref string) ([]byte, bool, error) {
+	query := url.Values{}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+	var data []byte
+	_, err := c.do(ctx, http.MethodGet, gitlabProjectPath(owner, repo, "repository/files/"+url.PathEscape(path)+"/raw"), query, nil, &data)
+	if err != nil {
+		var apiErr gitlabAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (c *gitlabClient) listDiscussions(ctx context.Context, owner, repo string, number, page, perPage int) ([]gitlabDiscussion, int, error) {
+	var discussions []gitlabDiscussion
+	path := gitlabProjectPath(owner, repo, fmt.Sprintf("merge_requests/%d/discussions", number))
+	header, err := c.do(ctx, http.MethodGet, path, gitlabPageQuery(page, perPage), nil, &discussions)
+	if err != nil {
+		return nil, 0, err
+	}
+	return discussions, gitlabNextPage(header), nil
+}
+
+func (c *gitlabClient) findDiscussion(ctx context.Context, owner, repo string, number int, noteID int64) (gitlabDiscussion, error) {
+	page := 1
+	for {
+		discussions, next, err := c.listDiscussions(ctx, owner, repo, number, page, 100)
+		if err != nil {
+			return gitlabDiscussion{}, err
+		}
+		for _, discussion := range discussions {
+			for _, note := range discussion.Notes {
+				if note.ID == noteID {
+					return discussion, nil
+				}
+			}
+		}
+		if next == 0 {
+			return gitlabDiscussion{}, NotFoundError{Message: fmt.Sprintf("comment %d not found on merge request !%d", noteID, number)}
+		}
+		page = next
+	}
+}
+
+// do sends a JSON request to path under the API URL and decodes the response
+// into out. A *[]byte out receives the raw body.
+func (c *gitlabClient) do(ctx context.Context, method, path string, query url.Values, body any, out any) (http.Header, error) {
+	endpoint := c.apiURL + "/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.Header, AuthRequiredError{Message: fmt.Sprintf("GitLab authentication required for %s", c.host)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return resp.Header, gitlabAPIError{StatusCode: resp.StatusCode, Message: gitlabErrorMessage(data)}
+	}
+	switch target := out.(type) {
+	case nil:
+		return resp.Header, nil
+	case *[]byte:
+		*target, err = io.ReadAll(resp.Body)
+		return resp.Header, err
+	default:
+		return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+	}
+}
+
+// gitlabErrorMessage extracts the message from a GitLab error body, which is
+// a string, a list, or a field-to-errors object.
+func gitlabErrorMessage(data []byte) string {
+	var payload struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return strings.TrimSpace(string(data))
+	}
+	switch message := payload.Message.(type) {
+	case string:
+		return message
+	case nil:
+		return payload.Error
+	default:
+		raw, _ := json.Marshal(message)
+		return string(raw)
+	}
+}
+
+func gitlabProjectPath(owner, repo, suffix string) string {
+	path := "projects/" + url.PathEscape(owner+"/"+repo)
+	if suffix != "" {
+		path += "/" + suffix
+	}
+	return path
+}
+
+func gitlabPageQuery(page, perPage int) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		query.Set("per_page", strconv.Itoa(perPage))
+	}
+	return query
+}
+
+func gitlabNextPage(header http.Header) int {
+	next, err := strconv.Atoi(strings.TrimSpace(header.Get("X-Next-Page")))
+	if err != nil {
+		return 0
+	}
+	return next
+}
+
+func mapGitLabMergeRequest(mr gitlabMergeRequest) GitHubPullRequest {
+	pr := GitHubPullRequest{
+		Number:        mr.IID,
+		URL:           mr.WebURL,
+		Title:         mr.Title,
+		Body:          mr.Description,
+		Draft:         mr.Draft,
+		State:         "closed",
+		BaseRef:       mr.TargetBranch,
+		HeadRef:       mr.SourceBranch,
+		HeadSHA:       mr.SHA,
+		Author:        mr.Author.Username,
+		CommentsCount: mr.UserNotesCount,
+	}
+	switch mr.State {
+	case "opened":
+		pr.State = "open"
+	case "merged":
+		pr.Merged = true
+	}
+	switch mr.DetailedMergeStatus {
+	case "", "checking", "unchecked", "preparing":
+	default:
+		mergeable := !mr.HasConflicts
+		pr.Mergeable = &mergeable
+	}
+	return pr
+}
+
+// mapGitLabDiscussion converts a discussion's user notes into comments that
+// reply to the discussion's first note.
+func mapGitLabDiscussion(project string, number int, discussion gitlabDiscussion) []PullRequestReviewCommentJSON {
+	out := []PullRequestReviewCommentJSON{}
+	var first int64
+	for _, note := range discussion.Notes {
+		if note.System {
+			continue
+		}
+		comment := PullRequestReviewCommentJSON{
+			ID:        note.ID,
+			Author:    note.Author.Username,
+			AuthorID:  note.Author.ID,
+			Body:      note.Body,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+			Resolved:  note.Resolved,
+		}
+		if note.Resolvable {
+			comment.ThreadID = fmt.Sprintf("%s!%d#%s", project, number, discussion.ID)
+		}
+		if position := note.Position; position != nil {
+			comment.Path, comment.Line, comment.Side = position.NewPath, position.NewLine, "RIGHT"
+			if position.NewLine == 0 && position.OldLine != 0 {
+				comment.Path, comment.Line, comment.Side = position.OldPath, position.OldLine, "LEFT"
+			}
+			comment.CommitID = position.HeadSHA
+		}
+		if first == 0 {
+			first = note.ID
+		} else {
+			comment.InReplyTo = first
+			comment.ReplyToComment = true
+		}
+		out = append(out, comment)
+	}
+	return out
+}
+
+func parseGitLabThreadID(threadID string) (string, int, string, error) {
+	invalid := ValidationError{Message: fmt.Sprintf("invalid GitLab thread ID %q (expected <namespace>/<project>!<iid>#<discussion>)", threadID)}
+	rest, discussionID, ok := strings.Cut(threadID, "#")
+	if !ok || discussionID == "" {
+		return "", 0, "", invalid
+	}
+	idx := strings.LastIndex(rest, "!")
+	if idx <= 0 {
+		return "", 0, "", invalid
+	}
+	number, err := strconv.Atoi(rest[idx+1:])
+	if err != nil || number <= 0 {
+		return "", 0, "", invalid
+	}
+	return rest[:idx], number, discussionID, nil
+}
+
+func mapGitLabJob(job gitlabJob) PullRequestCheckJSON {
+	check := PullRequestCheckJSON{
+		Name:        job.Name,
+		Status:      "completed",
+		DetailsURL:  job.WebURL,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.FinishedAt,
+		CheckRunID:  job.ID,
+	}
+	switch job.Status {
+	case "created", "pending", "waiting_for_resource", "preparing", "scheduled":
+		check.Status = "queued"
+	case "running":
+		check.Status = "in_progress"
+	case "success":
+		check.Conclusion = "success"
+	case "failed":
+		check.Conclusion = "failure"
+		if job.AllowFailure {
+			check.Conclusion = "neutral"
+		}
+	case "canceled":
+		check.Conclusion = "cancelled"
+	default:
+		check.Conclusion = "skipped"
+	}
+	return check
+}
@@ -0,0 +1,193 @@
+package worksetapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+)
+
+const gitlabTestProject = "/api/v4/projects/group%2Fsub%2Fproj"
+
+type fakeGitLab struct {
+	t        *testing.T
+	requests []string
+	created  map[string]any
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	route := r.Method + " " + r.URL.EscapedPath()
+	f.requests = append(f.requests, route+"?"+r.URL.RawQuery)
+	discussion := map[string]any{
+		"id": "d1",
+		"notes": []map[string]any{
+			{"id": 100, "body": "Please rename", "author": map[string]any{"id": 5, "username": "reviewer"}, "resolvable": true,
+				"resolved": r.Method == http.MethodPut, "position": map[string]any{"new_path": "main.go", "new_line": 12, "head_sha": "abc"}},
+			{"id": 101, "body": "changed target branch", "system": true},
+			{"id": 102, "body": "Done", "author": map[string]any{"id": 6, "username": "author"}, "resolvable": true,
+				"resolved": r.Method == http.MethodPut},
+		},
+	}
+	mr := map[string]any{
+		"iid": 7, "web_url": "https://gitlab.example.com/group/sub/proj/-/merge_requests/7",
+		"title": "Draft: Add login", "description": "Adds login.", "draft": true, "state": "opened",
+		"source_branch": "feature", "target_branch": "main", "sha": "abc", "has_conflicts": false,
+		"detailed_merge_status": "mergeable", "author": map[string]any{"username": "author"},
+	}
+	var payload any
+	switch route {
+	case "GET " + gitlabTestProject:
+		payload = map[string]any{"default_branch": "main"}
+	case "POST " + gitlabTestProject + "/merge_requests":
+		if err := json.NewDecoder(r.Body).Decode(&f.created); err != nil {
+			f.t.Fatalf("decode MR payload: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		payload = mr
+	case "GET " + gitlabTestProject + "/merge_requests/7":
+		payload = mr
+	case "GET " + gitlabTestProject + "/pipelines":
+		payload = []map[string]any{{"id": 99}}
+	case "GET " + gitlabTestProject + "/pipelines/99/jobs":
+		payload = []map[string]any{
+			{"id": 1, "name": "test", "status": "failed", "web_url": "https://gitlab.example.com/jobs/1"},
+			{"id": 2, "name": "lint", "status": "running"},
+		}
+	case "GET " + gitlabTestProject + "/merge_requests/7/discussions":
+		payload = []map[string]any{discussion}
+	case "PUT " + gitlabTestProject + "/merge_requests/7/discussions/d1":
+		payload = discussion
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		payload = map[string]any{"message": "404 Not Found"}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func setupGitLabServiceRepo(t *testing.T) (*testEnv, string, *fakeGitLab) {
+	t.Helper()
+	env, root, repoPath := setupGitHubServiceRepo(t)
+	fake := &fakeGitLab{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	cfg := env.loadConfig()
+	cfg.Hosts = map[string]config.HostConfig{
+		"gitlab.example.com": {Provider: hostProviderGitLab, APIURL: server.URL + "/api/v4", TokenEnv: "WORKSET_TEST_GITLAB_TOKEN"},
+	}
+	env.saveConfig(cfg)
+	t.Setenv("WORKSET_TEST_GITLAB_TOKEN", "secret")
+
+	env.git.remoteURLs[repoPath] = map[string][]string{"origin": {"git@gitlab.example.com:group/sub/proj.git"}}
+	env.git.remoteExists[repoPath] = map[string]bool{"upstream": false}
+	env.git.currentBranch[repoPath] = "feature"
+	env.git.currentOK[repoPath] = true
+	env.svc.commands = func(_ context.Context, _ string, command []string, _ []string, _ string) (CommandResult, error) {
+		if len(command) > 1 && command[1] == "ls-remote" {
+			return CommandResult{Stdout: "abc\trefs/heads/feature\n"}, nil
+		}
+		return CommandResult{}, nil
+	}
+	env.svc.github = nil
+	return env, root, fake
+}
+
+func TestGitLabMergeRequestLifecycle(t *testing.T) {
+	env, root, fake := setupGitLabServiceRepo(t)
+	ctx := context.Background()
+	workspace := WorkspaceSelector{Value: root}
+
+	created, err := env.svc.CreatePullRequest(ctx, PullRequestCreateInput{
+		Workspace: workspace,
+		Repo:      "repo-a",
+		Title:     "Add login",
+		Body:      "Adds login.",
+		Draft:     true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if created.Payload.Number != 7 || created.Payload.BaseRepo != "group/sub/proj" || !created.Payload.Draft {
+		t.Fatalf("unexpected created payload: %+v", created.Payload)
+	}
+	if fake.created["source_branch"] != "feature" || fake.created["target_branch"] != "main" || fake.created["title"] != "Draft: Add login" {
+		t.Fatalf("unexpected MR request: %+v", fake.created)
+	}
+
+	status, err := env.svc.GetPullRequestStatus(ctx, PullRequestStatusInput{Workspace: workspace, Repo: "repo-a", Number: 7})
+	if err != nil {
+		t.Fatalf("GetPullRequestStatus: %v", err)
+	}
+	if status.PullRequest.State != "open" || status.PullRequest.Mergeable != "mergeable" {
+		t.Fatalf("unexpected status: %+v", status.PullRequest)
+	}
+	if len(status.Checks) != 2 ||
+		status.Checks[0].Status != "completed" || status.Checks[0].Conclusion != "failure" ||
+		status.Checks[1].Status != "in_progress" {
+		t.Fatalf("unexpected checks: %+v", status.Checks)
+	}
+
+	comments, err := env.svc.ListPullRequestReviewComments(ctx, PullRequestReviewsInput{Workspace: workspace, Repo: "repo-a", Number: 7})
+	if err != nil {
+		t.Fatalf("ListPullRequestReviewComments: %v", err)
+	}
+	if len(comments.Comments) != 2 {
+		t.Fatalf("expected system note dropped, got %+v", comments.Comments)
+	}
+	first, reply := comments.Comments[0], comments.Comments[1]
+	if first.ThreadID != "group/sub/proj!7#d1" || first.Path != "main.go" || first.Line != 12 || first.Author != "reviewer" {
+		t.Fatalf("unexpected first comment: %+v", first)
+	}
+	if reply.InReplyTo != 100 || !reply.ReplyToComment || reply.ThreadID != first.ThreadID {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+
+	resolved, err := env.svc.ResolveReviewThread(ctx, ResolveReviewThreadInput{
+		Workspace: workspace,
+		Repo:      "repo-a",
+		ThreadID:  first.ThreadID,
+		Resolve:   true,
+	})
+	if err != nil {
+		t.Fatalf("ResolveReviewThread: %v", err)
+	}
+	if !resolved.Resolved {
+		t.Fatalf("expected discussion resolved")
+	}
+	last := fake.requests[len(fake.requests)-1]
+	if last != "PUT "+gitlabTestProject+"/merge_requests/7/discussions/d1?resolved=true" {
+		t.Fatalf("unexpected resolve request: %s", last)
+	}
+}
+
+func TestGitLabClientRequiresToken(t *testing.T) {
+	env, _, _ := setupGitLabServiceRepo(t)
+	t.Setenv("WORKSET_TEST_GITLAB_TOKEN", "")
+
+	_, err := env.svc.githubClient(context.Background(), "gitlab.example.com")
+	authErr := requireErrorType[AuthRequiredError](t, err)
+	if !strings.Contains(authErr.Message, "WORKSET_TEST_GITLAB_TOKEN") {
+		t.Fatalf("expected token env in message, got %q", authErr.Message)
+	}
+}
+
+func TestParseGitLabThreadID(t *testing.T) {
+	project, number, discussion, err := parseGitLabThreadID("group/sub/proj!7#d1")
+	if err != nil || project != "group/sub/proj" || number != 7 || discussion != "d1" {
+		t.Fatalf("unexpected parse: %q %d %q %v", project, number, discussion, err)
+	}
+	for _, invalid := range []string{"", "PRRT_abc", "group/proj#d1", "group/proj!x#d1", "group/proj!7#"} {
+		if _, _, _, err := parseGitLabThreadID(invalid); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+)
+
+const (
+	hostProviderGitHub    = "github"
+	hostProviderGitLab    = "gitlab"
+	defaultGitLabTokenEnv = "GITLAB_TOKEN"
+)
+
+// hostConfig returns the hosting provider settings for a remote host. Hosts
+// without an entry under hosts in the global config are served by GitHub.
+func (s *Service) hostConfig(ctx context.Context, host string) (config.HostConfig, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" || host == defaultGitHubHost || s.configs == nil {
+		return config.HostConfig{Provider: hostProviderGitHub}, nil
+	}
+	cfg, _, err := s.loadGlobal(ctx)
+	if err != nil {
+		return config.HostConfig{}, err
+	}
+	hostCfg := cfg.Hosts[host]
+	if hostCfg.Provider == "" {
+		hostCfg.Provider = hostProviderGitHub
+	}
+	return hostCfg, nil
+}
+
+// validateRemoteHost rejects hosts that no configured provider can serve.
+func (s *Service) validateRemoteHost(ctx context.Context, host string) error {
+	hostCfg, err := s.hostConfig(ctx, host)
+	if err != nil {
+		return err
+	}
+	switch hostCfg.Provider {
+	case hostProviderGitLab:
+		return nil
+	case hostProviderGitHub:
+		if host != defaultGitHubHost {
+			return ValidationError{Message: fmt.Sprintf("unsupported GitHub host %q: only github.com is supported in this release; set hosts.%s.provider to gitlab for GitLab remotes", host, host)}
+		}
+		return nil
+	default:
+		return ValidationError{Message: fmt.Sprintf("unknown provider %q for host %q (expected github or gitlab)", hostCfg.Provider, host)}
+	}
+}
+
+// newGitLabHostClient builds a GitLab client for host using the token read
+// from hostCfg.TokenEnv (GITLAB_TOKEN by default).
+func newGitLabHostClient(host string, hostCfg config.HostConfig) (GitHubClient, error) {
+	tokenEnv := hostCfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = defaultGitLabTokenEnv
+	}
+	token := strings.TrimSpace(os.Getenv(tokenEnv))
+	if token == "" {
+		return nil, AuthRequiredError{Message: fmt.Sprintf("GitLab authentication required for %s: set %s", host, tokenEnv)}
+	}
+	apiURL := hostCfg.APIURL
+	if apiURL == "" {
+		apiURL = "https://" + host + "/api/v4"
+	}
+	return newGitLabClient(host, apiURL, token, &http.Client{Timeout: 30 * time.Second}), nil
+}
@@ -0,0 +1,143 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git/gittest"
+)
+
+// newGittestEnv returns a test env backed by an in-memory git with an origin
+// repo for each name, registered by URL so threads check out from bare
+// mirrors in the repo store.
+func newGittestEnv(t *testing.T, names ...string) (*testEnv, *gittest.Fake) {
+	t.Helper()
+	fake := gittest.New()
+	env := newTestEnvWithGit(t, fake)
+	cfg := env.loadConfig()
+	cfg.Repos = map[string]config.RegisteredRepo{}
+	for _, name := range names {
+		origin := filepath.Join(env.root, "origins", name)
+		fake.InitRepo(t, origin, "main")
+		url := "https://example.com/" + name + ".git"
+		fake.RegisterURL(t, url, origin)
+		cfg.Repos[name] = config.RegisteredRepo{URL: url}
+	}
+	env.saveConfig(cfg)
+	return env, fake
+}
+
+func threadStatus(t *testing.T, env *testEnv, thread string) map[string]RepoStatusJSON {
+	t.Helper()
+	result, err := env.svc.StatusWorkspace(context.Background(), WorkspaceStatusInput{Selector: WorkspaceSelector{Value: thread}})
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	byName := map[string]RepoStatusJSON{}
+	for _, status := range result.Statuses {
+		byName[status.Name] = status
+	}
+	return byName
+}
+
+func TestGittestCreateAndStatusThread(t *testing.T) {
+	env, fake := newGittestEnv(t, "api", "web")
+	ctx := context.Background()
+
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api", "web"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, name := range []string{"api", "web"} {
+		names, err := fake.WorktreeList(filepath.Join(env.repoRoot, name))
+		if err != nil || len(names) != 1 {
+			t.Fatalf("expected one worktree of the %s mirror, got %v err=%v", name, names, err)
+		}
+	}
+
+	statuses := threadStatus(t, env, "demo")
+	api := statuses["api"]
+	if api.Dirty || api.Missing || api.Branch != "demo" || api.Upstream != "origin/main" {
+		t.Fatalf("expected clean api on demo tracking origin/main, got %+v", api)
+	}
+
+	apiPath := filepath.Join(created.Workspace.Path, "api")
+	fake.Commit(t, apiPath, "feature", map[string]string{"feature.go": "package api\n"})
+	fake.WriteFile(t, filepath.Join(created.Workspace.Path, "web"), "wip.txt", "wip\n")
+	statuses = threadStatus(t, env, "demo")
+	if got := statuses["api"]; got.Ahead != 1 || got.Dirty {
+		t.Fatalf("expected api one commit ahead and clean, got %+v", got)
+	}
+	if got := statuses["web"]; !got.Dirty || got.Ahead != 0 {
+		t.Fatalf("expected web dirty with no commits, got %+v", got)
+	}
+}
+
+func TestGittestRemoveRepoSafety(t *testing.T) {
+	env, fake := newGittestEnv(t, "api")
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	apiPath := filepath.Join(created.Workspace.Path, "api")
+	mirror := filepath.Join(env.repoRoot, "api")
+	remove := RepoRemoveInput{
+		Workspace:       WorkspaceSelector{Value: "demo"},
+		Name:            "api",
+		DeleteWorktrees: true,
+		Confirmed:       true,
+	}
+
+	fake.WriteFile(t, apiPath, "wip.txt", "wip\n")
+	var unsafe UnsafeOperation
+	if _, err := env.svc.RemoveRepo(ctx, remove); !errors.As(err, &unsafe) || !slices.Equal(unsafe.Dirty, []string{"demo"}) {
+		t.Fatalf("expected dirty worktree to block removal, got %v", err)
+	}
+
+	fake.Commit(t, apiPath, "wip", nil)
+	if _, err := env.svc.RemoveRepo(ctx, remove); !errors.As(err, &unsafe) || len(unsafe.Unmerged) == 0 {
+		t.Fatalf("expected unmerged branch to block removal, got %v", err)
+	}
+	if names, _ := fake.WorktreeList(mirror); len(names) != 1 {
+		t.Fatalf("expected refused removal to keep the worktree, got %v", names)
+	}
+
+	remove.Force = true
+	if _, err := env.svc.RemoveRepo(ctx, remove); err != nil {
+		t.Fatalf("forced remove: %v", err)
+	}
+	if names, _ := fake.WorktreeList(mirror); len(names) != 0 {
+		t.Fatalf("expected worktree removed from the mirror, got %v", names)
+	}
+	if status, err := fake.Status(apiPath); err != nil || !status.Missing {
+		t.Fatalf("expected worktree gone, got %+v err=%v", status, err)
+	}
+}
+
+func TestGittestRemoveRepoAllowsSquashMergedBranch(t *testing.T) {
+	env, fake := newGittestEnv(t, "api")
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	apiPath := filepath.Join(created.Workspace.Path, "api")
+	fake.Commit(t, apiPath, "feature", map[string]string{"feature.go": "package api\n"})
+	fake.Commit(t, filepath.Join(env.root, "origins", "api"), "squash feature", map[string]string{"feature.go": "package api\n"})
+
+	_, err = env.svc.RemoveRepo(ctx, RepoRemoveInput{
+		Workspace:       WorkspaceSelector{Value: "demo"},
+		Name:            "api",
+		DeleteWorktrees: true,
+		Confirmed:       true,
+		FetchRemotes:    true,
+	})
+	if err != nil {
+		t.Fatalf("expected squash-merged branch to be safe to remove, got %v", err)
+	}
+}
@@ -85,6 +85,7 @@ func (s *Service) RunHooks(ctx context.Context, input HooksRunInput) (HooksRunRe
 		LogRoot:        hooksLogRoot(wsRoot),
 		Context:        ctxPayload,
 		Observer:       hookObserverAdapter{observer: s.hookEvents},
+		MaxParallel:    cfg.Hooks.MaxParallel,
 	})
 	if err != nil {
 		return HooksRunResult{}, err
@@ -109,9 +110,12 @@ func (s *Service) RunHooks(ctx context.Context, input HooksRunInput) (HooksRunRe
 			status = HookRunStatusOK
 		}
 		results = append(results, HookRunJSON{
-			ID:      res.HookID,
-			Status:  status,
-			LogPath: res.LogPath,
+			ID:       res.HookID,
+			Status:   status,
+			LogPath:  res.LogPath,
+			Reason:   res.SkipReason,
+			Attempts: res.Attempts,
+			TimedOut: res.TimedOut,
 		})
 	}
 
@@ -140,68 +144,147 @@ func (s *Service) TrustRepoHooks(ctx context.Context, repoName string) (config.G
 }
 
 func (s *Service) runWorktreeCreatedHooks(ctx context.Context, cfg config.GlobalConfig, wsRoot, wsName string, repo config.RepoConfig, worktreePath, branch, reason string) (HookPending, []HookExecutionJSON, []string, error) {
-	hookFile, exists, err := hooks.LoadRepoHooks(worktreePath)
+	pending, runs, warnings, err := s.runRepoHooks(ctx, cfg, repoHookRequest{
+		event:        hooks.EventWorktreeCreated,
+		reason:       reason,
+		wsRoot:       wsRoot,
+		wsName:       wsName,
+		repo:         repo,
+		worktreePath: worktreePath,
+		branch:       branch,
+	})
 	if err != nil {
 		return HookPending{}, nil, nil, err
 	}
-	if !exists || len(hookFile.Hooks) == 0 {
+	return pending, runs, warnings, nil
+}
+
+// repoHookRequest describes one event firing against a single repo worktree.
+type repoHookRequest struct {
+	event        hooks.Event
+	reason       string
+	wsRoot       string
+	wsName       string
+	repo         config.RepoConfig
+	worktreePath string
+	branch       string
+	// extra carries event-specific context such as PR or commit details.
+	extra hooks.Context
+	// hookFile holds preloaded hooks; when nil they are read from worktreePath.
+	hookFile *hooks.File
+	cwd      string
+}
+
+// runRepoHooks runs the hooks a repo defines for an event, honoring
+// hooks.enabled and the trusted repo list, followed by the hooks of the
+// thread's workset template. Template hooks come from the user's config, so
+// they run even when the repo is untrusted, unless they depend on the repo's
+// hooks. On hook failure the partial runs are returned along with the error.
+func (s *Service) runRepoHooks(ctx context.Context, cfg config.GlobalConfig, req repoHookRequest) (HookPending, []HookExecutionJSON, []string, error) {
+	var hookFile hooks.File
+	if req.hookFile != nil {
+		hookFile = *req.hookFile
+	} else {
+		loaded, _, err := hooks.LoadRepoHooks(req.worktreePath)
+		if err != nil {
+			return HookPending{}, nil, nil, err
+		}
+		hookFile = loaded
+	}
+	var extraHooks []hooks.Hook
+	if template, ok := worksetTemplateForThread(cfg, req.wsName); ok {
+		extraHooks = templateHooks(template.Hooks)
+	}
+	if len(hookFile.Hooks) == 0 && len(extraHooks) == 0 {
 		return HookPending{}, nil, nil, nil
 	}
 
-	event := hooks.EventWorktreeCreated
+	event := req.event
+	repo := req.repo
 	candidateIDs := hookIDsForEvent(hookFile.Hooks, event)
-	if len(candidateIDs) == 0 {
+	templateIDs := hookIDsForEvent(extraHooks, event)
+	if len(candidateIDs) == 0 && len(templateIDs) == 0 {
 		return HookPending{}, nil, nil, nil
 	}
 
 	if !cfg.Hooks.Enabled {
-		warn := fmt.Sprintf("repo hooks found for %s but hooks are disabled (set hooks.enabled to true)", repo.Name)
+		source := "repo"
+		if len(candidateIDs) == 0 {
+			source = "workset template"
+		}
+		warn := fmt.Sprintf("%s hooks found for %s but hooks are disabled (set hooks.enabled to true)", source, repo.Name)
 		return HookPending{
 			Event:  string(event),
 			Repo:   repo.Name,
-			Hooks:  candidateIDs,
+			Hooks:  append(candidateIDs, templateIDs...),
 			Status: HookRunStatusSkipped,
 			Reason: "disabled",
 		}, nil, []string{warn}, nil
 	}
 
-	if !isTrustedRepo(&cfg, repo.Name) {
-		pending := HookPending{
+	runHooks := hookFile.Hooks
+	var pending HookPending
+	var warnings []string
+	if len(candidateIDs) > 0 && !isTrustedRepo(&cfg, repo.Name) {
+		pending = HookPending{
 			Event:  string(event),
 			Repo:   repo.Name,
 			Hooks:  candidateIDs,
 			Status: HookRunStatusSkipped,
 			Reason: "untrusted",
 		}
-		warn := fmt.Sprintf("repo %s defines hooks; run `workset hooks run -t %s %s` to execute or trust", repo.Name, wsName, repo.Name)
-		return pending, nil, []string{warn}, nil
+		eventArg := ""
+		if event != hooks.EventWorktreeCreated {
+			eventArg = " --event " + string(event)
+		}
+		warnings = append(warnings, fmt.Sprintf("repo %s defines hooks; run `workset hooks run -t %s%s %s` to execute or trust", repo.Name, req.wsName, eventArg, repo.Name))
+		runHooks = nil
 	}
-
-	ctxPayload := hooks.Context{
-		WorkspaceRoot:   wsRoot,
-		WorkspaceName:   wsName,
-		WorkspaceConfig: workspace.WorksetFile(wsRoot),
-		RepoName:        repo.Name,
-		RepoDir:         repo.RepoDir,
-		RepoPath:        worktreePath,
-		WorktreePath:    worktreePath,
-		Branch:          branch,
-		Event:           event,
-		Reason:          reason,
+	extraHooks, skippedHooks := scheduleTemplateHooks(extraHooks, hookFile.Hooks, runHooks, event)
+	var skipped []HookExecutionJSON
+	for _, hook := range skippedHooks {
+		skipped = append(skipped, HookExecutionJSON{
+			Event:  string(event),
+			Repo:   repo.Name,
+			ID:     hook.id,
+			Status: HookRunStatusSkipped,
+			Reason: hook.reason,
+		})
+		warnings = append(warnings, fmt.Sprintf("workset template hook %s skipped for %s: %s", hook.id, repo.Name, hook.reason))
 	}
+	if len(hookIDsForEvent(extraHooks, event)) == 0 && runHooks == nil {
+		return pending, skipped, warnings, nil
+	}
+	runHooks = append(append([]hooks.Hook{}, runHooks...), extraHooks...)
+
+	ctxPayload := req.extra
+	ctxPayload.WorkspaceRoot = req.wsRoot
+	ctxPayload.WorkspaceName = req.wsName
+	ctxPayload.WorkspaceConfig = workspace.WorksetFile(req.wsRoot)
+	ctxPayload.RepoName = repo.Name
+	ctxPayload.RepoDir = repo.RepoDir
+	ctxPayload.RepoPath = req.worktreePath
+	ctxPayload.WorktreePath = req.worktreePath
+	ctxPayload.Branch = req.branch
+	ctxPayload.Event = event
+	ctxPayload.Reason = req.reason
+
 	engine := hooks.Engine{Runner: s.hookRunner, Clock: s.clock}
 	report, err := engine.Run(ctx, hooks.RunInput{
 		Event:          event,
-		Hooks:          hookFile.Hooks,
+		Hooks:          runHooks,
 		DefaultOnError: cfg.Hooks.OnError,
-		LogRoot:        hooksLogRoot(wsRoot),
+		LogRoot:        hooksLogRoot(req.wsRoot),
 		Context:        ctxPayload,
 		Observer:       hookObserverAdapter{observer: s.hookEvents},
+		MaxParallel:    cfg.Hooks.MaxParallel,
+		Cwd:            req.cwd,
 	})
+	runs := append(hookExecutionsForEvent(report, repo.Name, event), skipped...)
 	if err != nil {
-		return HookPending{}, nil, nil, err
+		return pending, runs, warnings, err
 	}
-	return HookPending{}, hookExecutionsForEvent(report, repo.Name, event), nil, nil
+	return pending, runs, warnings, nil
 }
 
 func hooksLogRoot(workspaceRoot string) string {
@@ -252,15 +335,20 @@ func hookExecutionsForEvent(report hooks.RunReport, repoName string, event hooks
 		if status == "" {
 			status = HookRunStatusOK
 		}
-		if status == HookRunStatusSkipped {
+		// Hooks for other events come back skipped without a reason; only
+		// hooks whose when: condition skipped them are reported.
+		if status == HookRunStatusSkipped && result.SkipReason == "" {
 			continue
 		}
 		results = append(results, HookExecutionJSON{
-			Event:   string(event),
-			Repo:    repoName,
-			ID:      result.HookID,
-			Status:  status,
-			LogPath: result.LogPath,
+			Event:    string(event),
+			Repo:     repoName,
+			ID:       result.HookID,
+			Status:   status,
+			LogPath:  result.LogPath,
+			Reason:   result.SkipReason,
+			Attempts: result.Attempts,
+			TimedOut: result.TimedOut,
 		})
 	}
 	return results
@@ -294,15 +382,16 @@ func (a hookObserverAdapter) OnHookProgress(progress hooks.HookProgress) {
 	}
 	status := HookRunStatus(progress.Status)
 	a.observer.OnHookProgress(HookProgress{
-		Phase:     string(progress.Phase),
-		Event:     string(progress.Event),
-		Repo:      progress.RepoName,
-		Workspace: progress.WorkspaceName,
-		HookID:    progress.HookID,
-		Reason:    progress.Reason,
-		Status:    status,
-		LogPath:   progress.LogPath,
-		Error:     errorString(progress.Err),
+		Phase:      string(progress.Phase),
+		Event:      string(progress.Event),
+		Repo:       progress.RepoName,
+		Workspace:  progress.WorkspaceName,
+		HookID:     progress.HookID,
+		Reason:     progress.Reason,
+		SkipReason: progress.SkipReason,
+		Status:     status,
+		LogPath:    progress.LogPath,
+		Error:      errorString(progress.Err),
 	})
 }
 
@@ -157,11 +157,15 @@ func mapRepoHookPreviewHooks(file hooks.File) []RepoHookPreviewJSON {
 		}
 		run := append([]string(nil), hook.Run...)
 		result = append(result, RepoHookPreviewJSON{
-			ID:      hook.ID,
-			On:      on,
-			Run:     run,
-			Cwd:     hook.Cwd,
-			OnError: hook.OnError,
+			ID:        hook.ID,
+			On:        on,
+			Run:       run,
+			Cwd:       hook.Cwd,
+			OnError:   hook.OnError,
+			DependsOn: append([]string(nil), hook.DependsOn...),
+			Parallel:  hook.Parallel,
+			Timeout:   hook.Timeout,
+			Retries:   hook.Retries,
 		})
 	}
 	return result
@@ -0,0 +1,165 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+// lifecycleHookResult collects hook outcomes across the repos of a thread.
+type lifecycleHookResult struct {
+	Pending  []HookPending
+	Runs     []HookExecutionJSON
+	Warnings []string
+}
+
+// threadHookTargets resolves the repo worktrees of a thread. Hooks are loaded
+// up front so they can still fire once the worktrees are removed; repos whose
+// hooks file cannot be read are reported as warnings. Repos without hooks of
+// their own are still targeted when the workset template defines hooks.
+func (s *Service) threadHookTargets(
+	ctx context.Context,
+	cfg config.GlobalConfig,
+	wsRoot string,
+	wsConfig config.WorkspaceConfig,
+) ([]repoHookRequest, []string) {
+	if len(wsConfig.Repos) == 0 {
+		return nil, nil
+	}
+	wsName := wsConfig.Name
+	if wsName == "" {
+		wsName = threadNameByPath(&cfg, wsRoot)
+	}
+	if wsName == "" {
+		wsName = filepath.Base(wsRoot)
+	}
+	branch := cfg.Defaults.BaseBranch
+	if state, err := s.workspaces.LoadState(ctx, wsRoot); err == nil && state.CurrentBranch != "" {
+		branch = state.CurrentBranch
+	}
+
+	template, _ := worksetTemplateForThread(cfg, wsName)
+	targets := make([]repoHookRequest, 0, len(wsConfig.Repos))
+	var warnings []string
+	for _, repo := range wsConfig.Repos {
+		if repo.Name == "" {
+			continue
+		}
+		config.ApplyRepoDefaults(&repo, cfg.Defaults)
+		worktreePath := workspace.RepoWorktreePath(wsRoot, branch, repo.RepoDir)
+		hookFile, exists, err := hooks.LoadRepoHooks(worktreePath)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to load hooks for %s: %v", repo.Name, err))
+			continue
+		}
+		if (!exists || len(hookFile.Hooks) == 0) && len(template.Hooks) == 0 {
+			continue
+		}
+		targets = append(targets, repoHookRequest{
+			wsRoot:       wsRoot,
+			wsName:       wsName,
+			repo:         repo,
+			worktreePath: worktreePath,
+			branch:       branch,
+			hookFile:     &hookFile,
+		})
+	}
+	return targets, warnings
+}
+
+// runLifecycleHooks fires an event against each target. Hook failures on pre
+// events (see hooks.Event.IsPre) abort with the failure so the caller can veto
+// the operation; failures on other events are downgraded to warnings because
+// the operation has already happened.
+func (s *Service) runLifecycleHooks(
+	ctx context.Context,
+	cfg config.GlobalConfig,
+	targets []repoHookRequest,
+	event hooks.Event,
+	reason string,
+	extra hooks.Context,
+	cwd string,
+) (lifecycleHookResult, error) {
+	result := lifecycleHookResult{}
+	for _, target := range targets {
+		target.event = event
+		target.reason = reason
+		target.extra = extra
+		if cwd != "" {
+			target.cwd = cwd
+		}
+		pending, runs, warnings, err := s.runRepoHooks(ctx, cfg, target)
+		result.Runs = append(result.Runs, runs...)
+		result.Warnings = append(result.Warnings, warnings...)
+		if len(pending.Hooks) > 0 {
+			result.Pending = append(result.Pending, pending)
+		}
+		if err == nil {
+			continue
+		}
+		var failed hooks.HookFailedError
+		if event.IsPre() && errors.As(err, &failed) {
+			return result, err
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s hooks for %s: %v", event, target.repo.Name, err))
+	}
+	return result, nil
+}
+
+// runThreadLifecycleHooks fires a post event against every repo in a thread.
+func (s *Service) runThreadLifecycleHooks(
+	ctx context.Context,
+	cfg config.GlobalConfig,
+	wsRoot string,
+	event hooks.Event,
+	reason string,
+	extra hooks.Context,
+) lifecycleHookResult {
+	wsConfig, err := s.workspaces.LoadConfig(ctx, wsRoot)
+	if err != nil {
+		return lifecycleHookResult{Warnings: []string{fmt.Sprintf("%s hooks skipped: %v", event, err)}}
+	}
+	targets, warnings := s.threadHookTargets(ctx, cfg, wsRoot, wsConfig)
+	result, _ := s.runLifecycleHooks(ctx, cfg, targets, event, reason, extra, "")
+	result.Warnings = append(warnings, result.Warnings...)
+	return result
+}
+
+// runRepoLifecycleHooks fires an event for a single repo resolved through
+// resolveRepo, loading the global config for hook settings.
+func (s *Service) runRepoLifecycleHooks(
+	ctx context.Context,
+	resolution repoResolution,
+	event hooks.Event,
+	reason string,
+	extra hooks.Context,
+) lifecycleHookResult {
+	cfg, _, err := s.loadGlobal(ctx)
+	if err != nil {
+		return lifecycleHookResult{Warnings: []string{fmt.Sprintf("%s hooks skipped: %v", event, err)}}
+	}
+	result, _ := s.runLifecycleHooks(ctx, cfg, []repoHookRequest{{
+		wsRoot:       resolution.WorkspaceRoot,
+		wsName:       resolution.WorkspaceName,
+		repo:         resolution.Repo,
+		worktreePath: resolution.RepoPath,
+		branch:       resolution.Branch,
+	}}, event, reason, extra, "")
+	return result
+}
+
+// logHookWarnings reports hook warnings for operations whose results carry no
+// warnings of their own.
+func (s *Service) logHookWarnings(result lifecycleHookResult) {
+	if s.logf == nil {
+		return
+	}
+	for _, warning := range result.Warnings {
+		s.logf("warning: %s", warning)
+	}
+}
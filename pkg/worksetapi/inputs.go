@@ -1,12 +1,35 @@
 package worksetapi
 
+import (
+	"io"
+	"time"
+)
+
 // WorkspaceCreateInput describes inputs for CreateWorkspace.
+// Start applies to every repo; RepoStarts overrides it per repo name.
 type WorkspaceCreateInput struct {
 	Name        string
 	Path        string
 	Workset     string
 	WorksetOnly bool
 	Repos       []string
+	Start       RepoStart
+	RepoStarts  map[string]RepoStart
+}
+
+// RepoStart selects where a repo's worktree starts instead of a new thread
+// branch from the repo's default branch. At most one field may be set.
+type RepoStart struct {
+	// From starts the thread branch from a branch, tag, or commit.
+	From string
+	// ExistingBranch checks out the thread branch as it exists on the remote.
+	ExistingBranch bool
+	// Branch checks out this branch as it exists on the remote instead of
+	// the thread branch.
+	Branch string
+	// PullRequest checks out the head branch of this pull request and uses
+	// its base branch as the repo's base.
+	PullRequest int
 }
 
 // WorkspaceDeleteInput describes inputs for DeleteWorkspace.
@@ -16,6 +39,105 @@ type WorkspaceDeleteInput struct {
 	Force        bool
 	Confirmed    bool
 	FetchRemotes bool
+	// Purge deletes the thread's files permanently instead of moving them
+	// to the trash.
+	Purge bool
+}
+
+// TrashRestoreInput describes inputs for RestoreTrash. Path overrides where
+// the thread is restored to.
+type TrashRestoreInput struct {
+	ID   string
+	Path string
+}
+
+// TrashPurgeInput describes inputs for PurgeTrash. With no IDs and All unset,
+// only entries past the retention period are purged.
+type TrashPurgeInput struct {
+	IDs []string
+	All bool
+}
+
+// AutoArchiveInput describes inputs for AutoArchive. DryRun reports what
+// would be archived without archiving; Offline judges tracked pull requests
+// by their recorded state instead of querying the provider.
+type AutoArchiveInput struct {
+	DryRun  bool
+	Offline bool
+}
+
+// GCInput describes inputs for GC. PruneWorktrees also removes the
+// worktrees of archived threads.
+type GCInput struct {
+	DryRun         bool
+	Offline        bool
+	PruneWorktrees bool
+}
+
+// RepoStoreFetchInput selects the registered repos FetchRepoStore clones or
+// fetches; empty means every URL-registered repo.
+type RepoStoreFetchInput struct {
+	Repos []string
+}
+
+// RepoStoreGCInput describes inputs for GCRepoStore. PruneOrphans deletes
+// store directories no registered repo or thread uses.
+type RepoStoreGCInput struct {
+	DryRun       bool
+	PruneOrphans bool
+}
+
+// RepoStoreVerifyInput selects the registered repos VerifyRepoStore checks;
+// empty means every URL-registered repo.
+type RepoStoreVerifyInput struct {
+	Repos []string
+}
+
+// WorkspaceStatusInput describes inputs for StatusWorkspace.
+// FetchRemotes refreshes each repo's remote before status is read.
+type WorkspaceStatusInput struct {
+	Selector     WorkspaceSelector
+	FetchRemotes bool
+}
+
+// ThreadLogInput describes inputs for ThreadLog. Repos limits the log to
+// those repos; Author is a regular expression matched against
+// "Name <email>".
+type ThreadLogInput struct {
+	Selector WorkspaceSelector
+	Repos    []string
+	Since    time.Time
+	Author   string
+	Patch    bool
+}
+
+// WorkspaceSyncInput describes inputs for SyncWorkspace. Mode is "rebase"
+// (the default) or "merge". Continue and Abort resume or cancel rebases and
+// merges stopped on conflicts by an earlier sync and ignore Mode.
+type WorkspaceSyncInput struct {
+	Selector WorkspaceSelector
+	Mode     string
+	Continue bool
+	Abort    bool
+}
+
+// WorkspaceAdoptInput describes inputs for AdoptWorkspace. Dirs are searched
+// for git checkouts; Move relocates matched checkouts into the thread instead
+// of linking them in place.
+type WorkspaceAdoptInput struct {
+	Name    string
+	Path    string
+	Workset string
+	Dirs    []string
+	Move    bool
+}
+
+// ThreadImportInput describes inputs for ImportThread. Name and Path
+// default to the bundled thread name and the usual thread location.
+type ThreadImportInput struct {
+	Bundle ThreadBundle
+	Name   string
+	Path   string
 }
 
 // WorkspaceRenameInput describes inputs for RenameWorkspace.
@@ -33,6 +155,7 @@ type RepoAddInput struct {
 	RepoDir    string
 	URL        string
 	SourcePath string
+	Start      RepoStart
 }
 
 // WorksetRepoAddInput describes inputs for adding repos directly to a workset.
@@ -69,6 +192,41 @@ type ExecInput struct {
 	Command   []string
 }
 
+// ExecEachRepoInput describes inputs for ExecEachRepo.
+// Repos limits execution to the named repos; Parallel bounds concurrent runs
+// (values below 1 run sequentially). Nil writers default to os.Stdout/os.Stderr.
+type ExecEachRepoInput struct {
+	Workspace WorkspaceSelector
+	Command   []string
+	Repos     []string
+	Parallel  int
+	Stdout    io.Writer
+	Stderr    io.Writer
+}
+
+// ProcessesInput selects a thread's processes for ProcessesUp,
+// ProcessesDown and ProcessStatus. Names limits the call to those processes;
+// ProcessesUp also starts what they depend on. ReadyTimeout bounds how long
+// ProcessesUp waits for a dependency to become ready and defaults to two
+// minutes.
+type ProcessesInput struct {
+	Workspace    WorkspaceSelector
+	Names        []string
+	ReadyTimeout time.Duration
+}
+
+// ProcessLogsInput describes inputs for ProcessLogs. Offsets resumes each
+// process from the NextOffsets of an earlier result; processes without one
+// start from their last MaxBytes of output (64 KiB by default). Follow holds
+// back a trailing partial line so repeated calls return whole lines.
+type ProcessLogsInput struct {
+	Workspace WorkspaceSelector
+	Names     []string
+	Offsets   map[string]int64
+	MaxBytes  int
+	Follow    bool
+}
+
 // HooksRunInput describes inputs for running hooks.
 type HooksRunInput struct {
 	Workspace WorkspaceSelector
@@ -15,6 +15,7 @@ type repoPlan struct {
 	SourcePath    string
 	Remote        string
 	DefaultBranch string
+	Start         repoStartOptions
 }
 
 func buildNewWorkspaceRepoPlans(cfg config.GlobalConfig, repoNames []string) ([]repoPlan, error) {
@@ -0,0 +1,462 @@
+package worksetapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/session"
+	"github.com/strantalis/workset/pkg/terminalservice"
+)
+
+// TerminalService is the part of the terminal service that runs thread
+// processes. *terminalservice.Client implements it.
+type TerminalService interface {
+	CreateSession(ctx context.Context, req terminalservice.CreateRequest) (terminalservice.CreateResponse, error)
+	Inspect(ctx context.Context, sessionID string) (terminalservice.InspectResponse, error)
+	Read(ctx context.Context, req terminalservice.ReadRequest) (terminalservice.ReadResponse, error)
+	Stop(ctx context.Context, sessionID string) error
+}
+
+const (
+	ProcessStateRunning = "running"
+	ProcessStateExited  = "exited"
+	ProcessStateStopped = "stopped"
+)
+
+const (
+	defaultProcessReadyTimeout = 2 * time.Minute
+	defaultProcessLogBytes     = 64 * 1024
+	// processReadyWindow bounds the output kept while waiting for a ready
+	// pattern so a chatty process cannot grow it without limit.
+	processReadyWindow = 64 * 1024
+)
+
+var processReadyPoll = 200 * time.Millisecond
+
+// processTarget is a thread's processes in start order with the directory
+// each one runs in.
+type processTarget struct {
+	thread string
+	root   string
+	procs  []config.ProcessConfig
+	cwds   map[string]string
+}
+
+// ProcessesUp starts a thread's processes as terminal-service sessions in
+// dependency order, waiting for each process something depends on to become
+// ready first. Processes that are already running are left alone.
+func (s *Service) ProcessesUp(ctx context.Context, input ProcessesInput) (ProcessesResult, error) {
+	target, err := s.resolveProcesses(ctx, input.Workspace, input.Names, true)
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	terminals, err := s.terminalService()
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	timeout := input.ReadyTimeout
+	if timeout <= 0 {
+		timeout = defaultProcessReadyTimeout
+	}
+	needed := map[string]bool{}
+	for _, proc := range target.procs {
+		for _, dep := range proc.DependsOn {
+			needed[dep] = true
+		}
+	}
+	for _, proc := range target.procs {
+		restart := strings.TrimSpace(proc.Restart)
+		if restart == "" {
+			restart = terminalservice.RestartOnFailure
+		}
+		env := map[string]string{
+			"WORKSET_ROOT":      target.root,
+			"WORKSET_WORKSPACE": target.thread,
+			"WORKSET_PROCESS":   proc.Name,
+		}
+		if proc.Repo != "" {
+			env["WORKSET_REPO"] = proc.Repo
+		}
+		for key, value := range proc.Env {
+			env[key] = value
+		}
+		sessionID := processSessionID(target.thread, proc.Name)
+		shell, args := session.ShellCommand(proc.Command)
+		if _, err := terminals.CreateSession(ctx, terminalservice.CreateRequest{
+			SessionID: sessionID,
+			Cwd:       target.cwds[proc.Name],
+			Command:   shell,
+			Args:      args,
+			Env:       env,
+			Restart:   restart,
+			KeepAlive: true,
+		}); err != nil {
+			return ProcessesResult{}, fmt.Errorf("start process %q: %w", proc.Name, terminalServiceError(err))
+		}
+		if needed[proc.Name] {
+			if err := waitProcessReady(ctx, terminals, sessionID, proc, timeout); err != nil {
+				return ProcessesResult{}, err
+			}
+		}
+	}
+	return s.processStatuses(ctx, terminals, target), nil
+}
+
+// ProcessesDown stops a thread's processes, dependents first.
+func (s *Service) ProcessesDown(ctx context.Context, input ProcessesInput) (ProcessesResult, error) {
+	target, err := s.resolveProcesses(ctx, input.Workspace, input.Names, false)
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	terminals, err := s.terminalService()
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	for i := len(target.procs) - 1; i >= 0; i-- {
+		proc := target.procs[i]
+		if err := terminals.Stop(ctx, processSessionID(target.thread, proc.Name)); err != nil {
+			if isTerminalServiceDown(err) {
+				// Nothing runs without the service.
+				break
+			}
+			return ProcessesResult{}, fmt.Errorf("stop process %q: %w", proc.Name, err)
+		}
+	}
+	return s.processStatuses(ctx, terminals, target), nil
+}
+
+// ProcessStatus reports the state of a thread's processes.
+func (s *Service) ProcessStatus(ctx context.Context, input ProcessesInput) (ProcessesResult, error) {
+	target, err := s.resolveProcesses(ctx, input.Workspace, input.Names, false)
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	terminals, err := s.terminalService()
+	if err != nil {
+		return ProcessesResult{}, err
+	}
+	return s.processStatuses(ctx, terminals, target), nil
+}
+
+// ProcessLogs returns the buffered output of a thread's processes as
+// prefixed lines with terminal escape sequences removed.
+func (s *Service) ProcessLogs(ctx context.Context, input ProcessLogsInput) (ProcessLogsResult, error) {
+	target, err := s.resolveProcesses(ctx, input.Workspace, input.Names, false)
+	if err != nil {
+		return ProcessLogsResult{}, err
+	}
+	terminals, err := s.terminalService()
+	if err != nil {
+		return ProcessLogsResult{}, err
+	}
+	maxBytes := input.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultProcessLogBytes
+	}
+	result := ProcessLogsResult{Lines: []ProcessLogLineJSON{}, NextOffsets: map[string]int64{}}
+	for _, proc := range target.procs {
+		req := terminalservice.ReadRequest{SessionID: processSessionID(target.thread, proc.Name)}
+		if offset, ok := input.Offsets[proc.Name]; ok {
+			req.Offset = offset
+			result.NextOffsets[proc.Name] = offset
+		} else {
+			req.MaxBytes = maxBytes
+		}
+		resp, err := terminals.Read(ctx, req)
+		if err != nil {
+			if isTerminalServiceDown(err) {
+				return ProcessLogsResult{}, terminalServiceError(err)
+			}
+			// A process that is not running has no output to show.
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(resp.DataB64)
+		if err != nil {
+			return ProcessLogsResult{}, err
+		}
+		next := resp.NextOffset
+		if input.Follow {
+			if cut := bytes.LastIndexByte(data, '\n'); cut < len(data)-1 {
+				next -= int64(len(data) - cut - 1)
+				data = data[:cut+1]
+			}
+		}
+		result.NextOffsets[proc.Name] = next
+		text := strings.TrimSuffix(string(terminalservice.StripANSI(data)), "\n")
+		if text == "" {
+			continue
+		}
+		prefix := processPrefix(proc)
+		for line := range strings.SplitSeq(text, "\n") {
+			result.Lines = append(result.Lines, ProcessLogLineJSON{Process: proc.Name, Prefix: prefix, Text: line})
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) terminalService() (TerminalService, error) {
+	if s.terminals != nil {
+		return s.terminals, nil
+	}
+	socketPath, err := terminalservice.DefaultSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	return terminalservice.NewClient(socketPath), nil
+}
+
+// resolveProcesses loads and validates a thread's processes. With names it
+// keeps only those, plus what they depend on when withDeps is set.
+func (s *Service) resolveProcesses(ctx context.Context, selector WorkspaceSelector, names []string, withDeps bool) (processTarget, error) {
+	target, err := s.resolveExecTarget(ctx, selector)
+	if err != nil {
+		return processTarget{}, err
+	}
+	procs, err := orderProcesses(target.wsConfig.Processes)
+	if err != nil {
+		return processTarget{}, err
+	}
+	if len(procs) == 0 {
+		return processTarget{}, ValidationError{Message: "no processes in thread; add a processes section to " + filepath.Base(target.root) + "/workset.yaml"}
+	}
+	procs, err = selectProcesses(procs, names, withDeps)
+	if err != nil {
+		return processTarget{}, err
+	}
+
+	state, err := s.workspaces.LoadState(ctx, target.root)
+	if err != nil {
+		return processTarget{}, err
+	}
+	branch := state.CurrentBranch
+	if branch == "" {
+		branch = target.cfg.Defaults.BaseBranch
+	}
+	cwds := make(map[string]string, len(procs))
+	for _, proc := range procs {
+		base := target.root
+		if proc.Repo != "" {
+			repos, err := selectExecRepos(target.root, branch, target.wsConfig, []string{proc.Repo})
+			if err != nil {
+				return processTarget{}, err
+			}
+			base = repos[0].worktreePath
+		}
+		cwds[proc.Name] = filepath.Join(base, filepath.FromSlash(proc.Cwd))
+	}
+	return processTarget{thread: target.name, root: target.root, procs: procs, cwds: cwds}, nil
+}
+
+// orderProcesses validates processes and sorts them so every process comes
+// after its dependencies, otherwise keeping config order.
+func orderProcesses(procs []config.ProcessConfig) ([]config.ProcessConfig, error) {
+	byName := make(map[string]config.ProcessConfig, len(procs))
+	for _, proc := range procs {
+		name := strings.TrimSpace(proc.Name)
+		switch {
+		case name == "":
+			return nil, ValidationError{Message: "process name required"}
+		case strings.TrimSpace(proc.Command) == "":
+			return nil, ValidationError{Message: fmt.Sprintf("process %q: command required", name)}
+		case byName[name].Name != "":
+			return nil, ValidationError{Message: fmt.Sprintf("duplicate process %q", name)}
+		case filepath.IsAbs(proc.Cwd) || strings.HasPrefix(filepath.Clean(filepath.FromSlash(proc.Cwd)), ".."):
+			return nil, ValidationError{Message: fmt.Sprintf("process %q: cwd must stay inside its repo or the thread", name)}
+		}
+		if proc.Ready != "" {
+			if _, err := regexp.Compile(proc.Ready); err != nil {
+				return nil, ValidationError{Message: fmt.Sprintf("process %q: invalid ready pattern: %v", name, err)}
+			}
+		}
+		switch proc.Restart {
+		case "", terminalservice.RestartNever, terminalservice.RestartOnFailure, terminalservice.RestartAlways:
+		default:
+			return nil, ValidationError{Message: fmt.Sprintf("process %q: restart must be never, on-failure, or always", name)}
+		}
+		proc.Name = name
+		byName[name] = proc
+	}
+
+	ordered := make([]config.ProcessConfig, 0, len(procs))
+	const visiting, done = 1, 2
+	marks := map[string]int{}
+	var visit func(name string, from string) error
+	visit = func(name string, from string) error {
+		proc, ok := byName[name]
+		if !ok {
+			return ValidationError{Message: fmt.Sprintf("process %q depends on unknown process %q", from, name)}
+		}
+		switch marks[name] {
+		case done:
+			return nil
+		case visiting:
+			return ValidationError{Message: fmt.Sprintf("process dependency cycle through %q", name)}
+		}
+		marks[name] = visiting
+		for _, dep := range proc.DependsOn {
+			if err := visit(strings.TrimSpace(dep), name); err != nil {
+				return err
+			}
+		}
+		marks[name] = done
+		ordered = append(ordered, proc)
+		return nil
+	}
+	for _, proc := range procs {
+		if err := visit(strings.TrimSpace(proc.Name), ""); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func selectProcesses(ordered []config.ProcessConfig, names []string, withDeps bool) ([]config.ProcessConfig, error) {
+	if len(names) == 0 {
+		return ordered, nil
+	}
+	byName := make(map[string]config.ProcessConfig, len(ordered))
+	for _, proc := range ordered {
+		byName[proc.Name] = proc
+	}
+	keep := map[string]bool{}
+	var add func(name string)
+	add = func(name string) {
+		if keep[name] {
+			return
+		}
+		keep[name] = true
+		if withDeps {
+			for _, dep := range byName[name].DependsOn {
+				add(strings.TrimSpace(dep))
+			}
+		}
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := byName[name]; !ok {
+			return nil, NotFoundError{Message: fmt.Sprintf("process %q not found in thread", name)}
+		}
+		add(name)
+	}
+	selected := make([]config.ProcessConfig, 0, len(keep))
+	for _, proc := range ordered {
+		if keep[proc.Name] {
+			selected = append(selected, proc)
+		}
+	}
+	return selected, nil
+}
+
+// waitProcessReady polls a process's output until its ready pattern
+// matches. A process without a pattern is ready once it has started.
+func waitProcessReady(ctx context.Context, terminals TerminalService, sessionID string, proc config.ProcessConfig, timeout time.Duration) error {
+	if proc.Ready == "" {
+		return nil
+	}
+	pattern := regexp.MustCompile(proc.Ready)
+	deadline := time.Now().Add(timeout)
+	var offset int64
+	var window []byte
+	for {
+		resp, err := terminals.Read(ctx, terminalservice.ReadRequest{SessionID: sessionID, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("wait for process %q: %w", proc.Name, terminalServiceError(err))
+		}
+		data, err := base64.StdEncoding.DecodeString(resp.DataB64)
+		if err != nil {
+			return err
+		}
+		offset = resp.NextOffset
+		window = append(window, terminalservice.StripANSI(data)...)
+		if len(window) > processReadyWindow {
+			window = window[len(window)-processReadyWindow:]
+		}
+		if pattern.Match(window) {
+			return nil
+		}
+		info, err := terminals.Inspect(ctx, sessionID)
+		if err == nil && !info.Running {
+			code := -1
+			if info.ExitCode != nil {
+				code = *info.ExitCode
+			}
+			return fmt.Errorf("process %q exited with code %d before it was ready", proc.Name, code)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("process %q not ready after %s: no output matched %q", proc.Name, timeout, proc.Ready)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(processReadyPoll):
+		}
+	}
+}
+
+func (s *Service) processStatuses(ctx context.Context, terminals TerminalService, target processTarget) ProcessesResult {
+	result := ProcessesResult{Processes: make([]ProcessStatusJSON, 0, len(target.procs))}
+	serviceDown := false
+	for _, proc := range target.procs {
+		sessionID := processSessionID(target.thread, proc.Name)
+		status := ProcessStatusJSON{
+			Name:      proc.Name,
+			Repo:      proc.Repo,
+			Command:   proc.Command,
+			SessionID: sessionID,
+			State:     ProcessStateStopped,
+		}
+		if !serviceDown {
+			info, err := terminals.Inspect(ctx, sessionID)
+			switch {
+			case err == nil:
+				status.State = ProcessStateExited
+				if info.Running {
+					status.State = ProcessStateRunning
+				}
+				status.Restarts = info.Restarts
+				status.ExitCode = info.ExitCode
+				status.StartedAt = info.StartedAt
+				status.ExitedAt = info.ExitedAt
+			case isTerminalServiceDown(err):
+				serviceDown = true
+				result.Warnings = append(result.Warnings, "terminal service is not running")
+			}
+		}
+		result.Processes = append(result.Processes, status)
+	}
+	return result
+}
+
+func processSessionID(thread, name string) string {
+	return thread + "::process:" + name
+}
+
+func processPrefix(proc config.ProcessConfig) string {
+	if proc.Repo == "" {
+		return proc.Name
+	}
+	return proc.Repo + "/" + proc.Name
+}
+
+// isTerminalServiceDown reports whether err means nothing is listening on
+// the terminal service socket.
+func isTerminalServiceDown(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func terminalServiceError(err error) error {
+	if isTerminalServiceDown(err) {
+		return fmt.Errorf("terminal service is not running; open the desktop app or run `workset term serve`: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,247 @@
+package worksetapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/session"
+	"github.com/strantalis/workset/internal/workspace"
+	"github.com/strantalis/workset/pkg/terminalservice"
+)
+
+type fakeTerminals struct {
+	mu       sync.Mutex
+	created  []terminalservice.CreateRequest
+	stopped  []string
+	running  map[string]bool
+	output   map[string]string
+	failures map[string]error
+	// crash marks sessions whose command exits as soon as it starts.
+	crash map[string]bool
+}
+
+func newFakeTerminals() *fakeTerminals {
+	return &fakeTerminals{running: map[string]bool{}, output: map[string]string{}, failures: map[string]error{}, crash: map[string]bool{}}
+}
+
+func (f *fakeTerminals) CreateSession(_ context.Context, req terminalservice.CreateRequest) (terminalservice.CreateResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failures["create"]; err != nil {
+		return terminalservice.CreateResponse{}, err
+	}
+	f.created = append(f.created, req)
+	f.running[req.SessionID] = !f.crash[req.SessionID]
+	return terminalservice.CreateResponse{SessionID: req.SessionID}, nil
+}
+
+func (f *fakeTerminals) Inspect(_ context.Context, sessionID string) (terminalservice.InspectResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.failures["inspect"]; err != nil {
+		return terminalservice.InspectResponse{}, err
+	}
+	running, ok := f.running[sessionID]
+	if !ok {
+		return terminalservice.InspectResponse{}, errors.New("session not found")
+	}
+	info := terminalservice.InspectResponse{SessionID: sessionID, Running: running}
+	if !running {
+		code := 1
+		info.ExitCode = &code
+	}
+	return info, nil
+}
+
+func (f *fakeTerminals) Read(_ context.Context, req terminalservice.ReadRequest) (terminalservice.ReadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.running[req.SessionID]; !ok {
+		return terminalservice.ReadResponse{}, errors.New("session not found")
+	}
+	data := f.output[req.SessionID]
+	start := req.Offset
+	if start > int64(len(data)) {
+		start = int64(len(data))
+	}
+	return terminalservice.ReadResponse{
+		SessionID:  req.SessionID,
+		DataB64:    base64.StdEncoding.EncodeToString([]byte(data[start:])),
+		Offset:     start,
+		NextOffset: int64(len(data)),
+	}, nil
+}
+
+func (f *fakeTerminals) Stop(_ context.Context, sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = append(f.stopped, sessionID)
+	delete(f.running, sessionID)
+	return nil
+}
+
+func setupProcessThread(t *testing.T, procs []config.ProcessConfig) (*testEnv, *fakeTerminals, string) {
+	t.Helper()
+	env, _ := newGittestEnv(t, "api", "web")
+	created, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{Name: "demo", Repos: []string{"api", "web"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	root := created.Workspace.Path
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workset.yaml: %v", err)
+	}
+	wsCfg.Processes = procs
+	if err := config.SaveWorkspace(workspace.WorksetFile(root), wsCfg); err != nil {
+		t.Fatalf("save workset.yaml: %v", err)
+	}
+	terminals := newFakeTerminals()
+	env.svc.terminals = terminals
+	return env, terminals, root
+}
+
+func TestProcessesUpStartsInDependencyOrder(t *testing.T) {
+	env, terminals, root := setupProcessThread(t, []config.ProcessConfig{
+		{Name: "web", Command: "npm run dev", Repo: "web", DependsOn: []string{"api"}},
+		{Name: "api", Command: "go run ./cmd/api", Repo: "api", Cwd: "cmd", Env: map[string]string{"PORT": "8080"}, DependsOn: []string{"db"}},
+		{Name: "db", Command: "postgres", Ready: "ready to accept"},
+	})
+	terminals.output["demo::process:db"] = "\x1b[32mLOG\x1b[0m: ready to accept connections\r\n"
+	ctx := context.Background()
+
+	result, err := env.svc.ProcessesUp(ctx, ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	var order []string
+	for _, req := range terminals.created {
+		order = append(order, req.SessionID)
+	}
+	want := []string{"demo::process:db", "demo::process:api", "demo::process:web"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("expected start order %v, got %v", want, order)
+	}
+	api := terminals.created[1]
+	shell, args := session.ShellCommand("go run ./cmd/api")
+	if api.Cwd != filepath.Join(root, "api", "cmd") || api.Env["PORT"] != "8080" || api.Env["WORKSET_REPO"] != "api" ||
+		api.Restart != terminalservice.RestartOnFailure || api.Command != shell || !slices.Equal(api.Args, args) {
+		t.Fatalf("unexpected api session request %+v", api)
+	}
+	if db := terminals.created[0]; db.Cwd != root {
+		t.Fatalf("expected repo-less process to run in the thread root, got %q", db.Cwd)
+	}
+	for _, proc := range result.Processes {
+		if proc.State != ProcessStateRunning {
+			t.Fatalf("expected %s running, got %+v", proc.Name, proc)
+		}
+	}
+
+	terminals.output["demo::process:api"] = "listening on :8080\nGET /health\npartial"
+	logs, err := env.svc.ProcessLogs(ctx, ProcessLogsInput{Workspace: WorkspaceSelector{Value: "demo"}, Names: []string{"api"}, Follow: true})
+	if err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	if len(logs.Lines) != 2 || logs.Lines[0].Prefix != "api/api" || logs.Lines[1].Text != "GET /health" {
+		t.Fatalf("unexpected log lines %+v", logs.Lines)
+	}
+	if next := logs.NextOffsets["api"]; next != int64(len("listening on :8080\nGET /health\n")) {
+		t.Fatalf("expected follow to hold back the partial line, got offset %d", next)
+	}
+
+	down, err := env.svc.ProcessesDown(ctx, ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	slices.Reverse(want)
+	if !slices.Equal(terminals.stopped, want) {
+		t.Fatalf("expected stop order %v, got %v", want, terminals.stopped)
+	}
+	for _, proc := range down.Processes {
+		if proc.State != ProcessStateStopped {
+			t.Fatalf("expected %s stopped, got %+v", proc.Name, proc)
+		}
+	}
+}
+
+func TestProcessesUpSelectsDependencies(t *testing.T) {
+	env, terminals, _ := setupProcessThread(t, []config.ProcessConfig{
+		{Name: "db", Command: "postgres"},
+		{Name: "api", Command: "go run .", Repo: "api", DependsOn: []string{"db"}},
+		{Name: "web", Command: "npm run dev", Repo: "web"},
+	})
+	ctx := context.Background()
+	result, err := env.svc.ProcessesUp(ctx, ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}, Names: []string{"api"}})
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(terminals.created) != 2 || len(result.Processes) != 2 || result.Processes[1].Name != "api" {
+		t.Fatalf("expected db and api only, got %+v", result.Processes)
+	}
+	_, err = env.svc.ProcessesUp(ctx, ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}, Names: []string{"worker"}})
+	var notFound NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected not found for unknown process, got %v", err)
+	}
+}
+
+func TestProcessesUpFailsWhenDependencyExits(t *testing.T) {
+	env, terminals, _ := setupProcessThread(t, []config.ProcessConfig{
+		{Name: "db", Command: "postgres", Ready: "ready"},
+		{Name: "api", Command: "go run .", DependsOn: []string{"db"}},
+	})
+	terminals.output["demo::process:db"] = "FATAL: port in use\n"
+	terminals.crash["demo::process:db"] = true
+	_, err := env.svc.ProcessesUp(context.Background(), ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}})
+	if err == nil || !strings.Contains(err.Error(), "before it was ready") {
+		t.Fatalf("expected early exit error, got %v", err)
+	}
+	if len(terminals.created) != 1 {
+		t.Fatalf("expected api not to start, got %d sessions", len(terminals.created))
+	}
+}
+
+func TestProcessStatusWithoutTerminalService(t *testing.T) {
+	env, terminals, _ := setupProcessThread(t, []config.ProcessConfig{{Name: "db", Command: "postgres"}})
+	terminals.failures["inspect"] = &net.OpError{Op: "dial", Net: "unix", Err: errors.New("connection refused")}
+	result, err := env.svc.ProcessStatus(context.Background(), ProcessesInput{Workspace: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("ps: %v", err)
+	}
+	if len(result.Processes) != 1 || result.Processes[0].State != ProcessStateStopped || len(result.Warnings) != 1 {
+		t.Fatalf("expected stopped process with a warning, got %+v", result)
+	}
+}
+
+func TestOrderProcessesValidates(t *testing.T) {
+	cases := []struct {
+		name  string
+		procs []config.ProcessConfig
+		want  string
+	}{
+		{"missing command", []config.ProcessConfig{{Name: "db"}}, "command required"},
+		{"duplicate", []config.ProcessConfig{{Name: "db", Command: "a"}, {Name: "db", Command: "b"}}, "duplicate"},
+		{"unknown dep", []config.ProcessConfig{{Name: "api", Command: "a", DependsOn: []string{"db"}}}, "unknown process"},
+		{"cycle", []config.ProcessConfig{
+			{Name: "a", Command: "a", DependsOn: []string{"b"}},
+			{Name: "b", Command: "b", DependsOn: []string{"a"}},
+		}, "cycle"},
+		{"bad ready", []config.ProcessConfig{{Name: "db", Command: "a", Ready: "("}}, "ready pattern"},
+		{"escaping cwd", []config.ProcessConfig{{Name: "db", Command: "a", Cwd: "../other"}}, "cwd"},
+	}
+	for _, tc := range cases {
+		_, err := orderProcesses(tc.procs)
+		var validation ValidationError
+		if !errors.As(err, &validation) || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected validation error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
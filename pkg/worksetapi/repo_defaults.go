@@ -7,32 +7,31 @@ import (
 	"github.com/strantalis/workset/internal/ops"
 )
 
-func resolveRepoDefaults(cfg config.GlobalConfig, repoName string) ops.RepoDefaults {
-	alias, ok := cfg.Repos[repoName]
-	if !ok {
-		return ops.RepoDefaults{
-			Remote:        cfg.Defaults.Remote,
-			DefaultBranch: cfg.Defaults.BaseBranch,
-		}
-	}
-	remote := strings.TrimSpace(alias.Remote)
-	if remote == "" {
-		remote = cfg.Defaults.Remote
+// resolveRepoDefaults returns the remote and base branch for a thread repo:
+// the repo's recorded base_branch wins over its alias and the global default.
+func resolveRepoDefaults(cfg config.GlobalConfig, repo config.RepoConfig) ops.RepoDefaults {
+	defaults := ops.RepoDefaults{
+		Remote:        cfg.Defaults.Remote,
+		DefaultBranch: cfg.Defaults.BaseBranch,
 	}
-	branch := strings.TrimSpace(alias.DefaultBranch)
-	if branch == "" {
-		branch = cfg.Defaults.BaseBranch
+	if alias, ok := cfg.Repos[repo.Name]; ok {
+		if remote := strings.TrimSpace(alias.Remote); remote != "" {
+			defaults.Remote = remote
+		}
+		if branch := strings.TrimSpace(alias.DefaultBranch); branch != "" {
+			defaults.DefaultBranch = branch
+		}
 	}
-	return ops.RepoDefaults{
-		Remote:        remote,
-		DefaultBranch: branch,
+	if branch := strings.TrimSpace(repo.BaseBranch); branch != "" {
+		defaults.DefaultBranch = branch
 	}
+	return defaults
 }
 
 func repoDefaultBranches(ws config.WorkspaceConfig, cfg config.GlobalConfig) map[string]string {
 	branches := make(map[string]string, len(ws.Repos))
 	for _, repo := range ws.Repos {
-		branches[repo.Name] = resolveRepoDefaults(cfg, repo.Name).DefaultBranch
+		branches[repo.Name] = resolveRepoDefaults(cfg, repo).DefaultBranch
 	}
 	return branches
 }
@@ -40,7 +39,7 @@ func repoDefaultBranches(ws config.WorkspaceConfig, cfg config.GlobalConfig) map
 func repoDefaultsMap(ws config.WorkspaceConfig, cfg config.GlobalConfig) map[string]ops.RepoDefaults {
 	defaults := make(map[string]ops.RepoDefaults, len(ws.Repos))
 	for _, repo := range ws.Repos {
-		defaults[repo.Name] = resolveRepoDefaults(cfg, repo.Name)
+		defaults[repo.Name] = resolveRepoDefaults(cfg, repo)
 	}
 	return defaults
 }
@@ -0,0 +1,136 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/strantalis/workset/internal/ops"
+)
+
+// repoStartOptions is a RepoStart resolved into the ops.AddRepoInput fields
+// that control how a repo's worktree branch is created.
+type repoStartOptions struct {
+	FromRef        string
+	ExistingBranch bool
+	Branch         string
+	BaseBranch     string
+}
+
+func (o repoStartOptions) apply(input *ops.AddRepoInput) {
+	input.FromRef = o.FromRef
+	input.ExistingBranch = o.ExistingBranch
+	input.Branch = o.Branch
+	input.BaseBranch = o.BaseBranch
+}
+
+func validateRepoStart(start RepoStart) error {
+	if start.PullRequest < 0 {
+		return ValidationError{Message: "pull request number must be positive"}
+	}
+	set := 0
+	if strings.TrimSpace(start.From) != "" {
+		set++
+	}
+	if start.ExistingBranch {
+		set++
+	}
+	if strings.TrimSpace(start.Branch) != "" {
+		set++
+	}
+	if start.PullRequest > 0 {
+		set++
+	}
+	if set > 1 {
+		return ValidationError{Message: "choose only one of a start ref, an existing branch, or a pull request"}
+	}
+	return nil
+}
+
+// resolveRepoStart turns start into worktree options for a repo cloned from
+// url or linked from sourcePath. Pull requests are looked up with the hosting
+// provider for the repo's remote; their head branch must live on that remote.
+func (s *Service) resolveRepoStart(ctx context.Context, start RepoStart, url, sourcePath, remote string) (repoStartOptions, error) {
+	if err := validateRepoStart(start); err != nil {
+		return repoStartOptions{}, err
+	}
+	if branch := strings.TrimSpace(start.Branch); branch != "" {
+		return repoStartOptions{ExistingBranch: true, Branch: branch}, nil
+	}
+	if start.PullRequest == 0 {
+		return repoStartOptions{
+			FromRef:        strings.TrimSpace(start.From),
+			ExistingBranch: start.ExistingBranch,
+		}, nil
+	}
+
+	var (
+		info remoteInfo
+		err  error
+	)
+	if sourcePath != "" {
+		info, err = s.remoteInfoFor(sourcePath, remote)
+	} else {
+		info, err = parseGitHubRemoteURL(url)
+	}
+	if err != nil {
+		return repoStartOptions{}, err
+	}
+	if info.Host == "" {
+		info.Host = defaultGitHubHost
+	}
+	if err := s.validateRemoteHost(ctx, info.Host); err != nil {
+		return repoStartOptions{}, err
+	}
+	client, err := s.githubClient(ctx, info.Host)
+	if err != nil {
+		return repoStartOptions{}, err
+	}
+	pr, err := client.GetPullRequest(ctx, info.Owner, info.Repo, start.PullRequest)
+	if err != nil {
+		return repoStartOptions{}, err
+	}
+	if strings.TrimSpace(pr.HeadRef) == "" {
+		return repoStartOptions{}, ValidationError{Message: fmt.Sprintf("pull request #%d has no head branch", start.PullRequest)}
+	}
+	return repoStartOptions{
+		ExistingBranch: true,
+		Branch:         pr.HeadRef,
+		BaseBranch:     pr.BaseRef,
+	}, nil
+}
+
+// resolveWorkspaceRepoStarts resolves the thread-wide and per-repo start
+// overrides of a CreateWorkspace request onto its repo plans.
+func (s *Service) resolveWorkspaceRepoStarts(ctx context.Context, input WorkspaceCreateInput, plans []repoPlan) error {
+	if input.Start.PullRequest != 0 && len(plans) > 1 {
+		return ValidationError{Message: "a pull request applies to a single repo; name the repo it belongs to"}
+	}
+	names := make(map[string]bool, len(plans))
+	for _, plan := range plans {
+		names[plan.Name] = true
+	}
+	unknown := []string{}
+	for name := range input.RepoStarts {
+		if !names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return ValidationError{Message: fmt.Sprintf("start override for repo not in this thread: %s", strings.Join(unknown, ", "))}
+	}
+	for i := range plans {
+		start, ok := input.RepoStarts[plans[i].Name]
+		if !ok {
+			start = input.Start
+		}
+		resolved, err := s.resolveRepoStart(ctx, start, plans[i].URL, plans[i].SourcePath, plans[i].Remote)
+		if err != nil {
+			return err
+		}
+		plans[i].Start = resolved
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package worksetapi
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func testGitDir(t *testing.T, repoPath string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	return filepath.Join(resolved, ".git")
+}
+
+func worktreeAddFor(t *testing.T, adds []git.WorktreeAddOptions, repoDir string) git.WorktreeAddOptions {
+	t.Helper()
+	for _, add := range adds {
+		if filepath.Base(add.WorktreePath) == repoDir {
+			return add
+		}
+	}
+	t.Fatalf("no worktree added for %s in %+v", repoDir, adds)
+	return git.WorktreeAddOptions{}
+}
+
+func TestCreateWorkspaceStartsRepoFromRef(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 2, "repo-a", "repo-b")
+	cfg := env.loadConfig()
+	env.git.refs[refKey(testGitDir(t, cfg.Repos["repo-b"].Path), "refs/heads/demo")] = false
+
+	result, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:       "demo",
+		Repos:      []string{"repo-a", "repo-b"},
+		RepoStarts: map[string]RepoStart{"repo-b": {From: "release/1.2"}},
+	})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if add := worktreeAddFor(t, env.git.worktreeAdds, "repo-a"); add.StartRef != "" {
+		t.Fatalf("expected repo-a to start from its default branch, got %q", add.StartRef)
+	}
+	if add := worktreeAddFor(t, env.git.worktreeAdds, "repo-b"); add.StartRef != "origin/release/1.2" || add.BranchName != "demo" {
+		t.Fatalf("unexpected repo-b worktree add: %+v", add)
+	}
+
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(result.Workspace.Path))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	if wsCfg.Repos[1].BaseBranch != "release/1.2" || wsCfg.Repos[0].BaseBranch != "" {
+		t.Fatalf("unexpected recorded bases: %+v", wsCfg.Repos)
+	}
+	repos, err := env.svc.ListRepos(context.Background(), WorkspaceSelector{Value: "demo"})
+	if err != nil {
+		t.Fatalf("ListRepos: %v", err)
+	}
+	if repos.Repos[1].DefaultBranch != "release/1.2" {
+		t.Fatalf("expected repo-b base release/1.2, got %q", repos.Repos[1].DefaultBranch)
+	}
+}
+
+func TestAddRepoChecksOutPullRequestHead(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	root := env.createWorkspace(ctx, "demo")
+	local := env.createLocalRepo("repo-a")
+	gitDir := testGitDir(t, local)
+	env.git.remoteURLs[local] = map[string][]string{"origin": {"git@github.com:acme/app.git"}}
+	env.git.refs[refKey(gitDir, "refs/heads/fix/login")] = false
+	client := &readHelpersGitHubClient{
+		getPullRequestFunc: func(_ context.Context, _, _ string, number int) (GitHubPullRequest, error) {
+			return GitHubPullRequest{Number: number, HeadRef: "fix/login", BaseRef: "release"}, nil
+		},
+	}
+	env.svc.github = &readHelpersGitHubProvider{client: client}
+
+	if _, err := env.svc.AddRepo(ctx, RepoAddInput{
+		Workspace:  WorkspaceSelector{Value: root},
+		SourcePath: local,
+		Start:      RepoStart{PullRequest: 42},
+	}); err != nil {
+		t.Fatalf("AddRepo: %v", err)
+	}
+	if len(client.getPullRequestCalls) != 1 || client.getPullRequestCalls[0] != (readHelpersGetPullRequestCall{owner: "acme", repo: "app", number: 42}) {
+		t.Fatalf("unexpected pull request lookups: %+v", client.getPullRequestCalls)
+	}
+	add := worktreeAddFor(t, env.git.worktreeAdds, "repo-a")
+	if add.BranchName != "fix/login" || add.StartRef != "origin/fix/login" {
+		t.Fatalf("unexpected worktree add: %+v", add)
+	}
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	if got := wsCfg.Repos[0]; got.Branch != "fix/login" || got.BaseBranch != "release" {
+		t.Fatalf("unexpected repo config: %+v", got)
+	}
+}
+
+func TestCreateWorkspaceRepoStartValidation(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a", "repo-b")
+	cases := map[string]WorkspaceCreateInput{
+		"pull request for every repo": {Start: RepoStart{PullRequest: 7}},
+		"unknown repo override":       {RepoStarts: map[string]RepoStart{"repo-z": {From: "main"}}},
+		"conflicting start":           {Start: RepoStart{From: "main", ExistingBranch: true}},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			input.Name = "demo"
+			input.Repos = []string{"repo-a", "repo-b"}
+			_, err := env.svc.CreateWorkspace(context.Background(), input)
+			_ = requireErrorType[ValidationError](t, err)
+			if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+				t.Fatalf("thread registered despite invalid start")
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/repostore"
+)
+
+const (
+	repoStoreActionCloned    = "cloned"
+	repoStoreActionFetched   = "fetched"
+	repoStoreActionCollected = "collected"
+	repoStoreActionOrphaned  = "orphaned"
+	repoStoreActionRemoved   = "removed"
+	repoStoreActionVerified  = "ok"
+	repoStoreActionMissing   = "missing"
+	repoStoreActionFailed    = "failed"
+)
+
+// RepoStoreStatus reports the mirror of every URL-registered repo in
+// defaults.repo_store_root with its size, last fetch time, and the number of
+// worktrees it serves.
+func (s *Service) RepoStoreStatus(ctx context.Context) (RepoStoreResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	names, err := repoStoreNames(cfg, nil)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	result := RepoStoreResult{Root: cfg.Defaults.RepoStoreRoot, Repos: []RepoStoreEntryJSON{}, Config: info}
+	for _, name := range names {
+		entry, warning := s.describeStoreMirror(cfg, name)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+		result.Repos = append(result.Repos, entry)
+	}
+	return result, nil
+}
+
+// FetchRepoStore clones missing mirrors and fetches existing ones for the
+// selected URL-registered repos (all of them when input.Repos is empty),
+// running at most defaults.provision_parallelism at once. Failures are
+// reported per repo rather than stopping the others.
+func (s *Service) FetchRepoStore(ctx context.Context, input RepoStoreFetchInput) (RepoStoreResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	if strings.TrimSpace(cfg.Defaults.RepoStoreRoot) == "" {
+		return RepoStoreResult{}, ValidationError{Message: "defaults.repo_store_root required for the repo store"}
+	}
+	names, err := repoStoreNames(cfg, input.Repos)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	limit := cfg.Defaults.ProvisionParallelism
+	if limit <= 0 {
+		limit = 1
+	}
+	entries := make([]RepoStoreEntryJSON, len(names))
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			entries[i] = s.fetchStoreMirror(ctx, cfg, name)
+		}()
+	}
+	wg.Wait()
+
+	result := RepoStoreResult{Root: cfg.Defaults.RepoStoreRoot, Repos: entries, Config: info}
+	for _, entry := range entries {
+		if entry.Error != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", entry.Name, entry.Error))
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) fetchStoreMirror(ctx context.Context, cfg config.GlobalConfig, name string) RepoStoreEntryJSON {
+	alias := cfg.Repos[name]
+	remote := alias.Remote
+	if remote == "" {
+		remote = cfg.Defaults.Remote
+	}
+	path := repostore.MirrorPath(cfg.Defaults.RepoStoreRoot, name)
+	entry := RepoStoreEntryJSON{Name: name, URL: alias.URL, Path: path}
+	gitDir, _, ok := repostore.GitDir(path)
+	if ok {
+		if err := s.git.Fetch(ctx, gitDir, remote); err != nil {
+			entry.Action = repoStoreActionFailed
+			entry.Error = fmt.Sprintf("fetch %s: %v", remote, err)
+			return s.finishStoreEntry(cfg, entry)
+		}
+		entry.Action = repoStoreActionFetched
+	} else {
+		if err := s.git.CloneBare(ctx, alias.URL, path, remote); err != nil {
+			entry.Action = repoStoreActionFailed
+			entry.Error = fmt.Sprintf("clone %s: %v", alias.URL, err)
+			return entry
+		}
+		gitDir = path
+		entry.Action = repoStoreActionCloned
+	}
+	if err := repostore.RecordFetch(gitDir, alias.URL, s.clock()); err != nil {
+		entry.Error = fmt.Sprintf("record fetch: %v", err)
+	}
+	return s.finishStoreEntry(cfg, entry)
+}
+
+// finishStoreEntry fills in the on-disk details of a mirror while keeping the
+// action and error already set on entry.
+func (s *Service) finishStoreEntry(cfg config.GlobalConfig, entry RepoStoreEntryJSON) RepoStoreEntryJSON {
+	described, _ := s.describeStoreMirror(cfg, entry.Name)
+	described.Action = entry.Action
+	if entry.Error != "" {
+		described.Error = entry.Error
+	}
+	return described
+}
+
+// GCRepoStore prunes stale worktree metadata and repacks every mirror, and
+// reports store directories that no registered repo or thread uses. With
+// input.PruneOrphans those directories are deleted.
+func (s *Service) GCRepoStore(ctx context.Context, input RepoStoreGCInput) (RepoStoreResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	names, err := repoStoreNames(cfg, nil)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	root := cfg.Defaults.RepoStoreRoot
+	result := RepoStoreResult{Root: root, Repos: []RepoStoreEntryJSON{}, Config: info}
+	for _, name := range names {
+		entry, warning := s.describeStoreMirror(cfg, name)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+		if !entry.Present {
+			continue
+		}
+		if !input.DryRun {
+			if err := ctx.Err(); err != nil {
+				return RepoStoreResult{}, err
+			}
+			gitDir, _, _ := repostore.GitDir(entry.Path)
+			if err := s.git.GC(ctx, gitDir); err != nil {
+				entry.Action = repoStoreActionFailed
+				entry.Error = err.Error()
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: gc: %v", name, err))
+				result.Repos = append(result.Repos, entry)
+				continue
+			}
+			entry, _ = s.describeStoreMirror(cfg, name)
+		}
+		entry.Action = repoStoreActionCollected
+		result.Repos = append(result.Repos, entry)
+	}
+
+	orphans, err := s.repoStoreOrphans(ctx, cfg, names)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	for _, path := range orphans {
+		entry := RepoStoreEntryJSON{Name: filepath.Base(path), Path: path, Present: true, Action: repoStoreActionOrphaned}
+		if size, err := repostore.Size(path); err == nil {
+			entry.SizeBytes = size
+		}
+		if input.PruneOrphans && !input.DryRun {
+			if err := os.RemoveAll(path); err != nil {
+				entry.Error = err.Error()
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: remove orphan: %v", entry.Name, err))
+			} else {
+				entry.Action = repoStoreActionRemoved
+				entry.Present = false
+			}
+		}
+		result.Repos = append(result.Repos, entry)
+	}
+	return result, nil
+}
+
+// repoStoreOrphans lists directories under the store root that are neither
+// the mirror of a URL-registered repo nor the local path of any thread repo.
+func (s *Service) repoStoreOrphans(ctx context.Context, cfg config.GlobalConfig, mirrors []string) ([]string, error) {
+	root := strings.TrimSpace(cfg.Defaults.RepoStoreRoot)
+	if root == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, name := range mirrors {
+		used[filepath.Clean(repostore.MirrorPath(root, name))] = true
+	}
+	for _, repo := range cfg.Repos {
+		if repo.Path != "" {
+			used[filepath.Clean(repo.Path)] = true
+		}
+	}
+	for name, ref := range cfg.Workspaces {
+		if ref.Path == "" {
+			continue
+		}
+		wsConfig, err := s.workspaces.LoadConfig(ctx, ref.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("load thread %s: %w", name, err)
+		}
+		for _, repo := range wsConfig.Repos {
+			if repo.LocalPath != "" {
+				used[filepath.Clean(repo.LocalPath)] = true
+			}
+		}
+	}
+	orphans := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(root, entry.Name())
+		if abs, err := filepath.Abs(path); err == nil && (used[filepath.Clean(path)] || used[abs]) {
+			continue
+		}
+		gitDir, _, ok := repostore.GitDir(path)
+		if !ok {
+			continue
+		}
+		// A mirror that still serves worktrees is in use even if no config
+		// mentions it.
+		if worktrees, err := s.git.WorktreeList(gitDir); err != nil || len(worktrees) > 0 {
+			continue
+		}
+		orphans = append(orphans, path)
+	}
+	return orphans, nil
+}
+
+// VerifyRepoStore checks that each selected mirror (all when input.Repos is
+// empty) exists, has every object reachable from its refs, and still points
+// at its registered URL.
+func (s *Service) VerifyRepoStore(ctx context.Context, input RepoStoreVerifyInput) (RepoStoreResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	names, err := repoStoreNames(cfg, input.Repos)
+	if err != nil {
+		return RepoStoreResult{}, err
+	}
+	result := RepoStoreResult{Root: cfg.Defaults.RepoStoreRoot, Repos: []RepoStoreEntryJSON{}, Config: info}
+	for _, name := range names {
+		entry, _ := s.describeStoreMirror(cfg, name)
+		entry.Action = repoStoreActionVerified
+		if !entry.Present {
+			entry.Action = repoStoreActionMissing
+		} else if problem := s.verifyStoreMirror(ctx, cfg, entry); problem != "" {
+			entry.Action = repoStoreActionFailed
+			entry.Error = problem
+		}
+		result.Repos = append(result.Repos, entry)
+	}
+	return result, nil
+}
+
+func (s *Service) verifyStoreMirror(ctx context.Context, cfg config.GlobalConfig, entry RepoStoreEntryJSON) string {
+	gitDir, _, ok := repostore.GitDir(entry.Path)
+	if !ok {
+		return ""
+	}
+	if err := s.git.Fsck(ctx, gitDir); err != nil {
+		return fmt.Sprintf("fsck: %v", err)
+	}
+	remote := cfg.Repos[entry.Name].Remote
+	if remote == "" {
+		remote = cfg.Defaults.Remote
+	}
+	urls, err := s.git.RemoteURLs(gitDir, remote)
+	if err != nil {
+		return fmt.Sprintf("read remote %s: %v", remote, err)
+	}
+	if len(urls) > 0 && !slices.Contains(urls, entry.URL) {
+		return fmt.Sprintf("remote %s points at %s, registered url is %s", remote, strings.Join(urls, ", "), entry.URL)
+	}
+	return ""
+}
+
+// describeStoreMirror reports what is on disk for a registered repo's mirror.
+// The returned warning describes metadata that could not be read.
+func (s *Service) describeStoreMirror(cfg config.GlobalConfig, name string) (RepoStoreEntryJSON, string) {
+	alias := cfg.Repos[name]
+	path := repostore.MirrorPath(cfg.Defaults.RepoStoreRoot, name)
+	entry := RepoStoreEntryJSON{Name: name, URL: alias.URL, Path: path}
+	gitDir, bare, ok := repostore.GitDir(path)
+	if !ok {
+		return entry, ""
+	}
+	entry.Present = true
+	entry.Bare = bare
+	if size, err := repostore.Size(path); err == nil {
+		entry.SizeBytes = size
+	}
+	if worktrees, err := s.git.WorktreeList(gitDir); err == nil {
+		entry.Worktrees = len(worktrees)
+	}
+	meta, err := repostore.ReadMetadata(gitDir)
+	if err != nil {
+		return entry, fmt.Sprintf("%s: %v", name, err)
+	}
+	if !meta.LastFetch.IsZero() {
+		entry.LastFetch = meta.LastFetch.Format(time.RFC3339)
+	}
+	return entry, ""
+}
+
+// repoStoreNames returns the URL-registered repos the store manages, sorted,
+// limited to selected when it is non-empty.
+func repoStoreNames(cfg config.GlobalConfig, selected []string) ([]string, error) {
+	names := make([]string, 0, len(cfg.Repos))
+	for name, repo := range cfg.Repos {
+		if strings.TrimSpace(repo.URL) != "" && strings.TrimSpace(repo.Path) == "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(selected) == 0 {
+		return names, nil
+	}
+	filtered := make([]string, 0, len(selected))
+	for _, name := range selected {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(names, name) {
+			if _, ok := cfg.Repos[name]; ok {
+				return nil, ValidationError{Message: fmt.Sprintf("repo %q is registered by local path and is not in the repo store", name)}
+			}
+			return nil, NotFoundError{Message: fmt.Sprintf("registered repo %q not found", name)}
+		}
+		if !slices.Contains(filtered, name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered, nil
+}
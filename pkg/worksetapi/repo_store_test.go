@@ -0,0 +1,168 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+)
+
+func registerStoreRepos(env *testEnv, names ...string) {
+	cfg := env.loadConfig()
+	cfg.Repos = map[string]config.RegisteredRepo{
+		"local": {Path: env.createLocalRepo("local")},
+	}
+	for _, name := range names {
+		cfg.Repos[name] = config.RegisteredRepo{URL: "https://example.com/" + name + ".git"}
+	}
+	env.saveConfig(cfg)
+}
+
+func storeEntries(entries []RepoStoreEntryJSON) map[string]RepoStoreEntryJSON {
+	byName := map[string]RepoStoreEntryJSON{}
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+	return byName
+}
+
+func TestFetchRepoStoreClonesThenFetchesURLRepos(t *testing.T) {
+	env := newTestEnv(t)
+	registerStoreRepos(env, "alpha", "beta")
+	ctx := context.Background()
+
+	status, err := env.svc.RepoStoreStatus(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if len(status.Repos) != 2 || status.Repos[0].Name != "alpha" || status.Repos[0].Present {
+		t.Fatalf("expected two missing url repos, got %+v", status.Repos)
+	}
+
+	first, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{})
+	if err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	for _, entry := range first.Repos {
+		if entry.Action != repoStoreActionCloned || !entry.Present || !entry.Bare {
+			t.Fatalf("expected %s cloned as a bare mirror, got %+v", entry.Name, entry)
+		}
+		if entry.LastFetch != env.now.Format(time.RFC3339) {
+			t.Fatalf("expected last fetch recorded for %s, got %q", entry.Name, entry.LastFetch)
+		}
+	}
+	if len(env.git.fetches) != 0 {
+		t.Fatalf("expected clones to skip fetch, got %v", env.git.fetches)
+	}
+
+	second, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{Repos: []string{"beta"}})
+	if err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+	if len(second.Repos) != 1 || second.Repos[0].Action != repoStoreActionFetched {
+		t.Fatalf("expected beta fetched, got %+v", second.Repos)
+	}
+	if want := filepath.Join(env.repoRoot, "beta") + "|origin"; len(env.git.fetches) != 1 || env.git.fetches[0] != want {
+		t.Fatalf("expected fetch %s, got %v", want, env.git.fetches)
+	}
+
+	if _, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{Repos: []string{"local"}}); err == nil {
+		t.Fatalf("expected local-path repo to be rejected")
+	}
+}
+
+func TestCreateWorkspaceSkipsFetchForFreshMirror(t *testing.T) {
+	env := newTestEnv(t)
+	registerStoreRepos(env, "alpha")
+	cfg := env.loadConfig()
+	cfg.Defaults.RepoStore.FetchMaxAge = "15m"
+	env.saveConfig(cfg)
+	ctx := context.Background()
+
+	if _, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{}); err != nil {
+		t.Fatalf("prefetch: %v", err)
+	}
+	if _, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"alpha"}}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if len(env.git.fetches) != 0 {
+		t.Fatalf("expected fresh mirror to skip fetch, got %v", env.git.fetches)
+	}
+	if len(env.git.worktreeAdds) != 1 || env.git.worktreeAdds[0].RepoPath != filepath.Join(env.repoRoot, "alpha") {
+		t.Fatalf("expected worktree added from the mirror, got %+v", env.git.worktreeAdds)
+	}
+}
+
+func TestVerifyRepoStoreReportsProblems(t *testing.T) {
+	env := newTestEnv(t)
+	registerStoreRepos(env, "alpha", "beta", "gamma")
+	ctx := context.Background()
+	if _, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{Repos: []string{"alpha", "beta"}}); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	env.git.fsckErr = map[string]error{filepath.Join(env.repoRoot, "beta"): errors.New("missing blob")}
+
+	result, err := env.svc.VerifyRepoStore(ctx, RepoStoreVerifyInput{})
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	byName := storeEntries(result.Repos)
+	if byName["alpha"].Action != repoStoreActionVerified {
+		t.Fatalf("expected alpha ok, got %+v", byName["alpha"])
+	}
+	if byName["beta"].Action != repoStoreActionFailed || byName["beta"].Error == "" {
+		t.Fatalf("expected beta failed, got %+v", byName["beta"])
+	}
+	if byName["gamma"].Action != repoStoreActionMissing {
+		t.Fatalf("expected gamma missing, got %+v", byName["gamma"])
+	}
+}
+
+func TestGCRepoStoreCollectsMirrorsAndPrunesOrphans(t *testing.T) {
+	env := newTestEnv(t)
+	registerStoreRepos(env, "alpha")
+	ctx := context.Background()
+	if _, err := env.svc.FetchRepoStore(ctx, RepoStoreFetchInput{}); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	orphan := filepath.Join(env.repoRoot, "retired")
+	if err := os.MkdirAll(filepath.Join(orphan, ".git"), 0o755); err != nil {
+		t.Fatalf("create orphan: %v", err)
+	}
+
+	dryRun, err := env.svc.GCRepoStore(ctx, RepoStoreGCInput{DryRun: true, PruneOrphans: true})
+	if err != nil {
+		t.Fatalf("gc dry run: %v", err)
+	}
+	if len(env.git.gcs) != 0 {
+		t.Fatalf("expected dry run to skip gc, got %v", env.git.gcs)
+	}
+	if entry := storeEntries(dryRun.Repos)["retired"]; entry.Action != repoStoreActionOrphaned {
+		t.Fatalf("expected retired reported as orphan, got %+v", dryRun.Repos)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Fatalf("expected dry run to keep orphan: %v", err)
+	}
+
+	result, err := env.svc.GCRepoStore(ctx, RepoStoreGCInput{PruneOrphans: true})
+	if err != nil {
+		t.Fatalf("gc: %v", err)
+	}
+	if len(env.git.gcs) != 1 || env.git.gcs[0] != filepath.Join(env.repoRoot, "alpha") {
+		t.Fatalf("expected alpha collected, got %v", env.git.gcs)
+	}
+	byName := storeEntries(result.Repos)
+	if byName["alpha"].Action != repoStoreActionCollected || byName["retired"].Action != repoStoreActionRemoved {
+		t.Fatalf("unexpected gc result %+v", result.Repos)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan removed, stat err: %v", err)
+	}
+	if _, ok := byName["local"]; ok {
+		t.Fatalf("expected local-path repo to be left alone")
+	}
+}
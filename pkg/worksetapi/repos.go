@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
 	"github.com/strantalis/workset/internal/ops"
 	"github.com/strantalis/workset/internal/workspace"
 )
@@ -31,7 +32,7 @@ func (s *Service) ListRepos(ctx context.Context, selector WorkspaceSelector) (Re
 	rows := make([]RepoJSON, 0, len(wsConfig.Repos))
 	for _, repo := range wsConfig.Repos {
 		config.ApplyRepoDefaults(&repo, cfg.Defaults)
-		repoDefaults := resolveRepoDefaults(cfg, repo.Name)
+		repoDefaults := resolveRepoDefaults(cfg, repo)
 		rows = append(rows, RepoJSON{
 			Name:          repo.Name,
 			LocalPath:     repo.LocalPath,
@@ -120,7 +121,11 @@ func (s *Service) AddRepo(ctx context.Context, input RepoAddInput) (RepoAddResul
 	if aliasExists && alias.Remote != "" {
 		remote = alias.Remote
 	}
-	_, resolvedRemote, repoWarnings, err := ops.AddRepo(ctx, ops.AddRepoInput{
+	start, err := s.resolveRepoStart(ctx, input.Start, url, sourcePath, remote)
+	if err != nil {
+		return RepoAddResult{}, err
+	}
+	addInput := ops.AddRepoInput{
 		WorkspaceRoot: wsRoot,
 		Name:          name,
 		URL:           url,
@@ -131,7 +136,10 @@ func (s *Service) AddRepo(ctx context.Context, input RepoAddInput) (RepoAddResul
 		DefaultBranch: defaultBranch,
 		AllowFallback: false,
 		Git:           s.git,
-	})
+		Now:           s.clock,
+	}
+	start.apply(&addInput)
+	_, resolvedRemote, repoWarnings, err := ops.AddRepo(ctx, addInput)
 	if err != nil {
 		return RepoAddResult{}, err
 	}
@@ -219,6 +227,19 @@ func (s *Service) AddRepo(ctx context.Context, input RepoAddInput) (RepoAddResul
 	if len(pending.Hooks) > 0 {
 		pendingHooks = append(pendingHooks, pending)
 	}
+	added, _ := s.runLifecycleHooks(ctx, cfg, []repoHookRequest{{
+		wsRoot: wsRoot,
+		wsName: wsName,
+		repo: config.RepoConfig{
+			Name:    name,
+			RepoDir: repoDir,
+		},
+		worktreePath: worktreePath,
+		branch:       branch,
+	}}, hooks.EventRepoAdded, "repo.add", hooks.Context{}, "")
+	hookRuns = append(hookRuns, added.Runs...)
+	pendingHooks = append(pendingHooks, added.Pending...)
+	warnings = append(warnings, added.Warnings...)
 
 	payload := RepoAddResultJSON{
 		Status:    "ok",
@@ -458,7 +479,7 @@ func (s *Service) RemoveRepo(ctx context.Context, input RepoRemoveInput) (RepoRe
 		return RepoRemoveResult{}, NotFoundError{Message: "repo not found in workspace"}
 	}
 
-	repoDefaults := resolveRepoDefaults(cfg, repoCfg.Name)
+	repoDefaults := resolveRepoDefaults(cfg, repoCfg)
 	report, err := ops.CheckRepoSafety(ctx, ops.RepoSafetyInput{
 		WorkspaceRoot: wsRoot,
 		Repo:          repoCfg,
@@ -498,6 +519,21 @@ func (s *Service) RemoveRepo(ctx context.Context, input RepoRemoveInput) (RepoRe
 		return RepoRemoveResult{}, ConfirmationRequired{Message: "remove repo " + name}
 	}
 
+	var hookTargets []repoHookRequest
+	if input.DeleteWorktrees {
+		var hookWarnings []string
+		hookTargets, hookWarnings = s.threadHookTargets(ctx, cfg, wsRoot, config.WorkspaceConfig{
+			Name:  wsConfig.Name,
+			Repos: []config.RepoConfig{repoCfg},
+		})
+		warnings = append(warnings, hookWarnings...)
+		removing, err := s.runLifecycleHooks(ctx, cfg, hookTargets, hooks.EventWorktreeRemoving, "repo.remove", hooks.Context{}, "")
+		warnings = append(warnings, removing.Warnings...)
+		if err != nil {
+			return RepoRemoveResult{}, err
+		}
+	}
+
 	if _, err := ops.RemoveRepo(ctx, ops.RemoveRepoInput{
 		WorkspaceRoot:   wsRoot,
 		Name:            name,
@@ -510,6 +546,8 @@ func (s *Service) RemoveRepo(ctx context.Context, input RepoRemoveInput) (RepoRe
 	}); err != nil {
 		return RepoRemoveResult{}, err
 	}
+	removed, _ := s.runLifecycleHooks(ctx, cfg, hookTargets, hooks.EventWorktreeRemoved, "repo.remove", hooks.Context{}, wsRoot)
+	warnings = append(warnings, removed.Warnings...)
 	state, err := s.workspaces.LoadState(ctx, wsRoot)
 	if err == nil && len(state.PullRequests) > 0 {
 		if _, tracked := state.PullRequests[name]; tracked {
@@ -20,12 +20,17 @@ type Options struct {
 	TokenStore     TokenStore
 	// ExecFunc overrides how Exec runs commands (useful for embedding/tests).
 	ExecFunc func(ctx context.Context, root string, command []string, env []string) error
+	// ExecRepoFunc overrides how ExecEachRepo runs commands in repo worktrees.
+	ExecRepoFunc ExecRepoFunc
 	// HookRunner overrides how hooks run commands (useful for embedding/tests).
 	HookRunner hooks.Runner
 	// HookObserver receives per-hook execution lifecycle updates.
 	HookObserver HookProgressObserver
-	Clock        func() time.Time
-	Logf         func(format string, args ...any)
+	// TerminalService runs thread processes; it defaults to a client of the
+	// terminal service at terminalservice.DefaultSocketPath.
+	TerminalService TerminalService
+	Clock           func() time.Time
+	Logf            func(format string, args ...any)
 }
 
 // Service provides the public API for workspace, repo, alias, group,
@@ -37,11 +42,13 @@ type Service struct {
 	git        git.Client
 	commands   CommandRunner
 	exec       func(ctx context.Context, root string, command []string, env []string) error
+	execRepo   ExecRepoFunc
 	hookRunner hooks.Runner
 	hookEvents HookProgressObserver
 	clock      func() time.Time
 	logf       func(format string, args ...any)
 	github     GitHubProvider
+	terminals  TerminalService
 }
 
 // NewService constructs a Service with injected dependencies or defaults.
@@ -68,6 +75,10 @@ func NewService(opts Options) *Service {
 	if execFunc == nil {
 		execFunc = runExecCommand
 	}
+	execRepoFunc := opts.ExecRepoFunc
+	if execRepoFunc == nil {
+		execRepoFunc = runRepoExecCommand
+	}
 	hookRunner := opts.HookRunner
 	if hookRunner == nil {
 		hookRunner = hooks.ExecRunner{}
@@ -91,10 +102,12 @@ func NewService(opts Options) *Service {
 		git:        gitClient,
 		commands:   commandRunner,
 		exec:       execFunc,
+		execRepo:   execRepoFunc,
 		hookRunner: hookRunner,
 		hookEvents: opts.HookObserver,
 		clock:      clock,
 		logf:       opts.Logf,
 		github:     githubProvider,
+		terminals:  opts.TerminalService,
 	}
 }
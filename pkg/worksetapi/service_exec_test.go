@@ -1,14 +1,19 @@
 package worksetapi
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/workspace"
 )
 
 func TestExecUsesProvidedRunner(t *testing.T) {
@@ -119,3 +124,111 @@ func TestExecUsesDefaultWorkspace(t *testing.T) {
 		t.Fatalf("expected exec func called")
 	}
 }
+
+func TestExecEachRepoRunsPerWorktree(t *testing.T) {
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+	for _, name := range []string{"repo-a", "repo-b"} {
+		if _, err := env.svc.AddRepo(context.Background(), RepoAddInput{
+			Workspace:  WorkspaceSelector{Value: root},
+			Name:       name,
+			NameSet:    true,
+			SourcePath: env.createLocalRepo(name),
+		}); err != nil {
+			t.Fatalf("add repo %s: %v", name, err)
+		}
+	}
+
+	var mu sync.Mutex
+	gotEnv := map[string][]string{}
+	svc := NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		ExecRepoFunc: func(_ context.Context, dir string, _ []string, env []string, stdout, _ io.Writer) error {
+			mu.Lock()
+			gotEnv[dir] = env
+			mu.Unlock()
+			_, _ = io.WriteString(stdout, "line one\npartial")
+			if strings.HasSuffix(dir, "repo-b") {
+				return &exec.ExitError{}
+			}
+			return nil
+		},
+		Clock: func() time.Time { return env.now },
+		Logf:  func(string, ...any) {},
+	})
+
+	var stdout bytes.Buffer
+	result, err := svc.ExecEachRepo(context.Background(), ExecEachRepoInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Command:   []string{"make", "test"},
+		Parallel:  2,
+		Stdout:    &stdout,
+		Stderr:    io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("exec each repo: %v", err)
+	}
+	payload := result.Payload
+	if payload.Total != 2 || payload.Succeeded != 1 || payload.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", payload)
+	}
+	if payload.Repos[0].Repo != "repo-a" || payload.Repos[0].Status != ExecRepoStatusOK {
+		t.Fatalf("unexpected repo-a result: %+v", payload.Repos[0])
+	}
+	if payload.Repos[1].Repo != "repo-b" || payload.Repos[1].Status != ExecRepoStatusFailed {
+		t.Fatalf("unexpected repo-b result: %+v", payload.Repos[1])
+	}
+	for _, line := range []string{"[repo-a] line one\n", "[repo-a] partial\n", "[repo-b] line one\n"} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Fatalf("missing prefixed output %q in %q", line, stdout.String())
+		}
+	}
+	worktree := payload.Repos[0].Path
+	foundRepo := false
+	foundWorktree := false
+	for _, entry := range gotEnv[worktree] {
+		if entry == "WORKSET_REPO=repo-a" {
+			foundRepo = true
+		}
+		if entry == "WORKSET_WORKTREE="+worktree {
+			foundWorktree = true
+		}
+	}
+	if !foundRepo || !foundWorktree {
+		t.Fatalf("missing per-repo env vars: %v", gotEnv[worktree])
+	}
+}
+
+func TestExecEachRepoReportsMissingWorktree(t *testing.T) {
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	wsCfg.Repos = []config.RepoConfig{{Name: "repo-a", RepoDir: "repo-a"}}
+	if err := config.SaveWorkspace(workspace.WorksetFile(root), wsCfg); err != nil {
+		t.Fatalf("save workspace config: %v", err)
+	}
+
+	result, err := env.svc.ExecEachRepo(context.Background(), ExecEachRepoInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Command:   []string{"true"},
+		Stdout:    io.Discard,
+		Stderr:    io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("exec each repo: %v", err)
+	}
+	if result.Payload.Failed != 1 || result.Payload.Repos[0].Status != ExecRepoStatusMissing {
+		t.Fatalf("expected missing worktree, got %+v", result.Payload)
+	}
+
+	_, err = env.svc.ExecEachRepo(context.Background(), ExecEachRepoInput{
+		Workspace: WorkspaceSelector{Value: root},
+		Command:   []string{"true"},
+		Repos:     []string{"nope"},
+	})
+	_ = requireErrorType[NotFoundError](t, err)
+}
@@ -0,0 +1,219 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/hooks"
+)
+
+type recordingHookRunner struct {
+	requests []hooks.RunRequest
+	err      error
+}
+
+func (r *recordingHookRunner) Run(_ context.Context, req hooks.RunRequest) error {
+	r.requests = append(r.requests, req)
+	return r.err
+}
+
+func (r *recordingHookRunner) envValues(key string) []string {
+	values := []string{}
+	for _, req := range r.requests {
+		for _, entry := range req.Env {
+			if value, ok := strings.CutPrefix(entry, key+"="); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+func newLifecycleHookEnv(t *testing.T, hooksYAML string, runner hooks.Runner) (*testEnv, string) {
+	t.Helper()
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+	local := env.createLocalRepo("repo-a")
+	env.git.worktreeAddHook = func(path string) error {
+		hooksDir := filepath.Join(path, ".workset")
+		if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(hooksDir, "hooks.yaml"), []byte(hooksYAML), 0o644)
+	}
+	cfg := env.loadConfig()
+	cfg.Hooks.RepoHooks.TrustedRepos = []string{"repo-a"}
+	env.saveConfig(cfg)
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		HookRunner: runner,
+		Clock:      func() time.Time { return env.now },
+		Logf:       func(string, ...any) {},
+	})
+	if _, err := env.svc.AddRepo(context.Background(), RepoAddInput{
+		Workspace:  WorkspaceSelector{Value: root},
+		Name:       "repo-a",
+		NameSet:    true,
+		SourcePath: local,
+	}); err != nil {
+		t.Fatalf("add repo: %v", err)
+	}
+	return env, root
+}
+
+func TestAddRepoRunsRepoAddedHooks(t *testing.T) {
+	runner := &recordingHookRunner{}
+	_, _ = newLifecycleHookEnv(t, "hooks:\n  - id: announce\n    on: [repo.added]\n    run: [\"echo\", \"{repo.name}\"]\n", runner)
+
+	if len(runner.requests) != 1 {
+		t.Fatalf("expected one hook run, got %d", len(runner.requests))
+	}
+	if got := runner.requests[0].Command; len(got) != 2 || got[1] != "repo-a" {
+		t.Fatalf("unexpected command: %v", got)
+	}
+	if events := runner.envValues("WORKSET_EVENT"); len(events) != 1 || events[0] != "repo.added" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+type recordingHookObserver struct {
+	events []HookProgress
+}
+
+func (o *recordingHookObserver) OnHookProgress(progress HookProgress) {
+	o.events = append(o.events, progress)
+}
+
+func TestAddRepoReportsHooksSkippedByConditions(t *testing.T) {
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+	local := env.createLocalRepo("repo-a")
+	env.git.worktreeAddHook = func(path string) error {
+		hooksDir := filepath.Join(path, ".workset")
+		if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(hooksDir, "hooks.yaml"), []byte("hooks:\n  - id: api-only\n    on: [worktree.created]\n    run: [\"true\"]\n    when:\n      repos: [api]\n"), 0o644)
+	}
+	cfg := env.loadConfig()
+	cfg.Hooks.RepoHooks.TrustedRepos = []string{"repo-a"}
+	env.saveConfig(cfg)
+	runner := &recordingHookRunner{}
+	observer := &recordingHookObserver{}
+	env.svc = NewService(Options{
+		ConfigPath:   env.configPath,
+		Git:          env.git,
+		HookRunner:   runner,
+		HookObserver: observer,
+		Clock:        func() time.Time { return env.now },
+		Logf:         func(string, ...any) {},
+	})
+
+	result, err := env.svc.AddRepo(context.Background(), RepoAddInput{
+		Workspace:  WorkspaceSelector{Value: root},
+		Name:       "repo-a",
+		NameSet:    true,
+		SourcePath: local,
+	})
+	if err != nil {
+		t.Fatalf("add repo: %v", err)
+	}
+	if len(runner.requests) != 0 {
+		t.Fatalf("expected the hook not to run, got %d runs", len(runner.requests))
+	}
+	want := "repo repo-a not in api"
+	if len(result.HookRuns) != 1 || result.HookRuns[0].ID != "api-only" || result.HookRuns[0].Status != HookRunStatusSkipped || result.HookRuns[0].Reason != want {
+		t.Fatalf("expected the skipped hook with its reason, got %+v", result.HookRuns)
+	}
+	if len(observer.events) != 1 || observer.events[0].Status != HookRunStatusSkipped || observer.events[0].SkipReason != want {
+		t.Fatalf("expected a skipped progress event with its reason, got %+v", observer.events)
+	}
+}
+
+func TestDeleteWorkspaceVetoedByRemovingHook(t *testing.T) {
+	runner := &recordingHookRunner{}
+	env, root := newLifecycleHookEnv(t, "hooks:\n  - id: backup\n    on: [worktree.removing]\n    run: [\"backup\"]\n    on_error: fail\n", runner)
+	runner.err = errors.New("backup failed")
+
+	_, err := env.svc.DeleteWorkspace(context.Background(), WorkspaceDeleteInput{
+		Selector:    WorkspaceSelector{Value: root},
+		DeleteFiles: true,
+		Force:       true,
+		Confirmed:   true,
+	})
+	failed := requireErrorType[hooks.HookFailedError](t, err)
+	if failed.HookID != "backup" {
+		t.Fatalf("unexpected hook id: %s", failed.HookID)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Fatalf("expected thread to remain: %v", err)
+	}
+	if len(env.git.worktreeRemovs) != 0 {
+		t.Fatalf("expected no worktrees removed, got %d", len(env.git.worktreeRemovs))
+	}
+}
+
+func TestDeleteWorkspaceRunsRemovalHooks(t *testing.T) {
+	runner := &recordingHookRunner{}
+	env, root := newLifecycleHookEnv(t, "hooks:\n  - id: before\n    on: [worktree.removing]\n    run: [\"true\"]\n  - id: after\n    on: [worktree.removed]\n    run: [\"true\"]\n", runner)
+
+	if _, err := env.svc.DeleteWorkspace(context.Background(), WorkspaceDeleteInput{
+		Selector:    WorkspaceSelector{Value: root},
+		DeleteFiles: true,
+		Force:       true,
+		Confirmed:   true,
+		Purge:       true,
+	}); err != nil {
+		t.Fatalf("delete workspace: %v", err)
+	}
+	events := runner.envValues("WORKSET_EVENT")
+	if len(events) != 2 || events[0] != "worktree.removing" || events[1] != "worktree.removed" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+	if runner.requests[1].Cwd != root {
+		t.Fatalf("expected worktree.removed to run from thread root, got %q", runner.requests[1].Cwd)
+	}
+	if _, err := os.Stat(root); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected thread removed, stat err: %v", err)
+	}
+}
+
+func TestRenameWorkspaceRunsRenamedHooks(t *testing.T) {
+	runner := &recordingHookRunner{}
+	env, root := newLifecycleHookEnv(t, "hooks:\n  - id: rename\n    on: [thread.renamed]\n    run: [\"echo\", \"{workspace.previous_name}\", \"{workspace.name}\"]\n", runner)
+
+	if _, err := env.svc.RenameWorkspace(context.Background(), WorkspaceRenameInput{
+		Selector: WorkspaceSelector{Value: root},
+		NewName:  "renamed",
+	}); err != nil {
+		t.Fatalf("rename workspace: %v", err)
+	}
+	if len(runner.requests) != 1 {
+		t.Fatalf("expected one hook run, got %d", len(runner.requests))
+	}
+	if got := runner.requests[0].Command; len(got) != 3 || got[1] != "demo" || got[2] != "renamed" {
+		t.Fatalf("unexpected command: %v", got)
+	}
+}
+
+func TestArchiveWorkspaceHookFailureDoesNotFail(t *testing.T) {
+	runner := &recordingHookRunner{err: errors.New("boom")}
+	env, root := newLifecycleHookEnv(t, "hooks:\n  - id: notify\n    on: [thread.archived]\n    run: [\"notify\"]\n    on_error: fail\n", runner)
+
+	ref, _, err := env.svc.ArchiveWorkspace(context.Background(), WorkspaceSelector{Value: root}, "done")
+	if err != nil {
+		t.Fatalf("archive workspace: %v", err)
+	}
+	if !ref.Archived {
+		t.Fatalf("expected archived thread")
+	}
+	if len(runner.requests) != 1 {
+		t.Fatalf("expected one hook run, got %d", len(runner.requests))
+	}
+}
@@ -459,7 +459,7 @@ func TestCreateWorkspaceRunsTrustedHooks(t *testing.T) {
 	}
 }
 
-func TestCreateWorkspaceRegistersOnRepoFailure(t *testing.T) {
+func TestCreateWorkspaceUnregistersOnRepoFailure(t *testing.T) {
 	env := newTestEnv(t)
 	local := env.createLocalRepo("repo-a")
 	env.git.worktreeAddHook = func(string) error {
@@ -481,8 +481,8 @@ func TestCreateWorkspaceRegistersOnRepoFailure(t *testing.T) {
 	}
 
 	cfg = env.loadConfig()
-	if _, ok := cfg.Workspaces["demo"]; !ok {
-		t.Fatalf("workspace not registered after failure")
+	if _, ok := cfg.Workspaces["demo"]; ok {
+		t.Fatalf("workspace still registered after failure")
 	}
 }
 
@@ -714,7 +714,7 @@ func TestStatusWorkspaceReportsDirtyAndMissing(t *testing.T) {
 	env.git.status[filepath.Join(root, "repo-a")] = git.StatusSummary{Dirty: true}
 	env.git.status[filepath.Join(root, "repo-b")] = git.StatusSummary{Missing: true}
 
-	result, err := env.svc.StatusWorkspace(context.Background(), WorkspaceSelector{Value: root})
+	result, err := env.svc.StatusWorkspace(context.Background(), WorkspaceStatusInput{Selector: WorkspaceSelector{Value: root}})
 	if err != nil {
 		t.Fatalf("status workspace: %v", err)
 	}
@@ -729,6 +729,68 @@ func TestStatusWorkspaceReportsDirtyAndMissing(t *testing.T) {
 	}
 }
 
+func TestStatusWorkspaceReportsTrackingDetails(t *testing.T) {
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	wsCfg.Repos = []config.RepoConfig{{Name: "repo-a", RepoDir: "repo-a"}}
+	if err := config.SaveWorkspace(workspace.WorksetFile(root), wsCfg); err != nil {
+		t.Fatalf("save workspace config: %v", err)
+	}
+	repoPath := filepath.Join(root, "repo-a")
+	if err := os.MkdirAll(repoPath, 0o755); err != nil {
+		t.Fatalf("mkdir repo-a: %v", err)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	state.PullRequests = map[string]workspace.PullRequestState{
+		"repo-a": {Repo: "repo-a", Number: 42, URL: "https://example.com/pr/42", State: "open", Draft: true},
+	}
+	if err := workspace.SaveState(root, state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	env.git.statusDetail[repoPath] = git.StatusDetail{
+		StatusSummary: git.StatusSummary{Dirty: true},
+		Branch:        "feature",
+		Upstream:      "origin/feature",
+		Ahead:         2,
+		BaseRef:       "refs/remotes/origin/main",
+		BaseBehind:    3,
+		Stashes:       1,
+		Conflicts:     []string{"main.go"},
+	}
+
+	result, err := env.svc.StatusWorkspace(context.Background(), WorkspaceStatusInput{
+		Selector:     WorkspaceSelector{Value: root},
+		FetchRemotes: true,
+	})
+	if err != nil {
+		t.Fatalf("status workspace: %v", err)
+	}
+	if len(env.git.fetches) != 1 || env.git.fetches[0] != repoPath+"|origin" {
+		t.Fatalf("expected fetch of repo-a, got %v", env.git.fetches)
+	}
+	if len(result.Statuses) != 1 {
+		t.Fatalf("expected 1 status, got %d", len(result.Statuses))
+	}
+	status := result.Statuses[0]
+	if status.State != "conflicted" || status.Branch != "feature" || status.Upstream != "origin/feature" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status.Ahead != 2 || status.BaseBehind != 3 || status.Stashes != 1 {
+		t.Fatalf("unexpected counts: %+v", status)
+	}
+	if status.PullRequest == nil || status.PullRequest.Number != 42 || !status.PullRequest.Draft {
+		t.Fatalf("expected tracked pull request, got %+v", status.PullRequest)
+	}
+}
+
 func TestDeleteWorkspaceDeletesFiles(t *testing.T) {
 	env := newTestEnv(t)
 	root := env.createWorkspace(context.Background(), "demo")
@@ -47,6 +47,21 @@ func (f fakeGitClient) UpdateBranch(_ context.Context, _, _, _ string) error {
 func (f fakeGitClient) Status(_ string) (git.StatusSummary, error) {
 	return git.StatusSummary{}, errors.New("not implemented")
 }
+func (f fakeGitClient) StatusDetail(_ context.Context, _, _ string) (git.StatusDetail, error) {
+	return git.StatusDetail{}, errors.New("not implemented")
+}
+func (f fakeGitClient) Integrate(_ context.Context, _, _ string, _ git.IntegrateMode) error {
+	return errors.New("not implemented")
+}
+func (f fakeGitClient) IntegrationInProgress(_ context.Context, _ string) (git.IntegrateMode, bool, error) {
+	return "", false, errors.New("not implemented")
+}
+func (f fakeGitClient) ContinueIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return errors.New("not implemented")
+}
+func (f fakeGitClient) AbortIntegration(_ context.Context, _ string, _ git.IntegrateMode) error {
+	return errors.New("not implemented")
+}
 func (f fakeGitClient) IsRepo(_ string) (bool, error) { return false, errors.New("not implemented") }
 func (f fakeGitClient) IsAncestor(_, _, _ string) (bool, error) {
 	return false, errors.New("not implemented")
@@ -72,10 +87,30 @@ func (f fakeGitClient) WorktreeRemove(_ git.WorktreeRemoveOptions) error {
 	return errors.New("not implemented")
 }
 
+func (f fakeGitClient) WorktreeMove(_ context.Context, _, _, _ string) error {
+	return errors.New("not implemented")
+}
+
+func (f fakeGitClient) WorktreeRepair(_ context.Context, _ string) error {
+	return errors.New("not implemented")
+}
+
 func (f fakeGitClient) WorktreeList(_ string) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (f fakeGitClient) GC(_ context.Context, _ string) error {
+	return errors.New("not implemented")
+}
+
+func (f fakeGitClient) Fsck(_ context.Context, _ string) error {
+	return errors.New("not implemented")
+}
+
+func (f fakeGitClient) Log(_ context.Context, _ string, _ git.LogOptions) ([]git.Commit, error) {
+	return nil, errors.New("not implemented")
+}
+
 func TestPreflightSSHAuthAllowsIdentityFileWithoutAgent(t *testing.T) {
 	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
 	if err := os.WriteFile(keyPath, []byte("dummy"), 0o600); err != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,6 +27,15 @@ type testEnv struct {
 
 func newTestEnv(t *testing.T) *testEnv {
 	t.Helper()
+	fakeGit := newFakeGit()
+	env := newTestEnvWithGit(t, fakeGit)
+	env.git = fakeGit
+	return env
+}
+
+// newTestEnvWithGit builds a test env around client; env.git is left nil.
+func newTestEnvWithGit(t *testing.T, client git.Client) *testEnv {
+	t.Helper()
 
 	root := t.TempDir()
 	workspaceRoot := filepath.Join(root, "worksets")
@@ -46,10 +56,9 @@ func newTestEnv(t *testing.T) *testEnv {
 		t.Fatalf("save config: %v", err)
 	}
 
-	fakeGit := newFakeGit()
 	svc := NewService(Options{
 		ConfigPath: cfgPath,
-		Git:        fakeGit,
+		Git:        client,
 		Clock:      func() time.Time { return now },
 		Logf:       func(string, ...any) {},
 	})
@@ -61,7 +70,6 @@ func newTestEnv(t *testing.T) *testEnv {
 		workspaceRoot: workspaceRoot,
 		repoRoot:      repoRoot,
 		now:           now,
-		git:           fakeGit,
 		svc:           svc,
 	}
 }
@@ -101,8 +109,14 @@ func (e *testEnv) createWorkspace(ctx context.Context, name string) string {
 }
 
 type fakeGit struct {
+	mu              sync.Mutex
 	status          map[string]git.StatusSummary
+	statusDetail    map[string]git.StatusDetail
 	statusErr       map[string]error
+	fetches         []string
+	integrations    []string
+	integrateErr    map[string]error
+	inProgress      map[string]git.IntegrateMode
 	refs            map[string]bool
 	remotes         map[string][]string
 	remoteURLs      map[string]map[string][]string
@@ -113,7 +127,11 @@ type fakeGit struct {
 	currentOK       map[string]bool
 	worktreeAdds    []git.WorktreeAddOptions
 	worktreeRemovs  []worktreeRemoveCall
+	worktreeMoves   [][2]string
+	worktreeRepairs []string
 	worktreeAddHook func(path string) error
+	gcs             []string
+	fsckErr         map[string]error
 }
 
 type worktreeRemoveCall struct {
@@ -125,7 +143,10 @@ type worktreeRemoveCall struct {
 func newFakeGit() *fakeGit {
 	return &fakeGit{
 		status:        map[string]git.StatusSummary{},
+		statusDetail:  map[string]git.StatusDetail{},
 		statusErr:     map[string]error{},
+		integrateErr:  map[string]error{},
+		inProgress:    map[string]git.IntegrateMode{},
 		refs:          map[string]bool{},
 		remotes:       map[string][]string{},
 		remoteURLs:    map[string]map[string][]string{},
@@ -145,7 +166,7 @@ func (f *fakeGit) Clone(_ context.Context, _ string, path, _ string) error {
 }
 
 func (f *fakeGit) CloneBare(_ context.Context, _ string, path, _ string) error {
-	if err := os.MkdirAll(path, 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Join(path, "objects"), 0o755); err != nil {
 		return err
 	}
 	return os.WriteFile(filepath.Join(path, "HEAD"), []byte("ref: refs/heads/main"), 0o644)
@@ -181,7 +202,10 @@ func (f *fakeGit) ReferenceExists(_ context.Context, repoPath, ref string) (bool
 	return true, nil
 }
 
-func (f *fakeGit) Fetch(_ context.Context, _ string, _ string) error {
+func (f *fakeGit) Fetch(_ context.Context, repoPath string, remoteName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches = append(f.fetches, repoPath+"|"+remoteName)
 	return nil
 }
 
@@ -199,6 +223,43 @@ func (f *fakeGit) Status(path string) (git.StatusSummary, error) {
 	return git.StatusSummary{}, nil
 }
 
+func (f *fakeGit) StatusDetail(_ context.Context, path, baseRef string) (git.StatusDetail, error) {
+	if err, ok := f.statusErr[path]; ok {
+		return git.StatusDetail{}, err
+	}
+	if detail, ok := f.statusDetail[path]; ok {
+		return detail, nil
+	}
+	status, _ := f.Status(path)
+	return git.StatusDetail{StatusSummary: status, BaseRef: baseRef}, nil
+}
+
+func (f *fakeGit) Integrate(_ context.Context, path, ref string, mode git.IntegrateMode) error {
+	f.integrations = append(f.integrations, fmt.Sprintf("%s %s %s", mode, path, ref))
+	if err, ok := f.integrateErr[path]; ok {
+		f.inProgress[path] = mode
+		return err
+	}
+	return nil
+}
+
+func (f *fakeGit) IntegrationInProgress(_ context.Context, path string) (git.IntegrateMode, bool, error) {
+	mode, ok := f.inProgress[path]
+	return mode, ok, nil
+}
+
+func (f *fakeGit) ContinueIntegration(_ context.Context, path string, mode git.IntegrateMode) error {
+	f.integrations = append(f.integrations, fmt.Sprintf("%s --continue %s", mode, path))
+	delete(f.inProgress, path)
+	return nil
+}
+
+func (f *fakeGit) AbortIntegration(_ context.Context, path string, mode git.IntegrateMode) error {
+	f.integrations = append(f.integrations, fmt.Sprintf("%s --abort %s", mode, path))
+	delete(f.inProgress, path)
+	return nil
+}
+
 func (f *fakeGit) IsRepo(path string) (bool, error) {
 	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
 		return true, nil
@@ -251,11 +312,15 @@ func (f *fakeGit) WorktreeAdd(_ context.Context, opts git.WorktreeAddOptions) er
 			return err
 		}
 	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.worktreeAdds = append(f.worktreeAdds, opts)
 	return nil
 }
 
 func (f *fakeGit) WorktreeRemove(opts git.WorktreeRemoveOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.worktreeRemovs = append(f.worktreeRemovs, worktreeRemoveCall{
 		repoPath: opts.RepoPath,
 		name:     opts.WorktreeName,
@@ -264,10 +329,39 @@ func (f *fakeGit) WorktreeRemove(opts git.WorktreeRemoveOptions) error {
 	return nil
 }
 
+func (f *fakeGit) WorktreeMove(_ context.Context, _, worktreePath, newPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.worktreeMoves = append(f.worktreeMoves, [2]string{worktreePath, newPath})
+	return os.Rename(worktreePath, newPath)
+}
+
+func (f *fakeGit) WorktreeRepair(_ context.Context, worktreePath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.worktreeRepairs = append(f.worktreeRepairs, worktreePath)
+	return nil
+}
+
 func (f *fakeGit) WorktreeList(_ string) ([]string, error) {
 	return nil, nil
 }
 
+func (f *fakeGit) GC(_ context.Context, repoPath string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gcs = append(f.gcs, repoPath)
+	return nil
+}
+
+func (f *fakeGit) Fsck(_ context.Context, repoPath string) error {
+	return f.fsckErr[repoPath]
+}
+
+func (f *fakeGit) Log(_ context.Context, _ string, _ git.LogOptions) ([]git.Commit, error) {
+	return nil, nil
+}
+
 func refKey(repoPath, ref string) string {
 	return fmt.Sprintf("%s::%s", repoPath, ref)
 }
@@ -0,0 +1,284 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// ThreadBundleVersion is the bundle format written by ExportThread.
+const ThreadBundleVersion = 1
+
+// ThreadBundle describes a thread portably enough to recreate it on another
+// machine. Workspace and State are the thread's workset.yaml and state.json
+// with machine-specific paths removed.
+type ThreadBundle struct {
+	Version     int                           `yaml:"version" json:"version"`
+	Workset     string                        `yaml:"workset,omitempty" json:"workset,omitempty"`
+	Description string                        `yaml:"description,omitempty" json:"description,omitempty"`
+	Color       string                        `yaml:"color,omitempty" json:"color,omitempty"`
+	Workspace   config.WorkspaceConfig        `yaml:"workspace" json:"workspace"`
+	State       workspace.State               `yaml:"state" json:"state"`
+	Remotes     map[string]ThreadBundleRemote `yaml:"remotes,omitempty" json:"remotes,omitempty"`
+}
+
+// ThreadBundleRemote is where a bundled repo is cloned from.
+type ThreadBundleRemote struct {
+	URL           string `yaml:"url,omitempty" json:"url,omitempty"`
+	Remote        string `yaml:"remote,omitempty" json:"remote,omitempty"`
+	DefaultBranch string `yaml:"default_branch,omitempty" json:"default_branch,omitempty"`
+}
+
+// ParseThreadBundle decodes a YAML or JSON thread bundle.
+func ParseThreadBundle(data []byte) (ThreadBundle, error) {
+	var bundle ThreadBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return ThreadBundle{}, ValidationError{Message: fmt.Sprintf("invalid thread bundle: %v", err)}
+	}
+	if bundle.Version != ThreadBundleVersion {
+		return ThreadBundle{}, ValidationError{Message: fmt.Sprintf("unsupported thread bundle version %d", bundle.Version)}
+	}
+	return bundle, nil
+}
+
+// ExportThread captures a thread's repos, branches, base refs, metadata, and
+// tracked pull requests as a bundle ImportThread can recreate elsewhere.
+func (s *Service) ExportThread(ctx context.Context, selector WorkspaceSelector) (ThreadExportResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return ThreadExportResult{}, err
+	}
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, selector)
+	if err != nil {
+		return ThreadExportResult{}, err
+	}
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil {
+		return ThreadExportResult{}, err
+	}
+	name := strings.TrimSpace(wsConfig.Name)
+	if registered := threadNameByPath(&cfg, wsRoot); registered != "" {
+		name = registered
+	}
+	if name == "" {
+		name = filepath.Base(wsRoot)
+	}
+	ref := cfg.Workspaces[name]
+	branch := state.CurrentBranch
+	if branch == "" {
+		branch = cfg.Defaults.BaseBranch
+	}
+
+	bundle := ThreadBundle{
+		Version:     ThreadBundleVersion,
+		Workset:     worksetNameForThread(name, ref),
+		Description: ref.Description,
+		Color:       ref.Color,
+		Workspace:   config.WorkspaceConfig{Name: name},
+		State:       state,
+		Remotes:     map[string]ThreadBundleRemote{},
+	}
+	warnings := []string{}
+	for _, repo := range wsConfig.Repos {
+		config.ApplyRepoDefaults(&repo, cfg.Defaults)
+		defaults := resolveRepoDefaults(cfg, repo)
+		worktreePath := resolveRepoPath(wsRoot, branch, repo)
+		url := strings.TrimSpace(cfg.Repos[repo.Name].URL)
+		if url == "" && defaults.Remote != "" {
+			if urls, err := s.git.RemoteURLs(worktreePath, defaults.Remote); err == nil && len(urls) > 0 {
+				url = urls[0]
+			}
+		}
+		if url == "" {
+			warnings = append(warnings, fmt.Sprintf("repo %s has no remote URL; register it before importing", repo.Name))
+		}
+		repoBranch := repo.Branch
+		if repoBranch == "" {
+			repoBranch = branch
+		}
+		if defaults.Remote != "" && repoBranch != "" {
+			remoteRef := fmt.Sprintf("refs/remotes/%s/%s", defaults.Remote, repoBranch)
+			if exists, err := s.git.ReferenceExists(ctx, worktreePath, remoteRef); err == nil && !exists {
+				warnings = append(warnings, fmt.Sprintf("branch %s of repo %s is not on %s; push it before importing", repoBranch, repo.Name, defaults.Remote))
+			}
+		}
+		bundle.Workspace.Repos = append(bundle.Workspace.Repos, config.RepoConfig{
+			Name:       repo.Name,
+			RepoDir:    repo.RepoDir,
+			BaseBranch: repo.BaseBranch,
+			Branch:     repo.Branch,
+		})
+		bundle.Remotes[repo.Name] = ThreadBundleRemote{
+			URL:           url,
+			Remote:        defaults.Remote,
+			DefaultBranch: defaults.DefaultBranch,
+		}
+	}
+	return ThreadExportResult{Bundle: bundle, Warnings: warnings, Config: info}, nil
+}
+
+// ImportThread recreates a thread from a bundle. Repos missing from the
+// registry are registered from the bundle's remotes, and each worktree
+// checks out its branch as it exists on the remote.
+func (s *Service) ImportThread(ctx context.Context, input ThreadImportInput) (WorkspaceCreateResult, error) {
+	bundle := input.Bundle
+	if bundle.Version != ThreadBundleVersion {
+		return WorkspaceCreateResult{}, ValidationError{Message: fmt.Sprintf("unsupported thread bundle version %d", bundle.Version)}
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = strings.TrimSpace(bundle.Workspace.Name)
+	}
+	if name == "" {
+		return WorkspaceCreateResult{}, ValidationError{Message: "thread name required"}
+	}
+	if len(bundle.Workspace.Repos) == 0 {
+		return WorkspaceCreateResult{}, ValidationError{Message: "thread bundle lists no repos"}
+	}
+
+	registered, err := s.registerBundleRepos(ctx, bundle)
+	if err != nil {
+		return WorkspaceCreateResult{}, err
+	}
+	threadBranch := workspace.WorkspaceBranchName(name)
+	repos := make([]string, 0, len(bundle.Workspace.Repos))
+	starts := make(map[string]RepoStart, len(bundle.Workspace.Repos))
+	for _, repo := range bundle.Workspace.Repos {
+		repos = append(repos, repo.Name)
+		branch := strings.TrimSpace(repo.Branch)
+		if branch == "" {
+			branch = strings.TrimSpace(bundle.State.CurrentBranch)
+		}
+		switch branch {
+		case "":
+			starts[repo.Name] = RepoStart{}
+		case threadBranch:
+			starts[repo.Name] = RepoStart{ExistingBranch: true}
+		default:
+			starts[repo.Name] = RepoStart{Branch: branch}
+		}
+	}
+	result, err := s.CreateWorkspace(ctx, WorkspaceCreateInput{
+		Name:       name,
+		Path:       input.Path,
+		Workset:    bundle.Workset,
+		Repos:      repos,
+		RepoStarts: starts,
+	})
+	if err != nil {
+		s.unregisterBundleRepos(ctx, registered)
+		return WorkspaceCreateResult{}, err
+	}
+
+	// The thread exists at this point; failures restoring the rest of the
+	// bundle are reported rather than undoing the import.
+	root := result.Workspace.Path
+	if err := s.restoreBundleBaseBranches(ctx, root, bundle); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("restore base branches: %v", err))
+	}
+	if len(bundle.State.PullRequests) > 0 || bundle.State.PullRequestGroup != nil {
+		state, err := s.workspaces.LoadState(ctx, root)
+		if err == nil {
+			state.PullRequests = bundle.State.PullRequests
+			state.PullRequestGroup = bundle.State.PullRequestGroup
+			err = s.workspaces.SaveState(ctx, root, state)
+		}
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("restore tracked pull requests: %v", err))
+		}
+	}
+	if bundle.Description != "" || bundle.Color != "" {
+		if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, _ config.GlobalConfigLoadInfo) error {
+			ref, ok := cfg.Workspaces[name]
+			if !ok {
+				return nil
+			}
+			if bundle.Description != "" {
+				ref.Description = bundle.Description
+			}
+			if bundle.Color != "" {
+				ref.Color = bundle.Color
+			}
+			cfg.Workspaces[name] = ref
+			return nil
+		}); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("restore thread metadata: %v", err))
+		}
+	}
+	return result, nil
+}
+
+// registerBundleRepos registers the bundle's repos that are missing from the
+// registry and returns their names. A registered repo whose URL points
+// somewhere else is a conflict rather than being silently reused.
+func (s *Service) registerBundleRepos(ctx context.Context, bundle ThreadBundle) ([]string, error) {
+	var registered []string
+	_, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, _ config.GlobalConfigLoadInfo) error {
+		registered = nil
+		for _, repo := range bundle.Workspace.Repos {
+			remote := bundle.Remotes[repo.Name]
+			if existing, ok := cfg.Repos[repo.Name]; ok {
+				if remote.URL != "" && existing.URL != "" && normalizeRemoteURL(existing.URL) != normalizeRemoteURL(remote.URL) {
+					return ConflictError{Message: fmt.Sprintf(
+						"repo %q is registered with %s but the bundle uses %s",
+						repo.Name, existing.URL, remote.URL,
+					)}
+				}
+				continue
+			}
+			if strings.TrimSpace(remote.URL) == "" {
+				return ValidationError{Message: fmt.Sprintf("repo %q is not registered and the bundle has no URL for it", repo.Name)}
+			}
+			cfg.Repos[repo.Name] = config.RegisteredRepo{
+				URL:           remote.URL,
+				Remote:        remote.Remote,
+				DefaultBranch: remote.DefaultBranch,
+			}
+			registered = append(registered, repo.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return registered, nil
+}
+
+func (s *Service) unregisterBundleRepos(ctx context.Context, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	_, _ = s.updateGlobal(ctx, func(cfg *config.GlobalConfig, _ config.GlobalConfigLoadInfo) error {
+		for _, name := range names {
+			delete(cfg.Repos, name)
+		}
+		return nil
+	})
+}
+
+func (s *Service) restoreBundleBaseBranches(ctx context.Context, root string, bundle ThreadBundle) error {
+	bases := map[string]string{}
+	for _, repo := range bundle.Workspace.Repos {
+		if base := strings.TrimSpace(repo.BaseBranch); base != "" {
+			bases[repo.Name] = base
+		}
+	}
+	if len(bases) == 0 {
+		return nil
+	}
+	wsConfig, err := s.workspaces.LoadConfig(ctx, root)
+	if err != nil {
+		return err
+	}
+	for i, repo := range wsConfig.Repos {
+		if base, ok := bases[repo.Name]; ok {
+			wsConfig.Repos[i].BaseBranch = base
+		}
+	}
+	return s.workspaces.SaveConfig(ctx, root, wsConfig)
+}
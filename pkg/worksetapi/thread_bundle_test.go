@@ -0,0 +1,165 @@
+package worksetapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportThreadStripsLocalPaths(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a")
+	cfg := env.loadConfig()
+	repo := cfg.Repos["repo-a"]
+	repo.URL = "git@github.com:acme/repo-a.git"
+	cfg.Repos["repo-a"] = repo
+	env.saveConfig(cfg)
+	ctx := context.Background()
+
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"repo-a"}})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	root := created.Workspace.Path
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	wsCfg.Repos[0].BaseBranch = "release"
+	if err := config.SaveWorkspace(workspace.WorksetFile(root), wsCfg); err != nil {
+		t.Fatalf("save workspace config: %v", err)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	state.PullRequests = map[string]workspace.PullRequestState{"repo-a": {Repo: "acme/repo-a", Number: 42}}
+	if err := workspace.SaveState(root, state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	cfg = env.loadConfig()
+	ref := cfg.Workspaces["demo"]
+	ref.Description = "Login fixes"
+	cfg.Workspaces["demo"] = ref
+	env.saveConfig(cfg)
+	worktree := workspace.RepoWorktreePath(root, created.Workspace.Branch, "repo-a")
+	env.git.refs[refKey(worktree, "refs/remotes/origin/"+created.Workspace.Branch)] = false
+
+	result, err := env.svc.ExportThread(ctx, WorkspaceSelector{Value: "demo"})
+	if err != nil {
+		t.Fatalf("ExportThread: %v", err)
+	}
+	bundle := result.Bundle
+	if bundle.Version != ThreadBundleVersion || bundle.Workset != "demo" || bundle.Description != "Login fixes" {
+		t.Fatalf("unexpected bundle metadata: %+v", bundle)
+	}
+	if len(bundle.Workspace.Repos) != 1 {
+		t.Fatalf("expected one repo, got %+v", bundle.Workspace.Repos)
+	}
+	if got := bundle.Workspace.Repos[0]; got.LocalPath != "" || got.BaseBranch != "release" {
+		t.Fatalf("unexpected bundled repo: %+v", got)
+	}
+	if remote := bundle.Remotes["repo-a"]; remote.URL != "git@github.com:acme/repo-a.git" || remote.Remote != "origin" {
+		t.Fatalf("unexpected bundled remote: %+v", remote)
+	}
+	if bundle.State.PullRequests["repo-a"].Number != 42 {
+		t.Fatalf("expected tracked pull request, got %+v", bundle.State.PullRequests)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "push it before importing") {
+		t.Fatalf("expected unpushed branch warning, got %v", result.Warnings)
+	}
+}
+
+func TestImportThreadRegistersReposAndChecksOutBranches(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	bundle := ThreadBundle{
+		Version:     ThreadBundleVersion,
+		Workset:     "platform",
+		Description: "Login fixes",
+		Color:       "#3366ff",
+		Workspace: config.WorkspaceConfig{
+			Name: "demo",
+			Repos: []config.RepoConfig{
+				{Name: "api", RepoDir: "api", BaseBranch: "release"},
+				{Name: "web", RepoDir: "web", Branch: "fix/login"},
+			},
+		},
+		State: workspace.State{
+			CurrentBranch: "demo",
+			PullRequests:  map[string]workspace.PullRequestState{"web": {Repo: "acme/web", Number: 7}},
+		},
+		Remotes: map[string]ThreadBundleRemote{
+			"api": {URL: "git@github.com:acme/api.git", Remote: "origin", DefaultBranch: "main"},
+			"web": {URL: "git@github.com:acme/web.git", Remote: "origin", DefaultBranch: "main"},
+		},
+	}
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("marshal bundle: %v", err)
+	}
+	parsed, err := ParseThreadBundle(data)
+	if err != nil {
+		t.Fatalf("ParseThreadBundle: %v", err)
+	}
+
+	result, err := env.svc.ImportThread(ctx, ThreadImportInput{Bundle: parsed})
+	if err != nil {
+		t.Fatalf("ImportThread: %v", err)
+	}
+	cfg := env.loadConfig()
+	if cfg.Repos["api"].URL != "git@github.com:acme/api.git" || cfg.Repos["web"].URL != "git@github.com:acme/web.git" {
+		t.Fatalf("expected bundle repos registered, got %+v", cfg.Repos)
+	}
+	ref := cfg.Workspaces["demo"]
+	if ref.Workset != "platform" || ref.Description != "Login fixes" || ref.Color != "#3366ff" {
+		t.Fatalf("unexpected thread metadata: %+v", ref)
+	}
+	if add := worktreeAddFor(t, env.git.worktreeAdds, "web"); add.BranchName != "fix/login" {
+		t.Fatalf("expected web on fix/login, got %+v", add)
+	}
+	if add := worktreeAddFor(t, env.git.worktreeAdds, "api"); add.BranchName != "demo" {
+		t.Fatalf("expected api on the thread branch, got %+v", add)
+	}
+	root := result.Workspace.Path
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	for _, repo := range wsCfg.Repos {
+		if repo.Name == "api" && repo.BaseBranch != "release" {
+			t.Fatalf("expected api base branch restored, got %+v", repo)
+		}
+		if repo.Name == "web" && repo.Branch != "fix/login" {
+			t.Fatalf("expected web branch recorded, got %+v", repo)
+		}
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if state.PullRequests["web"].Number != 7 {
+		t.Fatalf("expected tracked pull request restored, got %+v", state.PullRequests)
+	}
+}
+
+func TestImportThreadRejectsConflictingRepoURL(t *testing.T) {
+	env := newTestEnv(t)
+	cfg := env.loadConfig()
+	cfg.Repos = map[string]config.RegisteredRepo{"api": {URL: "git@github.com:other/api.git"}}
+	env.saveConfig(cfg)
+
+	_, err := env.svc.ImportThread(context.Background(), ThreadImportInput{Bundle: ThreadBundle{
+		Version:   ThreadBundleVersion,
+		Workspace: config.WorkspaceConfig{Name: "demo", Repos: []config.RepoConfig{{Name: "api"}}},
+		Remotes:   map[string]ThreadBundleRemote{"api": {URL: "git@github.com:acme/api.git"}},
+	}})
+	_ = requireErrorType[ConflictError](t, err)
+	if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+		t.Fatalf("thread registered despite conflicting repo")
+	}
+}
@@ -0,0 +1,99 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/ops"
+)
+
+// ThreadLog merges the commits each repo's thread branch has made since its
+// merge-base with the repo's default branch into one timeline, newest first.
+// Repos with a missing worktree, no base branch or an unreadable log are
+// reported as warnings so one broken repo does not hide the rest of the
+// thread.
+func (s *Service) ThreadLog(ctx context.Context, input ThreadLogInput) (ThreadLogResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return ThreadLogResult{}, err
+	}
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, input.Selector)
+	if err != nil {
+		return ThreadLogResult{}, err
+	}
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return ThreadLogResult{}, err
+	}
+	branch := state.CurrentBranch
+	if branch == "" {
+		branch = cfg.Defaults.BaseBranch
+	}
+	targets, err := selectExecRepos(wsRoot, branch, wsConfig, input.Repos)
+	if err != nil {
+		return ThreadLogResult{}, err
+	}
+
+	result := ThreadLogResult{Commits: []ThreadCommitJSON{}, Config: info}
+	var times []time.Time
+	for _, target := range targets {
+		if stat, err := os.Stat(target.worktreePath); err != nil || !stat.IsDir() {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: worktree not found at %s", target.repo.Name, target.worktreePath))
+			continue
+		}
+		// Without a base to exclude the log would be the repo's whole history.
+		defaults := resolveRepoDefaults(cfg, target.repo)
+		base := ops.BaseRef(ctx, s.git, target.worktreePath, defaults.Remote, defaults.DefaultBranch)
+		if base == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: no default branch to compare against; skipped", target.repo.Name))
+			continue
+		}
+		if exists, err := s.git.ReferenceExists(ctx, target.worktreePath, base); err != nil || !exists {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: base branch %s not found; skipped", target.repo.Name, defaults.DefaultBranch))
+			continue
+		}
+		commits, err := s.git.Log(ctx, target.worktreePath, git.LogOptions{
+			Ref:     "HEAD",
+			Exclude: base,
+			Since:   input.Since,
+			Author:  input.Author,
+			Patch:   input.Patch,
+		})
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", target.repo.Name, err))
+			continue
+		}
+		for _, commit := range commits {
+			result.Commits = append(result.Commits, ThreadCommitJSON{
+				Repo:        target.repo.Name,
+				SHA:         commit.SHA,
+				Author:      commit.Author,
+				AuthorEmail: commit.AuthorEmail,
+				Time:        commit.Time.UTC().Format(time.RFC3339),
+				Subject:     commit.Subject,
+				Patch:       commit.Patch,
+			})
+			times = append(times, commit.Time)
+		}
+	}
+
+	// Sort indexes rather than commits so ties keep repo config order and
+	// each repo's own newest-first order.
+	order := make([]int, len(result.Commits))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return times[b].Compare(times[a])
+	})
+	sorted := make([]ThreadCommitJSON, len(order))
+	for i, index := range order {
+		sorted[i] = result.Commits[index]
+	}
+	result.Commits = sorted
+	return result, nil
+}
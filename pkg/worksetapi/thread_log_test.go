@@ -0,0 +1,116 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThreadLogMergesReposNewestFirst(t *testing.T) {
+	env, fake := newGittestEnv(t, "api", "web")
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api", "web"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	apiPath := filepath.Join(created.Workspace.Path, "api")
+	webPath := filepath.Join(created.Workspace.Path, "web")
+	fake.Commit(t, filepath.Join(env.root, "origins", "api"), "upstream change", map[string]string{"up.go": "package api\n"})
+	fake.Commit(t, apiPath, "add handler", map[string]string{"handler.go": "package api\n"})
+	fake.CommitAs(t, webPath, "Pat <pat@example.com>", "add page", map[string]string{"page.ts": "export {}\n"})
+	fake.Commit(t, apiPath, "wire handler", nil)
+
+	result, err := env.svc.ThreadLog(ctx, ThreadLogInput{Selector: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	var got []string
+	for _, commit := range result.Commits {
+		got = append(got, commit.Repo+":"+commit.Subject)
+	}
+	want := []string{"api:wire handler", "web:add page", "api:add handler"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if web := result.Commits[1]; web.Author != "Pat" || web.AuthorEmail != "pat@example.com" || web.SHA == "" {
+		t.Fatalf("expected web commit by Pat, got %+v", web)
+	}
+
+	result, err = env.svc.ThreadLog(ctx, ThreadLogInput{
+		Selector: WorkspaceSelector{Value: "demo"},
+		Author:   "pat@",
+		Patch:    true,
+	})
+	if err != nil {
+		t.Fatalf("log by author: %v", err)
+	}
+	if len(result.Commits) != 1 || !strings.Contains(result.Commits[0].Patch, "page.ts") {
+		t.Fatalf("expected Pat's commit with its patch, got %+v", result.Commits)
+	}
+
+	newest, err := time.Parse(time.RFC3339, result.Commits[0].Time)
+	if err != nil {
+		t.Fatalf("parse time: %v", err)
+	}
+	result, err = env.svc.ThreadLog(ctx, ThreadLogInput{
+		Selector: WorkspaceSelector{Value: "demo"},
+		Repos:    []string{"api"},
+		Since:    newest,
+	})
+	if err != nil {
+		t.Fatalf("log since: %v", err)
+	}
+	if len(result.Commits) != 1 || result.Commits[0].Subject != "wire handler" {
+		t.Fatalf("expected only api commits after %s, got %+v", newest, result.Commits)
+	}
+}
+
+func TestThreadLogWarnsOnUnreadableRepo(t *testing.T) {
+	env, fake := newGittestEnv(t, "api", "web")
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api", "web"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	fake.Commit(t, filepath.Join(created.Workspace.Path, "api"), "add handler", nil)
+	fake.Fail("Log", filepath.Join(created.Workspace.Path, "web"), errors.New("log failed"))
+
+	result, err := env.svc.ThreadLog(ctx, ThreadLogInput{Selector: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if len(result.Commits) != 1 || len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "web:") {
+		t.Fatalf("expected api commit and a web warning, got %+v", result)
+	}
+}
+
+func TestThreadLogSkipsRepoWithoutBaseBranch(t *testing.T) {
+	env, fake := newGittestEnv(t, "api", "web")
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"api", "web"}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	fake.Commit(t, filepath.Join(created.Workspace.Path, "api"), "add handler", nil)
+	fake.Commit(t, filepath.Join(created.Workspace.Path, "web"), "add page", nil)
+	cfg := env.loadConfig()
+	web := cfg.Repos["web"]
+	web.DefaultBranch = "trunk"
+	cfg.Repos["web"] = web
+	env.saveConfig(cfg)
+
+	result, err := env.svc.ThreadLog(ctx, ThreadLogInput{Selector: WorkspaceSelector{Value: "demo"}})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if len(result.Commits) != 1 || result.Commits[0].Repo != "api" {
+		t.Fatalf("expected only the api commit, got %+v", result.Commits)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "web: base branch trunk not found") {
+		t.Fatalf("expected web to be skipped with a warning, got %v", result.Warnings)
+	}
+}
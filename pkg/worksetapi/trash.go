@@ -0,0 +1,320 @@
+package worksetapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/ops"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+const (
+	trashDirName    = "trash"
+	trashRecordFile = ".workset-trash.json"
+	trashTimeLayout = "20060102-150405"
+)
+
+// trashRecord is written into a trashed thread directory so it can be
+// restored with its original name, location, and registration.
+type trashRecord struct {
+	Name      string              `json:"name"`
+	Path      string              `json:"path"`
+	DeletedAt string              `json:"deleted_at"`
+	Ref       config.WorkspaceRef `json:"ref"`
+}
+
+func trashRoot(cfg config.GlobalConfig) (string, error) {
+	root := strings.TrimSpace(cfg.Defaults.WorksetRoot)
+	if root == "" {
+		return "", ValidationError{Message: "defaults.workset_root must be set to use the trash"}
+	}
+	return filepath.Join(root, trashDirName), nil
+}
+
+// moveToTrash moves a thread directory into the trash and repairs the git
+// metadata of its worktrees so they stay usable there. It returns the trash
+// entry path.
+func (s *Service) moveToTrash(ctx context.Context, cfg config.GlobalConfig, name, root string) (string, error) {
+	trash, err := trashRoot(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(trash, 0o755); err != nil {
+		return "", err
+	}
+	displayName := name
+	if displayName == "" {
+		displayName = filepath.Base(root)
+	}
+	now := s.clock()
+	base := workspace.WorkspaceDirName(displayName) + "-" + now.UTC().Format(trashTimeLayout)
+	entry := filepath.Join(trash, base)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(entry); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		entry = filepath.Join(trash, fmt.Sprintf("%s-%d", base, i))
+	}
+	if err := os.Rename(root, entry); err != nil {
+		return "", fmt.Errorf("move thread to trash (delete it permanently with --purge instead): %w", err)
+	}
+	record := trashRecord{
+		Name:      displayName,
+		Path:      root,
+		DeletedAt: now.Format(time.RFC3339),
+		Ref:       cfg.Workspaces[name],
+	}
+	if err := writeTrashRecord(entry, record); err != nil {
+		_ = os.Rename(entry, root)
+		return "", err
+	}
+	if err := ops.RepairWorkspaceWorktrees(ctx, entry, s.git); err != nil {
+		_ = os.Remove(filepath.Join(entry, trashRecordFile))
+		if undoErr := os.Rename(entry, root); undoErr == nil {
+			_ = ops.RepairWorkspaceWorktrees(context.WithoutCancel(ctx), root, s.git)
+		}
+		return "", err
+	}
+	return entry, nil
+}
+
+// ListTrash lists deleted threads that can still be restored.
+func (s *Service) ListTrash(ctx context.Context) (TrashListResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return TrashListResult{}, err
+	}
+	entries, warnings, err := s.trashEntries(cfg)
+	if err != nil {
+		return TrashListResult{}, err
+	}
+	return TrashListResult{Entries: entries, Warnings: warnings, Config: info}, nil
+}
+
+// RestoreTrash moves a trashed thread back to where it was deleted from (or
+// to input.Path) and registers it again.
+func (s *Service) RestoreTrash(ctx context.Context, input TrashRestoreInput) (TrashRestoreResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return TrashRestoreResult{}, err
+	}
+	entry, record, err := s.findTrashEntry(cfg, input.ID)
+	if err != nil {
+		return TrashRestoreResult{}, err
+	}
+	name := record.Name
+	target := record.Path
+	if path := strings.TrimSpace(input.Path); path != "" {
+		if target, err = filepath.Abs(path); err != nil {
+			return TrashRestoreResult{}, err
+		}
+	}
+	if _, ok := cfg.Workspaces[name]; ok {
+		return TrashRestoreResult{}, ConflictError{Message: fmt.Sprintf("thread %q already exists; rename or remove it before restoring", name)}
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return TrashRestoreResult{}, ConflictError{Message: fmt.Sprintf("%s already exists; restore to another path with --path", target)}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return TrashRestoreResult{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return TrashRestoreResult{}, err
+	}
+	if err := os.Rename(entry.TrashPath, target); err != nil {
+		return TrashRestoreResult{}, err
+	}
+	var warnings []string
+	if err := ops.RepairWorkspaceWorktrees(ctx, target, s.git); err != nil {
+		warnings = append(warnings, fmt.Sprintf("repair worktrees: %v", err))
+	}
+
+	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, loadInfo config.GlobalConfigLoadInfo) error {
+		info = loadInfo
+		if err := workspaceCreateConflict(*cfg, name, target); err != nil {
+			return err
+		}
+		ref := record.Ref
+		ref.Path = target
+		cfg.Workspaces[name] = ref
+		registerWorkspace(cfg, name, target, s.clock(), workspaceRefWorkset(ref))
+		s.rebuildWorksetRepoModel(ctx, cfg)
+		return nil
+	}); err != nil {
+		if undoErr := os.Rename(target, entry.TrashPath); undoErr == nil {
+			_ = ops.RepairWorkspaceWorktrees(context.WithoutCancel(ctx), entry.TrashPath, s.git)
+		}
+		return TrashRestoreResult{}, err
+	}
+	if err := os.Remove(filepath.Join(target, trashRecordFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		warnings = append(warnings, fmt.Sprintf("remove trash record: %v", err))
+	}
+	entry.Path = target
+	return TrashRestoreResult{Entry: entry, Warnings: warnings, Config: info}, nil
+}
+
+// PurgeTrash permanently deletes trashed threads: the entries named in
+// input.IDs, every entry with input.All, or otherwise those older than
+// defaults.trash_retention_days.
+func (s *Service) PurgeTrash(ctx context.Context, input TrashPurgeInput) (TrashPurgeResult, error) {
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return TrashPurgeResult{}, err
+	}
+	var targets []TrashEntryJSON
+	var warnings []string
+	switch {
+	case len(input.IDs) > 0:
+		for _, id := range input.IDs {
+			entry, _, err := s.findTrashEntry(cfg, id)
+			if err != nil {
+				return TrashPurgeResult{}, err
+			}
+			targets = append(targets, entry)
+		}
+	default:
+		entries, listWarnings, err := s.trashEntries(cfg)
+		if err != nil {
+			return TrashPurgeResult{}, err
+		}
+		warnings = append(warnings, listWarnings...)
+		now := s.clock()
+		for _, entry := range entries {
+			if input.All || trashEntryExpired(entry, now) {
+				targets = append(targets, entry)
+			}
+		}
+	}
+	purged := make([]TrashEntryJSON, 0, len(targets))
+	for _, entry := range targets {
+		if err := s.deleteThreadFiles(ctx, entry.TrashPath, cfg.Defaults, true); err != nil {
+			warnings = append(warnings, fmt.Sprintf("purge %s: %v", entry.ID, err))
+			continue
+		}
+		purged = append(purged, entry)
+	}
+	return TrashPurgeResult{Purged: purged, Warnings: warnings, Config: info}, nil
+}
+
+// purgeExpiredTrash drops entries past the retention period, reporting
+// failures as warnings so they never block the deletion that triggered it.
+func (s *Service) purgeExpiredTrash(ctx context.Context) []string {
+	result, err := s.PurgeTrash(ctx, TrashPurgeInput{})
+	if err != nil {
+		return []string{fmt.Sprintf("purge expired trash: %v", err)}
+	}
+	return result.Warnings
+}
+
+// deleteThreadFiles removes a thread's worktrees from their repos and then
+// its directory.
+func (s *Service) deleteThreadFiles(ctx context.Context, root string, defaults config.Defaults, force bool) error {
+	if err := s.removeWorkspaceRepoWorktrees(ctx, root, defaults, force); err != nil {
+		return err
+	}
+	return os.RemoveAll(root)
+}
+
+func (s *Service) findTrashEntry(cfg config.GlobalConfig, id string) (TrashEntryJSON, trashRecord, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return TrashEntryJSON{}, trashRecord{}, ValidationError{Message: "trash entry id required"}
+	}
+	trash, err := trashRoot(cfg)
+	if err != nil {
+		return TrashEntryJSON{}, trashRecord{}, err
+	}
+	record, err := readTrashRecord(filepath.Join(trash, id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return TrashEntryJSON{}, trashRecord{}, NotFoundError{Message: fmt.Sprintf("trash entry %q not found", id)}
+		}
+		return TrashEntryJSON{}, trashRecord{}, err
+	}
+	return trashEntryJSON(cfg, trash, id, record), record, nil
+}
+
+func (s *Service) trashEntries(cfg config.GlobalConfig) ([]TrashEntryJSON, []string, error) {
+	trash, err := trashRoot(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	dirs, err := os.ReadDir(trash)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []TrashEntryJSON{}, nil, nil
+		}
+		return nil, nil, err
+	}
+	entries := make([]TrashEntryJSON, 0, len(dirs))
+	var warnings []string
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		record, err := readTrashRecord(filepath.Join(trash, dir.Name()))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping trash entry %s: %v", dir.Name(), err))
+			continue
+		}
+		entries = append(entries, trashEntryJSON(cfg, trash, dir.Name(), record))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].DeletedAt != entries[j].DeletedAt {
+			return entries[i].DeletedAt > entries[j].DeletedAt
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, warnings, nil
+}
+
+func trashEntryJSON(cfg config.GlobalConfig, trash, id string, record trashRecord) TrashEntryJSON {
+	entry := TrashEntryJSON{
+		ID:        id,
+		Name:      record.Name,
+		Workset:   worksetNameForThread(record.Name, record.Ref),
+		Path:      record.Path,
+		TrashPath: filepath.Join(trash, id),
+		DeletedAt: record.DeletedAt,
+	}
+	if deletedAt, err := time.Parse(time.RFC3339, record.DeletedAt); err == nil {
+		retention := time.Duration(cfg.Defaults.TrashRetentionDays) * 24 * time.Hour
+		entry.ExpiresAt = deletedAt.Add(retention).Format(time.RFC3339)
+	}
+	return entry
+}
+
+func trashEntryExpired(entry TrashEntryJSON, now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, entry.ExpiresAt)
+	if err != nil {
+		return false
+	}
+	return !now.Before(expiresAt)
+}
+
+func writeTrashRecord(entry string, record trashRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(entry, trashRecordFile), append(data, '\n'), 0o644)
+}
+
+func readTrashRecord(entry string) (trashRecord, error) {
+	data, err := os.ReadFile(filepath.Join(entry, trashRecordFile))
+	if err != nil {
+		return trashRecord{}, err
+	}
+	var record trashRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return trashRecord{}, fmt.Errorf("parse %s: %w", trashRecordFile, err)
+	}
+	return record, nil
+}
@@ -0,0 +1,166 @@
+package worksetapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// linkWorktreesToRepo makes the fake git's worktrees linked worktrees whose
+// .git file points at an admin dir inside repoPath.
+func linkWorktreesToRepo(t *testing.T, env *testEnv, repoPath string) {
+	t.Helper()
+	env.git.worktreeAddHook = func(path string) error {
+		admin := filepath.Join(repoPath, ".git", "worktrees", filepath.Base(path))
+		if err := os.MkdirAll(admin, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(admin, "commondir"), []byte("../..\n"), 0o644); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(filepath.Join(path, ".git")); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(path, ".git"), []byte("gitdir: "+admin+"\n"), 0o644)
+	}
+}
+
+func TestDeleteWorkspaceMovesThreadToTrashAndRestores(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a")
+	linkWorktreesToRepo(t, env, env.loadConfig().Repos["repo-a"].Path)
+	ctx := context.Background()
+	created, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{Name: "demo", Repos: []string{"repo-a"}})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	root := created.Workspace.Path
+
+	result, err := env.svc.DeleteWorkspace(ctx, WorkspaceDeleteInput{
+		Selector:    WorkspaceSelector{Value: "demo"},
+		DeleteFiles: true,
+		Force:       true,
+		Confirmed:   true,
+	})
+	if err != nil {
+		t.Fatalf("delete workspace: %v", err)
+	}
+	trashPath := result.Payload.TrashPath
+	if filepath.Dir(trashPath) != filepath.Join(env.root, "trash") || result.Payload.TrashID != "demo-20240102-030405" {
+		t.Fatalf("unexpected trash location: %+v", result.Payload)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected thread moved away, stat err: %v", err)
+	}
+	if len(env.git.worktreeRemovs) != 0 {
+		t.Fatalf("expected worktrees kept, got removals %+v", env.git.worktreeRemovs)
+	}
+	if len(env.git.worktreeRepairs) != 1 || env.git.worktreeRepairs[0] != filepath.Join(trashPath, "repo-a") {
+		t.Fatalf("expected trashed worktree repaired, got %v", env.git.worktreeRepairs)
+	}
+	if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+		t.Fatalf("expected thread unregistered")
+	}
+
+	list, err := env.svc.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].Name != "demo" || list.Entries[0].Path != root {
+		t.Fatalf("unexpected trash entries: %+v", list.Entries)
+	}
+	if list.Entries[0].ExpiresAt != "2024-01-16T03:04:05Z" {
+		t.Fatalf("expected default retention of 14 days, got %s", list.Entries[0].ExpiresAt)
+	}
+
+	if _, err := env.svc.RestoreTrash(ctx, TrashRestoreInput{ID: result.Payload.TrashID}); err != nil {
+		t.Fatalf("RestoreTrash: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "repo-a")); err != nil {
+		t.Fatalf("expected worktree restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, trashRecordFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected trash record removed, stat err: %v", err)
+	}
+	if ref, ok := env.loadConfig().Workspaces["demo"]; !ok || ref.Path != root {
+		t.Fatalf("expected thread registered at %s, got %+v", root, ref)
+	}
+	if got := env.git.worktreeRepairs[len(env.git.worktreeRepairs)-1]; got != filepath.Join(root, "repo-a") {
+		t.Fatalf("expected restored worktree repaired, got %s", got)
+	}
+}
+
+func TestDeleteWorkspacePurgesExpiredTrash(t *testing.T) {
+	env := newTestEnv(t)
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		Clock:      func() time.Time { return env.now },
+		Logf:       func(string, ...any) {},
+	})
+	ctx := context.Background()
+	deleteThread := func(name string) WorkspaceDeleteResultJSON {
+		t.Helper()
+		env.createWorkspace(ctx, name)
+		result, err := env.svc.DeleteWorkspace(ctx, WorkspaceDeleteInput{
+			Selector:    WorkspaceSelector{Value: name},
+			DeleteFiles: true,
+			Force:       true,
+			Confirmed:   true,
+		})
+		if err != nil {
+			t.Fatalf("delete %s: %v", name, err)
+		}
+		return result.Payload
+	}
+
+	old := deleteThread("old")
+	env.now = env.now.Add(15 * 24 * time.Hour)
+	recent := deleteThread("recent")
+
+	if _, err := os.Stat(old.TrashPath); !os.IsNotExist(err) {
+		t.Fatalf("expected expired entry purged, stat err: %v", err)
+	}
+	list, err := env.svc.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].ID != recent.TrashID {
+		t.Fatalf("unexpected trash entries: %+v", list.Entries)
+	}
+
+	purged, err := env.svc.PurgeTrash(ctx, TrashPurgeInput{All: true})
+	if err != nil {
+		t.Fatalf("PurgeTrash: %v", err)
+	}
+	if len(purged.Purged) != 1 {
+		t.Fatalf("expected one purged entry, got %+v", purged.Purged)
+	}
+	if _, err := os.Stat(recent.TrashPath); !os.IsNotExist(err) {
+		t.Fatalf("expected entry purged, stat err: %v", err)
+	}
+}
+
+func TestRestoreTrashRejectsExistingThread(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.createWorkspace(ctx, "demo")
+	result, err := env.svc.DeleteWorkspace(ctx, WorkspaceDeleteInput{
+		Selector:    WorkspaceSelector{Value: "demo"},
+		DeleteFiles: true,
+		Force:       true,
+		Confirmed:   true,
+	})
+	if err != nil {
+		t.Fatalf("delete workspace: %v", err)
+	}
+	env.createWorkspace(ctx, "demo")
+
+	_, err = env.svc.RestoreTrash(ctx, TrashRestoreInput{ID: result.Payload.TrashID})
+	_ = requireErrorType[ConflictError](t, err)
+	if _, err := os.Stat(result.Payload.TrashPath); err != nil {
+		t.Fatalf("expected entry kept in trash: %v", err)
+	}
+}
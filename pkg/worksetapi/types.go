@@ -135,6 +135,107 @@ type WorkspaceDeleteResultJSON struct {
 	Name         string `json:"name,omitempty"`
 	Path         string `json:"path"`
 	DeletedFiles bool   `json:"deleted_files"`
+	TrashID      string `json:"trash_id,omitempty"`
+	TrashPath    string `json:"trash_path,omitempty"`
+}
+
+// TrashEntryJSON describes a deleted thread held in the trash.
+type TrashEntryJSON struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Workset   string `json:"workset,omitempty"`
+	Path      string `json:"path"`
+	TrashPath string `json:"trash_path"`
+	DeletedAt string `json:"deleted_at"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// TrashListResult returns trash entries with config metadata.
+type TrashListResult struct {
+	Entries  []TrashEntryJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// TrashRestoreResult returns the restored entry with config metadata.
+type TrashRestoreResult struct {
+	Entry    TrashEntryJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// TrashPurgeResult returns the purged entries with config metadata.
+type TrashPurgeResult struct {
+	Purged   []TrashEntryJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// AutoArchiveThreadJSON reports a thread matched by the auto-archive policy.
+// Action is "archived", "would_archive" (dry run), or "kept" when the thread
+// still has dirty or unpushed repos.
+type AutoArchiveThreadJSON struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// AutoArchiveResult returns the matched threads with config metadata.
+type AutoArchiveResult struct {
+	Threads  []AutoArchiveThreadJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// GCPruneJSON reports an archived thread whose worktrees were considered for
+// pruning. Action is "pruned", "would_prune" (dry run), or "kept".
+type GCPruneJSON struct {
+	Name   string   `json:"name"`
+	Path   string   `json:"path"`
+	Repos  []string `json:"repos"`
+	Action string   `json:"action"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// GCResultJSON is the JSON payload for GC.
+type GCResultJSON struct {
+	Archived []AutoArchiveThreadJSON `json:"archived"`
+	Pruned   []GCPruneJSON           `json:"pruned"`
+}
+
+// GCResult returns archived and pruned threads with config metadata.
+type GCResult struct {
+	Archived []AutoArchiveThreadJSON
+	Pruned   []GCPruneJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// RepoStoreEntryJSON reports one mirror in the repo store. Bare is false for
+// clones made before the store switched to bare mirrors. LastFetch is empty
+// when the mirror was never fetched through workset. Action is set by
+// fetch, gc, and verify: "cloned", "fetched", "collected", "orphaned",
+// "removed", "ok", "missing", or "failed".
+type RepoStoreEntryJSON struct {
+	Name      string `json:"name"`
+	URL       string `json:"url,omitempty"`
+	Path      string `json:"path"`
+	Present   bool   `json:"present"`
+	Bare      bool   `json:"bare"`
+	SizeBytes int64  `json:"size_bytes"`
+	LastFetch string `json:"last_fetch,omitempty"`
+	Worktrees int    `json:"worktrees"`
+	Action    string `json:"action,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RepoStoreResult returns repo store entries with config metadata.
+type RepoStoreResult struct {
+	Root     string
+	Repos    []RepoStoreEntryJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
 }
 
 // WorkspaceDeleteResult includes safety details and config metadata.
@@ -217,11 +318,15 @@ type HookPending struct {
 
 // RepoHookPreviewJSON describes a single hook definition discovered from source.
 type RepoHookPreviewJSON struct {
-	ID      string   `json:"id"`
-	On      []string `json:"on,omitempty"`
-	Run     []string `json:"run,omitempty"`
-	Cwd     string   `json:"cwd,omitempty"`
-	OnError string   `json:"on_error,omitempty"`
+	ID        string   `json:"id"`
+	On        []string `json:"on,omitempty"`
+	Run       []string `json:"run,omitempty"`
+	Cwd       string   `json:"cwd,omitempty"`
+	OnError   string   `json:"on_error,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	Parallel  string   `json:"parallel,omitempty"`
+	Timeout   string   `json:"timeout,omitempty"`
+	Retries   int      `json:"retries,omitempty"`
 }
 
 // RepoHooksPreviewJSON is the JSON payload for pre-clone hook discovery.
@@ -253,31 +358,38 @@ type HooksRunResult struct {
 
 // HookRunJSON reports individual hook execution results.
 type HookRunJSON struct {
-	ID      string        `json:"id"`
-	Status  HookRunStatus `json:"status"`
-	LogPath string        `json:"log_path,omitempty"`
+	ID       string        `json:"id"`
+	Status   HookRunStatus `json:"status"`
+	LogPath  string        `json:"log_path,omitempty"`
+	Reason   string        `json:"reason,omitempty"`
+	Attempts int           `json:"attempts,omitempty"`
+	TimedOut bool          `json:"timed_out,omitempty"`
 }
 
 // HookExecutionJSON reports hook execution results with repo and event context.
 type HookExecutionJSON struct {
-	Event   string        `json:"event"`
-	Repo    string        `json:"repo"`
-	ID      string        `json:"id"`
-	Status  HookRunStatus `json:"status"`
-	LogPath string        `json:"log_path,omitempty"`
+	Event    string        `json:"event"`
+	Repo     string        `json:"repo"`
+	ID       string        `json:"id"`
+	Status   HookRunStatus `json:"status"`
+	LogPath  string        `json:"log_path,omitempty"`
+	Reason   string        `json:"reason,omitempty"`
+	Attempts int           `json:"attempts,omitempty"`
+	TimedOut bool          `json:"timed_out,omitempty"`
 }
 
 // HookProgress describes lifecycle updates emitted while hooks run.
 type HookProgress struct {
-	Phase     string        `json:"phase"`
-	Event     string        `json:"event"`
-	Repo      string        `json:"repo"`
-	Workspace string        `json:"workspace,omitempty"`
-	HookID    string        `json:"hook_id"`
-	Reason    string        `json:"reason,omitempty"`
-	Status    HookRunStatus `json:"status,omitempty"`
-	LogPath   string        `json:"log_path,omitempty"`
-	Error     string        `json:"error,omitempty"`
+	Phase      string        `json:"phase"`
+	Event      string        `json:"event"`
+	Repo       string        `json:"repo"`
+	Workspace  string        `json:"workspace,omitempty"`
+	HookID     string        `json:"hook_id"`
+	Reason     string        `json:"reason,omitempty"`
+	SkipReason string        `json:"skip_reason,omitempty"`
+	Status     HookRunStatus `json:"status,omitempty"`
+	LogPath    string        `json:"log_path,omitempty"`
+	Error      string        `json:"error,omitempty"`
 }
 
 // HookProgressObserver receives live hook lifecycle updates.
@@ -318,12 +430,33 @@ type RepoRemoveResult struct {
 
 // RepoStatusJSON is the JSON payload for repo status reporting.
 type RepoStatusJSON struct {
-	Name    string `json:"name"`
-	Path    string `json:"path,omitempty"`
-	State   string `json:"state"`
-	Dirty   bool   `json:"dirty,omitempty"`
-	Missing bool   `json:"missing,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Name        string                     `json:"name"`
+	Path        string                     `json:"path,omitempty"`
+	State       string                     `json:"state"`
+	Dirty       bool                       `json:"dirty,omitempty"`
+	Missing     bool                       `json:"missing,omitempty"`
+	Branch      string                     `json:"branch,omitempty"`
+	Detached    bool                       `json:"detached,omitempty"`
+	Upstream    string                     `json:"upstream,omitempty"`
+	Ahead       int                        `json:"ahead,omitempty"`
+	Behind      int                        `json:"behind,omitempty"`
+	BaseRef     string                     `json:"base_ref,omitempty"`
+	BaseAhead   int                        `json:"base_ahead,omitempty"`
+	BaseBehind  int                        `json:"base_behind,omitempty"`
+	Stashes     int                        `json:"stashes,omitempty"`
+	Conflicts   []string                   `json:"conflicts,omitempty"`
+	PullRequest *RepoStatusPullRequestJSON `json:"pull_request,omitempty"`
+	FetchError  string                     `json:"fetch_error,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+}
+
+// RepoStatusPullRequestJSON summarizes the tracked pull request for a repo.
+type RepoStatusPullRequestJSON struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+	Draft  bool   `json:"draft,omitempty"`
+	Merged bool   `json:"merged,omitempty"`
 }
 
 // WorkspaceStatusResult returns per-repo status with config metadata.
@@ -332,6 +465,133 @@ type WorkspaceStatusResult struct {
 	Config   config.GlobalConfigLoadInfo
 }
 
+// ThreadCommitJSON is one commit in a thread log.
+type ThreadCommitJSON struct {
+	Repo        string `json:"repo"`
+	SHA         string `json:"sha"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	Time        string `json:"time"`
+	Subject     string `json:"subject"`
+	Patch       string `json:"patch,omitempty"`
+}
+
+// ThreadLogResult lists a thread's commits across repos, newest first.
+// Warnings name repos whose log could not be read.
+type ThreadLogResult struct {
+	Commits  []ThreadCommitJSON
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// RepoSyncJSON reports how a single repo was synced with its base branch.
+// Status is one of synced, up_to_date, conflicted, aborted, skipped, or failed.
+type RepoSyncJSON struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path,omitempty"`
+	Status    string   `json:"status"`
+	Mode      string   `json:"mode,omitempty"`
+	BaseRef   string   `json:"base_ref,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// WorkspaceSyncResult returns per-repo sync outcomes with config metadata.
+type WorkspaceSyncResult struct {
+	Repos  []RepoSyncJSON
+	Config config.GlobalConfigLoadInfo
+}
+
+// AdoptedRepoJSON reports a checkout found by AdoptWorkspace. Status is
+// adopted or skipped; Path is where an adopted checkout now lives.
+type AdoptedRepoJSON struct {
+	Name   string `json:"name,omitempty"`
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// WorkspaceAdoptResult returns the adopted thread and per-checkout outcomes
+// with config metadata.
+type WorkspaceAdoptResult struct {
+	Workspace WorkspaceCreatedJSON
+	Repos     []AdoptedRepoJSON
+	Warnings  []string
+	Config    config.GlobalConfigLoadInfo
+}
+
+// ThreadExportResult returns a thread bundle with export warnings and config
+// metadata.
+type ThreadExportResult struct {
+	Bundle   ThreadBundle
+	Warnings []string
+	Config   config.GlobalConfigLoadInfo
+}
+
+// ExecRepoResultJSON reports a single repo invocation from ExecEachRepo.
+type ExecRepoResultJSON struct {
+	Repo       string `json:"repo"`
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExecEachRepoJSON is the combined exit summary for ExecEachRepo.
+type ExecEachRepoJSON struct {
+	Workspace string               `json:"workspace"`
+	Command   []string             `json:"command"`
+	Total     int                  `json:"total"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Repos     []ExecRepoResultJSON `json:"repos"`
+}
+
+// ExecEachRepoResult wraps the per-repo exec summary.
+type ExecEachRepoResult struct {
+	Payload ExecEachRepoJSON
+}
+
+// ProcessStatusJSON is the state of one thread process. State is running,
+// exited, or stopped; ExitCode and ExitedAt describe the last exit.
+type ProcessStatusJSON struct {
+	Name      string `json:"name"`
+	Repo      string `json:"repo,omitempty"`
+	Command   string `json:"command"`
+	SessionID string `json:"session_id"`
+	State     string `json:"state"`
+	Restarts  int    `json:"restarts,omitempty"`
+	ExitCode  *int   `json:"exit_code,omitempty"`
+	StartedAt string `json:"started_at,omitempty"`
+	ExitedAt  string `json:"exited_at,omitempty"`
+}
+
+// ProcessesResult lists thread processes in start order.
+type ProcessesResult struct {
+	Processes []ProcessStatusJSON
+	Warnings  []string
+}
+
+// ProcessLogLineJSON is one line of process output with escape sequences
+// removed. Prefix is "repo/name", or the name for processes without a repo.
+type ProcessLogLineJSON struct {
+	Process string `json:"process"`
+	Prefix  string `json:"prefix"`
+	Text    string `json:"text"`
+}
+
+// ProcessLogsResult holds output lines grouped by process in config order
+// and the offsets to pass back to continue reading.
+type ProcessLogsResult struct {
+	Lines       []ProcessLogLineJSON
+	NextOffsets map[string]int64
+	Warnings    []string
+}
+
 // RegisteredRepoJSON is the JSON-friendly view of a registered repo entry.
 type RegisteredRepoJSON struct {
 	Name          string `json:"name"`
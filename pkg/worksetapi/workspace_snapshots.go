@@ -72,7 +72,7 @@ func (s *Service) ListWorkspaceSnapshots(ctx context.Context, opts WorkspaceSnap
 		repoDefaults := make(map[string]string, len(wsConfig.Repos))
 		for _, repo := range wsConfig.Repos {
 			config.ApplyRepoDefaults(&repo, cfg.Defaults)
-			defaults := resolveRepoDefaults(cfg, repo.Name)
+			defaults := resolveRepoDefaults(cfg, repo)
 			repoDefaults[repo.Name] = defaults.DefaultBranch
 			var trackedPR *TrackedPullRequestSnapshotJSON
 			if pr, ok := state.PullRequests[repo.Name]; ok {
@@ -0,0 +1,74 @@
+package worksetapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/ops"
+)
+
+// SyncWorkspace fetches each repo's remote and rebases (or merges) the thread
+// branch onto <remote>/<default_branch>. Repos that hit conflicts are left
+// mid-operation and reported; call again with Continue after resolving and
+// staging the conflicts, or with Abort to restore the previous branch tips.
+func (s *Service) SyncWorkspace(ctx context.Context, input WorkspaceSyncInput) (WorkspaceSyncResult, error) {
+	if input.Continue && input.Abort {
+		return WorkspaceSyncResult{}, ValidationError{Message: "continue and abort are mutually exclusive"}
+	}
+	action := ops.SyncStart
+	switch {
+	case input.Continue:
+		action = ops.SyncContinue
+	case input.Abort:
+		action = ops.SyncAbort
+	}
+	mode := git.IntegrateRebase
+	switch strings.TrimSpace(input.Mode) {
+	case "", string(git.IntegrateRebase):
+	case string(git.IntegrateMerge):
+		mode = git.IntegrateMerge
+	default:
+		return WorkspaceSyncResult{}, ValidationError{Message: fmt.Sprintf("unsupported sync mode %q (use rebase or merge)", input.Mode)}
+	}
+
+	cfg, info, err := s.loadGlobal(ctx)
+	if err != nil {
+		return WorkspaceSyncResult{}, err
+	}
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, input.Selector)
+	if err != nil {
+		return WorkspaceSyncResult{}, err
+	}
+	results, err := ops.Sync(ctx, ops.SyncInput{
+		WorkspaceRoot:       wsRoot,
+		Defaults:            cfg.Defaults,
+		RepoDefaultBranches: repoDefaultBranches(wsConfig, cfg),
+		RepoDefaults:        repoDefaultsMap(wsConfig, cfg),
+		Mode:                mode,
+		Action:              action,
+		Git:                 s.git,
+	})
+	if err != nil {
+		return WorkspaceSyncResult{}, err
+	}
+
+	payload := make([]RepoSyncJSON, 0, len(results))
+	for _, result := range results {
+		entry := RepoSyncJSON{
+			Name:      result.Name,
+			Path:      result.Path,
+			Status:    string(result.Outcome),
+			Mode:      string(result.Mode),
+			BaseRef:   result.BaseRef,
+			Conflicts: result.Conflicts,
+			Reason:    result.Reason,
+		}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		}
+		payload = append(payload, entry)
+	}
+	return WorkspaceSyncResult{Repos: payload, Config: info}, nil
+}
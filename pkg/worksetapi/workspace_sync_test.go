@@ -0,0 +1,83 @@
+package worksetapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func TestSyncWorkspaceReportsConflictsAndContinues(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	root := env.createWorkspace(ctx, "demo")
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(root))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	wsCfg.Repos = []config.RepoConfig{{Name: "repo-a", RepoDir: "repo-a"}, {Name: "repo-b", RepoDir: "repo-b"}}
+	if err := config.SaveWorkspace(workspace.WorksetFile(root), wsCfg); err != nil {
+		t.Fatalf("save workspace config: %v", err)
+	}
+	paths := map[string]string{}
+	for _, name := range []string{"repo-a", "repo-b"} {
+		paths[name] = filepath.Join(root, name)
+		if err := os.MkdirAll(paths[name], 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		env.git.ancestors[refKey(paths[name], "refs/remotes/origin/main->HEAD")] = false
+	}
+	env.git.integrateErr[paths["repo-a"]] = git.ConflictError{Mode: git.IntegrateMerge, Files: []string{"go.mod"}}
+
+	result, err := env.svc.SyncWorkspace(ctx, WorkspaceSyncInput{
+		Selector: WorkspaceSelector{Value: root},
+		Mode:     "merge",
+	})
+	if err != nil {
+		t.Fatalf("SyncWorkspace: %v", err)
+	}
+	a, b := result.Repos[0], result.Repos[1]
+	if a.Status != "conflicted" || a.Mode != "merge" || strings.Join(a.Conflicts, ",") != "go.mod" {
+		t.Fatalf("unexpected repo-a result: %+v", a)
+	}
+	if b.Status != "synced" || b.BaseRef != "refs/remotes/origin/main" {
+		t.Fatalf("unexpected repo-b result: %+v", b)
+	}
+	if len(env.git.fetches) != 2 {
+		t.Fatalf("expected both repos fetched, got %v", env.git.fetches)
+	}
+
+	result, err = env.svc.SyncWorkspace(ctx, WorkspaceSyncInput{
+		Selector: WorkspaceSelector{Value: root},
+		Continue: true,
+	})
+	if err != nil {
+		t.Fatalf("SyncWorkspace continue: %v", err)
+	}
+	if result.Repos[0].Status != "synced" || result.Repos[1].Status != "skipped" {
+		t.Fatalf("unexpected continue results: %+v", result.Repos)
+	}
+	if last := env.git.integrations[len(env.git.integrations)-1]; last != "merge --continue "+paths["repo-a"] {
+		t.Fatalf("expected merge --continue on repo-a, got %v", env.git.integrations)
+	}
+}
+
+func TestSyncWorkspaceRejectsInvalidInput(t *testing.T) {
+	env := newTestEnv(t)
+	root := env.createWorkspace(context.Background(), "demo")
+	cases := map[string]WorkspaceSyncInput{
+		"both": {Selector: WorkspaceSelector{Value: root}, Continue: true, Abort: true},
+		"mode": {Selector: WorkspaceSelector{Value: root}, Mode: "squash"},
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := env.svc.SyncWorkspace(context.Background(), input)
+			requireErrorType[ValidationError](t, err)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+// worksetTemplateForThread returns the template of the workset a thread
+// belongs to.
+func worksetTemplateForThread(cfg config.GlobalConfig, threadName string) (config.WorksetTemplate, bool) {
+	threadName = strings.TrimSpace(threadName)
+	if threadName == "" || len(cfg.WorksetTemplates) == 0 {
+		return config.WorksetTemplate{}, false
+	}
+	template, ok := cfg.WorksetTemplates[worksetNameForThread(threadName, cfg.Workspaces[threadName])]
+	return template, ok
+}
+
+// withTemplateAgent applies a template's agent overrides to defaults.
+func withTemplateAgent(defaults config.Defaults, template config.WorksetTemplate) config.Defaults {
+	if agent := strings.TrimSpace(template.Agent); agent != "" {
+		defaults.Agent = agent
+	}
+	if model := strings.TrimSpace(template.AgentModel); model != "" {
+		defaults.AgentModel = model
+	}
+	return defaults
+}
+
+// applyTemplateMetadata fills a new thread's description and color from its
+// workset template without overwriting values already set.
+func applyTemplateMetadata(cfg *config.GlobalConfig, threadName string, template config.WorksetTemplate) {
+	ref, ok := cfg.Workspaces[threadName]
+	if !ok {
+		return
+	}
+	if ref.Description == "" {
+		ref.Description = strings.TrimSpace(template.Description)
+	}
+	if ref.Color == "" {
+		ref.Color = strings.TrimSpace(template.Color)
+	}
+	cfg.Workspaces[threadName] = ref
+}
+
+// templateHooks converts template hook specs to hooks.
+func templateHooks(specs []config.HookSpec) []hooks.Hook {
+	if len(specs) == 0 {
+		return nil
+	}
+	converted := make([]hooks.Hook, 0, len(specs))
+	for _, spec := range specs {
+		hook := hooks.Hook{
+			ID:           spec.ID,
+			Run:          spec.Run,
+			Cwd:          spec.Cwd,
+			Env:          spec.Env,
+			OnError:      spec.OnError,
+			DependsOn:    spec.DependsOn,
+			Parallel:     spec.Parallel,
+			Timeout:      spec.Timeout,
+			Retries:      spec.Retries,
+			RetryBackoff: spec.RetryBackoff,
+		}
+		for _, event := range spec.On {
+			hook.On = append(hook.On, hooks.Event(event))
+		}
+		if spec.When != nil {
+			hook.When = &hooks.When{
+				FileExists: spec.When.FileExists,
+				Branch:     spec.When.Branch,
+				Repos:      spec.When.Repos,
+			}
+		}
+		converted = append(converted, hook)
+	}
+	return converted
+}
+
+// skippedHook is a hook left out of a run and why.
+type skippedHook struct {
+	id     string
+	reason string
+}
+
+// scheduleTemplateHooks picks the template hooks that run for event next to
+// the repo hooks that were scheduled. A template hook gives way to a
+// scheduled repo hook with its ID so the repo's definition wins; an
+// unscheduled repo hook, which belongs to an untrusted repo, shadows nothing.
+// A template hook that depends on an unscheduled repo hook for the event is
+// skipped, as are the template hooks that depend on it.
+func scheduleTemplateHooks(templates, repoHooks, scheduled []hooks.Hook, event hooks.Event) ([]hooks.Hook, []skippedHook) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	provided := map[string]bool{}
+	shadowed := map[string]bool{}
+	for _, hook := range scheduled {
+		provided[hook.ID] = true
+		if slices.Contains(hook.On, event) {
+			shadowed[hook.ID] = true
+		}
+	}
+	unscheduled := map[string]bool{}
+	for _, hook := range repoHooks {
+		if !provided[hook.ID] {
+			unscheduled[hook.ID] = slices.Contains(hook.On, event)
+		}
+	}
+	candidates := make([]hooks.Hook, 0, len(templates))
+	for _, hook := range templates {
+		if !shadowed[hook.ID] {
+			candidates = append(candidates, hook)
+			provided[hook.ID] = true
+		}
+	}
+
+	reasons := map[string]string{}
+	depReason := func(dep string) string {
+		switch {
+		case reasons[dep] != "":
+			return "depends on skipped hook " + dep
+		case !provided[dep] && unscheduled[dep]:
+			return "depends on untrusted repo hook " + dep
+		default:
+			return ""
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, hook := range candidates {
+			if reasons[hook.ID] != "" || !slices.Contains(hook.On, event) {
+				continue
+			}
+			for _, dep := range hook.DependsOn {
+				if reason := depReason(strings.TrimSpace(dep)); reason != "" {
+					reasons[hook.ID] = reason
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	run := make([]hooks.Hook, 0, len(candidates))
+	var skipped []skippedHook
+	for _, hook := range candidates {
+		if reason := reasons[hook.ID]; reason != "" {
+			skipped = append(skipped, skippedHook{id: hook.ID, reason: reason})
+			continue
+		}
+		// Dependencies on repo hooks for other events are ignored when they
+		// are scheduled; drop them when they are not, so they are not unknown.
+		var deps []string
+		for _, dep := range hook.DependsOn {
+			id := strings.TrimSpace(dep)
+			if _, repoHook := unscheduled[id]; repoHook && !provided[id] {
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		hook.DependsOn = deps
+		run = append(run, hook)
+	}
+	return run, skipped
+}
+
+// writeTemplateFiles writes a template's files into a new thread root.
+// Sources resolve relative to the global config directory; rendered files
+// expand the thread-level hook tokens.
+func (s *Service) writeTemplateFiles(
+	ctx context.Context,
+	root string,
+	defaults config.Defaults,
+	template config.WorksetTemplate,
+	configPath string,
+	tokens hooks.Context,
+) error {
+	if len(template.Files) == 0 {
+		return nil
+	}
+	for _, file := range template.Files {
+		target, err := templateFileTarget(root, file.Path)
+		if err != nil {
+			return err
+		}
+		content, err := templateFileContent(file, configPath)
+		if err != nil {
+			return err
+		}
+		if file.Render {
+			content = tokens.Expand(content)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if file.Append {
+			err = appendTemplateFile(target, content)
+		} else {
+			err = os.WriteFile(target, []byte(content), 0o644)
+		}
+		if err != nil {
+			return fmt.Errorf("write template file %s: %w", file.Path, err)
+		}
+	}
+	// Restore the generated AGENTS.md section and CLAUDE.md mirror in case a
+	// file replaced or extended AGENTS.md.
+	ws, err := s.workspaces.Load(ctx, root, defaults)
+	if err != nil {
+		return err
+	}
+	return workspace.UpdateAgentsFile(root, ws.Config, ws.State)
+}
+
+// validateTemplateFiles rejects template files that could not be written, so
+// a thread is not created only to be rolled back.
+func validateTemplateFiles(template config.WorksetTemplate) error {
+	for _, file := range template.Files {
+		if _, err := templateFileTarget("", file.Path); err != nil {
+			return err
+		}
+		if strings.TrimSpace(file.Source) != "" && file.Content != "" {
+			return ValidationError{Message: fmt.Sprintf("template file %q sets both source and content", file.Path)}
+		}
+	}
+	return nil
+}
+
+func templateFileTarget(root, path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", ValidationError{Message: "template file path required"}
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", ValidationError{Message: fmt.Sprintf("template file path %q must stay inside the thread root", path)}
+	}
+	return filepath.Join(root, clean), nil
+}
+
+func templateFileContent(file config.TemplateFile, configPath string) (string, error) {
+	source := strings.TrimSpace(file.Source)
+	if source == "" {
+		return file.Content, nil
+	}
+	if strings.HasPrefix(source, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		source = filepath.Join(home, strings.TrimPrefix(source, "~"))
+	} else if !filepath.IsAbs(source) && configPath != "" {
+		source = filepath.Join(filepath.Dir(configPath), source)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return "", fmt.Errorf("read template source for %s: %w", file.Path, err)
+	}
+	return string(data), nil
+}
+
+func appendTemplateFile(path, content string) error {
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(current) > 0 && !strings.HasSuffix(string(current), "\n") {
+		current = append(current, '\n')
+	}
+	if len(current) > 0 {
+		current = append(current, '\n')
+	}
+	return os.WriteFile(path, append(current, content...), 0o644)
+}
@@ -0,0 +1,180 @@
+package worksetapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func TestCreateWorkspaceAppliesWorksetTemplate(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a")
+	if err := os.WriteFile(filepath.Join(filepath.Dir(env.configPath), "setup.md"), []byte("run make deps\n"), 0o644); err != nil {
+		t.Fatalf("write template source: %v", err)
+	}
+	cfg := env.loadConfig()
+	cfg.Hooks.Enabled = true
+	cfg.WorksetRepos["platform"] = []string{"repo-a"}
+	cfg.WorksetTemplates = map[string]config.WorksetTemplate{
+		"platform": {
+			Description: "Platform work",
+			Color:       "#3366ff",
+			Agent:       "claude",
+			Files: []config.TemplateFile{
+				{Path: ".envrc", Content: "export THREAD={workspace.name}\n", Render: true},
+				{Path: "AGENTS.md", Content: "## Platform\nUse make.\n", Append: true},
+				{Path: "notes/setup.md", Source: "setup.md"},
+			},
+			Hooks: []config.HookSpec{{ID: "deps", On: []string{"worktree.created"}, Run: []string{"make", "deps"}}},
+		},
+	}
+	env.saveConfig(cfg)
+	runner := &recordingHookRunner{}
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		HookRunner: runner,
+		Clock:      func() time.Time { return env.now },
+		Logf:       func(string, ...any) {},
+	})
+	ctx := context.Background()
+
+	result, err := env.svc.CreateWorkspace(ctx, WorkspaceCreateInput{
+		Name:    "demo",
+		Workset: "platform",
+		Repos:   []string{"repo-a"},
+	})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	root := result.Workspace.Path
+	readFile := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		return string(data)
+	}
+	if got := readFile(".envrc"); got != "export THREAD=demo\n" {
+		t.Fatalf("unexpected .envrc: %q", got)
+	}
+	if got := readFile("notes/setup.md"); got != "run make deps\n" {
+		t.Fatalf("unexpected copied file: %q", got)
+	}
+	agents := readFile("AGENTS.md")
+	if !strings.Contains(agents, "## Platform") || !strings.Contains(agents, "Workspace Layout (generated)") {
+		t.Fatalf("expected template section alongside generated section, got:\n%s", agents)
+	}
+	if got := readFile("CLAUDE.md"); got != agents {
+		t.Fatalf("expected CLAUDE.md to mirror AGENTS.md")
+	}
+
+	ref := env.loadConfig().Workspaces["demo"]
+	if ref.Description != "Platform work" || ref.Color != "#3366ff" {
+		t.Fatalf("unexpected thread metadata: %+v", ref)
+	}
+	// repo-a is untrusted, but template hooks come from the user's config.
+	if len(runner.requests) != 1 || strings.Join(runner.requests[0].Command, " ") != "make deps" {
+		t.Fatalf("expected template hook to run once, got %+v", runner.requests)
+	}
+	if repos := runner.envValues("WORKSET_REPO"); len(repos) != 1 || repos[0] != "repo-a" {
+		t.Fatalf("unexpected hook repos: %v", repos)
+	}
+
+	resolution, err := env.svc.resolveRepo(ctx, RepoSelectionInput{Workspace: WorkspaceSelector{Value: "demo"}, Repo: "repo-a"})
+	if err != nil {
+		t.Fatalf("resolveRepo: %v", err)
+	}
+	if resolution.Defaults.Agent != "claude" {
+		t.Fatalf("expected template agent, got %q", resolution.Defaults.Agent)
+	}
+}
+
+func TestTemplateHooksAgainstUntrustedRepoHooks(t *testing.T) {
+	env := newTestEnv(t)
+	cfg := env.loadConfig()
+	cfg.Hooks.Enabled = true
+	cfg.WorksetTemplates = map[string]config.WorksetTemplate{
+		"demo": {Hooks: []config.HookSpec{
+			{ID: "setup", On: []string{"worktree.created"}, Run: []string{"template-setup"}},
+			{ID: "build", On: []string{"worktree.created"}, Run: []string{"template-build"}, DependsOn: []string{"lint"}},
+			{ID: "test", On: []string{"worktree.created"}, Run: []string{"template-test"}, DependsOn: []string{"build", "cleanup"}},
+		}},
+	}
+	hookFile := hooks.File{Hooks: []hooks.Hook{
+		{ID: "setup", On: []hooks.Event{hooks.EventWorktreeCreated}, Run: []string{"repo-setup"}},
+		{ID: "lint", On: []hooks.Event{hooks.EventWorktreeCreated}, Run: []string{"repo-lint"}},
+		{ID: "cleanup", On: []hooks.Event{hooks.EventWorktreeRemoved}, Run: []string{"repo-cleanup"}},
+	}}
+	run := func(cfg config.GlobalConfig) ([]string, []HookExecutionJSON) {
+		t.Helper()
+		runner := &recordingHookRunner{}
+		svc := NewService(Options{ConfigPath: env.configPath, Git: env.git, HookRunner: runner, Logf: func(string, ...any) {}})
+		root := t.TempDir()
+		_, runs, _, err := svc.runRepoHooks(context.Background(), cfg, repoHookRequest{
+			event:        hooks.EventWorktreeCreated,
+			wsRoot:       root,
+			wsName:       "demo",
+			repo:         config.RepoConfig{Name: "repo-a"},
+			worktreePath: root,
+			hookFile:     &hookFile,
+		})
+		if err != nil {
+			t.Fatalf("runRepoHooks: %v", err)
+		}
+		commands := make([]string, 0, len(runner.requests))
+		for _, req := range runner.requests {
+			commands = append(commands, strings.Join(req.Command, " "))
+		}
+		return commands, runs
+	}
+
+	commands, runs := run(cfg)
+	if strings.Join(commands, ",") != "template-setup" {
+		t.Fatalf("expected only the template setup to run for an untrusted repo, got %v", commands)
+	}
+	statuses := map[string]HookRunStatus{}
+	for _, run := range runs {
+		statuses[run.ID] = run.Status
+	}
+	if statuses["setup"] != HookRunStatusOK || statuses["build"] != HookRunStatusSkipped || statuses["test"] != HookRunStatusSkipped {
+		t.Fatalf("expected build and test to be reported skipped, got %+v", runs)
+	}
+
+	addTrustedRepo(&cfg, "repo-a")
+	commands, _ = run(cfg)
+	if strings.Join(commands, ",") != "repo-setup,repo-lint,template-build,template-test" {
+		t.Fatalf("expected the trusted repo's setup to replace the template's, got %v", commands)
+	}
+}
+
+func TestCreateWorkspaceRejectsTemplateFileOutsideRoot(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a")
+	cfg := env.loadConfig()
+	cfg.WorksetTemplates = map[string]config.WorksetTemplate{
+		"demo": {Files: []config.TemplateFile{{Path: "../escape.txt", Content: "nope"}}},
+	}
+	env.saveConfig(cfg)
+
+	_, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-a"},
+	})
+	_ = requireErrorType[ValidationError](t, err)
+	if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+		t.Fatalf("thread registered despite invalid template")
+	}
+	root := filepath.Join(env.root, "worksets", workspace.WorkspaceDirName("demo"), workspace.WorkspaceDirName("demo"))
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected no thread directory, stat err: %v", err)
+	}
+}
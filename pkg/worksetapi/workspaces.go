@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
 	"github.com/strantalis/workset/internal/ops"
 	"github.com/strantalis/workset/internal/workspace"
 )
@@ -66,33 +67,23 @@ func (s *Service) CreateWorkspace(ctx context.Context, input WorkspaceCreateInpu
 		return WorkspaceCreateResult{}, err
 	}
 
-	root := strings.TrimSpace(input.Path)
-	if root == "" {
-		base := strings.TrimSpace(cfg.Defaults.WorksetRoot)
-		if base == "" {
-			cwd, err := os.Getwd()
-			if err != nil {
-				return WorkspaceCreateResult{}, err
-			}
-			base = cwd
-		}
-		root = filepath.Join(
-			base,
-			"worksets",
-			workspace.WorkspaceDirName(worksetName),
-			workspace.WorkspaceDirName(name),
-		)
+	root, err := newThreadRoot(cfg, name, worksetName, input.Path)
+	if err != nil {
+		return WorkspaceCreateResult{}, err
 	}
-	root, err = filepath.Abs(root)
+
+	repoPlans, err := buildNewWorkspaceRepoPlans(cfg, input.Repos)
 	if err != nil {
 		return WorkspaceCreateResult{}, err
 	}
-	worksetPath := workspace.WorksetFile(root)
-	if _, err := os.Stat(worksetPath); err == nil {
-		return WorkspaceCreateResult{}, ConflictError{
-			Message: fmt.Sprintf("thread %q already exists at %s", name, worksetPath),
-		}
-	} else if !errors.Is(err, os.ErrNotExist) {
+	if err := s.resolveWorkspaceRepoStarts(ctx, input, repoPlans); err != nil {
+		return WorkspaceCreateResult{}, err
+	}
+	_, statErr := os.Stat(root)
+	rootCreated := errors.Is(statErr, os.ErrNotExist)
+	worksetExisted := worksetExists(cfg, worksetName)
+	template := cfg.WorksetTemplates[worksetName]
+	if err := validateTemplateFiles(template); err != nil {
 		return WorkspaceCreateResult{}, err
 	}
 
@@ -107,13 +98,36 @@ func (s *Service) CreateWorkspace(ctx context.Context, input WorkspaceCreateInpu
 			return err
 		}
 		registerWorkspace(cfg, name, root, s.clock(), worksetName)
+		applyTemplateMetadata(cfg, name, template)
 		return nil
 	}); err != nil {
 		return WorkspaceCreateResult{}, err
 	}
+	// Register locally too so hooks below resolve the thread's workset template.
+	registerWorkspace(&cfg, name, root, s.clock(), worksetName)
+	var prepared []ops.PreparedRepo
+	rollback := func() {
+		rollbackCtx := context.WithoutCancel(ctx)
+		s.rollbackCreatedWorkspace(rollbackCtx, name, root, worksetName, cfg.Defaults, rootCreated, worksetExisted)
+		// Removing the recorded repos keeps their branches; the branches this
+		// thread created go too, so retrying the create does not collide.
+		s.discardPreparedRepos(rollbackCtx, name, prepared)
+	}
 
-	repoPlans, err := buildNewWorkspaceRepoPlans(cfg, input.Repos)
+	prepared, err = s.provisionWorkspaceRepos(ctx, name, ws.Root, cfg.Defaults, repoPlans)
 	if err != nil {
+		rollback()
+		return WorkspaceCreateResult{}, err
+	}
+	// Template files are written once the worktrees exist so they may target
+	// repo directories, and before hooks run so hooks can rely on them.
+	if err := s.writeTemplateFiles(ctx, ws.Root, cfg.Defaults, template, info.Path, hooks.Context{
+		WorkspaceRoot:   ws.Root,
+		WorkspaceName:   name,
+		WorkspaceConfig: workspace.WorksetFile(ws.Root),
+		Branch:          ws.State.CurrentBranch,
+	}); err != nil {
+		rollback()
 		return WorkspaceCreateResult{}, err
 	}
 	type aliasUpdate struct {
@@ -124,32 +138,15 @@ func (s *Service) CreateWorkspace(ctx context.Context, input WorkspaceCreateInpu
 	warnings := []string{}
 	pendingHooks := []HookPending{}
 	hookRuns := []HookExecutionJSON{}
-	for _, plan := range repoPlans {
-		s.emitWorktreeCloneProgress(name, plan.Name, "clone-started", "thread.create", nil)
-		_, resolvedRemote, repoWarnings, err := ops.AddRepo(ctx, ops.AddRepoInput{
-			WorkspaceRoot: ws.Root,
-			Name:          plan.Name,
-			URL:           plan.URL,
-			SourcePath:    plan.SourcePath,
-			Defaults:      cfg.Defaults,
-			Remote:        plan.Remote,
-			DefaultBranch: plan.DefaultBranch,
-			AllowFallback: false,
-			Git:           s.git,
-		})
-		if err != nil {
-			s.emitWorktreeCloneProgress(name, plan.Name, "clone-finished", "thread.create", err)
-			return WorkspaceCreateResult{}, err
-		}
-		s.emitWorktreeCloneProgress(name, plan.Name, "clone-finished", "thread.create", nil)
-		if len(repoWarnings) > 0 {
-			warnings = append(warnings, repoWarnings...)
+	for i, plan := range repoPlans {
+		if len(prepared[i].Warnings) > 0 {
+			warnings = append(warnings, prepared[i].Warnings...)
 		}
 		if alias, ok := cfg.Repos[plan.Name]; ok {
 			aliasUpdated := false
 			update := aliasUpdates[plan.Name]
-			if alias.Remote == "" && resolvedRemote != "" {
-				update.remote = resolvedRemote
+			if alias.Remote == "" && prepared[i].Remote != "" {
+				update.remote = prepared[i].Remote
 				aliasUpdated = true
 			}
 			if alias.DefaultBranch == "" && plan.DefaultBranch != "" {
@@ -167,6 +164,7 @@ func (s *Service) CreateWorkspace(ctx context.Context, input WorkspaceCreateInpu
 			RepoDir: repoDir,
 		}, worktreePath, ws.State.CurrentBranch, "thread.create")
 		if err != nil {
+			rollback()
 			return WorkspaceCreateResult{}, err
 		}
 		if len(runs) > 0 {
@@ -214,6 +212,10 @@ func (s *Service) CreateWorkspace(ctx context.Context, input WorkspaceCreateInpu
 	}); err != nil {
 		return WorkspaceCreateResult{}, err
 	}
+	created := s.runThreadLifecycleHooks(ctx, cfg, root, hooks.EventThreadCreated, "thread.create", hooks.Context{})
+	hookRuns = append(hookRuns, created.Runs...)
+	pendingHooks = append(pendingHooks, created.Pending...)
+	warnings = append(warnings, created.Warnings...)
 	return WorkspaceCreateResult{
 		Workspace:    infoPayload,
 		Warnings:     warnings,
@@ -301,7 +303,8 @@ func mergeWorksetRepoAlias(alias config.RegisteredRepo, plan repoPlan) config.Re
 	return alias
 }
 
-// DeleteWorkspace removes a thread registration or deletes files when requested.
+// DeleteWorkspace removes a thread registration or, when requested, moves its
+// files to the trash or deletes them permanently.
 func (s *Service) DeleteWorkspace(ctx context.Context, input WorkspaceDeleteInput) (WorkspaceDeleteResult, error) {
 	cfg, info, err := s.loadGlobal(ctx)
 	if err != nil {
@@ -361,6 +364,7 @@ func (s *Service) DeleteWorkspace(ctx context.Context, input WorkspaceDeleteInpu
 	var report ops.WorkspaceSafetyReport
 	var warnings []string
 	var unpushed []string
+	var trashPath string
 	if input.DeleteFiles {
 		report, err = ops.CheckWorkspaceSafety(ctx, ops.WorkspaceSafetyInput{
 			WorkspaceRoot: root,
@@ -400,15 +404,41 @@ func (s *Service) DeleteWorkspace(ctx context.Context, input WorkspaceDeleteInpu
 	}
 
 	if input.DeleteFiles && !input.Confirmed {
-		return WorkspaceDeleteResult{}, ConfirmationRequired{Message: fmt.Sprintf("delete thread %s?", root)}
+		message := fmt.Sprintf("move thread %s to the trash?", root)
+		if input.Purge {
+			message = fmt.Sprintf("delete thread %s permanently?", root)
+		}
+		return WorkspaceDeleteResult{}, ConfirmationRequired{Message: message}
 	}
 
 	if input.DeleteFiles {
-		if err := s.removeWorkspaceRepoWorktrees(ctx, root, cfg.Defaults, input.Force); err != nil {
+		targets, hookWarnings := s.threadHookTargets(ctx, cfg, root, wsConfig)
+		warnings = append(warnings, hookWarnings...)
+		removing, err := s.runLifecycleHooks(ctx, cfg, targets, hooks.EventWorktreeRemoving, "thread.delete", hooks.Context{}, "")
+		warnings = append(warnings, removing.Warnings...)
+		if err != nil {
 			return WorkspaceDeleteResult{}, err
 		}
-		if err := os.RemoveAll(root); err != nil {
-			return WorkspaceDeleteResult{}, err
+		hookDir := root
+		if input.Purge {
+			if err := s.removeWorkspaceRepoWorktrees(ctx, root, cfg.Defaults, input.Force); err != nil {
+				return WorkspaceDeleteResult{}, err
+			}
+		} else {
+			trashPath, err = s.moveToTrash(ctx, cfg, name, root)
+			if err != nil {
+				return WorkspaceDeleteResult{}, err
+			}
+			hookDir = trashPath
+		}
+		removed, _ := s.runLifecycleHooks(ctx, cfg, targets, hooks.EventWorktreeRemoved, "thread.delete", hooks.Context{}, hookDir)
+		warnings = append(warnings, removed.Warnings...)
+		if input.Purge {
+			if err := os.RemoveAll(root); err != nil {
+				return WorkspaceDeleteResult{}, err
+			}
+		} else {
+			warnings = append(warnings, s.purgeExpiredTrash(ctx)...)
 		}
 	}
 
@@ -452,6 +482,10 @@ func (s *Service) DeleteWorkspace(ctx context.Context, input WorkspaceDeleteInpu
 		Path:         root,
 		DeletedFiles: input.DeleteFiles,
 	}
+	if trashPath != "" {
+		payload.TrashID = filepath.Base(trashPath)
+		payload.TrashPath = trashPath
+	}
 	return WorkspaceDeleteResult{Payload: payload, Warnings: warnings, Unpushed: unpushed, Safety: report, Config: info}, nil
 }
 
@@ -523,6 +557,42 @@ func applyWorksetRepoModelAfterWorkspaceRemoval(
 	}
 }
 
+// newThreadRoot resolves where a new thread lives: path when given, otherwise
+// <workset_root>/worksets/<workset>/<thread>. It fails if a thread already
+// exists there.
+func newThreadRoot(cfg config.GlobalConfig, name, worksetName, path string) (string, error) {
+	root := strings.TrimSpace(path)
+	if root == "" {
+		base := strings.TrimSpace(cfg.Defaults.WorksetRoot)
+		if base == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return "", err
+			}
+			base = cwd
+		}
+		root = filepath.Join(
+			base,
+			"worksets",
+			workspace.WorkspaceDirName(worksetName),
+			workspace.WorkspaceDirName(name),
+		)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	worksetPath := workspace.WorksetFile(root)
+	if _, err := os.Stat(worksetPath); err == nil {
+		return "", ConflictError{
+			Message: fmt.Sprintf("thread %q already exists at %s", name, worksetPath),
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	return root, nil
+}
+
 func worksetNameForThread(threadName string, ref config.WorkspaceRef) string {
 	worksetName := strings.TrimSpace(workspaceRefWorkset(ref))
 	if worksetName == "" {
@@ -621,13 +691,14 @@ func loadThreadRepoNamesForRef(ref config.WorkspaceRef) ([]string, bool) {
 	return normalized, len(normalized) > 0
 }
 
-// StatusWorkspace reports per-repo status for a thread.
-func (s *Service) StatusWorkspace(ctx context.Context, selector WorkspaceSelector) (WorkspaceStatusResult, error) {
+// StatusWorkspace reports per-repo status for a thread, including branch
+// tracking details and the tracked pull request for each repo.
+func (s *Service) StatusWorkspace(ctx context.Context, input WorkspaceStatusInput) (WorkspaceStatusResult, error) {
 	cfg, info, err := s.loadGlobal(ctx)
 	if err != nil {
 		return WorkspaceStatusResult{}, err
 	}
-	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, selector)
+	wsRoot, wsConfig, err := s.resolveWorkspace(ctx, &cfg, info.Path, input.Selector)
 	if err != nil {
 		return WorkspaceStatusResult{}, err
 	}
@@ -636,33 +707,21 @@ func (s *Service) StatusWorkspace(ctx context.Context, selector WorkspaceSelecto
 		WorkspaceRoot:       wsRoot,
 		Defaults:            cfg.Defaults,
 		RepoDefaultBranches: repoDefaultBranches(wsConfig, cfg),
+		RepoDefaults:        repoDefaultsMap(wsConfig, cfg),
+		Detailed:            true,
+		Fetch:               input.FetchRemotes,
 		Git:                 s.git,
 	})
 	if err != nil {
 		return WorkspaceStatusResult{}, err
 	}
+	state, err := s.workspaces.LoadState(ctx, wsRoot)
+	if err != nil && !os.IsNotExist(err) {
+		return WorkspaceStatusResult{}, err
+	}
 	payload := make([]RepoStatusJSON, 0, len(statuses))
 	for _, repo := range statuses {
-		state := "clean"
-		switch {
-		case repo.Missing:
-			state = "missing"
-		case repo.Dirty:
-			state = "dirty"
-		case repo.Err != nil:
-			state = "error"
-		}
-		entry := RepoStatusJSON{
-			Name:    repo.Name,
-			Path:    repo.Path,
-			State:   state,
-			Dirty:   repo.Dirty,
-			Missing: repo.Missing,
-		}
-		if repo.Err != nil {
-			entry.Error = repo.Err.Error()
-		}
-		payload = append(payload, entry)
+		payload = append(payload, repoStatusPayload(repo, state))
 	}
 
 	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, loadInfo config.GlobalConfigLoadInfo) error {
@@ -829,3 +888,51 @@ func workspacesWithin(cfg config.GlobalConfig, targetName, absTarget string) ([]
 	sort.Strings(contained)
 	return contained, nil
 }
+
+func repoStatusPayload(repo ops.RepoStatus, state workspace.State) RepoStatusJSON {
+	entry := RepoStatusJSON{
+		Name:    repo.Name,
+		Path:    repo.Path,
+		State:   "clean",
+		Dirty:   repo.Dirty,
+		Missing: repo.Missing,
+	}
+	if detail := repo.Detail; detail != nil {
+		entry.Branch = detail.Branch
+		entry.Detached = detail.Detached
+		entry.Upstream = detail.Upstream
+		entry.Ahead = detail.Ahead
+		entry.Behind = detail.Behind
+		entry.BaseRef = detail.BaseRef
+		entry.BaseAhead = detail.BaseAhead
+		entry.BaseBehind = detail.BaseBehind
+		entry.Stashes = detail.Stashes
+		entry.Conflicts = detail.Conflicts
+	}
+	switch {
+	case repo.Missing:
+		entry.State = "missing"
+	case len(entry.Conflicts) > 0:
+		entry.State = "conflicted"
+	case repo.Dirty:
+		entry.State = "dirty"
+	case repo.Err != nil:
+		entry.State = "error"
+	}
+	if pr, ok := state.PullRequests[repo.Name]; ok {
+		entry.PullRequest = &RepoStatusPullRequestJSON{
+			Number: pr.Number,
+			URL:    pr.URL,
+			State:  pr.State,
+			Draft:  pr.Draft,
+			Merged: pr.Merged,
+		}
+	}
+	if repo.FetchErr != nil {
+		entry.FetchError = repo.FetchErr.Error()
+	}
+	if repo.Err != nil {
+		entry.Error = repo.Err.Error()
+	}
+	return entry
+}
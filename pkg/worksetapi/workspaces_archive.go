@@ -6,14 +6,16 @@ import (
 	"time"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
 )
 
 // ArchiveWorkspace marks a workspace as archived in the global config.
 func (s *Service) ArchiveWorkspace(ctx context.Context, selector WorkspaceSelector, reason string) (WorkspaceRefJSON, config.GlobalConfigLoadInfo, error) {
 	var (
-		info config.GlobalConfigLoadInfo
-		name string
-		ref  config.WorkspaceRef
+		info    config.GlobalConfigLoadInfo
+		name    string
+		ref     config.WorkspaceRef
+		updated config.GlobalConfig
 	)
 	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, loadInfo config.GlobalConfigLoadInfo) error {
 		info = loadInfo
@@ -30,10 +32,14 @@ func (s *Service) ArchiveWorkspace(ctx context.Context, selector WorkspaceSelect
 		ref.ArchivedAt = s.clock().Format(time.RFC3339)
 		ref.ArchivedReason = strings.TrimSpace(reason)
 		cfg.Workspaces[name] = ref
+		updated = *cfg
 		return nil
 	}); err != nil {
 		return WorkspaceRefJSON{}, info, err
 	}
+	if ref.Path != "" {
+		s.logHookWarnings(s.runThreadLifecycleHooks(ctx, updated, ref.Path, hooks.EventThreadArchived, "thread.archive", hooks.Context{}))
+	}
 	return workspaceRefJSON(name, ref), info, nil
 }
 
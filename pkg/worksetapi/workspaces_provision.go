@@ -0,0 +1,163 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/git"
+	"github.com/strantalis/workset/internal/ops"
+)
+
+// provisionWorkspaceRepos clones and checks out every planned repo, running
+// at most defaults.provision_parallelism at once, then records the repos that
+// succeeded in the workspace config in plan order. The first failure stops
+// repos that have not started and is returned once in-flight repos finish,
+// along with the repos that were prepared so the caller can discard them.
+func (s *Service) provisionWorkspaceRepos(
+	ctx context.Context,
+	threadName string,
+	root string,
+	defaults config.Defaults,
+	plans []repoPlan,
+) ([]ops.PreparedRepo, error) {
+	limit := defaults.ProvisionParallelism
+	if limit <= 0 {
+		limit = 1
+	}
+	provisionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	prepared := make([]*ops.PreparedRepo, len(plans))
+	errs := make([]error, len(plans))
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, plan := range plans {
+		// Repos start in plan order; once a repo fails, the rest never start.
+		select {
+		case slots <- struct{}{}:
+		case <-provisionCtx.Done():
+			errs[i] = provisionCtx.Err()
+			continue
+		}
+		if err := provisionCtx.Err(); err != nil {
+			<-slots
+			errs[i] = err
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.emitWorktreeCloneProgress(threadName, plan.Name, "clone-started", "thread.create", nil)
+			input := ops.AddRepoInput{
+				WorkspaceRoot: root,
+				Name:          plan.Name,
+				URL:           plan.URL,
+				SourcePath:    plan.SourcePath,
+				Defaults:      defaults,
+				Remote:        plan.Remote,
+				DefaultBranch: plan.DefaultBranch,
+				AllowFallback: false,
+				Git:           s.git,
+				Now:           s.clock,
+			}
+			plan.Start.apply(&input)
+			repo, err := ops.PrepareRepo(provisionCtx, input)
+			s.emitWorktreeCloneProgress(threadName, plan.Name, "clone-finished", "thread.create", err)
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			prepared[i] = &repo
+		}()
+	}
+	wg.Wait()
+
+	ready := make([]ops.PreparedRepo, 0, len(plans))
+	for _, repo := range prepared {
+		if repo != nil {
+			ready = append(ready, *repo)
+		}
+	}
+	var firstErr error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if firstErr == nil || (errors.Is(firstErr, context.Canceled) && !errors.Is(err, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if len(ready) > 0 {
+		if _, err := ops.RegisterRepos(root, defaults, ready...); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return ready, firstErr
+}
+
+// discardPreparedRepos removes the worktrees prepared for a thread, whether
+// or not they were recorded in it, along with the branches created for them.
+func (s *Service) discardPreparedRepos(ctx context.Context, threadName string, prepared []ops.PreparedRepo) {
+	for _, repo := range prepared {
+		if repo.WorktreeName == "" {
+			continue
+		}
+		if err := s.git.WorktreeRemove(git.WorktreeRemoveOptions{
+			RepoPath:     repo.GitDir,
+			WorktreeName: repo.WorktreeName,
+			Force:        true,
+		}); err != nil && !errors.Is(err, git.ErrWorktreeNotFound) && s.logf != nil {
+			s.logf("warning: rollback of thread %s: remove worktree for %s: %v", threadName, repo.Repo.Name, err)
+		}
+		if err := os.RemoveAll(repo.WorktreePath); err != nil && s.logf != nil {
+			s.logf("warning: rollback of thread %s: %v", threadName, err)
+		}
+		if repo.CreatedBranch == "" {
+			continue
+		}
+		if err := gitDeleteBranch(ctx, repo.GitDir, repo.CreatedBranch, s.commands); err != nil && s.logf != nil {
+			s.logf("warning: rollback of thread %s: delete branch %s of %s: %v", threadName, repo.CreatedBranch, repo.Repo.Name, err)
+		}
+	}
+}
+
+// rollbackCreatedWorkspace undoes a failed CreateWorkspace: it removes the
+// worktrees recorded in the new thread, deletes the thread directory when
+// CreateWorkspace created it, and unregisters the thread (and its workset,
+// if this thread introduced it) from the global config.
+func (s *Service) rollbackCreatedWorkspace(
+	ctx context.Context,
+	name string,
+	root string,
+	worksetName string,
+	defaults config.Defaults,
+	rootCreated bool,
+	worksetExisted bool,
+) {
+	if err := s.removeWorkspaceRepoWorktrees(ctx, root, defaults, true); err != nil && s.logf != nil {
+		s.logf("warning: rollback of thread %s: %v", name, err)
+	}
+	if rootCreated {
+		if err := os.RemoveAll(root); err != nil && s.logf != nil {
+			s.logf("warning: rollback of thread %s: %v", name, err)
+		}
+	}
+	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, _ config.GlobalConfigLoadInfo) error {
+		if ref, ok := cfg.Workspaces[name]; ok && samePath(ref.Path, root) {
+			delete(cfg.Workspaces, name)
+		}
+		if !worksetExisted && len(listThreadsForWorkset(cfg.Workspaces, worksetName)) == 0 {
+			delete(cfg.WorksetRepos, worksetName)
+		}
+		return nil
+	}); err != nil && s.logf != nil {
+		s.logf("warning: rollback of thread %s: %v", name, err)
+	}
+}
@@ -0,0 +1,226 @@
+package worksetapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
+	"github.com/strantalis/workset/internal/workspace"
+)
+
+func registerTestRepos(env *testEnv, parallelism int, names ...string) {
+	cfg := env.loadConfig()
+	cfg.Repos = map[string]config.RegisteredRepo{}
+	for _, name := range names {
+		cfg.Repos[name] = config.RegisteredRepo{Path: env.createLocalRepo(name)}
+	}
+	cfg.Defaults.ProvisionParallelism = parallelism
+	env.saveConfig(cfg)
+}
+
+func TestCreateWorkspaceProvisionsReposInPlanOrder(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 2, "repo-a", "repo-b", "repo-c")
+
+	result, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-c", "repo-a", "repo-b"},
+	})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	wsCfg, err := config.LoadWorkspace(workspace.WorksetFile(result.Workspace.Path))
+	if err != nil {
+		t.Fatalf("load workspace config: %v", err)
+	}
+	got := make([]string, 0, len(wsCfg.Repos))
+	for _, repo := range wsCfg.Repos {
+		got = append(got, repo.Name)
+	}
+	if strings.Join(got, ",") != "repo-c,repo-a,repo-b" {
+		t.Fatalf("expected repos in request order, got %v", got)
+	}
+	if len(env.git.worktreeAdds) != 3 {
+		t.Fatalf("expected 3 worktrees, got %d", len(env.git.worktreeAdds))
+	}
+}
+
+func TestCreateWorkspaceRollsBackOnRepoFailure(t *testing.T) {
+	env := newTestEnv(t)
+	// One worker keeps the order deterministic: repo-a is checked out before
+	// repo-b fails, so the rollback has a worktree to remove.
+	registerTestRepos(env, 1, "repo-a", "repo-b")
+	env.git.worktreeAddHook = func(path string) error {
+		if filepath.Base(path) == "repo-b" {
+			return errors.New("checkout failed")
+		}
+		return nil
+	}
+
+	_, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-a", "repo-b"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "checkout failed") {
+		t.Fatalf("expected checkout failure, got %v", err)
+	}
+
+	cfg := env.loadConfig()
+	if _, ok := cfg.Workspaces["demo"]; ok {
+		t.Fatalf("expected thread unregistered after rollback")
+	}
+	if _, ok := cfg.WorksetRepos["demo"]; ok {
+		t.Fatalf("expected workset introduced by the thread removed after rollback")
+	}
+	root := filepath.Join(env.root, "worksets", workspace.WorkspaceDirName("demo"), workspace.WorkspaceDirName("demo"))
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected thread directory removed, stat err: %v", err)
+	}
+	if len(env.git.worktreeRemovs) == 0 {
+		t.Fatalf("expected created worktrees removed")
+	}
+}
+
+func TestCreateWorkspaceDiscardsPreparedReposWhenRegisterFails(t *testing.T) {
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a", "repo-b")
+	cfg := env.loadConfig()
+	repoA := filepath.Join(cfg.Repos["repo-a"].Path, ".git")
+	repoB := filepath.Join(cfg.Repos["repo-b"].Path, ".git")
+	// repo-a gets a new thread branch; repo-b already has one.
+	env.git.refs[refKey(repoA, "refs/heads/demo")] = false
+	var deleted []string
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		CommandRunner: func(_ context.Context, root string, command []string, _ []string, _ string) (CommandResult, error) {
+			deleted = append(deleted, root+" "+strings.Join(command, " "))
+			return CommandResult{}, nil
+		},
+		Clock: func() time.Time { return env.now },
+		Logf:  func(string, ...any) {},
+	})
+	env.git.worktreeAddHook = func(path string) error {
+		if filepath.Base(path) != "repo-b" {
+			return nil
+		}
+		// Break the thread config so the prepared repos cannot be recorded.
+		root := filepath.Join(env.root, "worksets", workspace.WorkspaceDirName("demo"), workspace.WorkspaceDirName("demo"))
+		return os.WriteFile(workspace.WorksetFile(root), []byte("repos: [\n"), 0o644)
+	}
+
+	_, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-a", "repo-b"},
+	})
+	if err == nil {
+		t.Fatal("expected the thread config failure")
+	}
+	removed := map[string]bool{}
+	for _, call := range env.git.worktreeRemovs {
+		removed[call.repoPath] = true
+	}
+	if !removed[repoA] || !removed[repoB] {
+		t.Fatalf("expected both prepared worktrees removed, got %+v", env.git.worktreeRemovs)
+	}
+	if strings.Join(deleted, ",") != repoA+" git branch -D demo" {
+		t.Fatalf("expected only the branch created for repo-a deleted, got %v", deleted)
+	}
+}
+
+// newBranchRollbackEnv registers repo-a and repo-b, which both get a new
+// thread branch, and records the branches the rollback deletes.
+func newBranchRollbackEnv(t *testing.T, runner hooks.Runner) (*testEnv, *[]string, []string) {
+	t.Helper()
+	env := newTestEnv(t)
+	registerTestRepos(env, 1, "repo-a", "repo-b")
+	cfg := env.loadConfig()
+	gitDirs := []string{filepath.Join(cfg.Repos["repo-a"].Path, ".git"), filepath.Join(cfg.Repos["repo-b"].Path, ".git")}
+	for _, gitDir := range gitDirs {
+		env.git.refs[refKey(gitDir, "refs/heads/demo")] = false
+	}
+	cfg.Hooks.RepoHooks.TrustedRepos = []string{"repo-a", "repo-b"}
+	env.saveConfig(cfg)
+	deleted := []string{}
+	env.svc = NewService(Options{
+		ConfigPath: env.configPath,
+		Git:        env.git,
+		HookRunner: runner,
+		CommandRunner: func(_ context.Context, root string, command []string, _ []string, _ string) (CommandResult, error) {
+			deleted = append(deleted, root+" "+strings.Join(command, " "))
+			return CommandResult{}, nil
+		},
+		Clock: func() time.Time { return env.now },
+		Logf:  func(string, ...any) {},
+	})
+	return env, &deleted, gitDirs
+}
+
+func TestCreateWorkspaceDeletesCreatedBranchesWhenACloneFails(t *testing.T) {
+	env, deleted, gitDirs := newBranchRollbackEnv(t, nil)
+	env.git.worktreeAddHook = func(path string) error {
+		if filepath.Base(path) == "repo-b" {
+			return errors.New("checkout failed")
+		}
+		return nil
+	}
+
+	_, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-a", "repo-b"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "checkout failed") {
+		t.Fatalf("expected checkout failure, got %v", err)
+	}
+	if strings.Join(*deleted, ",") != gitDirs[0]+" git branch -D demo" {
+		t.Fatalf("expected the branch created for repo-a deleted, got %v", *deleted)
+	}
+}
+
+func TestCreateWorkspaceDeletesCreatedBranchesWhenAHookFails(t *testing.T) {
+	env, deleted, gitDirs := newBranchRollbackEnv(t, &recordingHookRunner{err: errors.New("boom")})
+	env.git.worktreeAddHook = func(path string) error {
+		hooksDir := filepath.Join(path, ".workset")
+		if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(hooksDir, "hooks.yaml"), []byte("hooks:\n  - id: bootstrap\n    on: [worktree.created]\n    run: [\"make\"]\n    on_error: fail\n"), 0o644)
+	}
+
+	_, err := env.svc.CreateWorkspace(context.Background(), WorkspaceCreateInput{
+		Name:  "demo",
+		Repos: []string{"repo-a", "repo-b"},
+	})
+	if err == nil {
+		t.Fatal("expected the hook failure")
+	}
+	if strings.Join(*deleted, ",") != gitDirs[0]+" git branch -D demo,"+gitDirs[1]+" git branch -D demo" {
+		t.Fatalf("expected the branches created for both repos deleted, got %v", *deleted)
+	}
+	if _, ok := env.loadConfig().Workspaces["demo"]; ok {
+		t.Fatal("expected thread unregistered after rollback")
+	}
+}
+
+func TestSetDefaultProvisionParallelism(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	if _, _, err := env.svc.SetDefault(ctx, "defaults.provision_parallelism", "8"); err != nil {
+		t.Fatalf("set provision_parallelism: %v", err)
+	}
+	if got := env.loadConfig().Defaults.ProvisionParallelism; got != 8 {
+		t.Fatalf("expected provision_parallelism 8, got %d", got)
+	}
+	for _, value := range []string{"0", "64", "many"} {
+		if _, _, err := env.svc.SetDefault(ctx, "defaults.provision_parallelism", value); err == nil {
+			t.Fatalf("expected error for provision_parallelism %q", value)
+		}
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"time"
 
 	"github.com/strantalis/workset/internal/config"
+	"github.com/strantalis/workset/internal/hooks"
 )
 
 // RenameWorkspace updates the workspace name in global config and workset.yaml.
 func (s *Service) RenameWorkspace(ctx context.Context, input WorkspaceRenameInput) (WorkspaceRefJSON, error) {
 	var (
-		ref     config.WorkspaceRef
-		outName string
+		ref      config.WorkspaceRef
+		outName  string
+		prevName string
+		updated  config.GlobalConfig
 	)
 	if _, err := s.updateGlobal(ctx, func(cfg *config.GlobalConfig, _ config.GlobalConfigLoadInfo) error {
 		currentName, root, err := resolveWorkspaceSelector(cfg, input.Selector)
@@ -63,10 +66,17 @@ func (s *Service) RenameWorkspace(ctx context.Context, input WorkspaceRenameInpu
 		delete(cfg.Workspaces, currentName)
 		cfg.Workspaces[newName] = ref
 		outName = newName
+		prevName = currentName
+		updated = *cfg
 		return nil
 	}); err != nil {
 		return WorkspaceRefJSON{}, err
 	}
+	if prevName != "" {
+		s.logHookWarnings(s.runThreadLifecycleHooks(ctx, updated, ref.Path, hooks.EventThreadRenamed, "thread.rename", hooks.Context{
+			PreviousName: prevName,
+		}))
+	}
 
 	return workspaceRefJSON(outName, ref), nil
 }
@@ -71,6 +71,8 @@ func (a *App) startup(ctx context.Context) {
 	debugTerminalServicef("app_startup build_marker=restart-logging-v3 pid=%d", os.Getpid())
 	ensureDevTerminalServiceSocket()
 	ensureTerminalServiceStarted(a)
+	go runStartupAutoArchive(a)
+	go runRepoStorePrefetch(a)
 }
 
 func (a *App) shutdown(_ context.Context) {
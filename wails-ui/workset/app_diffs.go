@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/strantalis/workset/pkg/worksetapi"
 )
@@ -930,3 +931,31 @@ func finalizePatch(patch string) RepoFileDiffSnapshot {
 		TotalLines: totalLines,
 	}
 }
+
+// ThreadLog is the thread timeline shown next to the diff views.
+type ThreadLog struct {
+	Commits  []worksetapi.ThreadCommitJSON `json:"commits"`
+	Warnings []string                      `json:"warnings,omitempty"`
+}
+
+// GetThreadLog returns the thread's commits across repos, newest first.
+// since is RFC3339 and may be empty.
+func (a *App) GetThreadLog(workspaceID, since string, patch bool) (ThreadLog, error) {
+	ctx, svc := a.serviceContext()
+	input := worksetapi.ThreadLogInput{
+		Selector: worksetapi.WorkspaceSelector{Value: workspaceID},
+		Patch:    patch,
+	}
+	if since = strings.TrimSpace(since); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return ThreadLog{}, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		input.Since = parsed
+	}
+	result, err := svc.ThreadLog(ctx, input)
+	if err != nil {
+		return ThreadLog{}, err
+	}
+	return ThreadLog{Commits: result.Commits, Warnings: result.Warnings}, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/strantalis/workset/pkg/worksetapi"
@@ -142,6 +143,46 @@ func (a *App) RemoveWorkspace(input WorkspaceRemoveRequest) (worksetapi.Workspac
 	return result.Payload, nil
 }
 
+func (a *App) ListTrash() ([]worksetapi.TrashEntryJSON, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+func (a *App) RestoreTrash(id string) (worksetapi.TrashEntryJSON, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.RestoreTrash(ctx, worksetapi.TrashRestoreInput{ID: id})
+	if err != nil {
+		return worksetapi.TrashEntryJSON{}, err
+	}
+	return result.Entry, nil
+}
+
+// PurgeTrash deletes the given trash entries; PurgeAllTrash empties the trash.
+func (a *App) PurgeTrash(ids []string) ([]worksetapi.TrashEntryJSON, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("trash ids are required")
+	}
+	ctx, svc := a.serviceContext()
+	result, err := svc.PurgeTrash(ctx, worksetapi.TrashPurgeInput{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	return result.Purged, nil
+}
+
+func (a *App) PurgeAllTrash() ([]worksetapi.TrashEntryJSON, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.PurgeTrash(ctx, worksetapi.TrashPurgeInput{All: true})
+	if err != nil {
+		return nil, err
+	}
+	return result.Purged, nil
+}
+
 func (a *App) RenameWorkspace(workspaceID, newName string) (worksetapi.WorkspaceRefJSON, error) {
 	ctx, svc := a.serviceContext()
 	result, err := svc.RenameWorkspace(ctx, worksetapi.WorkspaceRenameInput{
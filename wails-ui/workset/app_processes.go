@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/strantalis/workset/pkg/worksetapi"
+)
+
+type ThreadProcesses struct {
+	Processes []worksetapi.ProcessStatusJSON `json:"processes"`
+	Warnings  []string                       `json:"warnings,omitempty"`
+}
+
+type ThreadProcessLogs struct {
+	Lines       []worksetapi.ProcessLogLineJSON `json:"lines"`
+	NextOffsets map[string]int64                `json:"nextOffsets"`
+}
+
+// StartThreadProcesses starts the thread's processes, or only the named ones
+// and their dependencies, in the embedded terminal service.
+func (a *App) StartThreadProcesses(workspaceID string, names []string) (ThreadProcesses, error) {
+	if _, err := a.getTerminalServiceClient(); err != nil {
+		return ThreadProcesses{}, err
+	}
+	ctx, svc := a.serviceContext()
+	result, err := svc.ProcessesUp(ctx, worksetapi.ProcessesInput{
+		Workspace: worksetapi.WorkspaceSelector{Value: workspaceID},
+		Names:     names,
+	})
+	if err != nil {
+		return ThreadProcesses{}, err
+	}
+	return ThreadProcesses{Processes: result.Processes, Warnings: result.Warnings}, nil
+}
+
+func (a *App) StopThreadProcesses(workspaceID string, names []string) (ThreadProcesses, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.ProcessesDown(ctx, worksetapi.ProcessesInput{
+		Workspace: worksetapi.WorkspaceSelector{Value: workspaceID},
+		Names:     names,
+	})
+	if err != nil {
+		return ThreadProcesses{}, err
+	}
+	return ThreadProcesses{Processes: result.Processes, Warnings: result.Warnings}, nil
+}
+
+func (a *App) GetThreadProcesses(workspaceID string) (ThreadProcesses, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.ProcessStatus(ctx, worksetapi.ProcessesInput{
+		Workspace: worksetapi.WorkspaceSelector{Value: workspaceID},
+	})
+	if err != nil {
+		return ThreadProcesses{}, err
+	}
+	return ThreadProcesses{Processes: result.Processes, Warnings: result.Warnings}, nil
+}
+
+// GetThreadProcessLogs returns complete output lines after offsets, which
+// should be the nextOffsets of the previous call; empty offsets start from
+// each process's recent output.
+func (a *App) GetThreadProcessLogs(workspaceID string, offsets map[string]int64) (ThreadProcessLogs, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.ProcessLogs(ctx, worksetapi.ProcessLogsInput{
+		Workspace: worksetapi.WorkspaceSelector{Value: workspaceID},
+		Offsets:   offsets,
+		Follow:    true,
+	})
+	if err != nil {
+		return ThreadProcessLogs{}, err
+	}
+	return ThreadProcessLogs{Lines: result.Lines, NextOffsets: result.NextOffsets}, nil
+}
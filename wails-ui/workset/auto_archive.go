@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log"
+
+	"github.com/strantalis/workset/pkg/worksetapi"
+)
+
+// runStartupAutoArchive applies defaults.auto_archive when it is enabled and
+// tells the frontend to reload threads if any were archived.
+func runStartupAutoArchive(a *App) {
+	ctx, svc := a.serviceContext()
+	cfg, _, err := svc.GetConfig(ctx)
+	if err != nil || !cfg.Defaults.AutoArchive.Enabled {
+		return
+	}
+	result, err := svc.AutoArchive(ctx, worksetapi.AutoArchiveInput{})
+	if err != nil {
+		log.Printf("workset: auto-archive: %v", err)
+		return
+	}
+	for _, warning := range result.Warnings {
+		log.Printf("workset: auto-archive: %s", warning)
+	}
+	archived := []string{}
+	for _, thread := range result.Threads {
+		if thread.Action == "archived" {
+			archived = append(archived, thread.Name)
+		}
+	}
+	if len(archived) > 0 {
+		emitRuntimeEvent(ctx, EventWorkspacesAutoArchived, archived)
+	}
+}
@@ -8,6 +8,10 @@ const (
 	EventWorkspacePopoutOpened = "workspace:popout-opened"
 	EventWorkspacePopoutClosed = "workspace:popout-closed"
 
+	EventWorkspacesAutoArchived = "workspaces:auto-archived"
+
+	EventRepoStoreFetched = "repostore:fetched"
+
 	EventRepoDiffSummary      = "repodiff:summary"
 	EventRepoDiffLocalSummary = "repodiff:local-summary"
 	EventRepoDiffLocalStatus  = "repodiff:local-status"
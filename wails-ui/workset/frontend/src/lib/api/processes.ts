@@ -0,0 +1,32 @@
+import type { ThreadProcessLogs, ThreadProcesses } from '../types';
+import {
+	GetThreadProcessLogs,
+	GetThreadProcesses,
+	StartThreadProcesses,
+	StopThreadProcesses,
+} from '../../../bindings/workset/app';
+
+export async function startThreadProcesses(
+	workspaceId: string,
+	names: string[] = [],
+): Promise<ThreadProcesses> {
+	return StartThreadProcesses(workspaceId, names);
+}
+
+export async function stopThreadProcesses(
+	workspaceId: string,
+	names: string[] = [],
+): Promise<ThreadProcesses> {
+	return StopThreadProcesses(workspaceId, names);
+}
+
+export async function fetchThreadProcesses(workspaceId: string): Promise<ThreadProcesses> {
+	return GetThreadProcesses(workspaceId);
+}
+
+export async function fetchThreadProcessLogs(
+	workspaceId: string,
+	offsets: Record<string, number> = {},
+): Promise<ThreadProcessLogs> {
+	return GetThreadProcessLogs(workspaceId, offsets);
+}
@@ -1,10 +1,11 @@
-import type { RepoDiffSummary, RepoFileDiff } from '../types';
+import type { RepoDiffSummary, RepoFileDiff, ThreadLog } from '../types';
 import {
 	GetBranchDiffSummary,
 	GetBranchFileDiff,
 	GetRepoDiff,
 	GetRepoDiffSummary,
 	GetRepoFileDiff,
+	GetThreadLog,
 	StartRepoDiffWatch,
 	StopRepoDiffWatch,
 	UpdateRepoDiffWatch,
@@ -117,3 +118,11 @@ export async function fetchBranchFileDiff(
 ): Promise<RepoFileDiff> {
 	return GetBranchFileDiff(workspaceId, repoId, base, head, path, prevPath);
 }
+
+export async function fetchThreadLog(
+	workspaceId: string,
+	since = '',
+	patch = false,
+): Promise<ThreadLog> {
+	return GetThreadLog(workspaceId, since, patch);
+}
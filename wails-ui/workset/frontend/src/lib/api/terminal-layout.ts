@@ -2,12 +2,14 @@ import type { TerminalLayout, TerminalLayoutPayload } from '../types';
 import type {
 	TerminalLayout as BoundTerminalLayout,
 	TerminalLayoutRequest as BoundTerminalLayoutRequest,
+	TerminalSearchResult,
 	TerminalSessionDescriptor,
 } from '../../../bindings/workset/models';
 import {
 	CreateWorkspaceTerminal,
 	GetWorkspaceTerminalLayout,
 	LogTerminalDebug,
+	SearchTerminals,
 	SetWorkspaceTerminalLayout,
 	StartWorkspaceTerminalSessionForWindow,
 	StopWorkspaceTerminalForWindow,
@@ -93,3 +95,14 @@ export async function persistWorkspaceTerminalLayout(
 		layout: layout as unknown as BoundTerminalLayout,
 	} as unknown as BoundTerminalLayoutRequest);
 }
+
+// Searches terminal output, open and closed, in one workspace or in all of
+// them. A match's offset replays its terminal from the matching line when
+// passed as the socket startOffset.
+export async function searchTerminals(
+	query: string,
+	workspaceId = '',
+	ignoreCase = true,
+): Promise<TerminalSearchResult> {
+	return SearchTerminals(query, workspaceId, ignoreCase);
+}
@@ -4,6 +4,10 @@ export const EVENT_GITHUB_OPERATION = 'github:operation' as const;
 export const EVENT_WORKSPACE_POPOUT_OPENED = 'workspace:popout-opened' as const;
 export const EVENT_WORKSPACE_POPOUT_CLOSED = 'workspace:popout-closed' as const;
 
+export const EVENT_WORKSPACES_AUTO_ARCHIVED = 'workspaces:auto-archived' as const;
+
+export const EVENT_REPO_STORE_FETCHED = 'repostore:fetched' as const;
+
 export const EVENT_REPO_DIFF_SUMMARY = 'repodiff:summary' as const;
 export const EVENT_REPO_DIFF_LOCAL_SUMMARY = 'repodiff:local-summary' as const;
 export const EVENT_REPO_DIFF_LOCAL_STATUS = 'repodiff:local-status' as const;
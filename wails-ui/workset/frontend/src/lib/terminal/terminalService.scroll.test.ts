@@ -366,7 +366,7 @@ describe('terminalService resize flow', () => {
 		await vi.waitFor(() => {
 			expect(socket.sent).toContainEqual(
 				JSON.stringify({
-					protocolVersion: 2,
+					protocolVersion: 3,
 					type: 'input',
 					data: '\x1b[<64;10;10M',
 				}),
@@ -461,7 +461,7 @@ describe('terminalService resize flow', () => {
 
 		expect(socket.sent).toContainEqual(
 			JSON.stringify({
-				protocolVersion: 2,
+				protocolVersion: 3,
 				type: 'stop',
 			}),
 		);
@@ -25,7 +25,7 @@ import {
 } from './terminalServiceDeps';
 import { createTerminalServiceState } from './terminalServiceState';
 import { createTerminalServiceRuntime } from './terminalServiceRuntime';
-import { createTerminalSocketStream } from './terminalSocketStream';
+import { createTerminalSocketStream, type TerminalAttachMode } from './terminalSocketStream';
 import { emitTerminalActivity } from './terminalActivityBus';
 import type { TerminalSnapshotLike } from './terminalEmulatorContracts';
 
@@ -547,6 +547,13 @@ const resetSessionState = (id: string): void => {
 	});
 };
 
+// Popout windows take input ownership of their terminals, so the main window
+// keeps showing them without typing into them.
+const resolveAttachMode = (): TerminalAttachMode | undefined => {
+	if (typeof window === 'undefined') return undefined;
+	return new URLSearchParams(window.location.search).get('popout') === '1' ? 'owner' : undefined;
+};
+
 const terminalSocketStream = createTerminalSocketStream({
 	logDebug: (id, event, details) => runtime.logDebug(id, event, details),
 	onReady: (id) => {
@@ -574,6 +581,16 @@ const terminalSocketStream = createTerminalSocketStream({
 	onError: (id, error) => {
 		runtime.logDebug(id, 'frontend_socket_error', { error });
 	},
+	onAccessChange: (id, access) => {
+		runtime.logDebug(id, 'frontend_socket_access', access);
+		if (lifecycle.getStatus(id) !== 'ready') return;
+		runtime.setHealth(
+			id,
+			'ok',
+			access.canInput ? 'Session active.' : 'Watching: another window has input.',
+		);
+		emitState(id);
+	},
 	onClosed: (id, details) => {
 		runtime.logDebug(id, 'frontend_socket_closed', details);
 		if (details.intentional) {
@@ -667,7 +684,11 @@ const terminalSessionCoordinator = createTerminalSessionCoordinator({
 	onSessionReady: async (id, descriptor) => {
 		try {
 			const offset = lastStreamOffset.get(id) ?? 0;
-			await terminalSocketStream.connect(id, { ...descriptor, startOffset: offset });
+			await terminalSocketStream.connect(id, {
+				...descriptor,
+				startOffset: offset,
+				mode: resolveAttachMode(),
+			});
 		} catch (error) {
 			runtime.logDebug(id, 'frontend_socket_connect_failed', {
 				error: String(error),
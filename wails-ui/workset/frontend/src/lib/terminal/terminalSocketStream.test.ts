@@ -100,7 +100,7 @@ describe('terminalSocketStream', () => {
 
 		expect(socket.sent).toHaveLength(1);
 		expect(JSON.parse(String(socket.sent[0]))).toEqual({
-			protocolVersion: 2,
+			protocolVersion: 3,
 			type: 'attach',
 			sessionId: 'ws::term',
 			streamId: expect.any(String),
@@ -246,19 +246,60 @@ describe('terminalSocketStream', () => {
 		stream.stop('ws::term');
 
 		expect(JSON.parse(String(socket.sent[1]))).toEqual({
-			protocolVersion: 2,
+			protocolVersion: 3,
 			type: 'input',
 			data: 'ls\n',
 		});
 		expect(JSON.parse(String(socket.sent[2]))).toEqual({
-			protocolVersion: 2,
+			protocolVersion: 3,
 			type: 'resize',
 			cols: 120,
 			rows: 32,
 		});
 		expect(JSON.parse(String(socket.sent[3]))).toEqual({
-			protocolVersion: 2,
+			protocolVersion: 3,
 			type: 'stop',
 		});
 	});
+
+	it('holds input while another stream owns the session and hands ownership off', async () => {
+		const socket = new MockWebSocket('ws://127.0.0.1:9001/stream');
+		const onAccessChange = vi.fn();
+		const stream = createTerminalSocketStream({
+			createWebSocket: () => socket as unknown as WebSocket,
+			onChunk: vi.fn(),
+			onAccessChange,
+		});
+
+		const connectPromise = stream.connect('ws::term', { ...createDescriptor(), mode: 'owner' });
+		await Promise.resolve();
+		socket.open();
+		socket.emitText({ type: 'ready', mode: 'owner', owner: 'popout' });
+		await connectPromise;
+
+		expect(JSON.parse(String(socket.sent[0]))).toEqual(expect.objectContaining({ mode: 'owner' }));
+		stream.handoff('ws::term', 'main');
+		expect(JSON.parse(String(socket.sent[1]))).toEqual({
+			protocolVersion: 3,
+			type: 'handoff',
+			streamId: 'main',
+		});
+
+		socket.emitText({ type: 'mode', mode: 'interactive', owner: 'main' });
+		expect(onAccessChange).toHaveBeenLastCalledWith('ws::term', {
+			mode: 'interactive',
+			owner: 'main',
+			canInput: false,
+		});
+		stream.write('ws::term', 'ls\n');
+		expect(socket.sent).toHaveLength(2);
+
+		socket.emitText({ type: 'mode', mode: 'interactive' });
+		stream.write('ws::term', 'ls\n');
+		expect(JSON.parse(String(socket.sent[2]))).toEqual({
+			protocolVersion: 3,
+			type: 'input',
+			data: 'ls\n',
+		});
+	});
 });
@@ -1,20 +1,39 @@
+// TERMINAL_PROTOCOL_VERSION must match terminalservice.ProtocolVersion; the
+// service refuses streams that speak another version.
+export const TERMINAL_PROTOCOL_VERSION = 3;
+
+export type TerminalAttachMode = 'owner' | 'interactive' | 'readonly';
+
 type TerminalSocketDescriptor = {
 	sessionId: string;
 	socketUrl?: string;
 	socketToken?: string;
 	startOffset?: number;
+	mode?: TerminalAttachMode;
 };
 
 type TerminalSocketControlMessage = {
 	type?: string;
 	error?: string;
+	exitCode?: number;
+	restarting?: boolean;
+	restored?: boolean;
+	mode?: TerminalAttachMode;
+	owner?: string;
 };
 
 type TerminalSocketClientControlRequest = {
-	type: 'input' | 'resize' | 'stop';
+	type: 'input' | 'resize' | 'stop' | 'handoff';
 	data?: string;
 	cols?: number;
 	rows?: number;
+	streamId?: string;
+};
+
+export type TerminalSocketAccess = {
+	mode: TerminalAttachMode;
+	owner: string;
+	canInput: boolean;
 };
 
 type TerminalSocketDependencies = {
@@ -37,6 +56,8 @@ type TerminalSocketDependencies = {
 		},
 	) => void;
 	onError?: (id: string, error: string) => void;
+	onExit?: (id: string, details: { exitCode?: number; restarting: boolean }) => void;
+	onAccessChange?: (id: string, access: TerminalSocketAccess) => void;
 };
 
 type ActiveSocket = {
@@ -46,6 +67,8 @@ type ActiveSocket = {
 	socketURL: string;
 	sessionID: string;
 	socketToken: string;
+	streamID: string;
+	access: TerminalSocketAccess;
 	ready: boolean;
 	serverClosed: boolean;
 	pendingMessages: string[];
@@ -90,6 +113,18 @@ const decodeBinaryBuffer = (buffer: ArrayBuffer): { nextOffset: number; chunk: U
 	};
 };
 
+// A stream types when it owns the session, or when nobody does and it did
+// not attach read-only.
+const resolveAccess = (message: TerminalSocketControlMessage): TerminalSocketAccess => {
+	const mode = message.mode ?? 'interactive';
+	const owner = message.owner ?? '';
+	return {
+		mode,
+		owner,
+		canInput: mode === 'owner' || (mode === 'interactive' && owner === ''),
+	};
+};
+
 const decodeBinaryMessage = async (
 	value: Blob,
 ): Promise<{ nextOffset: number; chunk: Uint8Array }> =>
@@ -102,7 +137,7 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 
 	const encodeControlPayload = (message: TerminalSocketClientControlRequest): string =>
 		JSON.stringify({
-			protocolVersion: 2,
+			protocolVersion: TERMINAL_PROTOCOL_VERSION,
 			...message,
 		});
 
@@ -204,6 +239,8 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 			socketURL,
 			sessionID,
 			socketToken,
+			streamID,
+			access: resolveAccess({ mode: descriptor.mode }),
 			ready: false,
 			serverClosed: false,
 			pendingMessages: [],
@@ -293,12 +330,13 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 				});
 				socket.send(
 					JSON.stringify({
-						protocolVersion: 2,
+						protocolVersion: TERMINAL_PROTOCOL_VERSION,
 						type: 'attach',
 						sessionId: sessionID,
 						streamId: streamID,
 						token: socketToken,
 						startOffset: descriptor.startOffset ?? 0,
+						...(descriptor.mode ? { mode: descriptor.mode } : {}),
 					}),
 				);
 			});
@@ -316,16 +354,22 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 					}
 					if (message.type === 'ready') {
 						const current = getCurrent();
+						const access = resolveAccess(message);
 						if (current) {
 							current.ready = true;
+							current.access = access;
 							flushPendingMessages(id, current);
 						}
 						deps.logDebug?.(id, 'socket_ready', {
 							socketURL,
 							sessionID,
 							streamID,
+							restored: message.restored === true,
+							mode: access.mode,
+							owner: access.owner,
 						});
 						deps.onReady?.(id);
+						deps.onAccessChange?.(id, access);
 						if (!settled) {
 							settled = true;
 							clearTimeout(connectTimeout);
@@ -337,6 +381,36 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 						fail(message.error?.trim() || 'terminal socket attach failed');
 						return;
 					}
+					if (message.type === 'mode') {
+						const access = resolveAccess(message);
+						const current = getCurrent();
+						if (current) {
+							current.access = access;
+						}
+						deps.logDebug?.(id, 'socket_mode', {
+							socketURL,
+							sessionID,
+							streamID,
+							mode: access.mode,
+							owner: access.owner,
+						});
+						deps.onAccessChange?.(id, access);
+						return;
+					}
+					if (message.type === 'exit') {
+						const details = {
+							exitCode: message.exitCode,
+							restarting: message.restarting === true,
+						};
+						deps.logDebug?.(id, 'socket_process_exit', {
+							socketURL,
+							sessionID,
+							streamID,
+							...details,
+						});
+						deps.onExit?.(id, details);
+						return;
+					}
 					if (message.type === 'closed') {
 						const current = getCurrent();
 						if (current) {
@@ -405,8 +479,19 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 			const active = activeSockets.get(id);
 			return Boolean(active && active.ready && active.socket.readyState === WebSocket.OPEN);
 		},
+		getAccess: (id: string): TerminalSocketAccess | null => activeSockets.get(id)?.access ?? null,
+		getStreamId: (id: string): string | null => activeSockets.get(id)?.streamID ?? null,
 		write: (id: string, data: string): void => {
 			if (!data) return;
+			const active = activeSockets.get(id);
+			if (active?.ready && !active.access.canInput) {
+				deps.logDebug?.(id, 'socket_input_blocked', {
+					mode: active.access.mode,
+					owner: active.access.owner,
+					bytes: data.length,
+				});
+				return;
+			}
 			sendControl(id, {
 				type: 'input',
 				data,
@@ -419,6 +504,12 @@ export const createTerminalSocketStream = (deps: TerminalSocketDependencies) =>
 				rows,
 			});
 		},
+		handoff: (id: string, streamId = ''): void => {
+			sendControl(id, {
+				type: 'handoff',
+				streamId,
+			});
+		},
 		stop: (id: string): void => {
 			const active = activeSockets.get(id);
 			if (!active) {
@@ -232,6 +232,7 @@ export type HookExecution = {
 	id: string;
 	status: string;
 	log_path?: string;
+	reason?: string;
 };
 
 export type HooksRunResponse = {
@@ -253,6 +254,7 @@ export type HookProgressEvent = {
 	hookId: string;
 	phase: 'started' | 'finished' | 'clone-started' | 'clone-finished';
 	status?: string;
+	skipReason?: string;
 	logPath?: string;
 	error?: string;
 };
@@ -388,6 +390,51 @@ export type RepoDiffSummary = {
 	totalRemoved: number;
 };
 
+export type ThreadCommit = {
+	repo: string;
+	sha: string;
+	author: string;
+	author_email?: string;
+	time: string;
+	subject: string;
+	patch?: string;
+};
+
+export type ThreadLog = {
+	commits: ThreadCommit[];
+	warnings?: string[];
+};
+
+export type ThreadProcessState = 'running' | 'exited' | 'stopped';
+
+export type ThreadProcess = {
+	name: string;
+	repo?: string;
+	command: string;
+	session_id: string;
+	state: ThreadProcessState;
+	restarts?: number;
+	exit_code?: number;
+	started_at?: string;
+	exited_at?: string;
+};
+
+export type ThreadProcesses = {
+	processes: ThreadProcess[];
+	warnings?: string[];
+};
+
+export type ThreadProcessLogLine = {
+	process: string;
+	prefix: string;
+	text: string;
+};
+
+export type ThreadProcessLogs = {
+	lines: ThreadProcessLogLine[];
+	nextOffsets: Record<string, number>;
+};
+
 export type RepoFileDiff = {
 	patch: string;
 	truncated: boolean;
@@ -9,16 +9,17 @@ import (
 var hookEventsEmit = emitRuntimeEvent
 
 type HookProgressPayload struct {
-	Operation string `json:"operation,omitempty"`
-	Reason    string `json:"reason,omitempty"`
-	Workspace string `json:"workspace,omitempty"`
-	Repo      string `json:"repo"`
-	Event     string `json:"event"`
-	HookID    string `json:"hookId"`
-	Phase     string `json:"phase"`
-	Status    string `json:"status,omitempty"`
-	LogPath   string `json:"logPath,omitempty"`
-	Error     string `json:"error,omitempty"`
+	Operation  string `json:"operation,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	SkipReason string `json:"skipReason,omitempty"`
+	Workspace  string `json:"workspace,omitempty"`
+	Repo       string `json:"repo"`
+	Event      string `json:"event"`
+	HookID     string `json:"hookId"`
+	Phase      string `json:"phase"`
+	Status     string `json:"status,omitempty"`
+	LogPath    string `json:"logPath,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 type appHookObserver struct {
@@ -30,15 +31,16 @@ func (o appHookObserver) OnHookProgress(progress worksetapi.HookProgress) {
 		return
 	}
 	payload := HookProgressPayload{
-		Operation: hookOperation(progress.Reason),
-		Reason:    progress.Reason,
-		Workspace: progress.Workspace,
-		Repo:      progress.Repo,
-		Event:     progress.Event,
-		HookID:    progress.HookID,
-		Phase:     progress.Phase,
-		Status:    string(progress.Status),
-		LogPath:   progress.LogPath,
+		Operation:  hookOperation(progress.Reason),
+		Reason:     progress.Reason,
+		SkipReason: progress.SkipReason,
+		Workspace:  progress.Workspace,
+		Repo:       progress.Repo,
+		Event:      progress.Event,
+		HookID:     progress.HookID,
+		Phase:      progress.Phase,
+		Status:     string(progress.Status),
+		LogPath:    progress.LogPath,
 	}
 	if progress.Error != "" {
 		payload.Error = progress.Error
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/strantalis/workset/pkg/worksetapi"
+)
+
+// repoStorePrefetchIdleCheck is how often a disabled prefetch scheduler looks
+// at defaults.repo_store.prefetch_interval again.
+const repoStorePrefetchIdleCheck = time.Minute
+
+// runRepoStorePrefetch fetches every URL-registered repo into the repo store
+// every defaults.repo_store.prefetch_interval until the app exits, so new
+// threads can start from fresh mirrors without waiting on the network. The
+// interval is re-read each round so settings changes apply without a
+// restart.
+func runRepoStorePrefetch(a *App) {
+	ctx, svc := a.serviceContext()
+	for {
+		wait := repoStorePrefetchIdleCheck
+		cfg, _, err := svc.GetConfig(ctx)
+		if err == nil {
+			interval, parseErr := time.ParseDuration(strings.TrimSpace(cfg.Defaults.RepoStore.PrefetchInterval))
+			if parseErr == nil && interval > 0 {
+				wait = interval
+				prefetchRepoStore(a)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func prefetchRepoStore(a *App) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.FetchRepoStore(ctx, worksetapi.RepoStoreFetchInput{})
+	if err != nil {
+		log.Printf("workset: repo store prefetch: %v", err)
+		return
+	}
+	for _, warning := range result.Warnings {
+		log.Printf("workset: repo store prefetch: %s", warning)
+	}
+	emitRuntimeEvent(ctx, EventRepoStoreFetched, result.Repos)
+}
+
+func (a *App) GetRepoStoreStatus() ([]worksetapi.RepoStoreEntryJSON, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.RepoStoreStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Repos, nil
+}
+
+func (a *App) FetchRepoStore(repos []string) ([]worksetapi.RepoStoreEntryJSON, error) {
+	ctx, svc := a.serviceContext()
+	result, err := svc.FetchRepoStore(ctx, worksetapi.RepoStoreFetchInput{Repos: repos})
+	if err != nil {
+		return nil, err
+	}
+	emitRuntimeEvent(ctx, EventRepoStoreFetched, result.Repos)
+	return result.Repos, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/strantalis/workset/pkg/terminalservice"
 )
 
 func (a *App) startWorkspaceTerminal(workspaceID, terminalID string) error {
@@ -87,9 +88,13 @@ func (a *App) startWorkspaceTerminal(workspaceID, terminalID string) error {
 		a.terminalMu.Unlock()
 
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		resp, createErr := session.client.Create(ctx, sessionID, root)
-		_ = resp
-		err = createErr
+		// A popout window attaches as the owner, so its size wins over the
+		// main window's while it is open.
+		_, err = session.client.CreateSession(ctx, terminalservice.CreateRequest{
+			SessionID:    sessionID,
+			Cwd:          root,
+			ResizePolicy: terminalservice.ResizeOwner,
+		})
 		cancel()
 		if err == nil {
 			session.mu.Lock()
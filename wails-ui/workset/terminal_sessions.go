@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/strantalis/workset/pkg/terminalservice"
 )
 
 var transientTerminalErrorMarkers = []string{
@@ -76,3 +78,42 @@ func (a *App) workspaceTerminalSessionDescriptor(
 func (a *App) StopWorkspaceTerminalForWindow(_ context.Context, workspaceID, terminalID string) error {
 	return a.stopWorkspaceTerminal(workspaceID, terminalID)
 }
+
+// SearchTerminals searches the output of terminals, open and closed, in one
+// workspace or in all of them when workspaceID is empty.
+func (a *App) SearchTerminals(query, workspaceID string, ignoreCase bool) (TerminalSearchResult, error) {
+	client, err := a.getTerminalServiceClient()
+	if err != nil {
+		return TerminalSearchResult{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := client.Search(ctx, terminalservice.SearchRequest{
+		Query:      query,
+		IgnoreCase: ignoreCase,
+		Workspace:  strings.TrimSpace(workspaceID),
+		Context:    2,
+	})
+	if err != nil {
+		return TerminalSearchResult{}, err
+	}
+	result := TerminalSearchResult{
+		Matches:   make([]TerminalSearchMatch, 0, len(resp.Matches)),
+		Truncated: resp.Truncated,
+	}
+	for _, match := range resp.Matches {
+		_, terminalID, _ := strings.Cut(match.SessionID, terminalSessionSeparator)
+		result.Matches = append(result.Matches, TerminalSearchMatch{
+			WorkspaceID: match.Workspace,
+			TerminalID:  terminalID,
+			SessionID:   match.SessionID,
+			Offset:      match.Offset,
+			Timestamp:   match.Timestamp,
+			Live:        match.Live,
+			Line:        match.Line,
+			Before:      match.Before,
+			After:       match.After,
+		})
+	}
+	return result, nil
+}
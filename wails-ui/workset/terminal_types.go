@@ -30,6 +30,26 @@ type TerminalSessionDescriptor struct {
 	SocketToken string `json:"socketToken,omitempty"`
 }
 
+// TerminalSearchMatch is a line of terminal output that matched a search.
+// Offset replays the terminal from the matching line when passed as the
+// socket's startOffset.
+type TerminalSearchMatch struct {
+	WorkspaceID string   `json:"workspaceId,omitempty"`
+	TerminalID  string   `json:"terminalId,omitempty"`
+	SessionID   string   `json:"sessionId"`
+	Offset      int64    `json:"offset"`
+	Timestamp   string   `json:"timestamp,omitempty"`
+	Live        bool     `json:"live"`
+	Line        string   `json:"line"`
+	Before      []string `json:"before,omitempty"`
+	After       []string `json:"after,omitempty"`
+}
+
+type TerminalSearchResult struct {
+	Matches   []TerminalSearchMatch `json:"matches"`
+	Truncated bool                  `json:"truncated,omitempty"`
+}
+
 type terminalSession struct {
 	id          string
 	workspaceID string